@@ -327,6 +327,8 @@ func (m *ImageMessageRequest) validate(all bool) error {
 
 	// no validation rules for Type
 
+	// no validation rules for UploadId
+
 	// no validation rules for Url
 
 	// no validation rules for Width
@@ -468,6 +470,8 @@ func (m *VoiceMessageRequest) validate(all bool) error {
 
 	// no validation rules for Type
 
+	// no validation rules for UploadId
+
 	// no validation rules for Url
 
 	// no validation rules for Duration
@@ -607,6 +611,8 @@ func (m *VideoMessageRequest) validate(all bool) error {
 
 	// no validation rules for Type
 
+	// no validation rules for UploadId
+
 	// no validation rules for Url
 
 	// no validation rules for Duration
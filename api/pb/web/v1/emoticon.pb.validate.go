@@ -1231,6 +1231,40 @@ func (m *EmoticonUploadResponse) validate(all bool) error {
 
 	// no validation rules for Src
 
+	for idx, item := range m.GetRenditions() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, EmoticonUploadResponseValidationError{
+						field:  fmt.Sprintf("Renditions[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, EmoticonUploadResponseValidationError{
+						field:  fmt.Sprintf("Renditions[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return EmoticonUploadResponseValidationError{
+					field:  fmt.Sprintf("Renditions[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	if len(errors) > 0 {
 		return EmoticonUploadResponseMultiError(errors)
 	}
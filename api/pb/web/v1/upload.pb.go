@@ -21,6 +21,78 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// 图片缩略图信息
+type ImageRendition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Width  int32  `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height int32  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	Url    string `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+}
+
+func (x *ImageRendition) Reset() {
+	*x = ImageRendition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_web_v1_upload_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImageRendition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageRendition) ProtoMessage() {}
+
+func (x *ImageRendition) ProtoReflect() protoreflect.Message {
+	mi := &file_web_v1_upload_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageRendition.ProtoReflect.Descriptor instead.
+func (*ImageRendition) Descriptor() ([]byte, []int) {
+	return file_web_v1_upload_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ImageRendition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ImageRendition) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *ImageRendition) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *ImageRendition) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
 // 头像上传接口请求参数
 type UploadAvatarRequest struct {
 	state         protoimpl.MessageState
@@ -31,7 +103,7 @@ type UploadAvatarRequest struct {
 func (x *UploadAvatarRequest) Reset() {
 	*x = UploadAvatarRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_web_v1_upload_proto_msgTypes[0]
+		mi := &file_web_v1_upload_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -44,7 +116,7 @@ func (x *UploadAvatarRequest) String() string {
 func (*UploadAvatarRequest) ProtoMessage() {}
 
 func (x *UploadAvatarRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_web_v1_upload_proto_msgTypes[0]
+	mi := &file_web_v1_upload_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -57,7 +129,7 @@ func (x *UploadAvatarRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadAvatarRequest.ProtoReflect.Descriptor instead.
 func (*UploadAvatarRequest) Descriptor() ([]byte, []int) {
-	return file_web_v1_upload_proto_rawDescGZIP(), []int{0}
+	return file_web_v1_upload_proto_rawDescGZIP(), []int{1}
 }
 
 // 头像上传接口响应参数
@@ -66,13 +138,14 @@ type UploadAvatarResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Avatar string `protobuf:"bytes,1,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	Avatar     string            `protobuf:"bytes,1,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	Renditions []*ImageRendition `protobuf:"bytes,2,rep,name=renditions,proto3" json:"renditions,omitempty"`
 }
 
 func (x *UploadAvatarResponse) Reset() {
 	*x = UploadAvatarResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_web_v1_upload_proto_msgTypes[1]
+		mi := &file_web_v1_upload_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -85,7 +158,7 @@ func (x *UploadAvatarResponse) String() string {
 func (*UploadAvatarResponse) ProtoMessage() {}
 
 func (x *UploadAvatarResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_web_v1_upload_proto_msgTypes[1]
+	mi := &file_web_v1_upload_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -98,7 +171,7 @@ func (x *UploadAvatarResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadAvatarResponse.ProtoReflect.Descriptor instead.
 func (*UploadAvatarResponse) Descriptor() ([]byte, []int) {
-	return file_web_v1_upload_proto_rawDescGZIP(), []int{1}
+	return file_web_v1_upload_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *UploadAvatarResponse) GetAvatar() string {
@@ -108,6 +181,13 @@ func (x *UploadAvatarResponse) GetAvatar() string {
 	return ""
 }
 
+func (x *UploadAvatarResponse) GetRenditions() []*ImageRendition {
+	if x != nil {
+		return x.Renditions
+	}
+	return nil
+}
+
 // 批量上传文件初始化接口请求参数
 type UploadInitiateMultipartRequest struct {
 	state         protoimpl.MessageState
@@ -121,7 +201,7 @@ type UploadInitiateMultipartRequest struct {
 func (x *UploadInitiateMultipartRequest) Reset() {
 	*x = UploadInitiateMultipartRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_web_v1_upload_proto_msgTypes[2]
+		mi := &file_web_v1_upload_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -134,7 +214,7 @@ func (x *UploadInitiateMultipartRequest) String() string {
 func (*UploadInitiateMultipartRequest) ProtoMessage() {}
 
 func (x *UploadInitiateMultipartRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_web_v1_upload_proto_msgTypes[2]
+	mi := &file_web_v1_upload_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -147,7 +227,7 @@ func (x *UploadInitiateMultipartRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadInitiateMultipartRequest.ProtoReflect.Descriptor instead.
 func (*UploadInitiateMultipartRequest) Descriptor() ([]byte, []int) {
-	return file_web_v1_upload_proto_rawDescGZIP(), []int{2}
+	return file_web_v1_upload_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *UploadInitiateMultipartRequest) GetFileName() string {
@@ -177,7 +257,7 @@ type UploadInitiateMultipartResponse struct {
 func (x *UploadInitiateMultipartResponse) Reset() {
 	*x = UploadInitiateMultipartResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_web_v1_upload_proto_msgTypes[3]
+		mi := &file_web_v1_upload_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -190,7 +270,7 @@ func (x *UploadInitiateMultipartResponse) String() string {
 func (*UploadInitiateMultipartResponse) ProtoMessage() {}
 
 func (x *UploadInitiateMultipartResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_web_v1_upload_proto_msgTypes[3]
+	mi := &file_web_v1_upload_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -203,7 +283,7 @@ func (x *UploadInitiateMultipartResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadInitiateMultipartResponse.ProtoReflect.Descriptor instead.
 func (*UploadInitiateMultipartResponse) Descriptor() ([]byte, []int) {
-	return file_web_v1_upload_proto_rawDescGZIP(), []int{3}
+	return file_web_v1_upload_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *UploadInitiateMultipartResponse) GetUploadId() string {
@@ -234,7 +314,7 @@ type UploadMultipartRequest struct {
 func (x *UploadMultipartRequest) Reset() {
 	*x = UploadMultipartRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_web_v1_upload_proto_msgTypes[4]
+		mi := &file_web_v1_upload_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -247,7 +327,7 @@ func (x *UploadMultipartRequest) String() string {
 func (*UploadMultipartRequest) ProtoMessage() {}
 
 func (x *UploadMultipartRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_web_v1_upload_proto_msgTypes[4]
+	mi := &file_web_v1_upload_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -260,7 +340,7 @@ func (x *UploadMultipartRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadMultipartRequest.ProtoReflect.Descriptor instead.
 func (*UploadMultipartRequest) Descriptor() ([]byte, []int) {
-	return file_web_v1_upload_proto_rawDescGZIP(), []int{4}
+	return file_web_v1_upload_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *UploadMultipartRequest) GetUploadId() string {
@@ -297,7 +377,7 @@ type UploadMultipartResponse struct {
 func (x *UploadMultipartResponse) Reset() {
 	*x = UploadMultipartResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_web_v1_upload_proto_msgTypes[5]
+		mi := &file_web_v1_upload_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -310,7 +390,7 @@ func (x *UploadMultipartResponse) String() string {
 func (*UploadMultipartResponse) ProtoMessage() {}
 
 func (x *UploadMultipartResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_web_v1_upload_proto_msgTypes[5]
+	mi := &file_web_v1_upload_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -323,7 +403,7 @@ func (x *UploadMultipartResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadMultipartResponse.ProtoReflect.Descriptor instead.
 func (*UploadMultipartResponse) Descriptor() ([]byte, []int) {
-	return file_web_v1_upload_proto_rawDescGZIP(), []int{5}
+	return file_web_v1_upload_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UploadMultipartResponse) GetUploadId() string {
@@ -346,48 +426,58 @@ var file_web_v1_upload_proto_rawDesc = []byte{
 	0x0a, 0x13, 0x77, 0x65, 0x62, 0x2f, 0x76, 0x31, 0x2f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x77, 0x65, 0x62, 0x1a, 0x13, 0x74, 0x61, 0x67, 0x67,
 	0x65, 0x72, 0x2f, 0x74, 0x61, 0x67, 0x67, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
-	0x15, 0x0a, 0x13, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x41, 0x76, 0x61, 0x74, 0x61, 0x72, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x2e, 0x0a, 0x14, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
-	0x41, 0x76, 0x61, 0x74, 0x61, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16,
-	0x0a, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x22, 0x8c, 0x01, 0x0a, 0x1e, 0x55, 0x70, 0x6c, 0x6f, 0x61,
-	0x64, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61,
-	0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x34, 0x0a, 0x09, 0x66, 0x69, 0x6c,
-	0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x17, 0x9a, 0x84,
-	0x9e, 0x03, 0x12, 0x62, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x3a, 0x22, 0x72, 0x65, 0x71, 0x75,
-	0x69, 0x72, 0x65, 0x64, 0x22, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12,
-	0x34, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x03, 0x42, 0x17, 0x9a, 0x84, 0x9e, 0x03, 0x12, 0x62, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67,
-	0x3a, 0x22, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x22, 0x52, 0x08, 0x66, 0x69, 0x6c,
-	0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x5d, 0x0a, 0x1f, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49,
-	0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x72, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x70, 0x6c, 0x6f,
-	0x61, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x70, 0x6c,
-	0x6f, 0x61, 0x64, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x73,
-	0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x73, 0x70, 0x6c, 0x69, 0x74,
-	0x53, 0x69, 0x7a, 0x65, 0x22, 0xf6, 0x01, 0x0a, 0x16, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d,
-	0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x45, 0x0a, 0x09, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x42, 0x28, 0x9a, 0x84, 0x9e, 0x03, 0x23, 0x66, 0x6f, 0x72, 0x6d, 0x3a, 0x22, 0x75,
-	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x69, 0x64, 0x22, 0x20, 0x62, 0x69, 0x6e, 0x64, 0x69, 0x6e,
-	0x67, 0x3a, 0x22, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x22, 0x52, 0x08, 0x75, 0x70,
-	0x6c, 0x6f, 0x61, 0x64, 0x49, 0x64, 0x12, 0x48, 0x0a, 0x0b, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f,
-	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x42, 0x27, 0x9a, 0x84, 0x9e,
-	0x03, 0x22, 0x66, 0x6f, 0x72, 0x6d, 0x3a, 0x22, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x69, 0x6e,
-	0x64, 0x65, 0x78, 0x22, 0x20, 0x62, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x3a, 0x22, 0x6d, 0x69,
-	0x6e, 0x3d, 0x30, 0x22, 0x52, 0x0a, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78,
-	0x12, 0x4b, 0x0a, 0x09, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x05, 0x42, 0x2e, 0x9a, 0x84, 0x9e, 0x03, 0x29, 0x66, 0x6f, 0x72, 0x6d, 0x3a, 0x22,
-	0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x22, 0x20, 0x62, 0x69, 0x6e, 0x64, 0x69,
-	0x6e, 0x67, 0x3a, 0x22, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x2c, 0x6d, 0x69, 0x6e,
-	0x3d, 0x31, 0x22, 0x52, 0x08, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x4e, 0x75, 0x6d, 0x22, 0x51, 0x0a,
-	0x17, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x72, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x70, 0x6c, 0x6f,
-	0x61, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x70, 0x6c,
-	0x6f, 0x61, 0x64, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x6d, 0x65, 0x72, 0x67,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x4d, 0x65, 0x72, 0x67, 0x65,
-	0x42, 0x0c, 0x5a, 0x0a, 0x77, 0x65, 0x62, 0x2f, 0x76, 0x31, 0x3b, 0x77, 0x65, 0x62, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x64, 0x0a, 0x0e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x75, 0x72, 0x6c, 0x22, 0x15, 0x0a, 0x13, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x41,
+	0x76, 0x61, 0x74, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x63, 0x0a, 0x14,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x41, 0x76, 0x61, 0x74, 0x61, 0x72, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x12, 0x33, 0x0a, 0x0a,
+	0x72, 0x65, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x6e, 0x64,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x72, 0x65, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x22, 0x8c, 0x01, 0x0a, 0x1e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x74, 0x65, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x34, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x17, 0x9a, 0x84, 0x9e, 0x03, 0x12, 0x62, 0x69,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x3a, 0x22, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x22,
+	0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x34, 0x0a, 0x09, 0x66, 0x69,
+	0x6c, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x42, 0x17, 0x9a,
+	0x84, 0x9e, 0x03, 0x12, 0x62, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x3a, 0x22, 0x72, 0x65, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x64, 0x22, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x69, 0x7a, 0x65,
+	0x22, 0x5d, 0x0a, 0x1f, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61,
+	0x74, 0x65, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x64,
+	0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x22,
+	0xf6, 0x01, 0x0a, 0x16, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70,
+	0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x45, 0x0a, 0x09, 0x75, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x28, 0x9a,
+	0x84, 0x9e, 0x03, 0x23, 0x66, 0x6f, 0x72, 0x6d, 0x3a, 0x22, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x5f, 0x69, 0x64, 0x22, 0x20, 0x62, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x3a, 0x22, 0x72, 0x65,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x22, 0x52, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49,
+	0x64, 0x12, 0x48, 0x0a, 0x0b, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x42, 0x27, 0x9a, 0x84, 0x9e, 0x03, 0x22, 0x66, 0x6f, 0x72,
+	0x6d, 0x3a, 0x22, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x20,
+	0x62, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x3a, 0x22, 0x6d, 0x69, 0x6e, 0x3d, 0x30, 0x22, 0x52,
+	0x0a, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x4b, 0x0a, 0x09, 0x73,
+	0x70, 0x6c, 0x69, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x42, 0x2e,
+	0x9a, 0x84, 0x9e, 0x03, 0x29, 0x66, 0x6f, 0x72, 0x6d, 0x3a, 0x22, 0x73, 0x70, 0x6c, 0x69, 0x74,
+	0x5f, 0x6e, 0x75, 0x6d, 0x22, 0x20, 0x62, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x3a, 0x22, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x2c, 0x6d, 0x69, 0x6e, 0x3d, 0x31, 0x22, 0x52, 0x08,
+	0x73, 0x70, 0x6c, 0x69, 0x74, 0x4e, 0x75, 0x6d, 0x22, 0x51, 0x0a, 0x17, 0x55, 0x70, 0x6c, 0x6f,
+	0x61, 0x64, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x64,
+	0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x42, 0x0c, 0x5a, 0x0a, 0x77,
+	0x65, 0x62, 0x2f, 0x76, 0x31, 0x3b, 0x77, 0x65, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -402,21 +492,23 @@ func file_web_v1_upload_proto_rawDescGZIP() []byte {
 	return file_web_v1_upload_proto_rawDescData
 }
 
-var file_web_v1_upload_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_web_v1_upload_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_web_v1_upload_proto_goTypes = []interface{}{
-	(*UploadAvatarRequest)(nil),             // 0: web.UploadAvatarRequest
-	(*UploadAvatarResponse)(nil),            // 1: web.UploadAvatarResponse
-	(*UploadInitiateMultipartRequest)(nil),  // 2: web.UploadInitiateMultipartRequest
-	(*UploadInitiateMultipartResponse)(nil), // 3: web.UploadInitiateMultipartResponse
-	(*UploadMultipartRequest)(nil),          // 4: web.UploadMultipartRequest
-	(*UploadMultipartResponse)(nil),         // 5: web.UploadMultipartResponse
+	(*ImageRendition)(nil),                  // 0: web.ImageRendition
+	(*UploadAvatarRequest)(nil),             // 1: web.UploadAvatarRequest
+	(*UploadAvatarResponse)(nil),            // 2: web.UploadAvatarResponse
+	(*UploadInitiateMultipartRequest)(nil),  // 3: web.UploadInitiateMultipartRequest
+	(*UploadInitiateMultipartResponse)(nil), // 4: web.UploadInitiateMultipartResponse
+	(*UploadMultipartRequest)(nil),          // 5: web.UploadMultipartRequest
+	(*UploadMultipartResponse)(nil),         // 6: web.UploadMultipartResponse
 }
 var file_web_v1_upload_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: web.UploadAvatarResponse.renditions:type_name -> web.ImageRendition
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_web_v1_upload_proto_init() }
@@ -426,7 +518,7 @@ func file_web_v1_upload_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_web_v1_upload_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadAvatarRequest); i {
+			switch v := v.(*ImageRendition); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -438,7 +530,7 @@ func file_web_v1_upload_proto_init() {
 			}
 		}
 		file_web_v1_upload_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadAvatarResponse); i {
+			switch v := v.(*UploadAvatarRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -450,7 +542,7 @@ func file_web_v1_upload_proto_init() {
 			}
 		}
 		file_web_v1_upload_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadInitiateMultipartRequest); i {
+			switch v := v.(*UploadAvatarResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -462,7 +554,7 @@ func file_web_v1_upload_proto_init() {
 			}
 		}
 		file_web_v1_upload_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadInitiateMultipartResponse); i {
+			switch v := v.(*UploadInitiateMultipartRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -474,7 +566,7 @@ func file_web_v1_upload_proto_init() {
 			}
 		}
 		file_web_v1_upload_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadMultipartRequest); i {
+			switch v := v.(*UploadInitiateMultipartResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -486,6 +578,18 @@ func file_web_v1_upload_proto_init() {
 			}
 		}
 		file_web_v1_upload_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadMultipartRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_web_v1_upload_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UploadMultipartResponse); i {
 			case 0:
 				return &v.state
@@ -504,7 +608,7 @@ func file_web_v1_upload_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_web_v1_upload_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -35,6 +35,114 @@ var (
 	_ = sort.Sort
 )
 
+// Validate checks the field values on ImageRendition with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *ImageRendition) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImageRendition with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ImageRenditionMultiError,
+// or nil if none found.
+func (m *ImageRendition) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImageRendition) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Name
+
+	// no validation rules for Width
+
+	// no validation rules for Height
+
+	// no validation rules for Url
+
+	if len(errors) > 0 {
+		return ImageRenditionMultiError(errors)
+	}
+
+	return nil
+}
+
+// ImageRenditionMultiError is an error wrapping multiple validation errors
+// returned by ImageRendition.ValidateAll() if the designated constraints
+// aren't met.
+type ImageRenditionMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImageRenditionMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImageRenditionMultiError) AllErrors() []error { return m }
+
+// ImageRenditionValidationError is the validation error returned by
+// ImageRendition.Validate if the designated constraints aren't met.
+type ImageRenditionValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImageRenditionValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImageRenditionValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImageRenditionValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImageRenditionValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImageRenditionValidationError) ErrorName() string { return "ImageRenditionValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ImageRenditionValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImageRendition.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImageRenditionValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImageRenditionValidationError{}
+
 // Validate checks the field values on UploadAvatarRequest with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
@@ -161,6 +269,40 @@ func (m *UploadAvatarResponse) validate(all bool) error {
 
 	// no validation rules for Avatar
 
+	for idx, item := range m.GetRenditions() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, UploadAvatarResponseValidationError{
+						field:  fmt.Sprintf("Renditions[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, UploadAvatarResponseValidationError{
+						field:  fmt.Sprintf("Renditions[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return UploadAvatarResponseValidationError{
+					field:  fmt.Sprintf("Renditions[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	if len(errors) > 0 {
 		return UploadAvatarResponseMultiError(errors)
 	}
@@ -22,6 +22,8 @@ type Config struct {
 	Filesystem *Filesystem `json:"filesystem" yaml:"filesystem"`
 	Email      *Email      `json:"email" yaml:"email"`
 	Ports      *Ports      `json:"ports" yaml:"ports"`
+	Oidc       *Oidc       `json:"oidc" yaml:"oidc"`
+	Moderation *Moderation `json:"moderation" yaml:"moderation"`
 }
 
 type Ports struct {
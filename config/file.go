@@ -8,10 +8,12 @@ type LocalSystem struct {
 
 // OssSystem 阿里云 OSS 文件存储
 type OssSystem struct {
-	AccessID     string `json:"access_id" yaml:"access_id"`
-	AccessSecret string `json:"access_secret" yaml:"access_secret"`
-	Bucket       string `json:"bucket" yaml:"bucket"`
-	Endpoint     string `json:"endpoint" yaml:"endpoint"`
+	AccessID      string `json:"access_id" yaml:"access_id"`
+	AccessSecret  string `json:"access_secret" yaml:"access_secret"`
+	Bucket        string `json:"bucket" yaml:"bucket"`
+	Endpoint      string `json:"endpoint" yaml:"endpoint"` // 如 https://oss-cn-hangzhou.aliyuncs.com
+	SecurityToken string `json:"security_token" yaml:"security_token"`
+	Domain        string `json:"domain" yaml:"domain"` // 自定义访问域名，为空时使用 bucket 默认域名
 }
 
 // QiniuSystem 七牛云文件存储
@@ -19,10 +21,24 @@ type QiniuSystem struct {
 	AccessKey string `json:"access_key" yaml:"access_key"`
 	SecretKey string `json:"secret_key" yaml:"secret_key"`
 	Bucket    string `json:"bucket" yaml:"bucket"`
-	Domain    string `json:"domain" yaml:"domain"`
+	Domain    string `json:"domain" yaml:"domain"`   // 访问域名
+	UpHost    string `json:"up_host" yaml:"up_host"` // 上传域名，默认 https://up.qiniup.com
+	RsHost    string `json:"rs_host" yaml:"rs_host"` // 资源管理域名，默认 https://rs.qbox.me
 }
 
-// OssSystem 阿里云 OSS 文件存储
+// S3System S3 兼容协议文件存储(AWS S3、MinIO 等)
+type S3System struct {
+	AccessKey    string `json:"access_key" yaml:"access_key"`
+	SecretKey    string `json:"secret_key" yaml:"secret_key"`
+	SessionToken string `json:"session_token" yaml:"session_token"`
+	Bucket       string `json:"bucket" yaml:"bucket"`
+	Region       string `json:"region" yaml:"region"`
+	Endpoint     string `json:"endpoint" yaml:"endpoint"`     // 如 https://s3.amazonaws.com 或 http://127.0.0.1:9000
+	PathStyle    bool   `json:"path_style" yaml:"path_style"` // 是否使用路径风格访问(MinIO 需开启)
+	Domain       string `json:"domain" yaml:"domain"`         // 自定义访问域名，为空时使用 endpoint
+}
+
+// CosSystem 腾讯云 COS 文件存储
 type CosSystem struct {
 	SecretId  string `json:"secret_id" yaml:"secret_id"`
 	SecretKey string `json:"secret_key" yaml:"secret_key"`
@@ -30,10 +46,25 @@ type CosSystem struct {
 	Region    string `json:"region" yaml:"region"`
 }
 
+// ImageRendition 图片规格
+type ImageRendition struct {
+	Name string `json:"name" yaml:"name"` // 规格名称
+	Size int    `json:"size" yaml:"size"` // 长边像素
+}
+
+// ImageProcess 上传图片处理配置
+type ImageProcess struct {
+	MaxDimension int              `json:"max_dimension" yaml:"max_dimension"` // 原图长边最大像素
+	Quality      int              `json:"quality" yaml:"quality"`             // JPEG 压缩质量
+	Renditions   []ImageRendition `json:"renditions" yaml:"renditions"`       // 多尺寸规格
+}
+
 type Filesystem struct {
-	Default string      `json:"default" yaml:"default"`
-	Local   LocalSystem `json:"local" yaml:"local"`
-	Oss     OssSystem   `json:"oss" yaml:"oss"`
-	Qiniu   QiniuSystem `json:"qiniu" yaml:"qiniu"`
-	Cos     CosSystem   `json:"cos" yaml:"cos"`
+	Default string       `json:"default" yaml:"default"`
+	Local   LocalSystem  `json:"local" yaml:"local"`
+	Oss     OssSystem    `json:"oss" yaml:"oss"`
+	Qiniu   QiniuSystem  `json:"qiniu" yaml:"qiniu"`
+	Cos     CosSystem    `json:"cos" yaml:"cos"`
+	S3      S3System     `json:"s3" yaml:"s3"`
+	Image   ImageProcess `json:"image" yaml:"image"`
 }
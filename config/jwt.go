@@ -2,7 +2,52 @@ package config
 
 // Jwt 相关配置信息
 type Jwt struct {
-	Secret      string `yaml:"secret"`       // Jwt 秘钥
-	ExpiresTime int64  `yaml:"expires_time"` // 过期时间(单位秒)
-	BufferTime  int64  `yaml:"buffer_time"`  // 缓冲时间(单位秒)
+	Secret             string `yaml:"secret"`               // Jwt 秘钥，HS256 签名及旧令牌验签使用
+	ExpiresTime        int64  `yaml:"expires_time"`         // 过期时间(单位秒)
+	BufferTime         int64  `yaml:"buffer_time"`          // 缓冲时间(单位秒)
+	RefreshExpiresTime int64  `yaml:"refresh_expires_time"` // 刷新令牌过期时间(单位秒)，默认 30 天
+	Algorithm          string `yaml:"algorithm"`            // 签名算法[HS256;RS256;EdDSA;]，默认 HS256
+	RotateTime         int64  `yaml:"rotate_time"`          // 签名密钥轮换周期(单位秒)，默认 30 天
+	GraceTime          int64  `yaml:"grace_time"`           // 退役密钥继续验签的宽限期(单位秒)，默认 1 天
+}
+
+// GetRefreshExpiresTime 获取刷新令牌过期时间(单位秒)
+func (j *Jwt) GetRefreshExpiresTime() int64 {
+	if j.RefreshExpiresTime <= 0 {
+		return 30 * 86400
+	}
+
+	return j.RefreshExpiresTime
+}
+
+// GetAlgorithm 获取签名算法
+func (j *Jwt) GetAlgorithm() string {
+	if j.Algorithm == "" {
+		return "HS256"
+	}
+
+	return j.Algorithm
+}
+
+// GetRotateTime 获取签名密钥轮换周期(单位秒)
+func (j *Jwt) GetRotateTime() int64 {
+	if j.RotateTime <= 0 {
+		return 30 * 86400
+	}
+
+	return j.RotateTime
+}
+
+// GetGraceTime 获取退役密钥宽限期(单位秒)，不小于访问令牌的有效期
+func (j *Jwt) GetGraceTime() int64 {
+	grace := j.GraceTime
+	if grace <= 0 {
+		grace = 86400
+	}
+
+	if grace < j.ExpiresTime {
+		grace = j.ExpiresTime
+	}
+
+	return grace
 }
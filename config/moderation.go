@@ -0,0 +1,22 @@
+package config
+
+// Moderation 消息内容审核配置
+type Moderation struct {
+	Enable     bool                  `yaml:"enable"`      // 是否开启
+	SpamWindow int                   `yaml:"spam_window"` // 重复消息检测窗口(单位秒)
+	SpamLimit  int                   `yaml:"spam_limit"`  // 窗口内允许发送相同内容的次数，为 0 时不检测
+	Classifier *ModerationClassifier `yaml:"classifier"`  // 外部内容分类服务
+}
+
+// ModerationClassifier 外部内容分类服务配置
+type ModerationClassifier struct {
+	Url        string  `yaml:"url"`         // 分类接口地址，为空时不启用
+	FlagScore  float64 `yaml:"flag_score"`  // 达到该分值时进入人工审核
+	BlockScore float64 `yaml:"block_score"` // 达到该分值时拦截
+	FailOpen   bool    `yaml:"fail_open"`   // 分类服务异常时是否放行
+}
+
+// IsEnabled 是否开启消息内容审核
+func (m *Moderation) IsEnabled() bool {
+	return m != nil && m.Enable
+}
@@ -0,0 +1,28 @@
+package config
+
+// Oidc OpenID Connect 单点登录配置
+type Oidc struct {
+	Enable       bool          `yaml:"enable"`        // 是否开启
+	Name         string        `yaml:"name"`          // 登录方式名称，用于前端展示
+	Issuer       string        `yaml:"issuer"`        // IdP 标识，发现文档地址为 {issuer}/.well-known/openid-configuration
+	ClientId     string        `yaml:"client_id"`     // 客户端ID
+	ClientSecret string        `yaml:"client_secret"` // 客户端秘钥
+	RedirectUrl  string        `yaml:"redirect_url"`  // 授权回调地址(前端页面)
+	Scopes       []string      `yaml:"scopes"`        // 授权范围，默认 openid email profile
+	AutoRegister bool          `yaml:"auto_register"` // 首次登录时自动创建账号
+	LinkEmail    bool          `yaml:"link_email"`    // 通过已验证的邮箱关联已有账号
+	Organize     *OidcOrganize `yaml:"organize"`      // 同步企业成员信息
+}
+
+// OidcOrganize 根据 ID Token 声明同步企业成员的部门
+type OidcOrganize struct {
+	Enable            bool              `yaml:"enable"`             // 是否同步
+	DepartmentClaim   string            `yaml:"department_claim"`   // 部门声明名称
+	DepartmentMapping map[string]string `yaml:"department_mapping"` // 声明值与部门ID的映射
+	DefaultDepartment string            `yaml:"default_department"` // 未匹配时的默认部门ID
+}
+
+// IsEnabled 是否开启单点登录
+func (o *Oidc) IsEnabled() bool {
+	return o != nil && o.Enable && o.Issuer != "" && o.ClientId != ""
+}
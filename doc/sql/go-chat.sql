@@ -21,12 +21,13 @@ CREATE TABLE `article_annex`
     `id`            int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '文件ID',
     `user_id`       int(11) unsigned NOT NULL DEFAULT '0' COMMENT '上传文件的用户ID',
     `article_id`    int(11) unsigned NOT NULL DEFAULT '1' COMMENT '笔记ID',
-    `drive`         tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '文件驱动[1:local;2:cos;]',
+    `drive`         tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '文件驱动[1:local;2:cos;3:s3;4:oss;5:qiniu;]',
     `suffix`        varchar(10)  NOT NULL DEFAULT '' COMMENT '文件后缀名',
     `size`          bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '文件大小',
     `path`          varchar(500) NOT NULL DEFAULT '' COMMENT '文件地址（相对地址）',
     `original_name` varchar(100) NOT NULL DEFAULT '' COMMENT '原文件名',
     `status`        tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '附件状态[1:正常;2:已删除;]',
+    `blob_id`       int(11) unsigned NOT NULL DEFAULT '0' COMMENT '文件存储ID',
     `created_at`    datetime     NOT NULL COMMENT '创建时间',
     `updated_at`    datetime     NOT NULL COMMENT '更新时间',
     `deleted_at`    datetime              DEFAULT NULL COMMENT '删除时间',
@@ -90,10 +91,13 @@ CREATE TABLE `contact_apply`
     `user_id`    int(11) unsigned NOT NULL DEFAULT '0' COMMENT '申请人ID',
     `friend_id`  int(11) unsigned NOT NULL DEFAULT '0' COMMENT '被申请人',
     `remark`     varchar(50) NOT NULL DEFAULT '' COMMENT '申请备注',
+    `status`     tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '申请状态[0:待处理;1:已同意;2:已拒绝;3:已过期;]',
+    `reason`     varchar(50) NOT NULL DEFAULT '' COMMENT '拒绝原因',
     `created_at` datetime    NOT NULL COMMENT '申请时间',
+    `updated_at` datetime    NOT NULL COMMENT '更新时间',
     PRIMARY KEY (`id`),
-    KEY          `idx_user_id` (`user_id`) USING BTREE,
-    KEY          `idx_friend_id` (`friend_id`) USING BTREE
+    KEY          `idx_user_id_friend_id` (`user_id`,`friend_id`) USING BTREE,
+    KEY          `idx_friend_id_status` (`friend_id`,`status`) USING BTREE
 ) ENGINE=InnoDB AUTO_INCREMENT=257 DEFAULT CHARSET=utf8 COMMENT='用户添加好友申请表';;
 
 CREATE TABLE `emoticon`
@@ -117,6 +121,7 @@ CREATE TABLE `emoticon_item`
     `url`         varchar(255) NOT NULL DEFAULT '' COMMENT '图片链接',
     `file_suffix` varchar(10)  NOT NULL DEFAULT '' COMMENT '文件后缀名',
     `file_size`   bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '文件大小（单位字节）',
+    `blob_id`     int(11) unsigned NOT NULL DEFAULT '0' COMMENT '文件存储ID',
     `created_at`  datetime     NOT NULL COMMENT '创建时间',
     `updated_at`  datetime     NOT NULL COMMENT '更新时间',
     PRIMARY KEY (`id`)
@@ -131,9 +136,16 @@ CREATE TABLE `group`
     `profile`      varchar(100)                      NOT NULL DEFAULT '' COMMENT '群介绍',
     `is_dismiss`   tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否已解散[0:否;1:是;]',
     `avatar`       varchar(255)                      NOT NULL DEFAULT '' COMMENT '群头像',
-    `max_num`      smallint(5) unsigned NOT NULL DEFAULT '200' COMMENT '最大群成员数量',
+    `max_num`      int(11) unsigned NOT NULL DEFAULT '200' COMMENT '最大群成员数量',
     `is_overt`     tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否公开可见[0:否;1:是;]',
     `is_mute`      tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否全员禁言 [0:否;1:是;]，提示:不包含群主或管理员',
+    `mute_until`   datetime DEFAULT NULL COMMENT '全员禁言截止时间，为空时永久禁言',
+    `is_super`     tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否超级群[0:否;1:是;]',
+    `is_broadcast` tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否仅群主及管理员可发言[0:否;1:是;]',
+    `join_mode`    tinyint(4) unsigned NOT NULL DEFAULT '2' COMMENT '入群方式[1:直接加入;2:需要审核;3:仅限邀请;4:回答问题;]',
+    `join_question` varchar(100) CHARACTER SET utf8mb4 NOT NULL DEFAULT '' COMMENT '入群问题',
+    `join_answer`  varchar(100) CHARACTER SET utf8mb4 NOT NULL DEFAULT '' COMMENT '入群问题答案，为空时由管理员审核',
+    `is_share_history` tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '新成员是否可查看入群前的历史消息[0:否;1:是;]',
     `created_at`   datetime                          NOT NULL COMMENT '创建时间',
     `updated_at`   datetime                          NOT NULL COMMENT '更新时间',
     `dismissed_at` datetime                                   DEFAULT NULL COMMENT '解散时间',
@@ -147,10 +159,15 @@ CREATE TABLE `group_apply`
     `group_id`   int(11) unsigned NOT NULL DEFAULT '0' COMMENT '群组ID',
     `user_id`    int(11) unsigned NOT NULL DEFAULT '0' COMMENT '用户ID',
     `remark`     varchar(255) CHARACTER SET utf8mb4 NOT NULL DEFAULT '' COMMENT '备注信息',
+    `answer`     varchar(255) CHARACTER SET utf8mb4 NOT NULL DEFAULT '' COMMENT '入群问题回答',
+    `status`     tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '申请状态[0:待审核;1:已通过;2:已拒绝;]',
+    `reviewer_id` int(11) unsigned NOT NULL DEFAULT '0' COMMENT '审核人ID，自动通过时为 0',
+    `reason`     varchar(255) CHARACTER SET utf8mb4 NOT NULL DEFAULT '' COMMENT '拒绝原因',
     `created_at` datetime                           NOT NULL COMMENT '创建时间',
     `updated_at` datetime                           NOT NULL COMMENT '更新时间',
     PRIMARY KEY (`id`),
-    KEY          `idx_group_id_user_id` (`group_id`,`user_id`) USING BTREE
+    KEY          `idx_group_id_user_id` (`group_id`,`user_id`) USING BTREE,
+    KEY          `idx_group_id_status` (`group_id`,`status`) USING BTREE
 ) ENGINE=InnoDB AUTO_INCREMENT=2439 DEFAULT CHARSET=utf8 COMMENT='群聊成员';;
 
 CREATE TABLE `group_member`
@@ -159,15 +176,20 @@ CREATE TABLE `group_member`
     `group_id`   int(11) unsigned NOT NULL DEFAULT '0' COMMENT '群组ID',
     `user_id`    int(11) unsigned NOT NULL DEFAULT '0' COMMENT '用户ID',
     `leader`     tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '成员属性[0:普通成员;1:管理员;2:群主;]',
+    `role_id`    int(11) unsigned NOT NULL DEFAULT '0' COMMENT '自定义角色ID',
     `user_card`  varchar(20) CHARACTER SET utf8mb4 NOT NULL DEFAULT '' COMMENT '群名片',
     `is_quit`    tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否退群[0:否;1:是;]',
     `is_mute`    tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否禁言[0:否;1:是;]',
+    `mute_until` datetime DEFAULT NULL COMMENT '禁言截止时间，为空时永久禁言',
+    `min_record_id` int(11) unsigned NOT NULL DEFAULT '0' COMMENT '可查看历史记录最小ID',
+    `join_time`  datetime                                   DEFAULT NULL COMMENT '入群时间',
     `created_at` datetime                          NOT NULL COMMENT '创建时间',
     `updated_at` datetime                          NOT NULL COMMENT '更新时间',
     `deleted_at` datetime                                   DEFAULT NULL COMMENT '删除时间',
     PRIMARY KEY (`id`),
     UNIQUE KEY `uk_group_id_user_id` (`group_id`,`user_id`) USING BTREE,
-    KEY          `idx_user_id` (`user_id`) USING BTREE
+    KEY          `idx_user_id` (`user_id`) USING BTREE,
+    KEY          `idx_mute_until` (`mute_until`) USING BTREE
 ) ENGINE=InnoDB AUTO_INCREMENT=2909 DEFAULT CHARSET=utf8 COMMENT='群聊成员';;
 
 CREATE TABLE `group_notice`
@@ -194,6 +216,7 @@ CREATE TABLE `organize`
     `user_id`    int(11) unsigned NOT NULL DEFAULT '0' COMMENT '用户id',
     `department` varchar(100) NOT NULL DEFAULT '' COMMENT '部门ID',
     `position`   varchar(100) NOT NULL DEFAULT '' COMMENT '岗位ID',
+    `is_force_totp` tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT '是否强制开启两步验证[0:否;1:是;]',
     `created_at` datetime     NOT NULL COMMENT '创建时间',
     `updated_at` datetime     NOT NULL COMMENT '更新时间',
     PRIMARY KEY (`id`),
@@ -252,7 +275,7 @@ CREATE TABLE `split_upload`
 (
     `id`            int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '临时文件ID',
     `type`          tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '文件属性[1:合并文件;2:拆分文件]',
-    `drive`         tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '驱动类型[1:local;2:cos;]',
+    `drive`         tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '驱动类型[1:local;2:cos;3:s3;4:oss;5:qiniu;]',
     `upload_id`     varchar(100) NOT NULL DEFAULT '' COMMENT '临时文件hash名',
     `user_id`       int(11) unsigned NOT NULL DEFAULT '0' COMMENT '上传的用户ID',
     `original_name` varchar(100) NOT NULL DEFAULT '' COMMENT '原文件名',
@@ -262,11 +285,13 @@ CREATE TABLE `split_upload`
     `file_ext`      varchar(10)  NOT NULL DEFAULT '' COMMENT '文件后缀名',
     `file_size`     int(11) unsigned NOT NULL COMMENT '文件大小',
     `is_delete`     tinyint(2) unsigned NOT NULL DEFAULT '0' COMMENT '文件是否删除[0:否;1:是;] ',
+    `hash`          char(64)     NOT NULL DEFAULT '' COMMENT '合并文件内容 SHA-256',
     `attr`          json         NOT NULL COMMENT '额外参数json',
     `created_at`    datetime     NOT NULL COMMENT '更新时间',
     `updated_at`    datetime     NOT NULL COMMENT '创建时间',
     PRIMARY KEY (`id`),
-    KEY             `idx_user_id_hash_name` (`user_id`,`upload_id`) USING BTREE
+    KEY             `idx_user_id_hash_name` (`user_id`,`upload_id`) USING BTREE,
+    UNIQUE KEY      `uk_upload_id_type_split_index` (`upload_id`,`type`,`split_index`) USING BTREE
 ) ENGINE=InnoDB AUTO_INCREMENT=3509 DEFAULT CHARSET=utf8 COMMENT='文件拆分数据表';;
 
 CREATE TABLE `talk_records`
@@ -274,7 +299,7 @@ CREATE TABLE `talk_records`
     `id`          bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '聊天记录ID',
     `msg_id`      varchar(50)  NOT NULL DEFAULT '',
     `sequence`    int(10) unsigned NOT NULL DEFAULT '0',
-    `talk_type`   tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '对话类型[1:私信;2:群聊;]',
+    `talk_type`   tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '对话类型[1:私信;2:群聊;3:频道;]',
     `msg_type`    tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '消息类型[1:文本消息;2:文件消息;3:会话消息;4:代码消息;5:投票消息;6:群公告;7:好友申请;8:登录通知;9:入群消息/退群消息;]',
     `user_id`     int(11) unsigned NOT NULL DEFAULT '0' COMMENT '发送者ID（0:代表系统消息 >0: 用户ID）',
     `receiver_id` int(11) unsigned NOT NULL DEFAULT '0' COMMENT '接收者ID（用户ID 或 群ID）',
@@ -320,12 +345,19 @@ CREATE TABLE `talk_records_file`
     `user_id`       int(11) unsigned NOT NULL DEFAULT '0' COMMENT '上传文件的用户ID',
     `source`        tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '文件来源[1:用户上传;2:表情包;]',
     `type`          tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '文件类型[1:图片;2:音频文件;3:视频文件;4:其它文件;]',
-    `drive`         tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '驱动类型[1:local;2:cos;]',
+    `drive`         tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '驱动类型[1:local;2:cos;3:s3;4:oss;5:qiniu;]',
     `original_name` varchar(100) NOT NULL DEFAULT '' COMMENT '原文件名',
     `suffix`        varchar(10)  NOT NULL DEFAULT '' COMMENT '文件后缀',
     `size`          bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '文件大小',
     `path`          varchar(300) NOT NULL DEFAULT '' COMMENT '文件地址(相对地址)',
     `url`           varchar(255) NOT NULL DEFAULT '' COMMENT '网络地址(公开文件地址)',
+    `duration`      int(11) unsigned NOT NULL DEFAULT '0' COMMENT '媒体时长(秒)',
+    `width`         int(11) unsigned NOT NULL DEFAULT '0' COMMENT '媒体宽度(像素)',
+    `height`        int(11) unsigned NOT NULL DEFAULT '0' COMMENT '媒体高度(像素)',
+    `codec`         varchar(30)  NOT NULL DEFAULT '' COMMENT '媒体编码格式',
+    `thumbnail`     varchar(300) NOT NULL DEFAULT '' COMMENT '缩略图地址(相对地址)',
+    `renditions`    varchar(1000) NOT NULL DEFAULT '' COMMENT '多尺寸规格图信息(JSON)',
+    `blob_id`       int(11) unsigned NOT NULL DEFAULT '0' COMMENT '文件存储ID',
     `created_at`    datetime     NOT NULL COMMENT '创建时间',
     PRIMARY KEY (`id`),
     UNIQUE KEY `idx_record_id` (`record_id`) USING BTREE
@@ -347,7 +379,7 @@ CREATE TABLE `talk_records_invite`
 (
     `id`              int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '入群或退群通知ID',
     `record_id`       bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '消息记录ID',
-    `type`            tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '通知类型 （1:入群通知 2:自动退群 3:管理员踢群）',
+    `type`            tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '通知类型 （1:入群通知 2:自动退群 3:管理员踢群 4:创建群聊 5:转让群主 6:解散群聊）',
     `operate_user_id` int(11) unsigned NOT NULL DEFAULT '0' COMMENT '操作人的用户ID（邀请人OR管理员ID）',
     `user_ids`        varchar(255) NOT NULL COMMENT '用户ID，多个用 , 分割',
     PRIMARY KEY (`id`),
@@ -415,7 +447,7 @@ CREATE TABLE `talk_records_vote_answer`
 CREATE TABLE `talk_session`
 (
     `id`          int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '聊天列表ID',
-    `talk_type`   tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '聊天类型[1:私信;2:群聊;]',
+    `talk_type`   tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '聊天类型[1:私信;2:群聊;3:频道;]',
     `user_id`     int(11) NOT NULL DEFAULT '0' COMMENT '用户ID',
     `receiver_id` int(11) unsigned NOT NULL DEFAULT '0' COMMENT '接收者ID（用户ID 或 群ID）',
     `is_top`      tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否置顶[0:否;1:是;]',
@@ -431,7 +463,7 @@ CREATE TABLE `talk_session`
 CREATE TABLE `users`
 (
     `id`         int(10) unsigned NOT NULL AUTO_INCREMENT COMMENT '用户ID',
-    `mobile`     varchar(11)                       NOT NULL DEFAULT '' COMMENT '手机号',
+    `mobile`     varchar(11)                                DEFAULT NULL COMMENT '手机号(单点登录创建的账号可为空)',
     `nickname`   varchar(20) CHARACTER SET utf8mb4 NOT NULL DEFAULT '' COMMENT '用户昵称',
     `avatar`     varchar(255)                      NOT NULL DEFAULT '' COMMENT '用户头像地址',
     `gender`     tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT '用户性别[0:未知;1:男 ;2:女;]',
@@ -439,6 +471,7 @@ CREATE TABLE `users`
     `motto`      varchar(100)                      NOT NULL DEFAULT '' COMMENT '用户座右铭',
     `email`      varchar(30)                       NOT NULL DEFAULT '' COMMENT '用户邮箱',
     `is_robot`   tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否机器人[0:否;1:是;]',
+    `status`     tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '账号状态[0:正常;1:已禁用;]',
     `created_at` datetime                          NOT NULL COMMENT '注册时间',
     `updated_at` datetime                          NOT NULL COMMENT '更新时间',
     PRIMARY KEY (`id`) USING BTREE,
@@ -468,6 +501,266 @@ CREATE TABLE `contact_group`
     UNIQUE KEY `idx_user_id_name` (`user_id`,`name`) USING BTREE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='联系人分组';;
 
+CREATE TABLE `file_blob`
+(
+    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '文件ID',
+    `drive`      tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '驱动类型[1:local;2:cos;3:s3;4:oss;5:qiniu;]',
+    `hash`       char(64)     NOT NULL DEFAULT '' COMMENT '文件内容 SHA-256',
+    `is_public`  tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT '是否公开文件[0:否;1:是;]',
+    `path`       varchar(300) NOT NULL DEFAULT '' COMMENT '文件地址(相对地址)',
+    `size`       bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '文件大小',
+    `ref_count`  int(11) NOT NULL DEFAULT '0' COMMENT '引用次数',
+    `derived`    varchar(1000) NOT NULL DEFAULT '' COMMENT '衍生文件地址(JSON)',
+    `created_at` datetime     NOT NULL COMMENT '创建时间',
+    `updated_at` datetime     NOT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_drive_hash_public` (`drive`,`hash`,`is_public`) USING BTREE,
+    KEY `idx_ref_count_updated_at` (`ref_count`,`updated_at`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='文件存储(按内容去重)';;
+
+CREATE TABLE `user_session`
+(
+    `id`                 int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '会话ID',
+    `user_id`            int(11) unsigned NOT NULL DEFAULT '0' COMMENT '用户ID',
+    `session_id`         varchar(64)  NOT NULL DEFAULT '' COMMENT '设备会话标识',
+    `platform`           varchar(32)  NOT NULL DEFAULT '' COMMENT '登录平台',
+    `ip`                 varchar(64)  NOT NULL DEFAULT '' COMMENT '登录IP',
+    `address`            varchar(100) NOT NULL DEFAULT '' COMMENT '登录地址',
+    `agent`              varchar(300) NOT NULL DEFAULT '' COMMENT '设备信息',
+    `refresh_token`      char(64)     NOT NULL DEFAULT '' COMMENT '当前刷新令牌 SHA-256',
+    `refresh_expires_at` datetime     NOT NULL COMMENT '刷新令牌过期时间',
+    `is_revoked`         tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT '是否已注销[0:否;1:是;]',
+    `last_active_at`     datetime     NOT NULL COMMENT '最后活跃时间',
+    `created_at`         datetime     NOT NULL COMMENT '创建时间',
+    `updated_at`         datetime     NOT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_session_id` (`session_id`) USING BTREE,
+    KEY `idx_user_id_is_revoked` (`user_id`,`is_revoked`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户登录设备会话';;
+
+CREATE TABLE `user_totp`
+(
+    `id`             int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `user_id`        int(11) unsigned NOT NULL DEFAULT '0' COMMENT '用户ID',
+    `secret`         varchar(64)   NOT NULL DEFAULT '' COMMENT 'TOTP 秘钥(Base32)',
+    `is_enabled`     tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT '是否已启用[0:否;1:是;]',
+    `recovery_codes` varchar(1000) NOT NULL DEFAULT '' COMMENT '恢复码 SHA-256 列表(JSON)',
+    `last_counter`   bigint(20) NOT NULL DEFAULT '0' COMMENT '最后使用的时间步，防止验证码重放',
+    `enabled_at`     datetime DEFAULT NULL COMMENT '启用时间',
+    `created_at`     datetime      NOT NULL COMMENT '创建时间',
+    `updated_at`     datetime      NOT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_user_id` (`user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户两步验证';;
+
+CREATE TABLE `user_identity`
+(
+    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `user_id`    int(11) unsigned NOT NULL DEFAULT '0' COMMENT '用户ID',
+    `provider`   varchar(191) NOT NULL DEFAULT '' COMMENT '身份提供方(issuer)',
+    `subject`    varchar(191) NOT NULL DEFAULT '' COMMENT '身份提供方的用户标识(sub)',
+    `email`      varchar(255) NOT NULL DEFAULT '' COMMENT '身份提供方的邮箱',
+    `created_at` datetime     NOT NULL COMMENT '创建时间',
+    `updated_at` datetime     NOT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_provider_subject` (`provider`, `subject`) USING BTREE,
+    KEY          `idx_user_id` (`user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户第三方身份关联';;
+
+CREATE TABLE `admin`
+(
+    `id`            int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '管理员ID',
+    `username`      varchar(32)  NOT NULL DEFAULT '' COMMENT '登录账号',
+    `password`      varchar(255) NOT NULL DEFAULT '' COMMENT '登录密码',
+    `nickname`      varchar(32)  NOT NULL DEFAULT '' COMMENT '昵称',
+    `role`          varchar(16)  NOT NULL DEFAULT '' COMMENT '角色[super:超级管理员;operator:运营;auditor:审计;]',
+    `status`        tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '状态[0:正常;1:已禁用;]',
+    `last_login_ip` varchar(64)  NOT NULL DEFAULT '' COMMENT '最后登录IP',
+    `last_login_at` datetime     NOT NULL COMMENT '最后登录时间',
+    `created_at`    datetime     NOT NULL COMMENT '创建时间',
+    `updated_at`    datetime     NOT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_username` (`username`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='管理员表';;
+
+CREATE TABLE `admin_audit_log`
+(
+    `id`          int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `admin_id`    int(11) unsigned NOT NULL DEFAULT '0' COMMENT '管理员ID',
+    `action`      varchar(32)  NOT NULL DEFAULT '' COMMENT '操作类型',
+    `target_type` varchar(32)  NOT NULL DEFAULT '' COMMENT '操作对象类型',
+    `target_id`   int(11) unsigned NOT NULL DEFAULT '0' COMMENT '操作对象ID',
+    `detail`      text         NOT NULL COMMENT '操作详情(JSON)',
+    `ip`          varchar(64)  NOT NULL DEFAULT '' COMMENT '操作IP',
+    `agent`       varchar(300) NOT NULL DEFAULT '' COMMENT '设备信息',
+    `created_at`  datetime     NOT NULL COMMENT '操作时间',
+    PRIMARY KEY (`id`),
+    KEY           `idx_admin_id` (`admin_id`) USING BTREE,
+    KEY           `idx_action` (`action`) USING BTREE,
+    KEY           `idx_created_at` (`created_at`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='管理后台审计日志';;
+
+CREATE TABLE `group_role`
+(
+    `id`          int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '角色ID',
+    `group_id`    int(11) unsigned NOT NULL DEFAULT '0' COMMENT '群组ID',
+    `name`        varchar(20) CHARACTER SET utf8mb4 NOT NULL DEFAULT '' COMMENT '角色名称',
+    `permissions` varchar(255) NOT NULL DEFAULT '' COMMENT '权限列表(逗号分隔)',
+    `created_at`  datetime     NOT NULL COMMENT '创建时间',
+    `updated_at`  datetime     NOT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    KEY           `idx_group_id` (`group_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='群组自定义角色';;
+
+CREATE TABLE `moderation_word`
+(
+    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `word`       varchar(64) CHARACTER SET utf8mb4 NOT NULL DEFAULT '' COMMENT '敏感词',
+    `action`     varchar(10) NOT NULL DEFAULT '' COMMENT '处理动作[replace:替换;flag:人工审核;block:拦截;]',
+    `created_at` datetime    NOT NULL COMMENT '创建时间',
+    `updated_at` datetime    NOT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_word` (`word`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='消息审核敏感词';;
+
+CREATE TABLE `moderation_link`
+(
+    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `domain`     varchar(128) NOT NULL DEFAULT '' COMMENT '域名(包含子域名)',
+    `type`       tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT '名单类型[1:白名单;2:黑名单;]',
+    `created_at` datetime     NOT NULL COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_domain_type` (`domain`,`type`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='消息审核链接名单';;
+
+CREATE TABLE `moderation_review`
+(
+    `id`          int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `record_id`   int(11) unsigned NOT NULL DEFAULT '0' COMMENT '消息记录ID',
+    `user_id`     int(11) unsigned NOT NULL DEFAULT '0' COMMENT '发送者ID',
+    `talk_type`   tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT '对话类型[1:私信;2:群聊;]',
+    `receiver_id` int(11) unsigned NOT NULL DEFAULT '0' COMMENT '接收者ID(用户ID或群ID)',
+    `content`     text CHARACTER SET utf8mb4 NOT NULL COMMENT '消息内容',
+    `hook`        varchar(20)  NOT NULL DEFAULT '' COMMENT '命中的审核项',
+    `reason`      varchar(500) CHARACTER SET utf8mb4 NOT NULL DEFAULT '' COMMENT '命中原因',
+    `status`      tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT '审核状态[0:待审核;1:通过;2:驳回;]',
+    `admin_id`    int(11) unsigned NOT NULL DEFAULT '0' COMMENT '审核管理员ID',
+    `created_at`  datetime     NOT NULL COMMENT '创建时间',
+    `updated_at`  datetime     NOT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    KEY           `idx_status` (`status`) USING BTREE,
+    KEY           `idx_record_id` (`record_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='消息审核队列';;
+
+CREATE TABLE `user_privacy`
+(
+    `id`            int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `user_id`       int(11) unsigned NOT NULL DEFAULT '0' COMMENT '用户ID',
+    `add_contact`   tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT '谁可以添加我[0:所有人;1:仅企业成员;2:不允许;]',
+    `online_status` tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT '在线状态可见范围[0:联系人可见;1:不可见;]',
+    `mobile_search` tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT '通过手机号搜索[0:允许;1:不允许;]',
+    `created_at`    datetime NOT NULL COMMENT '创建时间',
+    `updated_at`    datetime NOT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_user_id` (`user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户隐私设置';;
+
+CREATE TABLE `user_block`
+(
+    `id`            int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `user_id`       int(11) unsigned NOT NULL DEFAULT '0' COMMENT '用户ID',
+    `block_user_id` int(11) unsigned NOT NULL DEFAULT '0' COMMENT '被拉黑的用户ID',
+    `created_at`    datetime NOT NULL COMMENT '拉黑时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_user_block` (`user_id`,`block_user_id`) USING BTREE,
+    KEY             `idx_block_user_id` (`block_user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户黑名单';;
+
+CREATE TABLE `group_invite_link`
+(
+    `id`            int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `group_id`      int(11) unsigned NOT NULL DEFAULT '0' COMMENT '群组ID',
+    `creator_id`    int(11) unsigned NOT NULL DEFAULT '0' COMMENT '创建人ID',
+    `code`          varchar(32) NOT NULL DEFAULT '' COMMENT '邀请码',
+    `max_uses`      int(11) unsigned NOT NULL DEFAULT '0' COMMENT '最大使用次数，0 为不限',
+    `used_num`      int(11) unsigned NOT NULL DEFAULT '0' COMMENT '已使用次数',
+    `need_approval` tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否需要审核[0:否;1:是;]',
+    `is_revoke`     tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否已作废[0:否;1:是;]',
+    `expired_at`    datetime DEFAULT NULL COMMENT '过期时间，为空时永久有效',
+    `created_at`    datetime NOT NULL COMMENT '创建时间',
+    `updated_at`    datetime NOT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_code` (`code`) USING BTREE,
+    KEY             `idx_group_id` (`group_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='群邀请链接';;
+
+CREATE TABLE `channel`
+(
+    `id`             int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '频道ID',
+    `creator_id`     int(11) unsigned NOT NULL DEFAULT '0' COMMENT '创建者ID',
+    `name`           varchar(64) NOT NULL DEFAULT '' COMMENT '频道名称',
+    `avatar`         varchar(255) NOT NULL DEFAULT '' COMMENT '频道头像',
+    `profile`        varchar(255) NOT NULL DEFAULT '' COMMENT '频道简介',
+    `is_overt`       tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否公开可见[0:否;1:是;]',
+    `subscriber_num` int(11) unsigned NOT NULL DEFAULT '0' COMMENT '订阅人数',
+    `is_dismiss`     tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否已解散[0:否;1:是;]',
+    `created_at`     datetime NOT NULL COMMENT '创建时间',
+    `updated_at`     datetime NOT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    KEY              `idx_creator_id` (`creator_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='频道表';;
+
+CREATE TABLE `channel_member`
+(
+    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `channel_id` int(11) unsigned NOT NULL DEFAULT '0' COMMENT '频道ID',
+    `user_id`    int(11) unsigned NOT NULL DEFAULT '0' COMMENT '用户ID',
+    `role`       tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '成员身份[0:订阅者;1:管理员;2:创建者;]',
+    `created_at` datetime NOT NULL COMMENT '订阅时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_channel_id_user_id` (`channel_id`,`user_id`) USING BTREE,
+    KEY          `idx_user_id` (`user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='频道订阅表';;
+
+CREATE TABLE `channel_reaction`
+(
+    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `channel_id` int(11) unsigned NOT NULL DEFAULT '0' COMMENT '频道ID',
+    `record_id`  int(11) unsigned NOT NULL DEFAULT '0' COMMENT '频道消息ID',
+    `user_id`    int(11) unsigned NOT NULL DEFAULT '0' COMMENT '用户ID',
+    `emoji`      varchar(16) NOT NULL DEFAULT '' COMMENT '互动表情',
+    `created_at` datetime NOT NULL COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_record_id_user_id_emoji` (`record_id`,`user_id`,`emoji`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='频道消息互动表';;
+
+CREATE TABLE `channel_view`
+(
+    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `channel_id` int(11) unsigned NOT NULL DEFAULT '0' COMMENT '频道ID',
+    `record_id`  int(11) unsigned NOT NULL DEFAULT '0' COMMENT '频道消息ID',
+    `view_num`   int(11) unsigned NOT NULL DEFAULT '0' COMMENT '浏览量',
+    `created_at` datetime NOT NULL COMMENT '创建时间',
+    `updated_at` datetime NOT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_record_id` (`record_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='频道消息浏览量表';;
+
+CREATE TABLE `talk_records_notice`
+(
+    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `record_id`  int(11) unsigned NOT NULL DEFAULT '0' COMMENT '消息记录ID',
+    `notice_id`  int(11) unsigned NOT NULL DEFAULT '0' COMMENT '群公告ID',
+    `title`      varchar(50)  NOT NULL DEFAULT '' COMMENT '公告标题',
+    `content`    text         NOT NULL COMMENT '公告内容',
+    `is_confirm` tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否需群成员确认公告[0:否;1:是;]',
+    `is_update`  tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '是否为更新公告[0:否;1:是;]',
+    `created_at` datetime     NOT NULL COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_record_id` (`record_id`) USING BTREE,
+    KEY          `idx_notice_id` (`notice_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='聊天对话记录（群公告）';;
+
 INSERT INTO `users`(`id`, `mobile`, `nickname`, `avatar`, `gender`, `password`, `motto`, `email`, `is_robot`,
                     `created_at`, `updated_at`)
 VALUES (1, '10046798935', '登录助手', '', 0, '$2y$10$4XW5vq07jVoRUJUfGHYDUeHWcPjFDlC7bVwHe9wplv5Ors2dZilau', '', '', 1,
@@ -29,10 +29,15 @@ type ICrontab interface {
 
 // Subcommands 注册的任务请务必实现 ICrontab 接口
 type Subcommands struct {
-	ClearWsCache      *crontab.ClearWsCache
-	ClearArticle      *crontab.ClearArticle
-	ClearTmpFile      *crontab.ClearTmpFile
-	ClearExpireServer *crontab.ClearExpireServer
+	ClearWsCache             *crontab.ClearWsCache
+	ClearArticle             *crontab.ClearArticle
+	ClearTmpFile             *crontab.ClearTmpFile
+	ClearExpireServer        *crontab.ClearExpireServer
+	ClearFileBlob            *crontab.ClearFileBlob
+	RotateJwtKey             *crontab.RotateJwtKey
+	ClearExpiredMute         *crontab.ClearExpiredMute
+	RemindGroupNotice        *crontab.RemindGroupNotice
+	ClearExpiredContactApply *crontab.ClearExpiredContactApply
 }
 
 func NewCrontabCommand(handles *Subcommands) Command {
@@ -42,35 +47,45 @@ func NewCrontabCommand(handles *Subcommands) Command {
 		Action: func(ctx *cli.Context) error {
 			c := cron.New()
 
-			for _, job := range toCrontab(handles) {
+			if err := register(c, ctx.Context, toCrontab(handles)); err != nil {
+				panic(err)
+			}
 
-				// 是否启动运行
-				if !job.Enable() {
-					continue
-				}
+			log.Println("Crontab 定时任务已启动...")
 
-				_, err := c.AddFunc(job.Spec(), func() {
-					defer func() {
-						if err := recover(); err != nil {
-							fmt.Printf("Crontab Err: %v \n", err)
-						}
-					}()
+			return run(c, ctx.Context)
+		},
+	}
+}
 
-					_ = job.Handle(ctx.Context)
-				})
+// register 注册已启用的定时任务
+func register(c *cron.Cron, ctx context.Context, jobs []ICrontab) error {
+	for _, job := range jobs {
+		job := job
 
-				fmt.Printf("已启动 %T 定时任务 => 任务计划 %s \n", job, job.Spec())
+		// 是否启动运行
+		if !job.Enable() {
+			continue
+		}
 
-				if err != nil {
-					panic(err)
+		_, err := c.AddFunc(job.Spec(), func() {
+			defer func() {
+				if err := recover(); err != nil {
+					fmt.Printf("Crontab Err: %v \n", err)
 				}
-			}
+			}()
 
-			log.Println("Crontab 定时任务已启动...")
+			_ = job.Handle(ctx)
+		})
 
-			return run(c, ctx.Context)
-		},
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("已启动 %T 定时任务 => 任务计划 %s \n", job, job.Spec())
 	}
+
+	return nil
 }
 
 func run(cron *cron.Cron, ctx context.Context) error {
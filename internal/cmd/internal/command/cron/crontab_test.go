@@ -0,0 +1,46 @@
+package cron
+
+import (
+	"context"
+	"testing"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCrontab struct {
+	enable bool
+	calls  int
+}
+
+func (t *testCrontab) Spec() string {
+	return "* * * * *"
+}
+
+func (t *testCrontab) Enable() bool {
+	return t.enable
+}
+
+func (t *testCrontab) Handle(_ context.Context) error {
+	t.calls++
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	jobs := []*testCrontab{{enable: true}, {enable: false}, {enable: true}}
+
+	c := cron.New()
+	assert.NoError(t, register(c, context.Background(), []ICrontab{jobs[0], jobs[1], jobs[2]}))
+
+	entries := c.Entries()
+	assert.Len(t, entries, 2)
+
+	// 每个任务只能触发自身的 Handle
+	for _, entry := range entries {
+		entry.Job.Run()
+	}
+
+	assert.Equal(t, 1, jobs[0].calls)
+	assert.Equal(t, 0, jobs[1].calls)
+	assert.Equal(t, 1, jobs[2].calls)
+}
@@ -0,0 +1,22 @@
+package other
+
+import (
+	"github.com/urfave/cli/v2"
+	"go-chat/internal/cmd/internal/handle/other"
+)
+
+type AdminCommand *cli.Command
+
+func NewAdminCommand(job *other.AdminHandle) AdminCommand {
+	return &cli.Command{
+		Name:  "admin",
+		Usage: "创建超级管理员",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "username", Usage: "管理员账号", Value: "admin"},
+			&cli.StringFlag{Name: "password", Usage: "管理员密码(不少于8位)", Required: true},
+		},
+		Action: func(tx *cli.Context) error {
+			return job.Handle(tx.Context, tx.String("username"), tx.String("password"))
+		},
+	}
+}
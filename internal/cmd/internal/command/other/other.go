@@ -11,6 +11,7 @@ type Command *cli.Command
 type Subcommands struct {
 	ExampleCommand ExampleCommand
 	MigrateCommand MigrateCommand
+	AdminCommand   AdminCommand
 }
 
 func NewOtherCommand(subcommands *Subcommands) Command {
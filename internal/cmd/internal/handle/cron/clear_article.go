@@ -7,16 +7,18 @@ import (
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/filesystem"
 	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
 	"gorm.io/gorm"
 )
 
 type ClearArticle struct {
 	db         *gorm.DB
 	fileSystem *filesystem.Filesystem
+	blob       *repo.FileBlob
 }
 
-func NewClearArticle(db *gorm.DB, fileSystem *filesystem.Filesystem) *ClearArticle {
-	return &ClearArticle{db: db, fileSystem: fileSystem}
+func NewClearArticle(db *gorm.DB, fileSystem *filesystem.Filesystem, blob *repo.FileBlob) *ClearArticle {
+	return &ClearArticle{db: db, fileSystem: fileSystem, blob: blob}
 }
 
 // Spec 配置定时任务规则
@@ -52,13 +54,7 @@ func (c *ClearArticle) clearAnnex() {
 		}
 
 		for _, item := range items {
-			if item.Drive == entity.FileDriveLocal {
-				_ = c.fileSystem.Local.Delete(item.Path)
-			} else if item.Drive == entity.FileDriveCos {
-				_ = c.fileSystem.Cos.Delete(item.Path)
-			}
-
-			c.db.Delete(&model.ArticleAnnex{}, item.Id)
+			c.deleteAnnex(item)
 		}
 
 		if len(items) < size {
@@ -85,18 +81,12 @@ func (c *ClearArticle) clearNote() {
 		for _, item := range items {
 			subItems := make([]*model.ArticleAnnex, 0)
 
-			if err := c.db.Model(&model.ArticleAnnex{}).Select("drive", "path").Where("article_id = ?", item.Id).Scan(&subItems).Error; err != nil {
+			if err := c.db.Model(&model.ArticleAnnex{}).Select("id", "drive", "path", "blob_id").Where("article_id = ?", item.Id).Scan(&subItems).Error; err != nil {
 				continue
 			}
 
 			for _, subItem := range subItems {
-				if subItem.Drive == entity.FileDriveLocal {
-					_ = c.fileSystem.Local.Delete(subItem.Path)
-				} else if subItem.Drive == entity.FileDriveCos {
-					_ = c.fileSystem.Cos.Delete(subItem.Path)
-				}
-
-				c.db.Delete(&model.ArticleAnnex{}, subItem.Id)
+				c.deleteAnnex(subItem)
 			}
 
 			c.db.Delete(&model.Article{}, item.Id)
@@ -110,3 +100,24 @@ func (c *ClearArticle) clearNote() {
 		lastId = items[size-1].Id
 	}
 }
+
+// 删除附件，去重存储的附件仅释放引用，由 ClearFileBlob 回收文件
+func (c *ClearArticle) deleteAnnex(item *model.ArticleAnnex) {
+	if item.BlobId > 0 {
+		_ = c.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Delete(&model.ArticleAnnex{}, item.Id).Error; err != nil {
+				return err
+			}
+
+			return c.blob.Release(tx, item.BlobId)
+		})
+
+		return
+	}
+
+	if adapter, err := c.fileSystem.Drive(entity.FileDriveName(item.Drive)); err == nil {
+		_ = adapter.Delete(item.Path)
+	}
+
+	c.db.Delete(&model.ArticleAnnex{}, item.Id)
+}
@@ -0,0 +1,29 @@
+package cron
+
+import (
+	"context"
+
+	"go-chat/internal/service"
+)
+
+// ClearExpiredContactApply 将超过有效期的待处理好友申请标记为已过期
+type ClearExpiredContactApply struct {
+	apply *service.ContactApplyService
+}
+
+func NewClearExpiredContactApply(apply *service.ContactApplyService) *ClearExpiredContactApply {
+	return &ClearExpiredContactApply{apply: apply}
+}
+
+// Spec 配置定时任务规则
+func (c *ClearExpiredContactApply) Spec() string {
+	return "0 * * * *"
+}
+
+func (c *ClearExpiredContactApply) Enable() bool {
+	return true
+}
+
+func (c *ClearExpiredContactApply) Handle(ctx context.Context) error {
+	return c.apply.ClearExpired(ctx)
+}
@@ -0,0 +1,29 @@
+package cron
+
+import (
+	"context"
+
+	"go-chat/internal/service"
+)
+
+// ClearExpiredMute 解除已到期的群成员禁言及全员禁言
+type ClearExpiredMute struct {
+	mute *service.GroupMuteService
+}
+
+func NewClearExpiredMute(mute *service.GroupMuteService) *ClearExpiredMute {
+	return &ClearExpiredMute{mute: mute}
+}
+
+// Spec 配置定时任务规则
+func (c *ClearExpiredMute) Spec() string {
+	return "* * * * *"
+}
+
+func (c *ClearExpiredMute) Enable() bool {
+	return true
+}
+
+func (c *ClearExpiredMute) Handle(ctx context.Context) error {
+	return c.mute.ClearExpired(ctx)
+}
@@ -0,0 +1,104 @@
+package cron
+
+import (
+	"context"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/filesystem"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type ClearFileBlob struct {
+	db         *gorm.DB
+	fileSystem *filesystem.Filesystem
+}
+
+func NewClearFileBlob(db *gorm.DB, fileSystem *filesystem.Filesystem) *ClearFileBlob {
+	return &ClearFileBlob{db: db, fileSystem: fileSystem}
+}
+
+// Spec 配置定时任务规则
+// 每天凌晨2点执行
+func (c *ClearFileBlob) Spec() string {
+	return "0 2 * * *"
+}
+
+func (c *ClearFileBlob) Enable() bool {
+	return true
+}
+
+// Handle 回收无引用的去重存储文件
+// 文件引用次数归零且超过 24 小时未被使用时删除，删除前会核对实际引用记录，避免计数异常导致误删
+func (c *ClearFileBlob) Handle(ctx context.Context) error {
+
+	lastId, size := 0, 100
+
+	for {
+		items := make([]*model.FileBlob, 0)
+
+		err := c.db.Model(&model.FileBlob{}).Where("id > ? and ref_count <= 0 and updated_at <= ?", lastId, time.Now().Add(-24*time.Hour)).Order("id asc").Limit(size).Scan(&items).Error
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			c.clear(item)
+		}
+
+		if len(items) < size {
+			break
+		}
+
+		lastId = items[size-1].Id
+	}
+
+	return nil
+}
+
+func (c *ClearFileBlob) clear(item *model.FileBlob) {
+
+	// 核对引用记录，存在引用时修正引用次数
+	if count := c.references(item.Id); count > 0 {
+		c.db.Model(&model.FileBlob{}).Where("id = ?", item.Id).UpdateColumn("ref_count", count)
+		return
+	}
+
+	// 条件删除，避免与新的引用并发
+	res := c.db.Where("id = ? and ref_count <= 0 and updated_at = ?", item.Id, item.UpdatedAt).Delete(&model.FileBlob{})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return
+	}
+
+	paths := []string{item.Path}
+	if item.Derived != "" {
+		derived := make([]string, 0)
+		if err := jsonutil.Decode(item.Derived, &derived); err == nil {
+			paths = append(paths, derived...)
+		}
+	}
+
+	adapter, err := c.fileSystem.Drive(entity.FileDriveName(item.Drive))
+	if err != nil {
+		return
+	}
+
+	for _, filePath := range paths {
+		_ = adapter.Delete(filePath)
+	}
+}
+
+// references 统计文件的实际引用数
+func (c *ClearFileBlob) references(blobId int) int {
+	var total int64
+
+	for _, table := range []interface{}{&model.TalkRecordsFile{}, &model.ArticleAnnex{}, &model.EmoticonItem{}} {
+		var count int64
+		c.db.Model(table).Where("blob_id = ?", blobId).Count(&count)
+		total += count
+	}
+
+	return int(total)
+}
@@ -2,9 +2,9 @@ package cron
 
 import (
 	"context"
-	"path"
 	"time"
 
+	"go-chat/internal/entity"
 	"go-chat/internal/pkg/filesystem"
 	"go-chat/internal/repository/model"
 	"gorm.io/gorm"
@@ -36,29 +36,13 @@ func (c *ClearTmpFile) Handle(ctx context.Context) error {
 	for {
 		items := make([]*model.SplitUpload, 0)
 
-		err := c.db.Model(&model.SplitUpload{}).Where("id > ? and type = 1 and drive = 1 and created_at <= ?", lastId, time.Now().Add(-24*time.Hour)).Order("id asc").Limit(size).Scan(&items).Error
+		err := c.db.Model(&model.SplitUpload{}).Where("id > ? and type = 1 and created_at <= ?", lastId, time.Now().Add(-24*time.Hour)).Order("id asc").Limit(size).Scan(&items).Error
 		if err != nil {
 			return err
 		}
 
 		for _, item := range items {
-
-			list := make([]*model.SplitUpload, 0)
-			c.db.Table("split_upload").Where("user_id = ? and upload_id = ? and type = 2", item.UserId, item.UploadId).Scan(&list)
-
-			for _, value := range list {
-				if err := c.fileSystem.Local.Delete(value.Path); err == nil {
-					c.db.Delete(model.SplitUpload{}, value.Id)
-				}
-			}
-
-			if len(list) > 0 {
-				_ = c.fileSystem.Local.DeleteDir(path.Dir(list[0].Path))
-			}
-
-			if err := c.fileSystem.Local.Delete(item.Path); err == nil {
-				c.db.Delete(model.SplitUpload{}, item.Id)
-			}
+			c.clear(item)
 		}
 
 		if len(items) == size {
@@ -70,3 +54,24 @@ func (c *ClearTmpFile) Handle(ctx context.Context) error {
 
 	return nil
 }
+
+func (c *ClearTmpFile) clear(item *model.SplitUpload) {
+	adapter, err := c.fileSystem.Drive(entity.FileDriveName(item.Drive))
+	if err != nil {
+		return
+	}
+
+	// 未合并完成的上传任务需取消分片上传，清理已上传的分片
+	if item.Hash == "" {
+		_ = adapter.AbortMultipartUpload(item.Path, item.UploadId)
+	}
+
+	if err := adapter.Delete(item.Path); err != nil {
+		if _, statErr := adapter.Stat(item.Path); statErr == nil {
+			return
+		}
+	}
+
+	c.db.Where("upload_id = ? and type = 2", item.UploadId).Delete(&model.SplitUpload{})
+	c.db.Delete(&model.SplitUpload{}, item.Id)
+}
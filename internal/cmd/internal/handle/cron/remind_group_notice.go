@@ -0,0 +1,30 @@
+package cron
+
+import (
+	"context"
+
+	"go-chat/internal/service"
+)
+
+// RemindGroupNotice 提醒群成员确认群公告
+type RemindGroupNotice struct {
+	notice *service.GroupNoticeService
+}
+
+func NewRemindGroupNotice(notice *service.GroupNoticeService) *RemindGroupNotice {
+	return &RemindGroupNotice{notice: notice}
+}
+
+// Spec 配置定时任务规则
+// 每天上午10点执行
+func (c *RemindGroupNotice) Spec() string {
+	return "0 10 * * *"
+}
+
+func (c *RemindGroupNotice) Enable() bool {
+	return true
+}
+
+func (c *RemindGroupNotice) Handle(ctx context.Context) error {
+	return c.notice.Remind(ctx)
+}
@@ -0,0 +1,29 @@
+package cron
+
+import (
+	"context"
+
+	"go-chat/internal/service"
+)
+
+// RotateJwtKey 定时轮换 JWT 签名密钥，并清理超出宽限期的退役密钥
+type RotateJwtKey struct {
+	jwtKey *service.JwtKeyService
+}
+
+func NewRotateJwtKey(jwtKey *service.JwtKeyService) *RotateJwtKey {
+	return &RotateJwtKey{jwtKey: jwtKey}
+}
+
+// Spec 配置定时任务规则
+func (c *RotateJwtKey) Spec() string {
+	return "*/10 * * * *"
+}
+
+func (c *RotateJwtKey) Enable() bool {
+	return c.jwtKey.IsAsymmetric()
+}
+
+func (c *RotateJwtKey) Handle(ctx context.Context) error {
+	return c.jwtKey.Rotate(ctx, false)
+}
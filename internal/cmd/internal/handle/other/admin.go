@@ -0,0 +1,45 @@
+package other
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/service"
+)
+
+type AdminHandle struct {
+	admin *service.AdminService
+}
+
+func NewAdminHandle(admin *service.AdminService) *AdminHandle {
+	return &AdminHandle{admin: admin}
+}
+
+// Handle 创建超级管理员，初始化数据库后通过该命令设置管理员密码
+func (a *AdminHandle) Handle(ctx context.Context, username string, password string) error {
+
+	if username == "" {
+		return errors.New("管理员账号不能为空")
+	}
+
+	if utf8.RuneCountInString(password) < 8 {
+		return errors.New("管理员密码长度不能少于8位")
+	}
+
+	admin, err := a.admin.Create(ctx, &service.AdminCreateOpts{
+		Username: username,
+		Password: password,
+		Nickname: "超级管理员",
+		Role:     entity.AdminRoleSuper,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("管理员[%s]创建成功，ID: %d\n", admin.Username, admin.Id)
+
+	return nil
+}
@@ -15,9 +15,13 @@ import (
 	cron2 "go-chat/internal/cmd/internal/handle/cron"
 	other2 "go-chat/internal/cmd/internal/handle/other"
 	queue2 "go-chat/internal/cmd/internal/handle/queue"
+	"go-chat/internal/logic"
 	"go-chat/internal/pkg/filesystem"
 	"go-chat/internal/provider"
 	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/repository/repo/organize"
+	"go-chat/internal/service"
 )
 
 var providerSet = wire.NewSet(
@@ -32,6 +36,50 @@ var providerSet = wire.NewSet(
 
 	// cache
 	cache.NewSidStorage,
+	cache.NewRedisLock,
+	cache.NewJwtKeyStorage,
+	cache.NewRelation,
+	cache.NewUnreadStorage,
+	cache.NewMessageStorage,
+	cache.NewTalkVote,
+	cache.NewClientStorage,
+	cache.NewContactRemark,
+	cache.NewRateLimitStorage,
+	cache.NewSequence,
+
+	// repo
+	repo.NewGroup,
+	repo.NewGroupMember,
+	repo.NewGroupNotice,
+	repo.NewFileBlob,
+	repo.NewAdmin,
+	repo.NewAdminAuditLog,
+	repo.NewUserPrivacy,
+	repo.NewUserBlock,
+	repo.NewTalkRecordsVote,
+	repo.NewFileSplitUpload,
+	repo.NewContact,
+	repo.NewGroupRole,
+	repo.NewModerationWord,
+	repo.NewModerationLink,
+	repo.NewModerationReview,
+	repo.NewSequence,
+	organize.NewOrganize,
+
+	// 服务
+	service.NewBaseService,
+	service.NewJwtKeyService,
+	service.NewTalkMessageService,
+	service.NewFileBlobService,
+	service.NewMediaService,
+	service.NewAuthPermissionService,
+	service.NewModerationService,
+	service.NewMessageService,
+	logic.NewMessageForwardLogic,
+	service.NewGroupMuteService,
+	service.NewGroupNoticeService,
+	service.NewContactApplyService,
+	service.NewAdminService,
 
 	// Crontab 命令行
 	cron.NewCrontabCommand,
@@ -39,6 +87,11 @@ var providerSet = wire.NewSet(
 	cron2.NewClearArticle,
 	cron2.NewClearWsCache,
 	cron2.NewClearExpireServer,
+	cron2.NewClearFileBlob,
+	cron2.NewRotateJwtKey,
+	cron2.NewClearExpiredMute,
+	cron2.NewRemindGroupNotice,
+	cron2.NewClearExpiredContactApply,
 	wire.Struct(new(cron.Subcommands), "*"),
 
 	// Queue Command
@@ -50,8 +103,10 @@ var providerSet = wire.NewSet(
 	other.NewOtherCommand,
 	other.NewExampleCommand,
 	other.NewMigrateCommand,
+	other.NewAdminCommand,
 	wire.Struct(new(other.Subcommands), "*"),
 	other2.NewExampleHandle,
+	other2.NewAdminHandle,
 
 	// 服务
 	wire.Struct(new(command.Commands), "*"),
@@ -17,9 +17,13 @@ import (
 	"go-chat/internal/cmd/internal/handle/cron"
 	"go-chat/internal/cmd/internal/handle/other"
 	queue2 "go-chat/internal/cmd/internal/handle/queue"
+	"go-chat/internal/logic"
 	"go-chat/internal/pkg/filesystem"
 	"go-chat/internal/provider"
 	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/repository/repo/organize"
+	"go-chat/internal/service"
 )
 
 // Injectors from wire.go:
@@ -30,14 +34,62 @@ func Initialize(ctx context.Context, conf *config.Config) *AppProvider {
 	clearWsCache := cron.NewClearWsCache(serverStorage)
 	db := provider.NewMySQLClient(conf)
 	filesystemFilesystem := filesystem.NewFilesystem(conf)
-	clearArticle := cron.NewClearArticle(db, filesystemFilesystem)
+	fileBlob := repo.NewFileBlob(db)
+	clearArticle := cron.NewClearArticle(db, filesystemFilesystem, fileBlob)
 	clearTmpFile := cron.NewClearTmpFile(db, filesystemFilesystem)
 	clearExpireServer := cron.NewClearExpireServer(serverStorage)
+	clearFileBlob := cron.NewClearFileBlob(db, filesystemFilesystem)
+	jwtKeyStorage := cache.NewJwtKeyStorage(client)
+	redisLock := cache.NewRedisLock(client)
+	jwtKeyService := service.NewJwtKeyService(conf, jwtKeyStorage, redisLock)
+	rotateJwtKey := cron.NewRotateJwtKey(jwtKeyService)
+	baseService := service.NewBaseService(db, client)
+	group := repo.NewGroup(db)
+	relation := cache.NewRelation(client)
+	groupMember := repo.NewGroupMember(db, relation)
+	unreadStorage := cache.NewUnreadStorage(client)
+	messageStorage := cache.NewMessageStorage(client)
+	talkVote := cache.NewTalkVote(client)
+	talkRecordsVote := repo.NewTalkRecordsVote(db, talkVote)
+	clientStorage := cache.NewClientStorage(client, conf, serverStorage)
+	splitUpload := repo.NewFileSplitUpload(db)
+	fileBlobService := service.NewFileBlobService(baseService, fileBlob, filesystemFilesystem)
+	mediaService := service.NewMediaService(conf, filesystemFilesystem, fileBlobService)
+	contactRemark := cache.NewContactRemark(client)
+	contact := repo.NewContact(db, contactRemark, relation)
+	organizeOrganize := organize.NewOrganize(db)
+	groupRole := repo.NewGroupRole(db)
+	authPermissionService := service.NewAuthPermissionService(contact, groupMember, organizeOrganize, groupRole)
+	httpClient := provider.NewHttpClient()
+	rateLimitStorage := cache.NewRateLimitStorage(client)
+	moderationWord := repo.NewModerationWord(db)
+	moderationLink := repo.NewModerationLink(db)
+	moderationReview := repo.NewModerationReview(db)
+	moderationService := service.NewModerationService(baseService, conf, httpClient, rateLimitStorage, moderationWord, moderationLink, moderationReview)
+	talkMessageService := service.NewTalkMessageService(baseService, conf, unreadStorage, messageStorage, talkRecordsVote, groupMember, serverStorage, clientStorage, filesystemFilesystem, splitUpload, mediaService, fileBlobService, authPermissionService, moderationService)
+	groupMuteService := service.NewGroupMuteService(baseService, group, groupMember, talkMessageService)
+	clearExpiredMute := cron.NewClearExpiredMute(groupMuteService)
+	groupNotice := repo.NewGroupNotice(db)
+	sequence := cache.NewSequence(client)
+	repoSequence := repo.NewSequence(db, sequence)
+	messageForwardLogic := logic.NewMessageForwardLogic(db, repoSequence, fileBlob)
+	messageService := service.NewMessageService(baseService, messageForwardLogic, groupMember, splitUpload, filesystemFilesystem, mediaService, fileBlobService, unreadStorage, messageStorage, serverStorage, clientStorage, repoSequence, moderationService)
+	groupNoticeService := service.NewGroupNoticeService(baseService, groupNotice, groupMember, messageService)
+	remindGroupNotice := cron.NewRemindGroupNotice(groupNoticeService)
+	userPrivacy := repo.NewUserPrivacy(db)
+	userBlock := repo.NewUserBlock(db)
+	contactApplyService := service.NewContactApplyService(baseService, organizeOrganize, userPrivacy, userBlock)
+	clearExpiredContactApply := cron.NewClearExpiredContactApply(contactApplyService)
 	subcommands := &cron2.Subcommands{
-		ClearWsCache:      clearWsCache,
-		ClearArticle:      clearArticle,
-		ClearTmpFile:      clearTmpFile,
-		ClearExpireServer: clearExpireServer,
+		ClearWsCache:             clearWsCache,
+		ClearArticle:             clearArticle,
+		ClearTmpFile:             clearTmpFile,
+		ClearExpireServer:        clearExpireServer,
+		ClearFileBlob:            clearFileBlob,
+		RotateJwtKey:             rotateJwtKey,
+		ClearExpiredMute:         clearExpiredMute,
+		RemindGroupNotice:        remindGroupNotice,
+		ClearExpiredContactApply: clearExpiredContactApply,
 	}
 	cronCommand := cron2.NewCrontabCommand(subcommands)
 	queueSubcommands := &queue.Subcommands{}
@@ -45,9 +97,15 @@ func Initialize(ctx context.Context, conf *config.Config) *AppProvider {
 	exampleHandle := other.NewExampleHandle(db)
 	exampleCommand := other2.NewExampleCommand(exampleHandle)
 	migrateCommand := other2.NewMigrateCommand(db)
+	admin := repo.NewAdmin(db)
+	adminAuditLog := repo.NewAdminAuditLog(db)
+	adminService := service.NewAdminService(baseService, admin, adminAuditLog)
+	adminHandle := other.NewAdminHandle(adminService)
+	adminCommand := other2.NewAdminCommand(adminHandle)
 	otherSubcommands := &other2.Subcommands{
 		ExampleCommand: exampleCommand,
 		MigrateCommand: migrateCommand,
+		AdminCommand:   adminCommand,
 	}
 	otherCommand := other2.NewOtherCommand(otherSubcommands)
 	commands := &command.Commands{
@@ -64,4 +122,4 @@ func Initialize(ctx context.Context, conf *config.Config) *AppProvider {
 
 // wire.go:
 
-var providerSet = wire.NewSet(provider.NewMySQLClient, provider.NewRedisClient, provider.NewHttpClient, provider.NewEmailClient, provider.NewRequestClient, filesystem.NewFilesystem, cache.NewSidStorage, cron2.NewCrontabCommand, cron.NewClearTmpFile, cron.NewClearArticle, cron.NewClearWsCache, cron.NewClearExpireServer, wire.Struct(new(cron2.Subcommands), "*"), queue.NewQueueCommand, wire.Struct(new(queue.Subcommands), "*"), queue2.NewEmailHandle, other2.NewOtherCommand, other2.NewExampleCommand, other2.NewMigrateCommand, wire.Struct(new(other2.Subcommands), "*"), other.NewExampleHandle, wire.Struct(new(command.Commands), "*"), wire.Struct(new(AppProvider), "*"))
+var providerSet = wire.NewSet(provider.NewMySQLClient, provider.NewRedisClient, provider.NewHttpClient, provider.NewEmailClient, provider.NewRequestClient, filesystem.NewFilesystem, cache.NewSidStorage, cache.NewRedisLock, cache.NewJwtKeyStorage, cache.NewRelation, cache.NewUnreadStorage, cache.NewMessageStorage, cache.NewTalkVote, cache.NewClientStorage, cache.NewContactRemark, cache.NewRateLimitStorage, cache.NewSequence, repo.NewGroup, repo.NewGroupMember, repo.NewGroupNotice, repo.NewFileBlob, repo.NewAdmin, repo.NewAdminAuditLog, repo.NewUserPrivacy, repo.NewUserBlock, repo.NewTalkRecordsVote, repo.NewFileSplitUpload, repo.NewContact, repo.NewGroupRole, repo.NewModerationWord, repo.NewModerationLink, repo.NewModerationReview, repo.NewSequence, organize.NewOrganize, service.NewBaseService, service.NewJwtKeyService, service.NewTalkMessageService, service.NewFileBlobService, service.NewMediaService, service.NewAuthPermissionService, service.NewModerationService, service.NewMessageService, logic.NewMessageForwardLogic, service.NewGroupMuteService, service.NewGroupNoticeService, service.NewContactApplyService, service.NewAdminService, cron2.NewCrontabCommand, cron.NewClearTmpFile, cron.NewClearArticle, cron.NewClearWsCache, cron.NewClearExpireServer, cron.NewClearFileBlob, cron.NewRotateJwtKey, cron.NewClearExpiredMute, cron.NewRemindGroupNotice, cron.NewClearExpiredContactApply, wire.Struct(new(cron2.Subcommands), "*"), queue.NewQueueCommand, wire.Struct(new(queue.Subcommands), "*"), queue2.NewEmailHandle, other2.NewOtherCommand, other2.NewExampleCommand, other2.NewMigrateCommand, other2.NewAdminCommand, wire.Struct(new(other2.Subcommands), "*"), other.NewExampleHandle, other.NewAdminHandle, wire.Struct(new(command.Commands), "*"), wire.Struct(new(AppProvider), "*"))
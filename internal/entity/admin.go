@@ -0,0 +1,40 @@
+package entity
+
+// 管理员角色
+const (
+	AdminRoleSuper    = "super"    // 超级管理员，拥有全部权限
+	AdminRoleOperator = "operator" // 运营人员，可管理用户、群组、表情包及机器人
+	AdminRoleAuditor  = "auditor"  // 审计人员，仅可查看数据及审计日志
+)
+
+// 管理员状态
+const (
+	AdminStatusNormal   = 0 // 正常
+	AdminStatusDisabled = 1 // 已禁用
+)
+
+// 用户状态
+const (
+	UserStatusNormal   = 0 // 正常
+	UserStatusDisabled = 1 // 已禁用
+)
+
+// 管理后台审计日志操作类型
+const (
+	AdminActionLogin          = "auth.login"
+	AdminActionLogout         = "auth.logout"
+	AdminActionUserDisable    = "user.disable"
+	AdminActionUserEnable     = "user.enable"
+	AdminActionUserLogout     = "user.logout"
+	AdminActionGroupDismiss   = "group.dismiss"
+	AdminActionEmoticonStatus = "emoticon.status"
+	AdminActionRobotUpdate    = "robot.update"
+	AdminActionAdminCreate    = "admin.create"
+	AdminActionAdminUpdate    = "admin.update"
+	AdminActionOrganizeTotp   = "organize.force_totp"
+	AdminActionWordCreate     = "moderation.word_create"
+	AdminActionWordDelete     = "moderation.word_delete"
+	AdminActionLinkCreate     = "moderation.link_create"
+	AdminActionLinkDelete     = "moderation.link_delete"
+	AdminActionReviewHandle   = "moderation.review"
+)
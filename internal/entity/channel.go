@@ -0,0 +1,11 @@
+package entity
+
+// 频道成员身份
+const (
+	ChannelRoleSubscriber = 0 // 订阅者
+	ChannelRoleAdmin      = 1 // 管理员
+	ChannelRoleOwner      = 2 // 创建者
+)
+
+// ChannelReactionEmojis 频道内容允许使用的互动表情
+var ChannelReactionEmojis = []string{"👍", "❤️", "😄", "🎉", "😮", "😢"}
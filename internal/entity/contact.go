@@ -0,0 +1,19 @@
+package entity
+
+// 好友申请状态
+const (
+	ContactApplyStatusPending  = 0 // 待处理
+	ContactApplyStatusAccepted = 1 // 已同意
+	ContactApplyStatusDeclined = 2 // 已拒绝
+	ContactApplyStatusExpired  = 3 // 已过期
+)
+
+// 好友申请事件类型
+const (
+	ContactApplyEventCreate  = 1 // 收到好友申请，推送给被申请人
+	ContactApplyEventAccept  = 2 // 好友申请已同意，推送给申请人
+	ContactApplyEventDecline = 3 // 好友申请已拒绝，推送给申请人
+)
+
+// ContactApplyExpireDays 好友申请有效天数，超过后自动过期
+const ContactApplyExpireDays = 7
@@ -13,11 +13,11 @@ var fileMediaMap = map[string]int{
 	"jpeg": MediaFileImage,
 	"png":  MediaFileImage,
 	"webp": MediaFileImage,
-	"ogg":  MediaFileVideo,
+	"ogg":  MediaFileAudio,
 	"mp3":  MediaFileAudio,
-	"wav":  MediaFileVideo,
+	"wav":  MediaFileAudio,
 	"mp4":  MediaFileVideo,
-	"webm": MediaFileAudio,
+	"webm": MediaFileVideo,
 }
 
 func GetMediaType(ext string) int {
@@ -32,11 +32,17 @@ func GetMediaType(ext string) int {
 const (
 	FileDriveLocal = 1
 	FileDriveCos   = 2
+	FileDriveS3    = 3
+	FileDriveOss   = 4
+	FileDriveQiniu = 5
 )
 
 var fileSystemDriveMap = map[string]int{
 	"local": FileDriveLocal,
 	"cos":   FileDriveCos,
+	"s3":    FileDriveS3,
+	"oss":   FileDriveOss,
+	"qiniu": FileDriveQiniu,
 }
 
 func FileDriveMode(drive string) int {
@@ -46,3 +52,14 @@ func FileDriveMode(drive string) int {
 
 	return 0
 }
+
+// FileDriveName 根据存储类型获取驱动名称
+func FileDriveName(mode int) string {
+	for drive, val := range fileSystemDriveMap {
+		if val == mode {
+			return drive
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,144 @@
+package entity
+
+// 群成员身份
+const (
+	GroupLeaderMember = 0 // 普通成员
+	GroupLeaderAdmin  = 1 // 管理员
+	GroupLeaderOwner  = 2 // 群主
+)
+
+// 入群方式
+const (
+	GroupJoinOpen     = 1 // 允许任何人直接加入
+	GroupJoinApproval = 2 // 需要管理员审核
+	GroupJoinInvite   = 3 // 仅允许成员邀请
+	GroupJoinQuestion = 4 // 回答问题，答案正确时直接加入，未设置答案时由管理员审核
+)
+
+// 群成员变动消息类型，对应 talk_records_invite.type
+const (
+	GroupInviteTypeJoin              = 1  // 邀请入群
+	GroupInviteTypeQuit              = 2  // 主动退群
+	GroupInviteTypeKicked            = 3  // 管理员踢群
+	GroupInviteTypeCreate            = 4  // 创建群聊
+	GroupInviteTypeHandover          = 5  // 转让群主
+	GroupInviteTypeDismiss           = 6  // 解散群聊
+	GroupInviteTypeSelfJoin          = 7  // 主动加入群聊
+	GroupInviteTypeMuted             = 8  // 开启全员禁言
+	GroupInviteTypeCancelMuted       = 9  // 解除全员禁言
+	GroupInviteTypeMemberMuted       = 10 // 群成员禁言
+	GroupInviteTypeMemberCancelMuted = 11 // 群成员解除禁言
+)
+
+// GroupInviteSysTypes 群成员变动消息对应的系统消息类型
+var GroupInviteSysTypes = map[int]int{
+	GroupInviteTypeJoin:              ChatMsgSysGroupMemberJoin,
+	GroupInviteTypeQuit:              ChatMsgSysGroupMemberQuit,
+	GroupInviteTypeKicked:            ChatMsgSysGroupMemberKicked,
+	GroupInviteTypeCreate:            ChatMsgSysGroupCreate,
+	GroupInviteTypeHandover:          ChatMsgSysGroupHandover,
+	GroupInviteTypeDismiss:           ChatMsgSysGroupDismissed,
+	GroupInviteTypeSelfJoin:          ChatMsgSysGroupMemberJoin,
+	GroupInviteTypeMuted:             ChatMsgSysGroupMuted,
+	GroupInviteTypeCancelMuted:       ChatMsgSysGroupCancelMuted,
+	GroupInviteTypeMemberMuted:       ChatMsgSysGroupMemberMuted,
+	GroupInviteTypeMemberCancelMuted: ChatMsgSysGroupMemberCancelMuted,
+}
+
+// 入群申请状态
+const (
+	GroupApplyStatusPending  = 0 // 待审核
+	GroupApplyStatusApproved = 1 // 已通过
+	GroupApplyStatusRejected = 2 // 已拒绝
+)
+
+// 群组权限
+const (
+	GroupPermInvite  = "invite"  // 邀请成员
+	GroupPermRemove  = "remove"  // 移除成员
+	GroupPermMute    = "mute"    // 禁言成员
+	GroupPermPin     = "pin"     // 置顶群公告
+	GroupPermNotice  = "notice"  // 编辑群公告
+	GroupPermRevoke  = "revoke"  // 撤回他人消息
+	GroupPermWebhook = "webhook" // 管理群机器人 Webhook
+	GroupPermSetting = "setting" // 修改群信息
+	GroupPermApply   = "apply"   // 处理入群申请
+
+	// 以下权限仅群主拥有，不能授予自定义角色
+	GroupPermDismiss  = "dismiss"  // 解散群组
+	GroupPermHandover = "handover" // 转让群主
+	GroupPermRole     = "role"     // 管理角色及分配管理员
+)
+
+// GroupGrantablePerms 可授予自定义角色的权限
+var GroupGrantablePerms = []string{
+	GroupPermInvite,
+	GroupPermRemove,
+	GroupPermMute,
+	GroupPermPin,
+	GroupPermNotice,
+	GroupPermRevoke,
+	GroupPermWebhook,
+	GroupPermSetting,
+	GroupPermApply,
+}
+
+// GroupAdminPerms 管理员默认权限
+var GroupAdminPerms = []string{
+	GroupPermInvite,
+	GroupPermRemove,
+	GroupPermMute,
+	GroupPermPin,
+	GroupPermNotice,
+	GroupPermRevoke,
+	GroupPermSetting,
+	GroupPermApply,
+}
+
+// GroupMemberPerms 普通成员默认权限
+var GroupMemberPerms = []string{
+	GroupPermInvite,
+}
+
+// GroupOwnerPerms 群主权限
+var GroupOwnerPerms = append(append([]string{}, GroupGrantablePerms...), GroupPermDismiss, GroupPermHandover, GroupPermRole)
+
+// IsGroupGrantablePerm 判断权限是否可授予自定义角色
+func IsGroupGrantablePerm(perm string) bool {
+	return HasGroupPerm(GroupGrantablePerms, perm)
+}
+
+// HasGroupPerm 判断权限列表中是否包含指定权限
+func HasGroupPerm(perms []string, perm string) bool {
+	for _, value := range perms {
+		if value == perm {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GroupPermissions 计算群成员拥有的权限，群主拥有全部权限，
+// 管理员及普通成员在默认权限的基础上叠加自定义角色中可授予的权限
+func GroupPermissions(leader int, rolePerms []string) []string {
+
+	var perms []string
+	switch leader {
+	case GroupLeaderOwner:
+		return append([]string{}, GroupOwnerPerms...)
+	case GroupLeaderAdmin:
+		perms = append(perms, GroupAdminPerms...)
+	default:
+		perms = append(perms, GroupMemberPerms...)
+	}
+
+	for _, perm := range rolePerms {
+		// 仅叠加可授予的权限，避免越权
+		if IsGroupGrantablePerm(perm) && !HasGroupPerm(perms, perm) {
+			perms = append(perms, perm)
+		}
+	}
+
+	return perms
+}
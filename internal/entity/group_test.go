@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupPermissions(t *testing.T) {
+	tests := []struct {
+		name      string
+		leader    int
+		rolePerms []string
+		has       []string
+		missing   []string
+	}{
+		{
+			name:    "普通成员",
+			leader:  GroupLeaderMember,
+			has:     []string{GroupPermInvite},
+			missing: []string{GroupPermMute, GroupPermRemove, GroupPermSetting, GroupPermDismiss},
+		},
+		{
+			name:      "普通成员叠加自定义角色",
+			leader:    GroupLeaderMember,
+			rolePerms: []string{GroupPermMute, GroupPermWebhook},
+			has:       []string{GroupPermInvite, GroupPermMute, GroupPermWebhook},
+			missing:   []string{GroupPermRemove, GroupPermSetting},
+		},
+		{
+			name:      "自定义角色不能授予群主权限",
+			leader:    GroupLeaderMember,
+			rolePerms: []string{GroupPermDismiss, GroupPermHandover, GroupPermRole, "unknown"},
+			has:       []string{GroupPermInvite},
+			missing:   []string{GroupPermDismiss, GroupPermHandover, GroupPermRole, "unknown"},
+		},
+		{
+			name:    "管理员",
+			leader:  GroupLeaderAdmin,
+			has:     GroupAdminPerms,
+			missing: []string{GroupPermWebhook, GroupPermDismiss, GroupPermHandover, GroupPermRole},
+		},
+		{
+			name:      "管理员叠加自定义角色",
+			leader:    GroupLeaderAdmin,
+			rolePerms: []string{GroupPermWebhook, GroupPermMute},
+			has:       append([]string{GroupPermWebhook}, GroupAdminPerms...),
+			missing:   []string{GroupPermDismiss},
+		},
+		{
+			name:      "群主",
+			leader:    GroupLeaderOwner,
+			rolePerms: []string{GroupPermMute},
+			has:       GroupOwnerPerms,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perms := GroupPermissions(tt.leader, tt.rolePerms)
+
+			for _, perm := range tt.has {
+				assert.True(t, HasGroupPerm(perms, perm), perm)
+			}
+
+			for _, perm := range tt.missing {
+				assert.False(t, HasGroupPerm(perms, perm), perm)
+			}
+
+			// 权限不重复
+			seen := make(map[string]bool)
+			for _, perm := range perms {
+				assert.False(t, seen[perm], perm)
+				seen[perm] = true
+			}
+		})
+	}
+}
+
+func TestGroupPermissions_OwnerCopy(t *testing.T) {
+	perms := GroupPermissions(GroupLeaderOwner, nil)
+	perms[0] = "changed"
+
+	assert.NotEqual(t, "changed", GroupOwnerPerms[0])
+}
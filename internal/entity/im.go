@@ -5,6 +5,7 @@ const (
 	// ImChannelChat 默认分组
 	ImChannelChat    = "chat"    // im.Sessions.Chat.Name()
 	ImChannelExample = "example" // im.Sessions.Example.Name()
+	ImChannelLive    = "live"    // im.Sessions.Live.Name()
 )
 
 const (
@@ -15,4 +16,7 @@ const (
 	// ImTopicExample Example渠道消息订阅
 	ImTopicExample        = "im:message:example:all"
 	ImTopicExamplePrivate = "im:message:example:%s"
+
+	// ImTopicLive 直播聊天室渠道消息订阅
+	ImTopicLive = "im:message:live:all"
 )
@@ -0,0 +1,19 @@
+package entity
+
+// 直播聊天室消息事件
+const (
+	EventLiveMessage = "event_live_message" // 聊天室消息
+	EventLiveHistory = "event_live_history" // 进入聊天室时推送的最近消息
+	EventLiveDelete  = "event_live_delete"  // 聊天室消息删除通知
+	EventLiveJoin    = "event_live_join"    // 成员进入聊天室通知
+	EventLiveLeave   = "event_live_leave"   // 成员离开聊天室通知
+	EventLiveSetting = "event_live_setting" // 聊天室设置变更通知
+	EventLiveClose   = "event_live_close"   // 聊天室关闭通知
+	EventLiveError   = "event_live_error"   // 聊天室操作失败提示
+)
+
+const (
+	LiveGuestGuard    = "live-guest" // 游客令牌授权守卫
+	LiveHistoryMaxNum = 200          // 聊天室保留的历史消息数
+	LiveSlowModeMax   = 3600         // 慢速模式最大间隔(单位秒)
+)
@@ -0,0 +1,14 @@
+package entity
+
+// 内容审核链接名单类型
+const (
+	ModerationLinkAllow = 1 // 白名单
+	ModerationLinkDeny  = 2 // 黑名单
+)
+
+// 内容审核队列状态
+const (
+	ModerationReviewPending = 0 // 待审核
+	ModerationReviewPass    = 1 // 审核通过
+	ModerationReviewReject  = 2 // 审核驳回(撤回消息)
+)
@@ -0,0 +1,20 @@
+package entity
+
+// 隐私设置：谁可以添加我为联系人
+const (
+	PrivacyAddEveryone = 0 // 所有人
+	PrivacyAddOrganize = 1 // 仅企业成员
+	PrivacyAddNobody   = 2 // 不允许任何人添加
+)
+
+// 隐私设置：谁可以看到我的在线状态
+const (
+	PrivacyOnlineContact = 0 // 联系人可见
+	PrivacyOnlineNobody  = 1 // 所有人不可见
+)
+
+// 隐私设置：是否允许通过手机号搜索到我
+const (
+	PrivacyMobileSearchAllow = 0 // 允许
+	PrivacyMobileSearchDeny  = 1 // 不允许
+)
@@ -3,6 +3,7 @@ package entity
 type RoomType string
 
 const (
-	RoomImGroup RoomType = "room_chat_group" // 群聊房间
-	RoomExample RoomType = "room_example"    // 案例房间
+	RoomImGroup   RoomType = "room_chat_group"   // 群聊房间
+	RoomImChannel RoomType = "room_chat_channel" // 频道房间
+	RoomExample   RoomType = "room_example"      // 案例房间
 )
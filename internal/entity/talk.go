@@ -4,7 +4,7 @@ package entity
 const (
 	ChatPrivateMode = 1 // 私信模式
 	ChatGroupMode   = 2 // 群聊模式
-	ChatRoomMode    = 3 // 房间模式
+	ChatChannelMode = 3 // 频道模式
 )
 
 // WebSocket 消息事件枚举
@@ -16,6 +16,12 @@ const (
 	EventTalkRead      = "event_talk_read"       // 对话消息读事件
 	EventOnlineStatus  = "event_login"           // 用户在线状态通知
 	EventContactApply  = "event_contact_apply"   // 好友申请消息通知
+	EventSessionRevoke = "event_session_revoke"  // 设备会话注销通知
+	EventGroupApply    = "event_group_apply"     // 入群申请通知
+	EventGroupMute     = "event_group_mute"      // 群禁言状态变更通知
+	EventChannelJoin   = "event_channel_join"    // 订阅或退订频道通知
+
+	EventGroupNoticeRemind = "event_group_notice_remind" // 群公告确认提醒
 )
 
 // 聊天消息类型
@@ -67,6 +73,7 @@ const (
 	ChatMsgSysGroupCancelMuted       = 1108 // 群解除禁言
 	ChatMsgSysGroupMemberMuted       = 1109 // 群成员禁言
 	ChatMsgSysGroupMemberCancelMuted = 1110 // 群成员解除禁言
+	ChatMsgSysGroupHandover          = 1111 // 转让群主
 )
 
 const (
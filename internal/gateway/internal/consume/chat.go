@@ -43,7 +43,12 @@ func (s *ChatSubscribe) init() {
 	s.handlers[entity.EventTalkRevoke] = s.onConsumeTalkRevoke
 	s.handlers[entity.EventTalkJoinGroup] = s.onConsumeTalkJoinGroup
 	s.handlers[entity.EventContactApply] = s.onConsumeContactApply
+	s.handlers[entity.EventGroupApply] = s.onConsumeGroupApply
+	s.handlers[entity.EventGroupMute] = s.onConsumeGroupMute
+	s.handlers[entity.EventGroupNoticeRemind] = s.onConsumeGroupNoticeRemind
+	s.handlers[entity.EventChannelJoin] = s.onConsumeChannelJoin
 	s.handlers[entity.EventTalkRead] = s.onConsumeTalkRead
+	s.handlers[entity.EventSessionRevoke] = s.onConsumeSessionRevoke
 }
 
 // Call 触发回调事件
@@ -91,6 +96,15 @@ func (s *ChatSubscribe) onConsumeTalk(body string) {
 			Sid:      s.config.ServerId(),
 		})
 
+		cids = append(cids, ids...)
+	} else if msg.TalkType == entity.ChatChannelMode {
+		ids := s.roomStorage.All(ctx, &cache.RoomOption{
+			Channel:  im.Session.Chat.Name(),
+			RoomType: entity.RoomImChannel,
+			Number:   strconv.Itoa(int(msg.ReceiverID)),
+			Sid:      s.config.ServerId(),
+		})
+
 		cids = append(cids, ids...)
 	}
 
@@ -165,7 +179,7 @@ func (s *ChatSubscribe) onConsumeLogin(body string) {
 	ctx := context.Background()
 	cids := make([]int64, 0)
 
-	uids := s.contactService.GetContactIds(ctx, msg.UserID)
+	uids := s.contactService.GetOnlineNotifyIds(ctx, msg.UserID)
 	sid := s.config.ServerId()
 	for _, uid := range uids {
 		ids := s.clientStorage.GetUidFromClientIds(ctx, sid, im.Session.Chat.Name(), fmt.Sprintf("%d", uid))
@@ -188,13 +202,16 @@ func (s *ChatSubscribe) onConsumeLogin(body string) {
 }
 
 // onConsumeTalkRevoke 撤销聊天消息
+// 批量撤回时 record_ids 中的消息须属于同一会话
 func (s *ChatSubscribe) onConsumeTalkRevoke(body string) {
 	var (
 		msg struct {
-			RecordId int `json:"record_id"`
+			RecordId   int   `json:"record_id"`
+			RecordIds  []int `json:"record_ids"`
+			OperatorId int   `json:"operator_id"`
 		}
-		record *model.TalkRecords
-		ctx    = context.Background()
+		records = make([]*model.TalkRecords, 0)
+		ctx     = context.Background()
 	)
 
 	if err := jsonutil.Decode(body, &msg); err != nil {
@@ -202,10 +219,17 @@ func (s *ChatSubscribe) onConsumeTalkRevoke(body string) {
 		return
 	}
 
-	if err := s.recordsService.Db().First(&record, msg.RecordId).Error; err != nil {
+	ids := msg.RecordIds
+	if len(ids) == 0 {
+		ids = []int{msg.RecordId}
+	}
+
+	if err := s.recordsService.Db().Where("id in ?", ids).Find(&records).Error; err != nil || len(records) == 0 {
 		return
 	}
 
+	record := records[0]
+
 	cids := make([]int64, 0)
 	if record.TalkType == entity.ChatPrivateMode {
 		for _, uid := range [2]int{record.UserId, record.ReceiverId} {
@@ -225,19 +249,35 @@ func (s *ChatSubscribe) onConsumeTalkRevoke(body string) {
 		return
 	}
 
-	c := im.NewSenderContent()
-	c.SetReceive(cids...)
-	c.SetMessage(&im.Message{
-		Event: entity.EventTalkRevoke,
-		Content: entity.MapStrAny{
+	for _, record := range records {
+		// 兼容未携带操作人的历史事件
+		operatorId := msg.OperatorId
+		if operatorId == 0 {
+			operatorId = record.UserId
+		}
+
+		content := entity.MapStrAny{
 			"talk_type":   record.TalkType,
 			"sender_id":   record.UserId,
 			"receiver_id": record.ReceiverId,
 			"record_id":   record.Id,
-		},
-	})
+			"operator_id": operatorId,
+		}
 
-	im.Session.Chat.Write(c)
+		// 管理员撤回成员消息
+		if operatorId != record.UserId {
+			content["type"] = entity.ChatMsgSysGroupMessageRevoke
+		}
+
+		c := im.NewSenderContent()
+		c.SetReceive(cids...)
+		c.SetMessage(&im.Message{
+			Event:   entity.EventTalkRevoke,
+			Content: content,
+		})
+
+		im.Session.Chat.Write(c)
+	}
 }
 
 // nolint onConsumeContactApply 好友申请消息
@@ -260,7 +300,13 @@ func (s *ChatSubscribe) onConsumeContactApply(body string) {
 		return
 	}
 
-	cids := s.clientStorage.GetUidFromClientIds(ctx, s.config.ServerId(), im.Session.Chat.Name(), strconv.Itoa(apply.FriendId))
+	// 新申请推送给被申请人，同意或拒绝结果推送给申请人
+	receiver := apply.FriendId
+	if msg.Type == entity.ContactApplyEventAccept || msg.Type == entity.ContactApplyEventDecline {
+		receiver = apply.UserId
+	}
+
+	cids := s.clientStorage.GetUidFromClientIds(ctx, s.config.ServerId(), im.Session.Chat.Name(), strconv.Itoa(receiver))
 	if len(cids) == 0 {
 		return
 	}
@@ -271,15 +317,23 @@ func (s *ChatSubscribe) onConsumeContactApply(body string) {
 	}
 
 	data := entity.MapStrAny{}
+	data["type"] = msg.Type
+	data["apply_id"] = apply.Id
+	data["status"] = apply.Status
 	data["sender_id"] = apply.UserId
 	data["receiver_id"] = apply.FriendId
 	data["remark"] = apply.Remark
 	data["friend"] = entity.MapStrAny{
 		"nickname":   user.Nickname,
+		"avatar":     user.Avatar,
 		"remark":     apply.Remark,
 		"created_at": timeutil.FormatDatetime(apply.CreatedAt),
 	}
 
+	if msg.Type == entity.ContactApplyEventDecline {
+		data["reason"] = apply.Reason
+	}
+
 	c := im.NewSenderContent()
 	c.SetReceive(cids...)
 	c.SetMessage(&im.Message{
@@ -290,41 +344,226 @@ func (s *ChatSubscribe) onConsumeContactApply(body string) {
 	im.Session.Chat.Write(c)
 }
 
-// onConsumeTalkJoinGroup 加入群房间
-func (s *ChatSubscribe) onConsumeTalkJoinGroup(body string) {
+// onConsumeGroupApply 入群申请通知
+func (s *ChatSubscribe) onConsumeGroupApply(body string) {
 	var (
-		ctx  = context.Background()
-		sid  = s.config.ServerId()
-		data struct {
-			Gid  int   `json:"group_id"`
-			Type int   `json:"type"`
-			Uids []int `json:"uids"`
+		msg struct {
+			ApplyId   int    `json:"apply_id"`
+			GroupId   int    `json:"group_id"`
+			UserId    int    `json:"user_id"`
+			Status    int    `json:"status"`
+			Reason    string `json:"reason"`
+			Receivers []int  `json:"receivers"`
+		}
+		ctx = context.Background()
+	)
+
+	if err := jsonutil.Decode(body, &msg); err != nil {
+		logger.Error("[ChatSubscribe] onConsumeGroupApply Unmarshal err: ", err.Error())
+		return
+	}
+
+	cids := s.clientStorage.BatchGetUidFromClientIds(ctx, s.config.ServerId(), im.Session.Chat.Name(), msg.Receivers)
+	if len(cids) == 0 {
+		return
+	}
+
+	c := im.NewSenderContent()
+	c.SetReceive(cids...)
+	c.SetMessage(&im.Message{
+		Event: entity.EventGroupApply,
+		Content: entity.MapStrAny{
+			"apply_id": msg.ApplyId,
+			"group_id": msg.GroupId,
+			"user_id":  msg.UserId,
+			"status":   msg.Status,
+			"reason":   msg.Reason,
+		},
+	})
+
+	im.Session.Chat.Write(c)
+}
+
+// onConsumeGroupMute 群禁言状态变更通知
+func (s *ChatSubscribe) onConsumeGroupMute(body string) {
+	var msg struct {
+		Type       int    `json:"type"`
+		GroupId    int    `json:"group_id"`
+		UserId     int    `json:"user_id"`
+		OperatorId int    `json:"operator_id"`
+		IsMute     bool   `json:"is_mute"`
+		MuteUntil  string `json:"mute_until"`
+	}
+
+	if err := jsonutil.Decode(body, &msg); err != nil {
+		logger.Error("[ChatSubscribe] onConsumeGroupMute Unmarshal err: ", err.Error())
+		return
+	}
+
+	cids := s.roomStorage.All(context.Background(), &cache.RoomOption{
+		Channel:  im.Session.Chat.Name(),
+		RoomType: entity.RoomImGroup,
+		Number:   strconv.Itoa(msg.GroupId),
+		Sid:      s.config.ServerId(),
+	})
+
+	if len(cids) == 0 {
+		return
+	}
+
+	c := im.NewSenderContent()
+	c.SetReceive(cids...)
+	c.SetMessage(&im.Message{
+		Event: entity.EventGroupMute,
+		Content: entity.MapStrAny{
+			"type":        msg.Type,
+			"group_id":    msg.GroupId,
+			"user_id":     msg.UserId,
+			"operator_id": msg.OperatorId,
+			"is_mute":     msg.IsMute,
+			"mute_until":  msg.MuteUntil,
+		},
+	})
+
+	im.Session.Chat.Write(c)
+}
+
+// onConsumeGroupNoticeRemind 群公告确认提醒
+func (s *ChatSubscribe) onConsumeGroupNoticeRemind(body string) {
+	var (
+		msg struct {
+			GroupId   int    `json:"group_id"`
+			NoticeId  int    `json:"notice_id"`
+			Title     string `json:"title"`
+			Receivers []int  `json:"receivers"`
 		}
+		ctx = context.Background()
 	)
 
+	if err := jsonutil.Decode(body, &msg); err != nil {
+		logger.Error("[ChatSubscribe] onConsumeGroupNoticeRemind Unmarshal err: ", err.Error())
+		return
+	}
+
+	cids := s.clientStorage.BatchGetUidFromClientIds(ctx, s.config.ServerId(), im.Session.Chat.Name(), msg.Receivers)
+	if len(cids) == 0 {
+		return
+	}
+
+	c := im.NewSenderContent()
+	c.SetReceive(cids...)
+	c.SetMessage(&im.Message{
+		Event: entity.EventGroupNoticeRemind,
+		Content: entity.MapStrAny{
+			"group_id":  msg.GroupId,
+			"notice_id": msg.NoticeId,
+			"title":     msg.Title,
+		},
+	})
+
+	im.Session.Chat.Write(c)
+}
+
+// onConsumeTalkJoinGroup 加入或退出群房间
+func (s *ChatSubscribe) onConsumeTalkJoinGroup(body string) {
+	var data struct {
+		Gid      int   `json:"group_id"`
+		Type     int   `json:"type"`
+		Uids     []int `json:"uids"`
+		RecordId int64 `json:"record_id"`
+	}
+
 	if err := json.Unmarshal([]byte(body), &data); err != nil {
 		logger.Error("[ChatSubscribe] onConsumeTalkJoinGroup Unmarshal err: ", err.Error())
 		return
 	}
 
-	for _, uid := range data.Uids {
-		cids := s.clientStorage.GetUidFromClientIds(ctx, sid, im.Session.Chat.Name(), strconv.Itoa(uid))
+	ctx := context.Background()
+
+	// 退出房间前先向离开的成员推送消息(如群解散通知)
+	if data.Type == 2 && data.RecordId > 0 {
+		s.pushRecord(ctx, data.Gid, data.RecordId, data.Uids)
+	}
+
+	s.updateRooms(ctx, entity.RoomImGroup, data.Gid, data.Type, data.Uids)
+}
 
-		for _, cid := range cids {
-			opts := &cache.RoomOption{
-				Channel:  im.Session.Chat.Name(),
-				RoomType: entity.RoomImGroup,
-				Number:   strconv.Itoa(data.Gid),
-				Sid:      s.config.ServerId(),
-				Cid:      cid,
-			}
+// pushRecord 向指定用户的在线客户端推送群聊消息
+func (s *ChatSubscribe) pushRecord(ctx context.Context, groupId int, recordId int64, uids []int) {
 
-			if data.Type == 2 {
-				_ = s.roomStorage.Del(ctx, opts)
-			} else {
-				_ = s.roomStorage.Add(ctx, opts)
-			}
-		}
+	cids := s.clientStorage.BatchGetUidFromClientIds(ctx, s.config.ServerId(), im.Session.Chat.Name(), uids)
+	if len(cids) == 0 {
+		return
+	}
+
+	data, err := s.recordsService.GetTalkRecord(ctx, recordId)
+	if err != nil {
+		logger.Error("[ChatSubscribe] 读取对话记录失败 err: ", err.Error())
+		return
+	}
+
+	c := im.NewSenderContent()
+	c.SetReceive(cids...)
+	c.SetMessage(&im.Message{
+		Event: entity.EventTalk,
+		Content: entity.MapStrAny{
+			"sender_id":   int64(data.UserId),
+			"receiver_id": int64(groupId),
+			"talk_type":   entity.ChatGroupMode,
+			"data":        data,
+		},
+	})
+
+	im.Session.Chat.Write(c)
+}
+
+// onConsumeChannelJoin 订阅或退订频道事件
+func (s *ChatSubscribe) onConsumeChannelJoin(body string) {
+	var data struct {
+		ChannelId int   `json:"channel_id"`
+		Type      int   `json:"type"`
+		Uids      []int `json:"uids"`
+	}
+
+	if err := jsonutil.Decode(body, &data); err != nil {
+		logger.Error("[ChatSubscribe] onConsumeChannelJoin Unmarshal err: ", err.Error())
+		return
+	}
+
+	s.updateRooms(context.Background(), entity.RoomImChannel, data.ChannelId, data.Type, data.Uids)
+}
+
+// updateRooms 用户的在线客户端加入或退出房间，mode 2 为退出
+func (s *ChatSubscribe) updateRooms(ctx context.Context, roomType entity.RoomType, number int, mode int, uids []int) {
+
+	sid := s.config.ServerId()
+
+	// 大群成员较多，批量读取客户端并通过 pipeline 更新房间
+	cids := s.clientStorage.BatchGetUidFromClientIds(ctx, sid, im.Session.Chat.Name(), uids)
+	if len(cids) == 0 {
+		return
+	}
+
+	rooms := make([]*cache.RoomOption, 0, len(cids))
+	for _, cid := range cids {
+		rooms = append(rooms, &cache.RoomOption{
+			Channel:  im.Session.Chat.Name(),
+			RoomType: roomType,
+			Number:   strconv.Itoa(number),
+			Sid:      sid,
+			Cid:      cid,
+		})
+	}
+
+	var err error
+	if mode == 2 {
+		err = s.roomStorage.BatchDel(ctx, rooms)
+	} else {
+		err = s.roomStorage.BatchAdd(ctx, rooms)
+	}
+
+	if err != nil {
+		logger.Error("[ChatSubscribe] 更新房间失败 err: ", err.Error())
 	}
 }
 
@@ -360,3 +599,28 @@ func (s *ChatSubscribe) onConsumeTalkRead(body string) {
 
 	im.Session.Chat.Write(c)
 }
+
+// onConsumeSessionRevoke 设备会话注销，断开该设备在当前节点的连接
+func (s *ChatSubscribe) onConsumeSessionRevoke(body string) {
+	var msg struct {
+		UserId    int    `json:"user_id"`
+		SessionId string `json:"session_id"`
+	}
+
+	if err := json.Unmarshal([]byte(body), &msg); err != nil {
+		logger.Error("[ChatSubscribe] onConsumeSessionRevoke Unmarshal err: ", err.Error())
+		return
+	}
+
+	if msg.SessionId == "" {
+		return
+	}
+
+	cids := s.clientStorage.GetUidFromClientIds(context.Background(), s.config.ServerId(), im.Session.Chat.Name(), strconv.Itoa(msg.UserId))
+
+	for _, cid := range cids {
+		if client, ok := im.Session.Chat.Client(cid); ok && client.Device() == msg.SessionId {
+			client.Close(2001, "当前设备已退出登录")
+		}
+	}
+}
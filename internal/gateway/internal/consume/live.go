@@ -0,0 +1,100 @@
+package consume
+
+import (
+	"context"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/im"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/repository/cache"
+)
+
+type LiveSubscribe struct {
+	config   *config.Config
+	storage  *cache.LiveRoomStorage
+	handlers map[string]onConsumeFunc
+}
+
+func NewLiveSubscribe(config *config.Config, storage *cache.LiveRoomStorage) *LiveSubscribe {
+	return &LiveSubscribe{config: config, storage: storage}
+}
+
+// Events 注册事件
+func (s *LiveSubscribe) init() {
+	s.handlers = make(map[string]onConsumeFunc)
+
+	for _, event := range []string{entity.EventLiveMessage, entity.EventLiveDelete, entity.EventLiveJoin, entity.EventLiveLeave, entity.EventLiveSetting} {
+		event := event
+		s.handlers[event] = func(data string) {
+			s.push(event, data)
+		}
+	}
+
+	s.handlers[entity.EventLiveClose] = s.onConsumeClose
+}
+
+// Call 触发回调事件
+func (s *LiveSubscribe) Call(event string, data string) {
+
+	if s.handlers == nil {
+		s.init()
+	}
+
+	if f, ok := s.handlers[event]; ok {
+		f(data)
+	} else {
+		logger.Warnf("LiveSubscribe Event: [%s]未注册回调方法\n", event)
+	}
+}
+
+// push 推送消息到当前网关下的聊天室客户端
+func (s *LiveSubscribe) push(event string, data string) []int64 {
+
+	room := gjson.Get(data, "room").String()
+	if room == "" {
+		return nil
+	}
+
+	cids := s.storage.Clients(context.Background(), s.config.ServerId(), room)
+	if len(cids) == 0 {
+		return nil
+	}
+
+	var content interface{}
+	if err := jsonutil.Decode(data, &content); err != nil {
+		logger.Error("[LiveSubscribe] Unmarshal err: ", err.Error())
+		return nil
+	}
+
+	c := im.NewSenderContent()
+	c.SetReceive(cids...)
+	c.SetMessage(&im.Message{
+		Event:   event,
+		Content: content,
+	})
+
+	im.Session.Live.Write(c)
+
+	return cids
+}
+
+// onConsumeClose 聊天室关闭，通知并断开当前网关下的客户端
+func (s *LiveSubscribe) onConsumeClose(data string) {
+
+	cids := s.push(entity.EventLiveClose, data)
+
+	s.storage.DelClients(context.Background(), s.config.ServerId(), gjson.Get(data, "room").String())
+
+	// 等待关闭通知推送完成后再断开连接
+	time.AfterFunc(time.Second, func() {
+		for _, cid := range cids {
+			if client, ok := im.Session.Live.Client(cid); ok {
+				client.Close(1000, "聊天室已关闭")
+			}
+		}
+	})
+}
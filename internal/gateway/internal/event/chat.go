@@ -13,6 +13,7 @@ import (
 	"go-chat/internal/pkg/im"
 	"go-chat/internal/pkg/jsonutil"
 	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/repo"
 	"go-chat/internal/service"
 )
 
@@ -21,11 +22,12 @@ type ChatEvent struct {
 	config        *config.Config
 	roomStorage   *cache.RoomStorage
 	memberService *service.GroupMemberService
+	channelMember *repo.ChannelMember
 	handler       *chat.Handler
 }
 
-func NewChatEvent(redis *redis.Client, config *config.Config, roomStorage *cache.RoomStorage, memberService *service.GroupMemberService, handler *chat.Handler) *ChatEvent {
-	return &ChatEvent{redis: redis, config: config, roomStorage: roomStorage, memberService: memberService, handler: handler}
+func NewChatEvent(redis *redis.Client, config *config.Config, roomStorage *cache.RoomStorage, memberService *service.GroupMemberService, channelMember *repo.ChannelMember, handler *chat.Handler) *ChatEvent {
+	return &ChatEvent{redis: redis, config: config, roomStorage: roomStorage, memberService: memberService, channelMember: channelMember, handler: handler}
 }
 
 // OnOpen 连接成功回调事件
@@ -36,17 +38,9 @@ func (d *ChatEvent) OnOpen(client im.IClient) {
 	// 1.查询用户群列表
 	ids := d.memberService.Dao().GetUserGroupIds(ctx, client.Uid())
 
-	// 2.客户端加入群房间
-	rooms := make([]*cache.RoomOption, 0, len(ids))
-	for _, id := range ids {
-		rooms = append(rooms, &cache.RoomOption{
-			Channel:  im.Session.Chat.Name(),
-			RoomType: entity.RoomImGroup,
-			Number:   strconv.Itoa(id),
-			Sid:      d.config.ServerId(),
-			Cid:      client.Cid(),
-		})
-	}
+	// 2.客户端加入群房间及已订阅的频道房间
+	rooms := d.rooms(client, entity.RoomImGroup, ids)
+	rooms = append(rooms, d.rooms(client, entity.RoomImChannel, d.channelMember.GetUserChannelIds(ctx, client.Uid()))...)
 
 	if err := d.roomStorage.BatchAdd(ctx, rooms); err != nil {
 		fmt.Println("加入群聊失败", err.Error())
@@ -82,17 +76,9 @@ func (d *ChatEvent) OnClose(client im.IClient, code int, text string) {
 	// 2.查询用户群列表
 	ids := d.memberService.Dao().GetUserGroupIds(ctx, client.Uid())
 
-	// 3.客户端退出群房间
-	rooms := make([]*cache.RoomOption, 0, len(ids))
-	for _, id := range ids {
-		rooms = append(rooms, &cache.RoomOption{
-			Channel:  im.Session.Chat.Name(),
-			RoomType: entity.RoomImGroup,
-			Number:   strconv.Itoa(id),
-			Sid:      d.config.ServerId(),
-			Cid:      client.Cid(),
-		})
-	}
+	// 3.客户端退出群房间及频道房间
+	rooms := d.rooms(client, entity.RoomImGroup, ids)
+	rooms = append(rooms, d.rooms(client, entity.RoomImChannel, d.channelMember.GetUserChannelIds(ctx, client.Uid()))...)
 
 	if err := d.roomStorage.BatchDel(ctx, rooms); err != nil {
 		fmt.Println("退出群聊失败", err.Error())
@@ -107,3 +93,20 @@ func (d *ChatEvent) OnClose(client im.IClient, code int, text string) {
 		}),
 	}))
 }
+
+// rooms 客户端所在的房间列表
+func (d *ChatEvent) rooms(client im.IClient, roomType entity.RoomType, ids []int) []*cache.RoomOption {
+
+	rooms := make([]*cache.RoomOption, 0, len(ids))
+	for _, id := range ids {
+		rooms = append(rooms, &cache.RoomOption{
+			Channel:  im.Session.Chat.Name(),
+			RoomType: roomType,
+			Number:   strconv.Itoa(id),
+			Sid:      d.config.ServerId(),
+			Cid:      client.Cid(),
+		})
+	}
+
+	return rooms
+}
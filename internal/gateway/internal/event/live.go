@@ -0,0 +1,69 @@
+package event
+
+import (
+	"context"
+
+	"github.com/tidwall/gjson"
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/im"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/service"
+)
+
+type LiveEvent struct {
+	config  *config.Config
+	service *service.LiveRoomService
+}
+
+func NewLiveEvent(config *config.Config, service *service.LiveRoomService) *LiveEvent {
+	return &LiveEvent{config: config, service: service}
+}
+
+// OnOpen 连接成功回调事件
+func (e *LiveEvent) OnOpen(client im.IClient, room string, member *cache.LiveMember) {
+
+	ctx := context.Background()
+
+	if err := e.service.Join(ctx, e.config.ServerId(), room, client.Cid(), member); err != nil {
+		logger.Error("[LiveEvent] 进入聊天室失败 err: ", err.Error())
+		return
+	}
+
+	// 推送最近的聊天记录
+	_ = client.Write(&im.ClientOutContent{
+		Content: jsonutil.Marshal(&im.Message{
+			Event: entity.EventLiveHistory,
+			Content: entity.H{
+				"room":    room,
+				"member":  member,
+				"history": e.service.Storage().History(ctx, room, 50),
+			},
+		}),
+	})
+}
+
+// OnMessage 消息回调事件
+func (e *LiveEvent) OnMessage(client im.IClient, room string, member *cache.LiveMember, message []byte) {
+
+	if gjson.GetBytes(message, "event").String() != entity.EventLiveMessage {
+		return
+	}
+
+	content := gjson.GetBytes(message, "data.content").String()
+	if _, err := e.service.Send(context.Background(), room, member, content); err != nil {
+		_ = client.Write(&im.ClientOutContent{
+			Content: jsonutil.Marshal(&im.Message{
+				Event:   entity.EventLiveError,
+				Content: entity.H{"room": room, "message": err.Error()},
+			}),
+		})
+	}
+}
+
+// OnClose 连接关闭回调事件
+func (e *LiveEvent) OnClose(client im.IClient, room string, member *cache.LiveMember) {
+	e.service.Leave(context.Background(), e.config.ServerId(), room, client.Cid(), member)
+}
@@ -30,19 +30,20 @@ func (c *ChatChannel) WsConn(ctx *ichat.Context) error {
 	}
 
 	// 创建客户端
-	c.client(ctx.Ctx(), ctx.UserId(), conn)
+	c.client(ctx.Ctx(), ctx.UserId(), ctx.JwtSession().SessionId, conn)
 
 	return nil
 }
 
 // TcpConn 初始化连接
-func (c *ChatChannel) TcpConn(ctx context.Context, uid int, conn *adapter.TcpAdapter) {
-	c.client(ctx, uid, conn)
+func (c *ChatChannel) TcpConn(ctx context.Context, uid int, device string, conn *adapter.TcpAdapter) {
+	c.client(ctx, uid, device, conn)
 }
 
-func (c *ChatChannel) client(ctx context.Context, uid int, conn im.IConn) {
+func (c *ChatChannel) client(ctx context.Context, uid int, device string, conn im.IConn) {
 	im.NewClient(ctx, conn, &im.ClientOption{
 		Uid:     uid,
+		Device:  device,
 		Channel: im.Session.Chat,
 		Storage: c.storage,
 		Buffer:  10,
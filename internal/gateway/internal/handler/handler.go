@@ -12,18 +12,23 @@ import (
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/im/adapter"
 	"go-chat/internal/pkg/jsonutil"
-	"go-chat/internal/pkg/jwt"
 	"go-chat/internal/pkg/logger"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/service"
 )
 
 type Handler struct {
 	Chat    *ChatChannel
 	Example *ExampleChannel
+	Live    *LiveChannel
 	Config  *config.Config
+	Session *cache.TokenSessionStorage
+	JwtKey  *service.JwtKeyService
 }
 
 type AuthConn struct {
 	Uid     int    `json:"uid"`
+	Device  string `json:"device"`
 	Channel string `json:"channel"`
 	conn    *adapter.TcpAdapter
 }
@@ -57,7 +62,7 @@ func (h *Handler) Dispatch(conn net.Conn) {
 		fmt.Println(conn.RemoteAddr(), "认证成功==>>>", time.Now().Unix())
 
 		if info.Channel == entity.ImChannelChat {
-			h.Chat.TcpConn(context.Background(), info.Uid, info.conn)
+			h.Chat.TcpConn(context.Background(), info.Uid, info.Device, info.conn)
 		}
 	}
 }
@@ -84,15 +89,20 @@ func (h *Handler) auth(connect net.Conn, data chan *AuthConn) {
 		return
 	}
 
-	claims, err := jwt.ParseToken(detail.Token, h.Config.Jwt.Secret)
+	claims, err := h.JwtKey.ParseToken(context.Background(), detail.Token)
 	if err != nil || claims.Valid() != nil {
 		return
 	}
 
+	// 已退出登录或已注销的设备不允许连接
+	if h.Session.IsBlackList(context.Background(), detail.Token) || (claims.Subject != "" && h.Session.IsRevoked(context.Background(), claims.Subject)) {
+		return
+	}
+
 	uid, err := strconv.Atoi(claims.ID)
 	if err != nil {
 		return
 	}
 
-	data <- &AuthConn{Uid: uid, conn: conn, Channel: detail.Channel}
+	data <- &AuthConn{Uid: uid, Device: claims.Subject, conn: conn, Channel: detail.Channel}
 }
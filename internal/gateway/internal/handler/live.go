@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-chat/internal/gateway/internal/event"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/pkg/ichat/middleware"
+	"go-chat/internal/pkg/im"
+	"go-chat/internal/pkg/im/adapter"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/service"
+)
+
+// LiveChannel 直播聊天室，登录用户及持有游客令牌的访客均可进入
+type LiveChannel struct {
+	session *cache.TokenSessionStorage
+	jwtKey  *service.JwtKeyService
+	service *service.LiveRoomService
+	event   *event.LiveEvent
+}
+
+func NewLiveChannel(session *cache.TokenSessionStorage, jwtKey *service.JwtKeyService, service *service.LiveRoomService, event *event.LiveEvent) *LiveChannel {
+	return &LiveChannel{session: session, jwtKey: jwtKey, service: service, event: event}
+}
+
+// WsConnect 初始化连接
+func (c *LiveChannel) WsConnect(ctx *ichat.Context) error {
+
+	room := ctx.Context.Query("room")
+	token := middleware.QueryToken(ctx.Context)
+
+	claims, err := c.jwtKey.ParseToken(ctx.Ctx(), token)
+	if err != nil || claims.Valid() != nil {
+		return ctx.Unauthorized("请登录后操作!")
+	}
+
+	var member *cache.LiveMember
+	if claims.Guard == "api" {
+		if c.session.IsBlackList(ctx.Ctx(), token) || (claims.Subject != "" && c.session.IsRevoked(ctx.Ctx(), claims.Subject)) {
+			return ctx.Unauthorized("请登录再试.")
+		}
+
+		uid, err := strconv.Atoi(claims.ID)
+		if err != nil {
+			return ctx.Unauthorized("请登录再试.")
+		}
+
+		member, err = c.service.Member(ctx.Ctx(), uid, "")
+		if err != nil {
+			return ctx.Unauthorized("请登录再试.")
+		}
+	} else {
+		guestId, err := c.service.ParseGuestToken(claims, room)
+		if err != nil {
+			return ctx.Unauthorized(err.Error())
+		}
+
+		member, err = c.service.Member(ctx.Ctx(), 0, guestId)
+		if err != nil {
+			return ctx.Unauthorized(err.Error())
+		}
+	}
+
+	if _, err := c.service.Storage().Get(ctx.Ctx(), room); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	conn, err := adapter.NewWsAdapter(ctx.Context.Writer, ctx.Context.Request)
+	if err != nil {
+		logger.Errorf("websocket connect error: %s", err.Error())
+		return nil
+	}
+
+	// 创建客户端，回调中携带聊天室及成员信息
+	im.NewClient(ctx.Ctx(), conn, &im.ClientOption{
+		Uid:     member.Uid,
+		Channel: im.Session.Live,
+		Buffer:  10,
+	}, im.NewClientCallback(
+		im.WithOpenCallback(func(client im.IClient) {
+			c.event.OnOpen(client, room, member)
+		}),
+		im.WithMessageCallback(func(client im.IClient, message []byte) {
+			c.event.OnMessage(client, room, member, message)
+		}),
+		im.WithCloseCallback(func(client im.IClient, code int, text string) {
+			c.event.OnClose(client, room, member)
+		}),
+	))
+
+	return nil
+}
@@ -20,10 +20,11 @@ type MessageSubscribe struct {
 	redis          *redis.Client
 	defaultConsume *consume.ChatSubscribe
 	exampleConsume *consume.ExampleSubscribe
+	liveConsume    *consume.LiveSubscribe
 }
 
-func NewMessageSubscribe(config *config.Config, redis *redis.Client, defaultConsume *consume.ChatSubscribe, exampleConsume *consume.ExampleSubscribe) *MessageSubscribe {
-	return &MessageSubscribe{config: config, redis: redis, defaultConsume: defaultConsume, exampleConsume: exampleConsume}
+func NewMessageSubscribe(config *config.Config, redis *redis.Client, defaultConsume *consume.ChatSubscribe, exampleConsume *consume.ExampleSubscribe, liveConsume *consume.LiveSubscribe) *MessageSubscribe {
+	return &MessageSubscribe{config: config, redis: redis, defaultConsume: defaultConsume, exampleConsume: exampleConsume, liveConsume: liveConsume}
 }
 
 type IConsume interface {
@@ -38,6 +39,8 @@ func (m *MessageSubscribe) Setup(ctx context.Context) error {
 
 	go m.subscribe(ctx, []string{entity.ImTopicExample, fmt.Sprintf(entity.ImTopicExamplePrivate, m.config.ServerId())}, m.exampleConsume)
 
+	go m.subscribe(ctx, []string{entity.ImTopicLive}, m.liveConsume)
+
 	<-ctx.Done()
 
 	return nil
@@ -9,18 +9,19 @@ import (
 	"go-chat/internal/pkg/ichat/middleware"
 	"go-chat/internal/pkg/im"
 	"go-chat/internal/repository/cache"
+	"go-chat/internal/service"
 
 	"go-chat/config"
 	"go-chat/internal/gateway/internal/handler"
 )
 
 // NewRouter 初始化配置路由
-func NewRouter(conf *config.Config, handle *handler.Handler, session *cache.TokenSessionStorage) *gin.Engine {
+func NewRouter(conf *config.Config, handle *handler.Handler, session *cache.TokenSessionStorage, keys *service.JwtKeyService) *gin.Engine {
 
 	router := gin.Default()
 
 	// 授权验证中间件
-	authorize := middleware.Auth(conf.Jwt.Secret, "api", session)
+	authorize := middleware.Auth(conf.Jwt.Secret, keys, "api", session)
 
 	// 查看客户端连接状态
 	router.GET("/wss/connect/detail", func(ctx *gin.Context) {
@@ -28,12 +29,16 @@ func NewRouter(conf *config.Config, handle *handler.Handler, session *cache.Toke
 			"max_client_id": im.Counter.GetMaxID(),
 			"chat":          im.Session.Chat.Count(),
 			"example":       im.Session.Example.Count(),
+			"live":          im.Session.Live.Count(),
 		})
 	})
 
 	router.GET("/wss/default.io", authorize, ichat.HandlerFunc(handle.Chat.WsConn))
 	router.GET("/wss/example.io", authorize, ichat.HandlerFunc(handle.Example.WsConnect))
 
+	// 直播聊天室支持游客令牌，由处理器自行校验授权
+	router.GET("/wss/live.io", ichat.HandlerFunc(handle.Live.WsConnect))
+
 	router.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, entity.H{"ok": "success"})
 	})
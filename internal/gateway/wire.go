@@ -23,6 +23,7 @@ var providerSet = wire.NewSet(
 	// 基础服务
 	provider.NewMySQLClient,
 	provider.NewRedisClient,
+	provider.NewHttpClient,
 	provider.NewWebsocketServer,
 
 	// 路由
@@ -35,6 +36,7 @@ var providerSet = wire.NewSet(
 	process.NewMessageSubscribe,
 	consume2.NewChatSubscribe,
 	consume2.NewExampleSubscribe,
+	consume2.NewLiveSubscribe,
 
 	// 缓存
 	cache.NewTokenSessionStorage,
@@ -46,27 +48,42 @@ var providerSet = wire.NewSet(
 	cache.NewRelation,
 	cache.NewContactRemark,
 	cache.NewSequence,
+	cache.NewJwtKeyStorage,
+	cache.NewLiveRoomStorage,
+	cache.NewRateLimitStorage,
 
 	// dao 数据层
 	repo.NewTalkRecords,
 	repo.NewTalkRecordsVote,
 	repo.NewGroupMember,
 	repo.NewContact,
+	repo.NewUserPrivacy,
+	repo.NewUserBlock,
+	repo.NewChannelMember,
+	repo.NewUsers,
+	repo.NewModerationWord,
+	repo.NewModerationLink,
+	repo.NewModerationReview,
 
 	chat.NewHandler,
 
 	event.NewChatEvent,
 	event.NewExampleEvent,
+	event.NewLiveEvent,
 
 	// 服务
 	service.NewBaseService,
 	service.NewTalkRecordsService,
 	service.NewGroupMemberService,
 	service.NewContactService,
+	service.NewJwtKeyService,
+	service.NewLiveRoomService,
+	service.NewModerationService,
 
 	// handle
 	handler.NewChatChannel,
 	handler.NewExampleChannel,
+	handler.NewLiveChannel,
 
 	wire.Struct(new(handler.Handler), "*"),
 	wire.Struct(new(AppProvider), "*"),
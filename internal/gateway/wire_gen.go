@@ -34,17 +34,35 @@ func Initialize(conf *config.Config) *AppProvider {
 	groupMember := repo.NewGroupMember(db, relation)
 	groupMemberService := service.NewGroupMemberService(baseService, groupMember)
 	chatHandler := chat.NewHandler(client, groupMemberService)
-	chatEvent := event.NewChatEvent(client, conf, roomStorage, groupMemberService, chatHandler)
+	channelMember := repo.NewChannelMember(db)
+	chatEvent := event.NewChatEvent(client, conf, roomStorage, groupMemberService, channelMember, chatHandler)
 	chatChannel := handler.NewChatChannel(clientStorage, chatEvent)
 	exampleEvent := event.NewExampleEvent()
 	exampleChannel := handler.NewExampleChannel(clientStorage, exampleEvent)
+	tokenSessionStorage := cache.NewTokenSessionStorage(client)
+	jwtKeyStorage := cache.NewJwtKeyStorage(client)
+	redisLock := cache.NewRedisLock(client)
+	jwtKeyService := service.NewJwtKeyService(conf, jwtKeyStorage, redisLock)
+	liveRoomStorage := cache.NewLiveRoomStorage(client, serverStorage)
+	users := repo.NewUsers(db)
+	httpClient := provider.NewHttpClient()
+	rateLimitStorage := cache.NewRateLimitStorage(client)
+	moderationWord := repo.NewModerationWord(db)
+	moderationLink := repo.NewModerationLink(db)
+	moderationReview := repo.NewModerationReview(db)
+	moderationService := service.NewModerationService(baseService, conf, httpClient, rateLimitStorage, moderationWord, moderationLink, moderationReview)
+	liveRoomService := service.NewLiveRoomService(baseService, liveRoomStorage, users, jwtKeyService, moderationService)
+	liveEvent := event.NewLiveEvent(conf, liveRoomService)
+	liveChannel := handler.NewLiveChannel(tokenSessionStorage, jwtKeyService, liveRoomService, liveEvent)
 	handlerHandler := &handler.Handler{
 		Chat:    chatChannel,
 		Example: exampleChannel,
+		Live:    liveChannel,
 		Config:  conf,
+		Session: tokenSessionStorage,
+		JwtKey:  jwtKeyService,
 	}
-	tokenSessionStorage := cache.NewTokenSessionStorage(client)
-	engine := router.NewRouter(conf, handlerHandler, tokenSessionStorage)
+	engine := router.NewRouter(conf, handlerHandler, tokenSessionStorage, jwtKeyService)
 	websocketServer := provider.NewWebsocketServer(conf, engine)
 	healthSubscribe := process.NewHealthSubscribe(conf, serverStorage)
 	talkVote := cache.NewTalkVote(client)
@@ -53,10 +71,13 @@ func Initialize(conf *config.Config) *AppProvider {
 	talkRecordsService := service.NewTalkRecordsService(baseService, talkVote, talkRecordsVote, groupMember, talkRecords)
 	contactRemark := cache.NewContactRemark(client)
 	contact := repo.NewContact(db, contactRemark, relation)
-	contactService := service.NewContactService(baseService, contact)
+	userPrivacy := repo.NewUserPrivacy(db)
+	userBlock := repo.NewUserBlock(db)
+	contactService := service.NewContactService(baseService, contact, userPrivacy, userBlock)
 	chatSubscribe := consume.NewChatSubscribe(conf, clientStorage, roomStorage, talkRecordsService, contactService)
 	exampleSubscribe := consume.NewExampleSubscribe()
-	messageSubscribe := process.NewMessageSubscribe(conf, client, chatSubscribe, exampleSubscribe)
+	liveSubscribe := consume.NewLiveSubscribe(conf, liveRoomStorage)
+	messageSubscribe := process.NewMessageSubscribe(conf, client, chatSubscribe, exampleSubscribe, liveSubscribe)
 	subServers := &process.SubServers{
 		HealthSubscribe:  healthSubscribe,
 		MessageSubscribe: messageSubscribe,
@@ -73,4 +94,4 @@ func Initialize(conf *config.Config) *AppProvider {
 
 // wire.go:
 
-var providerSet = wire.NewSet(provider.NewMySQLClient, provider.NewRedisClient, provider.NewWebsocketServer, router.NewRouter, wire.Struct(new(process.SubServers), "*"), process.NewServer, process.NewHealthSubscribe, process.NewMessageSubscribe, consume.NewChatSubscribe, consume.NewExampleSubscribe, cache.NewTokenSessionStorage, cache.NewSidStorage, cache.NewRedisLock, cache.NewClientStorage, cache.NewRoomStorage, cache.NewTalkVote, cache.NewRelation, cache.NewContactRemark, cache.NewSequence, repo.NewTalkRecords, repo.NewTalkRecordsVote, repo.NewGroupMember, repo.NewContact, chat.NewHandler, event.NewChatEvent, event.NewExampleEvent, service.NewBaseService, service.NewTalkRecordsService, service.NewGroupMemberService, service.NewContactService, handler.NewChatChannel, handler.NewExampleChannel, wire.Struct(new(handler.Handler), "*"), wire.Struct(new(AppProvider), "*"))
+var providerSet = wire.NewSet(provider.NewMySQLClient, provider.NewRedisClient, provider.NewHttpClient, provider.NewWebsocketServer, router.NewRouter, wire.Struct(new(process.SubServers), "*"), process.NewServer, process.NewHealthSubscribe, process.NewMessageSubscribe, consume.NewChatSubscribe, consume.NewExampleSubscribe, consume.NewLiveSubscribe, cache.NewTokenSessionStorage, cache.NewSidStorage, cache.NewRedisLock, cache.NewClientStorage, cache.NewRoomStorage, cache.NewTalkVote, cache.NewRelation, cache.NewContactRemark, cache.NewSequence, cache.NewJwtKeyStorage, cache.NewLiveRoomStorage, cache.NewRateLimitStorage, repo.NewTalkRecords, repo.NewTalkRecordsVote, repo.NewGroupMember, repo.NewContact, repo.NewUserPrivacy, repo.NewUserBlock, repo.NewChannelMember, repo.NewUsers, repo.NewModerationWord, repo.NewModerationLink, repo.NewModerationReview, chat.NewHandler, event.NewChatEvent, event.NewExampleEvent, event.NewLiveEvent, service.NewBaseService, service.NewTalkRecordsService, service.NewGroupMemberService, service.NewContactService, service.NewJwtKeyService, service.NewLiveRoomService, service.NewModerationService, handler.NewChatChannel, handler.NewExampleChannel, handler.NewLiveChannel, wire.Struct(new(handler.Handler), "*"), wire.Struct(new(AppProvider), "*"))
@@ -3,8 +3,16 @@ package admin
 import "go-chat/internal/http/internal/handler/admin/v1"
 
 type V1 struct {
-	Index *v1.Index
-	Auth  *v1.Auth
+	Index      *v1.Index
+	Auth       *v1.Auth
+	Organize   *v1.Organize
+	User       *v1.User
+	Group      *v1.Group
+	Emoticon   *v1.Emoticon
+	Robot      *v1.Robot
+	Account    *v1.Account
+	Audit      *v1.Audit
+	Moderation *v1.Moderation
 }
 
 type V2 struct {
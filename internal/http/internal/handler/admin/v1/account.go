@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+)
+
+// Account 管理员账号管理
+type Account struct {
+	adminService *service.AdminService
+}
+
+func NewAccount(adminService *service.AdminService) *Account {
+	return &Account{adminService: adminService}
+}
+
+type AccountCreateRequest struct {
+	Username string `json:"username" binding:"required,min=4,max=32,alphanum"`
+	Password string `json:"password" binding:"required,min=8,max=64"`
+	Nickname string `json:"nickname" binding:"required,max=32"`
+	Role     string `json:"role" binding:"required,oneof=super operator auditor"`
+}
+
+type AccountUpdateRequest struct {
+	AdminId  int    `json:"admin_id" binding:"required,min=1"`
+	Nickname string `json:"nickname" binding:"required,max=32"`
+	Role     string `json:"role" binding:"required,oneof=super operator auditor"`
+	Status   *int   `json:"status" binding:"required,oneof=0 1"`
+	Password string `json:"password" binding:"omitempty,min=8,max=64"`
+}
+
+// List 管理员列表
+func (c *Account) List(ctx *ichat.Context) error {
+
+	params := &PageRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	params.init()
+
+	items, total, err := c.adminService.List(ctx.Ctx(), params.Page, params.Size)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{
+		"items":    items,
+		"paginate": &Paginate{Page: params.Page, Size: params.Size, Total: total},
+	})
+}
+
+// Create 创建管理员
+func (c *Account) Create(ctx *ichat.Context) error {
+
+	params := &AccountCreateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	admin, err := c.adminService.Create(ctx.Ctx(), &service.AdminCreateOpts{
+		Username: params.Username,
+		Password: params.Password,
+		Nickname: params.Nickname,
+		Role:     params.Role,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	audit(ctx, c.adminService, entity.AdminActionAdminCreate, "admin", admin.Id, entity.H{"username": admin.Username, "role": admin.Role})
+
+	return ctx.Success(entity.H{"id": admin.Id})
+}
+
+// Update 修改管理员信息
+func (c *Account) Update(ctx *ichat.Context) error {
+
+	params := &AccountUpdateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	// 不能修改自己的角色或禁用自己，避免失去管理权限
+	if params.AdminId == ctx.UserId() && (params.Role != entity.AdminRoleSuper || *params.Status != entity.AdminStatusNormal) {
+		return ctx.ErrorBusiness("不能修改自己的角色或状态！")
+	}
+
+	err := c.adminService.Update(ctx.Ctx(), &service.AdminUpdateOpts{
+		Id:       params.AdminId,
+		Nickname: params.Nickname,
+		Role:     params.Role,
+		Status:   *params.Status,
+		Password: params.Password,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	audit(ctx, c.adminService, entity.AdminActionAdminUpdate, "admin", params.AdminId, entity.H{
+		"role":     params.Role,
+		"status":   *params.Status,
+		"password": params.Password != "",
+	})
+
+	return ctx.Success(nil)
+}
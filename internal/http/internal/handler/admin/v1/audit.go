@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+)
+
+// PageRequest 分页参数
+type PageRequest struct {
+	Page int `form:"page" binding:"omitempty,min=1"`
+	Size int `form:"size" binding:"omitempty,min=1,max=100"`
+}
+
+func (p *PageRequest) init() {
+	if p.Page == 0 {
+		p.Page = 1
+	}
+
+	if p.Size == 0 {
+		p.Size = 20
+	}
+}
+
+// Paginate 分页信息
+type Paginate struct {
+	Page  int   `json:"page"`
+	Size  int   `json:"size"`
+	Total int64 `json:"total"`
+}
+
+type Audit struct {
+	adminService *service.AdminService
+}
+
+func NewAudit(adminService *service.AdminService) *Audit {
+	return &Audit{adminService: adminService}
+}
+
+type AuditListRequest struct {
+	PageRequest
+	AdminId int    `form:"admin_id" binding:"omitempty,min=1"`
+	Action  string `form:"action"`
+}
+
+// List 审计日志列表
+func (c *Audit) List(ctx *ichat.Context) error {
+
+	params := &AuditListRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	params.init()
+
+	items, total, err := c.adminService.AuditList(ctx.Ctx(), &service.AdminAuditListOpts{
+		AdminId: params.AdminId,
+		Action:  params.Action,
+		Page:    params.Page,
+		Size:    params.Size,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{
+		"items":    items,
+		"paginate": &Paginate{Page: params.Page, Size: params.Size, Total: total},
+	})
+}
+
+// audit 记录当前管理员的操作日志
+func audit(ctx *ichat.Context, adminService *service.AdminService, action string, targetType string, targetId int, detail interface{}) {
+	_ = adminService.Audit(ctx.Ctx(), &service.AdminAuditOpts{
+		AdminId:    ctx.UserId(),
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+		Detail:     detail,
+		Ip:         ctx.Context.ClientIP(),
+		Agent:      ctx.Context.GetHeader("user-agent"),
+	})
+}
@@ -1,26 +1,33 @@
 package v1
 
 import (
-	"fmt"
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/mojocn/base64Captcha"
 	"go-chat/api/pb/admin/v1"
 	"go-chat/config"
+	"go-chat/internal/entity"
 	"go-chat/internal/pkg/ichat"
 	"go-chat/internal/pkg/jwt"
 	"go-chat/internal/repository/cache"
-	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
 )
 
+const adminTokenExpires = 12 * time.Hour // 管理员登录凭证有效期
+
 type Auth struct {
-	config  *config.Config
-	captcha *cache.CaptchaStorage
-	test    *repo.Test
+	config       *config.Config
+	captcha      *cache.CaptchaStorage
+	session      *cache.TokenSessionStorage
+	jwtKey       *service.JwtKeyService
+	loginLimit   *service.LoginLimitService
+	adminService *service.AdminService
 }
 
-func NewAuth(config *config.Config, captcha *cache.CaptchaStorage, test *repo.Test) *Auth {
-	return &Auth{config: config, captcha: captcha, test: test}
+func NewAuth(config *config.Config, captcha *cache.CaptchaStorage, session *cache.TokenSessionStorage, jwtKey *service.JwtKeyService, loginLimit *service.LoginLimitService, adminService *service.AdminService) *Auth {
+	return &Auth{config: config, captcha: captcha, session: session, jwtKey: jwtKey, loginLimit: loginLimit, adminService: adminService}
 }
 
 // Login 登录接口
@@ -31,25 +38,53 @@ func (c *Auth) Login(ctx *ichat.Context) error {
 		return ctx.InvalidParams(err)
 	}
 
+	limit := &service.LoginLimitOpts{
+		Scene:   service.LoginLimitSceneAdmin,
+		Account: params.Username,
+		Ip:      ctx.Context.ClientIP(),
+	}
+
+	if _, err := c.loginLimit.Check(ctx.Ctx(), limit); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
 	if !c.captcha.Verify(params.CaptchaVoucher, params.Captcha, true) {
+		c.loginLimit.Fail(ctx.Ctx(), limit)
 		return ctx.InvalidParams("验证码填写不正确")
 	}
 
-	expiresAt := time.Now().Add(12 * time.Hour)
+	info, err := c.adminService.Login(ctx.Ctx(), params.Username, params.Password, ctx.Context.ClientIP())
+	if err != nil {
+		if errors.Is(err, service.ErrAdminLogin) {
+			if state := c.loginLimit.Fail(ctx.Ctx(), limit); state.LockTTL >= time.Minute {
+				return ctx.ErrorBusiness((&service.LoginLimitError{TTL: state.LockTTL}).Error())
+			}
+		}
 
-	// 生成登录凭证
-	token := jwt.GenerateToken("admin", c.config.Jwt.Secret, &jwt.Options{
-		ExpiresAt: jwt.NewNumericDate(expiresAt),
-		ID:        "1",
-		Issuer:    "im.admin",
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	c.loginLimit.Success(ctx.Ctx(), limit)
+
+	token, err := c.token(ctx, info.Id)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	_ = c.adminService.Audit(ctx.Ctx(), &service.AdminAuditOpts{
+		AdminId:    info.Id,
+		Action:     entity.AdminActionLogin,
+		TargetType: "admin",
+		TargetId:   info.Id,
+		Ip:         ctx.Context.ClientIP(),
+		Agent:      ctx.Context.GetHeader("user-agent"),
 	})
 
 	return ctx.Success(&admin.AuthLoginResponse{
 		Auth: &admin.AccessToken{
 			Type:        "Bearer",
 			AccessToken: token,
-			ExpiresIn:   60 * 60 * 12,
+			ExpiresIn:   int32(adminTokenExpires.Seconds()),
 		},
 	})
 }
@@ -73,54 +108,46 @@ func (c *Auth) Captcha(ctx *ichat.Context) error {
 // Logout 退出登录接口
 func (c *Auth) Logout(ctx *ichat.Context) error {
 
-	// TODO 业务逻辑 ...
-
-	// all, err := c.test.FindAll(ctx.Ctx(), func(db *gorm.DB) {
-	// 	db.Order("id desc").Limit(3)
-	// })
-	// if err != nil {
-	// 	return ctx.Error(err.Error())
-	// }
-	//
-	// for _, m := range all {
-	// 	fmt.Println(m.Id)
-	// 	fmt.Printf("%T \n", m)
-	// }
-	//
-	// data, err := c.test.FindById(ctx.Ctx(), 10)
-	// if err != nil {
-	// 	return ctx.Error(err.Error())
-	// }
-	//
-	// fmt.Println(jsonutil.Encode(data))
-	//
-	// data, err = c.test.FindByWhere(ctx.Ctx(), "user_id = ? and class_id = ?", 4135, 3236)
-	// if err != nil {
-	// 	return ctx.Error(err.Error())
-	// }
-	//
-	// fmt.Println(jsonutil.Encode(data))
-
-	// count, err := c.test.UpdateWhere(ctx.Ctx(), map[string]interface{}{
-	// 	"is_asterisk": 0,
-	// }, "1 = 1")
-	//
-	// fmt.Println(count, err)
-
-	isTrue, err := c.test.QueryExist(ctx.Ctx(), "user_id = ?", 2054)
-	if err != nil {
-		return err
-	}
+	c.toBlackList(ctx)
 
-	fmt.Println(isTrue)
+	audit(ctx, c.adminService, entity.AdminActionLogout, "admin", ctx.UserId(), nil)
 
 	return ctx.Success(nil)
 }
 
-// Refresh Token 刷新接口
+// Refresh Token 刷新接口，签发新的登录凭证并注销当前凭证
 func (c *Auth) Refresh(ctx *ichat.Context) error {
 
-	// TODO 业务逻辑 ...
+	token, err := c.token(ctx, ctx.UserId())
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
 
-	return ctx.Success(nil)
+	c.toBlackList(ctx)
+
+	return ctx.Success(&admin.AuthRefreshResponse{
+		Token:    token,
+		ExpireIn: int32(adminTokenExpires.Seconds()),
+	})
+}
+
+// token 生成管理员登录凭证
+func (c *Auth) token(ctx *ichat.Context, id int) (string, error) {
+	return c.jwtKey.GenerateToken(ctx.Ctx(), "admin", &jwt.Options{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(adminTokenExpires)),
+		ID:        strconv.Itoa(id),
+		Issuer:    "im.admin",
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	})
+}
+
+func (c *Auth) toBlackList(ctx *ichat.Context) {
+
+	session := ctx.JwtSession()
+	if session != nil {
+		ex := session.ExpiresAt - time.Now().Unix()
+
+		// 将 session 加入黑名单
+		_ = c.session.SetBlackList(ctx.Ctx(), session.Token, time.Duration(ex)*time.Second)
+	}
 }
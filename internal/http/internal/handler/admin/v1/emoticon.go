@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+	"gorm.io/gorm"
+)
+
+type Emoticon struct {
+	emoticonService *service.EmoticonService
+	adminService    *service.AdminService
+}
+
+func NewEmoticon(emoticonService *service.EmoticonService, adminService *service.AdminService) *Emoticon {
+	return &Emoticon{emoticonService: emoticonService, adminService: adminService}
+}
+
+type EmoticonStatusRequest struct {
+	EmoticonId int  `json:"emoticon_id" binding:"required,min=1"`
+	Status     *int `json:"status" binding:"required,oneof=-1 0 1"` // [-1:删除;0:正常;1:禁用;]
+}
+
+// List 系统表情包列表(不含已删除)
+func (c *Emoticon) List(ctx *ichat.Context) error {
+
+	items, err := c.emoticonService.Dao().FindAll(ctx.Ctx(), func(db *gorm.DB) {
+		db.Where("status != ?", -1).Order("id asc")
+	})
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{"items": items})
+}
+
+// Status 修改系统表情包状态
+func (c *Emoticon) Status(ctx *ichat.Context) error {
+
+	params := &EmoticonStatusRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	info, err := c.emoticonService.Dao().FindById(ctx.Ctx(), params.EmoticonId)
+	if err != nil || info.Status == -1 {
+		return ctx.ErrorBusiness("表情包不存在！")
+	}
+
+	if _, err := c.emoticonService.Dao().UpdateById(ctx.Ctx(), info.Id, map[string]interface{}{"status": *params.Status}); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	audit(ctx, c.adminService, entity.AdminActionEmoticonStatus, "emoticon", info.Id, entity.H{"from": info.Status, "to": *params.Status})
+
+	return ctx.Success(nil)
+}
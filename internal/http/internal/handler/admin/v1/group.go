@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+)
+
+type Group struct {
+	groupService   *service.GroupService
+	messageService *service.TalkMessageService
+	adminService   *service.AdminService
+}
+
+func NewGroup(groupService *service.GroupService, messageService *service.TalkMessageService, adminService *service.AdminService) *Group {
+	return &Group{groupService: groupService, messageService: messageService, adminService: adminService}
+}
+
+type GroupListRequest struct {
+	PageRequest
+	Keyword string `form:"keyword"`
+}
+
+type GroupDismissRequest struct {
+	GroupId int    `json:"group_id" binding:"required,min=1"`
+	Reason  string `json:"reason" binding:"max=200"`
+}
+
+// List 群组搜索
+func (c *Group) List(ctx *ichat.Context) error {
+
+	params := &GroupListRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	params.init()
+
+	items, total, err := c.groupService.Dao().SearchList(ctx.Ctx(), params.Keyword, params.Page, params.Size)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{
+		"items":    items,
+		"paginate": &Paginate{Page: params.Page, Size: params.Size, Total: total},
+	})
+}
+
+// Dismiss 解散群组
+func (c *Group) Dismiss(ctx *ichat.Context) error {
+
+	params := &GroupDismissRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	group, err := c.groupService.Dao().FindById(ctx.Ctx(), params.GroupId)
+	if err != nil {
+		return ctx.ErrorBusiness("群组不存在！")
+	}
+
+	if group.IsDismiss == 1 {
+		return ctx.ErrorBusiness("群组已解散！")
+	}
+
+	if err := c.groupService.Dismiss(ctx.Ctx(), group.Id, group.CreatorId); err != nil {
+		return ctx.ErrorBusiness("群组解散失败！")
+	}
+
+	_ = c.messageService.SendSysMessage(ctx.Ctx(), &service.SysTextMessageOpt{
+		UserId:     group.CreatorId,
+		TalkType:   entity.ChatGroupMode,
+		ReceiverId: group.Id,
+		Text:       "群组已被系统管理员解散！",
+	})
+
+	audit(ctx, c.adminService, entity.AdminActionGroupDismiss, "group", group.Id, entity.H{"group_name": group.Name, "reason": params.Reason})
+
+	return ctx.Success(nil)
+}
@@ -1,14 +1,25 @@
 package v1
 
-import "go-chat/internal/pkg/ichat"
+import (
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+)
 
 type Index struct {
+	adminService *service.AdminService
 }
 
-func NewIndex() *Index {
-	return &Index{}
+func NewIndex(adminService *service.AdminService) *Index {
+	return &Index{adminService: adminService}
 }
 
+// Index 系统统计数据
 func (c *Index) Index(ctx *ichat.Context) error {
-	return nil
+
+	stats, err := c.adminService.Stats(ctx.Ctx())
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(stats)
 }
@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+)
+
+type Moderation struct {
+	moderationService *service.ModerationService
+	adminService      *service.AdminService
+}
+
+func NewModeration(moderationService *service.ModerationService, adminService *service.AdminService) *Moderation {
+	return &Moderation{moderationService: moderationService, adminService: adminService}
+}
+
+type ModerationWordCreateRequest struct {
+	Words  []string `json:"words" binding:"required,min=1,max=500,dive,required,max=64"`
+	Action string   `json:"action" binding:"required,oneof=replace flag block"`
+}
+
+type ModerationLinkCreateRequest struct {
+	Domain string `json:"domain" binding:"required,max=128"`
+	Type   int    `json:"type" binding:"required,oneof=1 2"` // [1:白名单;2:黑名单;]
+}
+
+type ModerationDeleteRequest struct {
+	Ids []int `json:"ids" binding:"required,min=1,max=100"`
+}
+
+type ModerationReviewListRequest struct {
+	PageRequest
+	Status *int `form:"status" binding:"omitempty,oneof=-1 0 1 2"` // 默认查询待审核
+}
+
+type ModerationReviewHandleRequest struct {
+	ReviewId int `json:"review_id" binding:"required,min=1"`
+	Status   int `json:"status" binding:"required,oneof=1 2"` // [1:通过;2:驳回并撤回消息;]
+}
+
+// WordList 敏感词列表
+func (c *Moderation) WordList(ctx *ichat.Context) error {
+
+	items, err := c.moderationService.WordList(ctx.Ctx())
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{"items": items})
+}
+
+// WordCreate 批量添加敏感词
+func (c *Moderation) WordCreate(ctx *ichat.Context) error {
+
+	params := &ModerationWordCreateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.moderationService.WordCreate(ctx.Ctx(), params.Words, params.Action); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	audit(ctx, c.adminService, entity.AdminActionWordCreate, "moderation_word", 0, params)
+
+	return ctx.Success(nil)
+}
+
+// WordDelete 删除敏感词
+func (c *Moderation) WordDelete(ctx *ichat.Context) error {
+
+	params := &ModerationDeleteRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.moderationService.WordDelete(ctx.Ctx(), params.Ids); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	audit(ctx, c.adminService, entity.AdminActionWordDelete, "moderation_word", 0, params)
+
+	return ctx.Success(nil)
+}
+
+// LinkList 链接域名名单
+func (c *Moderation) LinkList(ctx *ichat.Context) error {
+
+	items, err := c.moderationService.LinkList(ctx.Ctx())
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{"items": items})
+}
+
+// LinkCreate 添加链接域名名单
+func (c *Moderation) LinkCreate(ctx *ichat.Context) error {
+
+	params := &ModerationLinkCreateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.moderationService.LinkCreate(ctx.Ctx(), params.Domain, params.Type); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	audit(ctx, c.adminService, entity.AdminActionLinkCreate, "moderation_link", 0, params)
+
+	return ctx.Success(nil)
+}
+
+// LinkDelete 删除链接域名名单
+func (c *Moderation) LinkDelete(ctx *ichat.Context) error {
+
+	params := &ModerationDeleteRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.moderationService.LinkDelete(ctx.Ctx(), params.Ids); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	audit(ctx, c.adminService, entity.AdminActionLinkDelete, "moderation_link", 0, params)
+
+	return ctx.Success(nil)
+}
+
+// ReviewList 人工审核队列
+func (c *Moderation) ReviewList(ctx *ichat.Context) error {
+
+	params := &ModerationReviewListRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	params.init()
+
+	status := entity.ModerationReviewPending
+	if params.Status != nil {
+		status = *params.Status
+	}
+
+	items, total, err := c.moderationService.ReviewList(ctx.Ctx(), &service.ModerationReviewListOpts{
+		Status: status,
+		Page:   params.Page,
+		Size:   params.Size,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{
+		"items":    items,
+		"paginate": &Paginate{Page: params.Page, Size: params.Size, Total: total},
+	})
+}
+
+// ReviewHandle 处理审核记录
+func (c *Moderation) ReviewHandle(ctx *ichat.Context) error {
+
+	params := &ModerationReviewHandleRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	review, err := c.moderationService.ReviewHandle(ctx.Ctx(), ctx.UserId(), params.ReviewId, params.Status)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	audit(ctx, c.adminService, entity.AdminActionReviewHandle, "moderation_review", review.Id, entity.H{"record_id": review.RecordId, "status": params.Status})
+
+	return ctx.Success(nil)
+}
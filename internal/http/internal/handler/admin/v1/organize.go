@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+)
+
+type Organize struct {
+	totpService  *service.UserTotpService
+	adminService *service.AdminService
+}
+
+func NewOrganize(totpService *service.UserTotpService, adminService *service.AdminService) *Organize {
+	return &Organize{totpService: totpService, adminService: adminService}
+}
+
+type OrganizeForceTotpRequest struct {
+	UserIds []int `json:"user_ids" binding:"required,min=1"`
+	IsForce int   `json:"is_force" binding:"oneof=0 1"`
+}
+
+// ForceTotp 设置企业成员是否强制开启两步验证
+func (c *Organize) ForceTotp(ctx *ichat.Context) error {
+
+	params := &OrganizeForceTotpRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	num, err := c.totpService.SetForce(ctx.Ctx(), params.UserIds, params.IsForce == 1)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	audit(ctx, c.adminService, entity.AdminActionOrganizeTotp, "user", 0, entity.H{"user_ids": params.UserIds, "is_force": params.IsForce, "num": num})
+
+	return ctx.Success(entity.H{"num": num})
+}
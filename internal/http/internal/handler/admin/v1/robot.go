@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+	"gorm.io/gorm"
+)
+
+type Robot struct {
+	robotRepo    *repo.Robot
+	adminService *service.AdminService
+}
+
+func NewRobot(robotRepo *repo.Robot, adminService *service.AdminService) *Robot {
+	return &Robot{robotRepo: robotRepo, adminService: adminService}
+}
+
+type RobotUpdateRequest struct {
+	RobotId  int    `json:"robot_id" binding:"required,min=1"`
+	Name     string `json:"robot_name" binding:"required,max=30"`
+	Describe string `json:"describe" binding:"max=255"`
+	Logo     string `json:"logo" binding:"max=255"`
+	IsTalk   *int   `json:"is_talk" binding:"required,oneof=0 1"`
+	Status   *int   `json:"status" binding:"required,oneof=0 1"`
+}
+
+// List 机器人列表(不含已删除)
+func (c *Robot) List(ctx *ichat.Context) error {
+
+	items, err := c.robotRepo.FindAll(ctx.Ctx(), func(db *gorm.DB) {
+		db.Where("status != ?", -1).Order("id asc")
+	})
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{"items": items})
+}
+
+// Update 修改机器人信息及状态
+func (c *Robot) Update(ctx *ichat.Context) error {
+
+	params := &RobotUpdateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	info, err := c.robotRepo.FindById(ctx.Ctx(), params.RobotId)
+	if err != nil || info.Status == -1 {
+		return ctx.ErrorBusiness("机器人不存在！")
+	}
+
+	data := map[string]interface{}{
+		"robot_name": params.Name,
+		"describe":   params.Describe,
+		"logo":       params.Logo,
+		"is_talk":    *params.IsTalk,
+		"status":     *params.Status,
+	}
+
+	if _, err := c.robotRepo.UpdateById(ctx.Ctx(), info.Id, data); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	audit(ctx, c.adminService, entity.AdminActionRobotUpdate, "robot", info.Id, data)
+
+	return ctx.Success(nil)
+}
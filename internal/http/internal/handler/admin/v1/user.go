@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+)
+
+type User struct {
+	userService        *service.UserService
+	userSessionService *service.UserSessionService
+	adminService       *service.AdminService
+}
+
+func NewUser(userService *service.UserService, userSessionService *service.UserSessionService, adminService *service.AdminService) *User {
+	return &User{userService: userService, userSessionService: userSessionService, adminService: adminService}
+}
+
+type UserListRequest struct {
+	PageRequest
+	Keyword string `form:"keyword"`
+	Status  *int   `form:"status" binding:"omitempty,oneof=0 1"`
+}
+
+type UserRequest struct {
+	UserId int `json:"user_id" binding:"required,min=1"`
+}
+
+type UserItem struct {
+	Id        int    `json:"id"`
+	Mobile    string `json:"mobile"`
+	Nickname  string `json:"nickname"`
+	Avatar    string `json:"avatar"`
+	Gender    int    `json:"gender"`
+	Email     string `json:"email"`
+	Status    int    `json:"status"`
+	CreatedAt string `json:"created_at"`
+}
+
+// List 用户搜索
+func (c *User) List(ctx *ichat.Context) error {
+
+	params := &UserListRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	params.init()
+
+	status := -1
+	if params.Status != nil {
+		status = *params.Status
+	}
+
+	list, total, err := c.userService.Dao().SearchList(ctx.Ctx(), params.Keyword, status, params.Page, params.Size)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	items := make([]*UserItem, 0, len(list))
+	for _, item := range list {
+		items = append(items, &UserItem{
+			Id:        item.Id,
+			Mobile:    item.Mobile,
+			Nickname:  item.Nickname,
+			Avatar:    item.Avatar,
+			Gender:    item.Gender,
+			Email:     item.Email,
+			Status:    item.Status,
+			CreatedAt: item.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return ctx.Success(entity.H{
+		"items":    items,
+		"paginate": &Paginate{Page: params.Page, Size: params.Size, Total: total},
+	})
+}
+
+// Disable 禁用用户，并注销其所有设备会话
+func (c *User) Disable(ctx *ichat.Context) error {
+
+	params := &UserRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.userService.SetStatus(ctx.Ctx(), params.UserId, entity.UserStatusDisabled); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	num, _ := c.userSessionService.RevokeAll(ctx.Ctx(), params.UserId)
+
+	audit(ctx, c.adminService, entity.AdminActionUserDisable, "user", params.UserId, entity.H{"revoked": num})
+
+	return ctx.Success(nil)
+}
+
+// Enable 启用用户
+func (c *User) Enable(ctx *ichat.Context) error {
+
+	params := &UserRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.userService.SetStatus(ctx.Ctx(), params.UserId, entity.UserStatusNormal); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	audit(ctx, c.adminService, entity.AdminActionUserEnable, "user", params.UserId, nil)
+
+	return ctx.Success(nil)
+}
+
+// Logout 强制用户退出登录，注销其所有设备会话
+func (c *User) Logout(ctx *ichat.Context) error {
+
+	params := &UserRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	num, err := c.userSessionService.RevokeAll(ctx.Ctx(), params.UserId)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	audit(ctx, c.adminService, entity.AdminActionUserLogout, "user", params.UserId, entity.H{"revoked": num})
+
+	return ctx.Success(entity.H{"num": num})
+}
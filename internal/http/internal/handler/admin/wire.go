@@ -8,6 +8,14 @@ import (
 var ProviderSet = wire.NewSet(
 	v1.NewIndex,
 	v1.NewAuth,
+	v1.NewOrganize,
+	v1.NewUser,
+	v1.NewGroup,
+	v1.NewEmoticon,
+	v1.NewRobot,
+	v1.NewAccount,
+	v1.NewAudit,
+	v1.NewModeration,
 
 	wire.Struct(new(V1), "*"),
 	wire.Struct(new(V2), "*"),
@@ -4,6 +4,7 @@ import "go-chat/internal/http/internal/handler/open/v1"
 
 type V1 struct {
 	Index *v1.Index
+	Jwks  *v1.Jwks
 }
 
 type Handler struct {
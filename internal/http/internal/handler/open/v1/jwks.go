@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"net/http"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+)
+
+type Jwks struct {
+	jwtKeyService *service.JwtKeyService
+}
+
+func NewJwks(jwtKeyService *service.JwtKeyService) *Jwks {
+	return &Jwks{jwtKeyService: jwtKeyService}
+}
+
+// Keys JWT 验签公钥集合(JWKS)，包含宽限期内的退役密钥
+func (c *Jwks) Keys(ctx *ichat.Context) error {
+
+	keys := make([]map[string]string, 0)
+
+	if c.jwtKeyService.IsAsymmetric() {
+		items, err := c.jwtKeyService.Keys(ctx.Ctx())
+		if err != nil {
+			return ctx.ErrorBusiness(err.Error())
+		}
+
+		for _, key := range items {
+			keys = append(keys, key.JWK())
+		}
+	}
+
+	ctx.Context.Header("Cache-Control", "public, max-age=300")
+	ctx.Context.JSON(http.StatusOK, entity.H{"keys": keys})
+
+	return nil
+}
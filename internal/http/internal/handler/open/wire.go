@@ -7,6 +7,7 @@ import (
 
 var ProviderSet = wire.NewSet(
 	v1.NewIndex,
+	v1.NewJwks,
 
 	wire.Struct(new(V1), "*"),
 )
@@ -12,6 +12,8 @@ type V1 struct {
 	Common       *v1.Common
 	Auth         *v1.Auth
 	User         *v1.User
+	Totp         *v1.Totp
+	Privacy      *v1.Privacy
 	Organize     *v1.Organize
 	Talk         *talk.Session
 	TalkMessage  *talk.Message
@@ -21,6 +23,10 @@ type V1 struct {
 	Group        *group.Group
 	GroupNotice  *group.Notice
 	GroupApply   *group.Apply
+	GroupRole    *group.Role
+	GroupInvite  *group.InviteLink
+	Channel      *v1.Channel
+	Live         *v1.Live
 	Contact      *contact.Contact
 	ContactApply *contact.Apply
 	ContactGroup *contact.Group
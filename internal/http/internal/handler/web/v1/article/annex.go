@@ -2,7 +2,6 @@ package article
 
 import (
 	"database/sql"
-	"fmt"
 	"math"
 	"net/http"
 	"time"
@@ -12,18 +11,19 @@ import (
 	"go-chat/internal/pkg/filesystem"
 	"go-chat/internal/pkg/ichat"
 	"go-chat/internal/pkg/strutil"
-	"go-chat/internal/pkg/timeutil"
 	"go-chat/internal/repository/model"
+	"go-chat/internal/service"
 	"go-chat/internal/service/note"
 )
 
 type Annex struct {
 	service    *note.ArticleAnnexService
 	fileSystem *filesystem.Filesystem
+	blob       *service.FileBlobService
 }
 
-func NewAnnex(service *note.ArticleAnnexService, fileSystem *filesystem.Filesystem) *Annex {
-	return &Annex{service, fileSystem}
+func NewAnnex(service *note.ArticleAnnexService, fileSystem *filesystem.Filesystem, blob *service.FileBlobService) *Annex {
+	return &Annex{service, fileSystem, blob}
 }
 
 // Upload 上传附件
@@ -51,21 +51,21 @@ func (c *Annex) Upload(ctx *ichat.Context) error {
 
 	ext := strutil.FileSuffix(file.Filename)
 
-	filePath := fmt.Sprintf("private/files/note/%s/%s", timeutil.DateNumber(), strutil.GenFileName(ext))
-
-	if err := c.fileSystem.Default.Write(stream, filePath); err != nil {
+	blob, err := c.blob.Store(ctx.Ctx(), stream, ext, false)
+	if err != nil {
 		return ctx.ErrorBusiness("附件上传失败")
 	}
 
 	data := &model.ArticleAnnex{
 		UserId:       ctx.UserId(),
 		ArticleId:    int(params.ArticleId),
-		Drive:        entity.FileDriveMode(c.fileSystem.Driver()),
+		Drive:        blob.Drive,
 		Suffix:       ext,
 		Size:         int(file.Size),
-		Path:         filePath,
+		Path:         blob.Path,
 		OriginalName: file.Filename,
 		Status:       1,
+		BlobId:       blob.Id,
 		DeletedAt: sql.NullTime{
 			Valid: false,
 		},
@@ -181,13 +181,16 @@ func (c *Annex) Download(ctx *ichat.Context) error {
 		return ctx.Forbidden("无权限下载")
 	}
 
-	switch info.Drive {
-	case entity.FileDriveLocal:
-		ctx.Context.FileAttachment(c.fileSystem.Local.Path(info.Path), info.OriginalName)
-	case entity.FileDriveCos:
-		ctx.Context.Redirect(http.StatusFound, c.fileSystem.Cos.PrivateUrl(info.Path, 60))
-	default:
-		return ctx.ErrorBusiness("未知文件驱动类型")
+	adapter, err := c.fileSystem.Drive(entity.FileDriveName(info.Drive))
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	// 本地存储直接输出文件，对象存储跳转至临时访问地址
+	if local, ok := adapter.(*filesystem.LocalFilesystem); ok {
+		ctx.Context.FileAttachment(local.Path(info.Path), info.OriginalName)
+	} else {
+		ctx.Context.Redirect(http.StatusFound, adapter.PrivateUrl(info.Path, 60))
 	}
 
 	return nil
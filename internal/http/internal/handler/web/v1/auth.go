@@ -1,14 +1,18 @@
 package v1
 
 import (
+	"errors"
 	"strconv"
 	"time"
 
+	"github.com/mojocn/base64Captcha"
 	"go-chat/api/pb/message/v1"
 	"go-chat/api/pb/web/v1"
 	"go-chat/internal/pkg/ichat"
 	"go-chat/internal/pkg/jwt"
+	"go-chat/internal/pkg/timeutil"
 	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
 	"go-chat/internal/service/note"
 
@@ -29,55 +33,238 @@ type Auth struct {
 	noteClassService   *note.ArticleClassService
 	robotRepo          *repo.Robot
 	message            *service.MessageService
+	userSessionService *service.UserSessionService
+	userTotpService    *service.UserTotpService
+	jwtKeyService      *service.JwtKeyService
+	captcha            *cache.CaptchaStorage
+	loginLimitService  *service.LoginLimitService
+	userOidcService    *service.UserOidcService
 }
 
-func NewAuth(config *config.Config, userService *service.UserService, smsService *service.SmsService, session *cache.TokenSessionStorage, redisLock *cache.RedisLock, talkMessageService *service.TalkMessageService, ipAddressService *service.IpAddressService, talkSessionService *service.TalkSessionService, noteClassService *note.ArticleClassService, robotDao *repo.Robot, message *service.MessageService) *Auth {
-	return &Auth{config: config, userService: userService, smsService: smsService, session: session, redisLock: redisLock, talkMessageService: talkMessageService, ipAddressService: ipAddressService, talkSessionService: talkSessionService, noteClassService: noteClassService, robotRepo: robotDao, message: message}
+func NewAuth(config *config.Config, userService *service.UserService, smsService *service.SmsService, session *cache.TokenSessionStorage, redisLock *cache.RedisLock, talkMessageService *service.TalkMessageService, ipAddressService *service.IpAddressService, talkSessionService *service.TalkSessionService, noteClassService *note.ArticleClassService, robotDao *repo.Robot, message *service.MessageService, userSessionService *service.UserSessionService, userTotpService *service.UserTotpService, jwtKeyService *service.JwtKeyService, captcha *cache.CaptchaStorage, loginLimitService *service.LoginLimitService, userOidcService *service.UserOidcService) *Auth {
+	return &Auth{config: config, userService: userService, smsService: smsService, session: session, redisLock: redisLock, talkMessageService: talkMessageService, ipAddressService: ipAddressService, talkSessionService: talkSessionService, noteClassService: noteClassService, robotRepo: robotDao, message: message, userSessionService: userSessionService, userTotpService: userTotpService, jwtKeyService: jwtKeyService, captcha: captcha, loginLimitService: loginLimitService, userOidcService: userOidcService}
+}
+
+// AuthLoginRequest 登录参数，失败次数过多后需携带图形验证码
+type AuthLoginRequest struct {
+	Mobile         string `json:"mobile" binding:"required"`
+	Password       string `json:"password" binding:"required"`
+	Platform       string `json:"platform" binding:"required,oneof=h5 ios windows mac web"`
+	Captcha        string `json:"captcha"`
+	CaptchaVoucher string `json:"captcha_voucher"`
+}
+
+// AuthCaptchaResponse 图形验证码
+type AuthCaptchaResponse struct {
+	Type    string `json:"type"`
+	Voucher string `json:"voucher"`
+	Captcha string `json:"captcha"`
+}
+
+type AuthOidcAuthorizeRequest struct {
+	Platform string `form:"platform" binding:"required,oneof=h5 ios windows mac web"`
+}
+
+type AuthOidcLoginRequest struct {
+	State string `json:"state" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
+
+// AuthTokenResponse 登录凭证
+type AuthTokenResponse struct {
+	Type             string `json:"type"`
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int32  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int32  `json:"refresh_expires_in"`
+	SessionId        string `json:"session_id"`
+}
+
+// AuthTotpChallengeResponse 两步验证登录挑战
+type AuthTotpChallengeResponse struct {
+	Type           string `json:"type"`
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int32  `json:"expires_in"`
+	SetupRequired  bool   `json:"setup_required"` // 是否需要先绑定认证器
+}
+
+type AuthTotpSetupRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+type AuthTotpVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // 验证码或恢复码
+}
+
+type AuthRefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type AuthSessionRevokeRequest struct {
+	SessionId string `json:"session_id" binding:"required"`
+}
+
+type AuthSessionItem struct {
+	SessionId    string `json:"session_id"`
+	Platform     string `json:"platform"`
+	Ip           string `json:"ip"`
+	Address      string `json:"address"`
+	Agent        string `json:"agent"`
+	IsCurrent    bool   `json:"is_current"`
+	LastActiveAt string `json:"last_active_at"`
+	CreatedAt    string `json:"created_at"`
 }
 
 // Login 登录接口
 func (c *Auth) Login(ctx *ichat.Context) error {
 
-	params := &web.AuthLoginRequest{}
+	params := &AuthLoginRequest{}
 	if err := ctx.Context.ShouldBindJSON(params); err != nil {
 		return ctx.InvalidParams(err)
 	}
 
+	limit := &service.LoginLimitOpts{
+		Scene:    service.LoginLimitSceneWeb,
+		Account:  params.Mobile,
+		Ip:       ctx.Context.ClientIP(),
+		Platform: params.Platform,
+		Agent:    ctx.Context.GetHeader("user-agent"),
+	}
+
+	state, err := c.loginLimitService.Check(ctx.Ctx(), limit)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	// 失败次数过多时需要图形验证码，未携带则下发验证码
+	if state.CaptchaRequired {
+		if params.CaptchaVoucher == "" {
+			return c.Captcha(ctx)
+		}
+
+		if !c.captcha.Verify(params.CaptchaVoucher, params.Captcha, true) {
+			return ctx.InvalidParams("验证码填写不正确")
+		}
+	}
+
 	user, err := c.userService.Login(params.Mobile, params.Password)
 	if err != nil {
+		// 密码正确但账号已禁用，不计入失败次数
+		if errors.Is(err, service.ErrUserDisabled) {
+			return ctx.ErrorBusiness(err.Error())
+		}
+
+		if info, e := c.userService.Dao().FindByMobile(params.Mobile); e == nil {
+			limit.UserId = info.Id
+		}
+
+		if state := c.loginLimitService.Fail(ctx.Ctx(), limit); state.LockTTL >= time.Minute {
+			return ctx.ErrorBusiness((&service.LoginLimitError{TTL: state.LockTTL}).Error())
+		}
+
 		return ctx.ErrorBusiness(err.Error())
 	}
 
-	root, _ := c.robotRepo.GetLoginRobot(ctx.Ctx())
-	if root != nil {
-		ip := ctx.Context.ClientIP()
+	limit.UserId = user.Id
+	c.loginLimitService.Success(ctx.Ctx(), limit)
 
-		address, _ := c.ipAddressService.FindAddress(ip)
+	return c.login(ctx, user.Id, params.Platform)
+}
 
-		_, _ = c.talkSessionService.Create(ctx.Ctx(), &service.TalkSessionCreateOpt{
-			UserId:     user.Id,
-			TalkType:   entity.ChatPrivateMode,
-			ReceiverId: root.UserId,
-			IsBoot:     true,
-		})
+// OidcAuthorize 获取单点登录授权地址
+func (c *Auth) OidcAuthorize(ctx *ichat.Context) error {
 
-		// 推送登录消息
-		_ = c.message.SendLogin(ctx.Ctx(), user.Id, &message.LoginMessageRequest{
-			Ip:       ip,
-			Address:  address,
-			Platform: params.Platform,
-			Agent:    ctx.Context.GetHeader("user-agent"),
-			Reason:   "常用设备登录",
-		})
+	params := &AuthOidcAuthorizeRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	uri, err := c.userOidcService.Authorize(ctx.Ctx(), params.Platform)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
 	}
 
-	return ctx.Success(&web.AuthLoginResponse{
-		Type:        "Bearer",
-		AccessToken: c.token(user.Id),
-		ExpiresIn:   int32(c.config.Jwt.ExpiresTime),
+	return ctx.Success(entity.H{
+		"name":          c.config.Oidc.Name,
+		"authorize_url": uri,
 	})
 }
 
+// OidcLogin 单点登录，使用 IdP 回调的授权码换取登录凭证
+func (c *Auth) OidcLogin(ctx *ichat.Context) error {
+
+	params := &AuthOidcLoginRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	user, platform, err := c.userOidcService.Login(ctx.Ctx(), params.State, params.Code)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return c.login(ctx, user.Id, platform)
+}
+
+// Captcha 登录图形验证码
+func (c *Auth) Captcha(ctx *ichat.Context) error {
+
+	captcha := base64Captcha.NewCaptcha(base64Captcha.DefaultDriverDigit, c.captcha)
+
+	voucher, base64, err := captcha.Generate()
+	if err != nil {
+		return ctx.ErrorBusiness(err)
+	}
+
+	return ctx.Success(&AuthCaptchaResponse{
+		Type:    "CAPTCHA",
+		Voucher: voucher,
+		Captcha: base64,
+	})
+}
+
+// TotpSetup 登录时绑定认证器(企业要求开启两步验证且未绑定)
+func (c *Auth) TotpSetup(ctx *ichat.Context) error {
+
+	params := &AuthTotpSetupRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	data, err := c.userTotpService.ChallengeSetup(ctx.Ctx(), params.ChallengeToken)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(data)
+}
+
+// TotpVerify 两步验证登录
+func (c *Auth) TotpVerify(ctx *ichat.Context) error {
+
+	params := &AuthTotpVerifyRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	challenge, codes, err := c.userTotpService.ChallengeVerify(ctx.Ctx(), params.ChallengeToken, params.Code)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	data, err := c.signIn(ctx, challenge.UserId, challenge.Platform)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	if len(codes) > 0 {
+		return ctx.Success(entity.H{"auth": data, "recovery_codes": codes})
+	}
+
+	return ctx.Success(data)
+}
+
 // Register 注册接口
 func (c *Auth) Register(ctx *ichat.Context) error {
 
@@ -110,19 +297,85 @@ func (c *Auth) Logout(ctx *ichat.Context) error {
 
 	c.toBlackList(ctx)
 
+	if session := ctx.JwtSession(); session != nil && session.SessionId != "" {
+		_ = c.userSessionService.Revoke(ctx.Ctx(), session.Uid, session.SessionId)
+	}
+
 	return ctx.Success(nil)
 }
 
-// Refresh Token 刷新接口
+// Refresh Token 刷新接口，使用刷新令牌换取新的登录凭证
 func (c *Auth) Refresh(ctx *ichat.Context) error {
 
-	c.toBlackList(ctx)
+	params := &AuthRefreshRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
 
-	return ctx.Success(&web.AuthRefreshResponse{
-		Type:        "Bearer",
-		AccessToken: c.token(ctx.UserId()),
-		ExpiresIn:   int32(c.config.Jwt.ExpiresTime),
-	})
+	session, refreshToken, err := c.userSessionService.Refresh(ctx.Ctx(), params.RefreshToken)
+	if err != nil {
+		return ctx.Unauthorized(err.Error())
+	}
+
+	data, err := c.authorize(ctx, session, refreshToken)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(data)
+}
+
+// Sessions 已登录设备列表
+func (c *Auth) Sessions(ctx *ichat.Context) error {
+
+	items, err := c.userSessionService.List(ctx.Ctx(), ctx.UserId())
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	current := ctx.JwtSession().SessionId
+
+	list := make([]*AuthSessionItem, 0, len(items))
+	for _, item := range items {
+		list = append(list, &AuthSessionItem{
+			SessionId:    item.SessionId,
+			Platform:     item.Platform,
+			Ip:           item.Ip,
+			Address:      item.Address,
+			Agent:        item.Agent,
+			IsCurrent:    item.SessionId == current,
+			LastActiveAt: timeutil.FormatDatetime(item.LastActiveAt),
+			CreatedAt:    timeutil.FormatDatetime(item.CreatedAt),
+		})
+	}
+
+	return ctx.Success(entity.H{"items": list})
+}
+
+// RevokeSession 注销指定设备
+func (c *Auth) RevokeSession(ctx *ichat.Context) error {
+
+	params := &AuthSessionRevokeRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.userSessionService.Revoke(ctx.Ctx(), ctx.UserId(), params.SessionId); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// RevokeOtherSessions 注销除当前设备外的所有设备
+func (c *Auth) RevokeOtherSessions(ctx *ichat.Context) error {
+
+	num, err := c.userSessionService.RevokeOthers(ctx.Ctx(), ctx.UserId(), ctx.JwtSession().SessionId)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{"num": num})
 }
 
 // Forget 账号找回接口
@@ -151,19 +404,96 @@ func (c *Auth) Forget(ctx *ichat.Context) error {
 	return ctx.Success(&web.AuthForgetResponse{})
 }
 
-func (c *Auth) token(uid int) string {
+// login 身份校验通过后，开启两步验证的账号下发验证挑战，否则直接签发登录凭证
+func (c *Auth) login(ctx *ichat.Context, uid int, platform string) error {
+
+	token, challenge, err := c.userTotpService.CreateChallenge(ctx.Ctx(), uid, platform)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	if challenge != nil {
+		return ctx.Success(&AuthTotpChallengeResponse{
+			Type:           "TOTP",
+			ChallengeToken: token,
+			ExpiresIn:      int32(5 * 60),
+			SetupRequired:  challenge.Setup,
+		})
+	}
+
+	data, err := c.signIn(ctx, uid, platform)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(data)
+}
+
+// signIn 创建设备会话并签发登录凭证
+func (c *Auth) signIn(ctx *ichat.Context, uid int, platform string) (*AuthTokenResponse, error) {
+
+	session, refreshToken, err := c.userSessionService.Create(ctx.Ctx(), &service.UserSessionCreateOpts{
+		UserId:   uid,
+		Platform: platform,
+		Ip:       ctx.Context.ClientIP(),
+		Agent:    ctx.Context.GetHeader("user-agent"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	root, _ := c.robotRepo.GetLoginRobot(ctx.Ctx())
+	if root != nil {
+		_, _ = c.talkSessionService.Create(ctx.Ctx(), &service.TalkSessionCreateOpt{
+			UserId:     uid,
+			TalkType:   entity.ChatPrivateMode,
+			ReceiverId: root.UserId,
+			IsBoot:     true,
+		})
+
+		// 推送登录消息
+		_ = c.message.SendLogin(ctx.Ctx(), uid, &message.LoginMessageRequest{
+			Ip:       session.Ip,
+			Address:  session.Address,
+			Platform: session.Platform,
+			Agent:    session.Agent,
+			Reason:   "常用设备登录",
+		})
+	}
+
+	return c.authorize(ctx, session, refreshToken)
+}
+
+// authorize 签发登录凭证
+func (c *Auth) authorize(ctx *ichat.Context, session *model.UserSession, refreshToken string) (*AuthTokenResponse, error) {
+
+	token, err := c.token(ctx, session.UserId, session.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthTokenResponse{
+		Type:             "Bearer",
+		AccessToken:      token,
+		ExpiresIn:        int32(c.config.Jwt.ExpiresTime),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int32(c.config.Jwt.GetRefreshExpiresTime()),
+		SessionId:        session.SessionId,
+	}, nil
+}
+
+func (c *Auth) token(ctx *ichat.Context, uid int, sessionId string) (string, error) {
 
 	expiresAt := time.Now().Add(time.Second * time.Duration(c.config.Jwt.ExpiresTime))
 
 	// 生成登录凭证
-	token := jwt.GenerateToken("api", c.config.Jwt.Secret, &jwt.Options{
+	return c.jwtKeyService.GenerateToken(ctx.Ctx(), "api", &jwt.Options{
 		ExpiresAt: jwt.NewNumericDate(expiresAt),
 		ID:        strconv.Itoa(uid),
+		Subject:   sessionId,
 		Issuer:    "im.web",
 		IssuedAt:  jwt.NewNumericDate(time.Now()),
 	})
-
-	return token
 }
 
 // 设置黑名单
@@ -0,0 +1,365 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/pkg/sliceutil"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/service"
+)
+
+type Channel struct {
+	service *service.ChannelService
+}
+
+func NewChannel(service *service.ChannelService) *Channel {
+	return &Channel{service: service}
+}
+
+type ChannelCreateRequest struct {
+	Name    string `json:"name" binding:"required,max=30"`
+	Avatar  string `json:"avatar" binding:"max=255"`
+	Profile string `json:"profile" binding:"max=255"`
+	IsOvert int    `json:"is_overt" binding:"oneof=0 1"`
+}
+
+type ChannelUpdateRequest struct {
+	ChannelId int    `json:"channel_id" binding:"required,min=1"`
+	Name      string `json:"name" binding:"required,max=30"`
+	Avatar    string `json:"avatar" binding:"max=255"`
+	Profile   string `json:"profile" binding:"max=255"`
+	IsOvert   int    `json:"is_overt" binding:"oneof=0 1"`
+}
+
+type ChannelIdRequest struct {
+	ChannelId int `form:"channel_id" json:"channel_id" binding:"required,min=1"`
+}
+
+type ChannelOvertListRequest struct {
+	Name string `form:"name" binding:"max=50"`
+	Page int    `form:"page" binding:"required,min=1"`
+}
+
+type ChannelAdminRequest struct {
+	ChannelId int `json:"channel_id" binding:"required,min=1"`
+	UserId    int `json:"user_id" binding:"required,min=1"`
+	Mode      int `json:"mode" binding:"oneof=0 1"` // 0:取消管理员 1:设置管理员
+}
+
+type ChannelPostRequest struct {
+	ChannelId int    `json:"channel_id" binding:"required,min=1"`
+	Content   string `json:"content" binding:"required,max=10000"`
+}
+
+type ChannelPostsRequest struct {
+	ChannelId int `form:"channel_id" binding:"required,min=1"`
+	RecordId  int `form:"record_id" binding:"min=0"`
+	Limit     int `form:"limit" binding:"required,min=1,max=100"`
+}
+
+type ChannelReactRequest struct {
+	RecordId int    `json:"record_id" binding:"required,min=1"`
+	Emoji    string `json:"emoji" binding:"required"`
+	Mode     int    `json:"mode" binding:"oneof=0 1"` // 0:取消互动 1:添加互动
+}
+
+type ChannelItem struct {
+	Id            int    `json:"id"`
+	Name          string `json:"name"`
+	Avatar        string `json:"avatar"`
+	Profile       string `json:"profile"`
+	IsOvert       int    `json:"is_overt"`
+	SubscriberNum int    `json:"subscriber_num"`
+	IsSubscribed  bool   `json:"is_subscribed"`
+	CreatedAt     string `json:"created_at"`
+}
+
+func (c *Channel) item(channel *model.Channel, subscribed bool) *ChannelItem {
+	return &ChannelItem{
+		Id:            channel.Id,
+		Name:          channel.Name,
+		Avatar:        channel.Avatar,
+		Profile:       channel.Profile,
+		IsOvert:       channel.IsOvert,
+		SubscriberNum: channel.SubscriberNum,
+		IsSubscribed:  subscribed,
+		CreatedAt:     timeutil.FormatDatetime(channel.CreatedAt),
+	}
+}
+
+// Create 创建频道
+func (c *Channel) Create(ctx *ichat.Context) error {
+
+	params := &ChannelCreateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	channel, err := c.service.Create(ctx.Ctx(), &service.ChannelCreateOpts{
+		UserId:  ctx.UserId(),
+		Name:    params.Name,
+		Avatar:  params.Avatar,
+		Profile: params.Profile,
+		IsOvert: params.IsOvert == 1,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness("创建频道失败，请稍后再试！")
+	}
+
+	return ctx.Success(entity.H{"channel_id": channel.Id})
+}
+
+// Update 修改频道信息
+func (c *Channel) Update(ctx *ichat.Context) error {
+
+	params := &ChannelUpdateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	err := c.service.Update(ctx.Ctx(), &service.ChannelUpdateOpts{
+		ChannelId: params.ChannelId,
+		UserId:    ctx.UserId(),
+		Name:      params.Name,
+		Avatar:    params.Avatar,
+		Profile:   params.Profile,
+		IsOvert:   params.IsOvert == 1,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// Dismiss 解散频道
+func (c *Channel) Dismiss(ctx *ichat.Context) error {
+
+	params := &ChannelIdRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.Dismiss(ctx.Ctx(), params.ChannelId, ctx.UserId()); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// Detail 频道详情
+func (c *Channel) Detail(ctx *ichat.Context) error {
+
+	params := &ChannelIdRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	channel, err := c.service.Find(ctx.Ctx(), params.ChannelId)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	role := -1
+	if member, err := c.service.MemberDao().FindMember(ctx.Ctx(), channel.Id, ctx.UserId()); err == nil {
+		role = member.Role
+	}
+
+	// 非公开频道仅订阅者可查看
+	if channel.IsOvert == 0 && role == -1 {
+		return ctx.ErrorBusiness(service.ErrChannelNotSubscriber.Error())
+	}
+
+	return ctx.Success(entity.H{
+		"channel": c.item(channel, role != -1),
+		"role":    role,
+	})
+}
+
+// OvertList 公开频道列表
+func (c *Channel) OvertList(ctx *ichat.Context) error {
+
+	params := &ChannelOvertListRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	list, total, err := c.service.Dao().SearchOvertList(ctx.Ctx(), params.Name, params.Page, 20)
+	if err != nil {
+		return ctx.ErrorBusiness("查询异常！")
+	}
+
+	ids := make([]int, 0, len(list))
+	for _, value := range list {
+		ids = append(ids, value.Id)
+	}
+
+	subscribed := make([]int, 0)
+	if len(ids) > 0 {
+		subscribed = c.service.MemberDao().GetSubscribedIds(ctx.Ctx(), ctx.UserId(), ids)
+	}
+
+	items := make([]*ChannelItem, 0, len(list))
+	for _, value := range list {
+		items = append(items, c.item(value, sliceutil.Include(value.Id, subscribed)))
+	}
+
+	return ctx.Success(entity.H{
+		"items": items,
+		"total": total,
+		"next":  int64(params.Page*20) < total,
+	})
+}
+
+// List 已订阅的频道列表
+func (c *Channel) List(ctx *ichat.Context) error {
+
+	list, err := c.service.Dao().UserChannels(ctx.Ctx(), ctx.UserId())
+	if err != nil {
+		return ctx.ErrorBusiness("查询异常！")
+	}
+
+	items := make([]*ChannelItem, 0, len(list))
+	for _, value := range list {
+		items = append(items, c.item(value, true))
+	}
+
+	return ctx.Success(entity.H{"items": items})
+}
+
+// Subscribe 订阅频道
+func (c *Channel) Subscribe(ctx *ichat.Context) error {
+
+	params := &ChannelIdRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.Subscribe(ctx.Ctx(), params.ChannelId, ctx.UserId()); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// Unsubscribe 退订频道
+func (c *Channel) Unsubscribe(ctx *ichat.Context) error {
+
+	params := &ChannelIdRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.Unsubscribe(ctx.Ctx(), params.ChannelId, ctx.UserId()); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// Admin 设置或取消频道管理员
+func (c *Channel) Admin(ctx *ichat.Context) error {
+
+	params := &ChannelAdminRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.SetAdmin(ctx.Ctx(), params.ChannelId, ctx.UserId(), params.UserId, params.Mode == 1); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// Open 打开频道会话
+func (c *Channel) Open(ctx *ichat.Context) error {
+
+	params := &ChannelIdRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	session, err := c.service.Open(ctx.Ctx(), params.ChannelId, ctx.UserId())
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{
+		"id":          session.Id,
+		"talk_type":   session.TalkType,
+		"receiver_id": session.ReceiverId,
+	})
+}
+
+// Post 发布频道消息
+func (c *Channel) Post(ctx *ichat.Context) error {
+
+	params := &ChannelPostRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	record, err := c.service.Post(ctx.Ctx(), &service.ChannelPostOpts{
+		ChannelId: params.ChannelId,
+		UserId:    ctx.UserId(),
+		Content:   params.Content,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{"record_id": record.Id})
+}
+
+// Posts 频道消息列表
+func (c *Channel) Posts(ctx *ichat.Context) error {
+
+	params := &ChannelPostsRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	items, err := c.service.Posts(ctx.Ctx(), &service.ChannelPostsOpts{
+		ChannelId: params.ChannelId,
+		UserId:    ctx.UserId(),
+		RecordId:  params.RecordId,
+		Limit:     params.Limit,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	recordId := params.RecordId
+	if length := len(items); length > 0 {
+		recordId = items[length-1].Id
+	}
+
+	return ctx.Success(entity.H{
+		"limit":     params.Limit,
+		"record_id": recordId,
+		"items":     items,
+	})
+}
+
+// React 频道消息互动
+func (c *Channel) React(ctx *ichat.Context) error {
+
+	params := &ChannelReactRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	err := c.service.React(ctx.Ctx(), &service.ChannelReactOpts{
+		RecordId: params.RecordId,
+		UserId:   ctx.UserId(),
+		Emoji:    params.Emoji,
+		Cancel:   params.Mode == 0,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
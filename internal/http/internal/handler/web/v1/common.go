@@ -12,10 +12,11 @@ type Common struct {
 	config      *config.Config
 	smsService  *service.SmsService
 	userService *service.UserService
+	loginLimit  *service.LoginLimitService
 }
 
-func NewCommon(config *config.Config, smsService *service.SmsService, userService *service.UserService) *Common {
-	return &Common{config: config, smsService: smsService, userService: userService}
+func NewCommon(config *config.Config, smsService *service.SmsService, userService *service.UserService, loginLimit *service.LoginLimitService) *Common {
+	return &Common{config: config, smsService: smsService, userService: userService, loginLimit: loginLimit}
 }
 
 // SmsCode 发送短信验证码
@@ -43,6 +44,11 @@ func (c *Common) SmsCode(ctx *ichat.Context) error {
 		return ctx.ErrorBusiness("发送异常！")
 	}
 
+	// 按手机号及 IP 限制发送频率
+	if err := c.loginLimit.AllowSms(ctx.Ctx(), params.Mobile, ctx.Context.ClientIP()); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
 	// 发送短信验证码
 	code, err := c.smsService.Send(ctx.Ctx(), params.Channel, params.Mobile)
 	if err != nil {
@@ -39,7 +39,7 @@ func (c *Apply) Create(ctx *ichat.Context) error {
 		return ctx.Success(nil)
 	}
 
-	if err := c.service.Create(ctx.Ctx(), &service.ContactApplyCreateOpts{
+	if _, err := c.service.Create(ctx.Ctx(), &service.ContactApplyCreateOpts{
 		UserId:   ctx.UserId(),
 		Remarks:  params.Remark,
 		FriendId: int(params.FriendId),
@@ -69,6 +69,8 @@ func (c *Apply) Accept(ctx *ichat.Context) error {
 		return ctx.ErrorBusiness(err)
 	}
 
+	_ = c.talkMessageService.SendFriendApplyMessage(ctx.Ctx(), applyInfo)
+
 	_ = c.talkMessageService.SendSysMessage(ctx.Ctx(), &service.SysTextMessageOpt{
 		UserId:     applyInfo.UserId,
 		TalkType:   entity.ChatPrivateMode,
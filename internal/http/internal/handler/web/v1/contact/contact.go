@@ -7,6 +7,7 @@ import (
 	"go-chat/api/pb/web/v1"
 	"go-chat/internal/pkg/ichat"
 	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
 	"go-chat/internal/service/organize"
 	"gorm.io/gorm"
 
@@ -17,6 +18,7 @@ import (
 
 type Contact struct {
 	service            *service.ContactService
+	groupService       *service.ContactGroupService
 	wsClient           *cache.ClientStorage
 	userService        *service.UserService
 	talkListService    *service.TalkSessionService
@@ -24,20 +26,57 @@ type Contact struct {
 	organizeService    *organize.OrganizeService
 }
 
-func NewContact(service *service.ContactService, wsClient *cache.ClientStorage, userService *service.UserService, talkListService *service.TalkSessionService, talkMessageService *service.TalkMessageService, organizeService *organize.OrganizeService) *Contact {
-	return &Contact{service: service, wsClient: wsClient, userService: userService, talkListService: talkListService, talkMessageService: talkMessageService, organizeService: organizeService}
+func NewContact(service *service.ContactService, groupService *service.ContactGroupService, wsClient *cache.ClientStorage, userService *service.UserService, talkListService *service.TalkSessionService, talkMessageService *service.TalkMessageService, organizeService *organize.OrganizeService) *Contact {
+	return &Contact{service: service, groupService: groupService, wsClient: wsClient, userService: userService, talkListService: talkListService, talkMessageService: talkMessageService, organizeService: organizeService}
+}
+
+type ContactListRequest struct {
+	GroupId *int `form:"group_id" binding:"omitempty,min=0"` // 联系人分组ID，0 为默认分组，不传时获取全部
+}
+
+type ContactEditGroupRequest struct {
+	FriendId int `json:"friend_id" binding:"required,min=1"`
+	GroupId  int `json:"group_id" binding:"min=0"` // 分组ID，0 为默认分组
 }
 
 // List 联系人列表
 func (c *Contact) List(ctx *ichat.Context) error {
 
-	list, err := c.service.List(ctx.Ctx(), ctx.UserId())
+	params := &ContactListRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	var (
+		list []*model.ContactListItem
+		err  error
+	)
+
+	if params.GroupId != nil {
+		list, err = c.service.ListByGroup(ctx.Ctx(), ctx.UserId(), *params.GroupId)
+	} else {
+		list, err = c.service.List(ctx.Ctx(), ctx.UserId())
+	}
+
 	if err != nil {
 		return ctx.ErrorBusiness(err.Error())
 	}
 
+	uids := make([]int, 0, len(list))
+	for _, item := range list {
+		uids = append(uids, item.Id)
+	}
+
+	// 隐藏在线状态的联系人
+	invisible := c.service.OnlineInvisibleIds(ctx.Ctx(), ctx.UserId(), uids)
+
 	items := make([]*web.ContactListResponse_Item, 0, len(list))
 	for _, item := range list {
+		isOnline := false
+		if !invisible[item.Id] {
+			isOnline = c.wsClient.IsOnline(ctx.Ctx(), entity.ImChannelChat, strconv.Itoa(item.Id))
+		}
+
 		items = append(items, &web.ContactListResponse_Item{
 			Id:       int32(item.Id),
 			Nickname: item.Nickname,
@@ -45,7 +84,7 @@ func (c *Contact) List(ctx *ichat.Context) error {
 			Motto:    item.Motto,
 			Avatar:   item.Avatar,
 			Remark:   item.Remark,
-			IsOnline: int32(strutil.BoolToInt(c.wsClient.IsOnline(ctx.Ctx(), entity.ImChannelChat, strconv.Itoa(item.Id)))),
+			IsOnline: int32(strutil.BoolToInt(isOnline)),
 			GroupId:  int32(item.GroupId),
 		})
 	}
@@ -91,7 +130,7 @@ func (c *Contact) Search(ctx *ichat.Context) error {
 		return ctx.InvalidParams(err)
 	}
 
-	user, err := c.userService.Dao().FindByMobile(params.Mobile)
+	user, err := c.service.SearchByMobile(ctx.Ctx(), ctx.UserId(), params.Mobile)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ctx.ErrorBusiness("用户不存在！")
@@ -170,3 +209,18 @@ func (c *Contact) Detail(ctx *ichat.Context) error {
 
 	return ctx.Success(&data)
 }
+
+// EditGroup 修改联系人分组
+func (c *Contact) EditGroup(ctx *ichat.Context) error {
+
+	params := &ContactEditGroupRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.groupService.Assign(ctx.Ctx(), ctx.UserId(), params.GroupId, []int{params.FriendId}); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
@@ -3,11 +3,21 @@ package contact
 import (
 	"go-chat/api/pb/web/v1"
 	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/pkg/sliceutil"
 	"go-chat/internal/pkg/timeutil"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/service"
 )
 
+type ContactGroupAssignRequest struct {
+	GroupId   int    `json:"group_id" binding:"min=0"` // 分组ID，0 为默认分组
+	FriendIds string `json:"friend_ids" binding:"required,ids"`
+}
+
+type ContactGroupUnassignRequest struct {
+	FriendIds string `json:"friend_ids" binding:"required,ids"`
+}
+
 type Group struct {
 	service *service.ContactGroupService
 	contact *service.ContactService
@@ -126,3 +136,33 @@ func (c *Group) Sort(ctx *ichat.Context) error {
 
 	return ctx.Success(&web.ContactGroupSortResponse{})
 }
+
+// Assign 批量移动联系人到指定分组
+func (c *Group) Assign(ctx *ichat.Context) error {
+
+	params := &ContactGroupAssignRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.Assign(ctx.Ctx(), ctx.UserId(), params.GroupId, sliceutil.ParseIds(params.FriendIds)); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// Unassign 批量将联系人移出分组
+func (c *Group) Unassign(ctx *ichat.Context) error {
+
+	params := &ContactGroupUnassignRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.Assign(ctx.Ctx(), ctx.UserId(), 0, sliceutil.ParseIds(params.FriendIds)); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
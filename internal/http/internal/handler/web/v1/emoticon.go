@@ -1,13 +1,10 @@
 package v1
 
 import (
-	"bytes"
 	"fmt"
-	"time"
 
 	"go-chat/api/pb/web/v1"
 	"go-chat/internal/pkg/ichat"
-	"go-chat/internal/pkg/utils"
 	"go-chat/internal/repository/cache"
 	"go-chat/internal/repository/model"
 
@@ -21,10 +18,11 @@ type Emoticon struct {
 	fileSystem *filesystem.Filesystem
 	service    *service.EmoticonService
 	redisLock  *cache.RedisLock
+	media      *service.MediaService
 }
 
-func NewEmoticon(fileSystem *filesystem.Filesystem, service *service.EmoticonService, redisLock *cache.RedisLock) *Emoticon {
-	return &Emoticon{fileSystem: fileSystem, service: service, redisLock: redisLock}
+func NewEmoticon(fileSystem *filesystem.Filesystem, service *service.EmoticonService, redisLock *cache.RedisLock, media *service.MediaService) *Emoticon {
+	return &Emoticon{fileSystem: fileSystem, service: service, redisLock: redisLock, media: media}
 }
 
 // CollectList 收藏列表
@@ -112,28 +110,28 @@ func (c *Emoticon) Upload(ctx *ichat.Context) error {
 		return ctx.ErrorBusiness("上传失败！")
 	}
 
-	meta := utils.ReadImageMeta(bytes.NewReader(stream))
-	ext := strutil.FileSuffix(file.Filename)
-	src := fmt.Sprintf("public/media/image/emoticon/%s/%s", time.Now().Format("20060102"), strutil.GenImageName(ext, meta.Width, meta.Height))
-	if err = c.fileSystem.Default.Write(stream, src); err != nil {
+	image, err := c.media.StoreImage(ctx.Ctx(), stream)
+	if err != nil {
 		return ctx.ErrorBusiness("上传失败！")
 	}
 
 	m := &model.EmoticonItem{
 		UserId:     ctx.UserId(),
 		Describe:   "自定义表情包",
-		Url:        c.fileSystem.Default.PublicUrl(src),
-		FileSuffix: ext,
-		FileSize:   int(file.Size),
+		Url:        image.Url,
+		FileSuffix: image.Ext,
+		FileSize:   image.Size,
+		BlobId:     image.BlobId,
 	}
 
-	if err := c.service.Db().Create(m).Error; err != nil {
+	if err := c.service.CreateCollect(ctx.Ctx(), m); err != nil {
 		return ctx.ErrorBusiness("上传失败！")
 	}
 
 	return ctx.Success(&web.EmoticonUploadResponse{
-		MediaId: int32(m.Id),
-		Src:     m.Url,
+		MediaId:    int32(m.Id),
+		Src:        m.Url,
+		Renditions: toImageRenditions(image.Renditions),
 	})
 }
 
@@ -2,40 +2,91 @@ package group
 
 import (
 	"go-chat/api/pb/web/v1"
+	"go-chat/internal/entity"
 	"go-chat/internal/pkg/ichat"
 	"go-chat/internal/pkg/logger"
 	"go-chat/internal/pkg/timeutil"
-	"go-chat/internal/repository/model"
 	"go-chat/internal/service"
 )
 
 type Apply struct {
-	applyServ  *service.GroupApplyService
-	memberServ *service.GroupMemberService
-	groupServ  *service.GroupService
+	applyServ      *service.GroupApplyService
+	memberServ     *service.GroupMemberService
+	groupServ      *service.GroupService
+	authPermission *service.AuthPermissionService
 }
 
-func NewApply(applyServ *service.GroupApplyService, memberServ *service.GroupMemberService, groupServ *service.GroupService) *Apply {
-	return &Apply{applyServ: applyServ, memberServ: memberServ, groupServ: groupServ}
+func NewApply(applyServ *service.GroupApplyService, memberServ *service.GroupMemberService, groupServ *service.GroupService, authPermission *service.AuthPermissionService) *Apply {
+	return &Apply{applyServ: applyServ, memberServ: memberServ, groupServ: groupServ, authPermission: authPermission}
 }
 
+type GroupApplyCreateRequest struct {
+	GroupId int    `form:"group_id" json:"group_id" binding:"required,min=1"`
+	Remark  string `form:"remark" json:"remark" binding:"max=255"`
+	Answer  string `form:"answer" json:"answer" binding:"max=100"` // 入群问题回答
+}
+
+type GroupApplyDeclineRequest struct {
+	ApplyId int    `json:"apply_id" binding:"required,min=1"`
+	Reason  string `json:"reason" binding:"max=255"`
+}
+
+type GroupApplyListRequest struct {
+	GroupId int  `form:"group_id" binding:"required,min=1"`
+	Status  *int `form:"status" binding:"omitempty,oneof=-1 0 1 2"` // 默认查询待审核
+}
+
+type GroupJoinSettingRequest struct {
+	GroupId int `form:"group_id" binding:"required,min=1"`
+}
+
+type GroupJoinSettingUpdateRequest struct {
+	GroupId  int    `json:"group_id" binding:"required,min=1"`
+	JoinMode int    `json:"join_mode" binding:"required,oneof=1 2 3 4"` // [1:直接加入;2:需要审核;3:仅限邀请;4:回答问题;]
+	Question string `json:"question" binding:"required_if=JoinMode 4,max=100"`
+	Answer   string `json:"answer" binding:"max=100"` // 为空时回答问题后由管理员审核
+}
+
+type GroupApplyItem struct {
+	Id         int    `json:"id"`
+	GroupId    int    `json:"group_id"`
+	UserId     int    `json:"user_id"`
+	Nickname   string `json:"nickname"`
+	Avatar     string `json:"avatar"`
+	Remark     string `json:"remark"`
+	Answer     string `json:"answer"`
+	Status     int    `json:"status"`
+	ReviewerId int    `json:"reviewer_id"`
+	Reason     string `json:"reason"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// Create 提交入群申请
 func (c *Apply) Create(ctx *ichat.Context) error {
 
-	params := &web.GroupApplyCreateRequest{}
+	params := &GroupApplyCreateRequest{}
 	if err := ctx.Context.ShouldBind(params); err != nil {
 		return ctx.InvalidParams(err)
 	}
 
-	err := c.applyServ.Insert(ctx.Ctx(), int(params.GroupId), ctx.UserId(), params.Remark)
+	apply, err := c.applyServ.Create(ctx.Ctx(), &service.GroupApplyCreateOpts{
+		GroupId: params.GroupId,
+		UserId:  ctx.UserId(),
+		Remark:  params.Remark,
+		Answer:  params.Answer,
+	})
 	if err != nil {
-		return ctx.ErrorBusiness("创建群聊失败，请稍后再试！")
+		return ctx.ErrorBusiness(err.Error())
 	}
 
-	// TODO 这里需要推送给群主
-
-	return ctx.Success(nil)
+	return ctx.Success(entity.H{
+		"apply_id": apply.Id,
+		"status":   apply.Status,
+	})
 }
 
+// Agree 同意入群申请
 func (c *Apply) Agree(ctx *ichat.Context) error {
 
 	params := &web.GroupApplyAgreeRequest{}
@@ -43,36 +94,29 @@ func (c *Apply) Agree(ctx *ichat.Context) error {
 		return ctx.InvalidParams(err)
 	}
 
-	uid := ctx.UserId()
-
-	apply := &model.GroupApply{}
-	if err := c.applyServ.Db().First(apply, params.ApplyId).Error; err != nil {
-		return ctx.ErrorBusiness("数据不存在！")
+	if err := c.applyServ.Agree(ctx.Ctx(), int(params.ApplyId), ctx.UserId()); err != nil {
+		return ctx.ErrorBusiness(err.Error())
 	}
 
-	if !c.memberServ.Dao().IsLeader(ctx.Ctx(), apply.GroupId, uid) {
-		return ctx.Forbidden("无权限访问")
-	}
+	return ctx.Success(nil)
+}
 
-	if !c.memberServ.Dao().IsMember(ctx.Ctx(), apply.GroupId, apply.UserId, false) {
-		err := c.groupServ.InviteMembers(ctx.Ctx(), &service.InviteGroupMembersOpt{
-			UserId:    uid,
-			GroupId:   apply.GroupId,
-			MemberIds: []int{apply.UserId},
-		})
-		if err != nil {
-			return ctx.ErrorBusiness("处理失败！")
-		}
+// Decline 拒绝入群申请
+func (c *Apply) Decline(ctx *ichat.Context) error {
+
+	params := &GroupApplyDeclineRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
 	}
 
-	err := c.applyServ.Db().Delete(model.GroupApply{}, "id = ?", apply.Id).Error
-	if err != nil {
-		logger.Error("数据删除失败 err", err.Error())
+	if err := c.applyServ.Decline(ctx.Ctx(), params.ApplyId, ctx.UserId(), params.Reason); err != nil {
+		return ctx.ErrorBusiness(err.Error())
 	}
 
 	return ctx.Success(nil)
 }
 
+// Delete 撤回或删除入群申请
 func (c *Apply) Delete(ctx *ichat.Context) error {
 
 	params := &web.GroupApplyDeleteRequest{}
@@ -80,43 +124,106 @@ func (c *Apply) Delete(ctx *ichat.Context) error {
 		return ctx.InvalidParams(err)
 	}
 
-	err := c.applyServ.Delete(ctx.Ctx(), int(params.ApplyId), ctx.UserId())
-	if err != nil {
-		return ctx.ErrorBusiness("创建群聊失败，请稍后再试！")
+	if err := c.applyServ.Delete(ctx.Ctx(), int(params.ApplyId), ctx.UserId()); err != nil {
+		return ctx.ErrorBusiness(err.Error())
 	}
 
 	return ctx.Success(nil)
 }
 
+// List 入群申请列表
 func (c *Apply) List(ctx *ichat.Context) error {
 
-	params := &web.GroupApplyListRequest{}
-	if err := ctx.Context.ShouldBind(params); err != nil {
+	params := &GroupApplyListRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
 		return ctx.InvalidParams(err)
 	}
 
-	if !c.memberServ.Dao().IsLeader(ctx.Ctx(), int(params.GroupId), ctx.UserId()) {
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), params.GroupId, ctx.UserId(), entity.GroupPermApply); err != nil {
 		return ctx.Forbidden("无权限访问")
 	}
 
-	list, err := c.applyServ.Dao().List(ctx.Ctx(), int(params.GroupId))
+	status := entity.GroupApplyStatusPending
+	if params.Status != nil {
+		status = *params.Status
+	}
+
+	list, err := c.applyServ.Dao().List(ctx.Ctx(), params.GroupId, status)
 	if err != nil {
 		logger.Error("[Apply List] 接口异常 err:", err.Error())
-		return ctx.ErrorBusiness("创建群聊失败，请稍后再试！")
+		return ctx.ErrorBusiness("获取入群申请失败，请稍后再试！")
 	}
 
-	items := make([]*web.GroupApplyListResponse_Item, 0)
+	items := make([]*GroupApplyItem, 0, len(list))
 	for _, item := range list {
-		items = append(items, &web.GroupApplyListResponse_Item{
-			Id:        int32(item.Id),
-			UserId:    int32(item.UserId),
-			GroupId:   int32(item.GroupId),
-			Remark:    item.Remark,
-			Avatar:    item.Avatar,
-			Nickname:  item.Nickname,
-			CreatedAt: timeutil.FormatDatetime(item.CreatedAt),
+		items = append(items, &GroupApplyItem{
+			Id:         item.Id,
+			GroupId:    item.GroupId,
+			UserId:     item.UserId,
+			Nickname:   item.Nickname,
+			Avatar:     item.Avatar,
+			Remark:     item.Remark,
+			Answer:     item.Answer,
+			Status:     item.Status,
+			ReviewerId: item.ReviewerId,
+			Reason:     item.Reason,
+			CreatedAt:  timeutil.FormatDatetime(item.CreatedAt),
+			UpdatedAt:  timeutil.FormatDatetime(item.UpdatedAt),
 		})
 	}
 
-	return ctx.Success(&web.GroupApplyListResponse{Items: items})
+	return ctx.Success(entity.H{"items": items})
+}
+
+// JoinSetting 获取入群方式，仅有审核权限的成员可查看问题答案
+func (c *Apply) JoinSetting(ctx *ichat.Context) error {
+
+	params := &GroupJoinSettingRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	group, err := c.groupServ.Dao().FindById(ctx.Ctx(), params.GroupId)
+	if err != nil || group.IsDismiss == 1 {
+		return ctx.ErrorBusiness("群组不存在！")
+	}
+
+	data := entity.H{
+		"join_mode": group.JoinMode,
+		"question":  group.Question,
+	}
+
+	if c.authPermission.GroupAuthorize(ctx.Ctx(), params.GroupId, ctx.UserId(), entity.GroupPermApply) == nil {
+		data["answer"] = group.Answer
+	}
+
+	return ctx.Success(data)
+}
+
+// UpdateJoinSetting 修改入群方式
+func (c *Apply) UpdateJoinSetting(ctx *ichat.Context) error {
+
+	params := &GroupJoinSettingUpdateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), params.GroupId, ctx.UserId(), entity.GroupPermSetting); err != nil {
+		return ctx.ErrorBusiness("无权限操作")
+	}
+
+	if params.JoinMode != entity.GroupJoinQuestion {
+		params.Question, params.Answer = "", ""
+	}
+
+	if err := c.applyServ.JoinSetting(ctx.Ctx(), &service.GroupJoinSettingOpts{
+		GroupId:  params.GroupId,
+		JoinMode: params.JoinMode,
+		Question: params.Question,
+		Answer:   params.Answer,
+	}); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
 }
@@ -1,6 +1,7 @@
 package group
 
 import (
+	"errors"
 	"fmt"
 
 	"go-chat/api/pb/web/v1"
@@ -23,25 +24,93 @@ type Group struct {
 	contactService     *service.ContactService
 	groupNoticeService *service.GroupNoticeService
 	messageService     *service.TalkMessageService
+	authPermission     *service.AuthPermissionService
+	muteService        *service.GroupMuteService
 }
 
-func NewGroup(service *service.GroupService, memberService *service.GroupMemberService, talkListService *service.TalkSessionService, userService *service.UserService, redisLock *cache.RedisLock, contactService *service.ContactService, groupNoticeService *service.GroupNoticeService, messageService *service.TalkMessageService) *Group {
-	return &Group{service: service, memberService: memberService, talkListService: talkListService, userService: userService, redisLock: redisLock, contactService: contactService, groupNoticeService: groupNoticeService, messageService: messageService}
+func NewGroup(service *service.GroupService, memberService *service.GroupMemberService, talkListService *service.TalkSessionService, userService *service.UserService, redisLock *cache.RedisLock, contactService *service.ContactService, groupNoticeService *service.GroupNoticeService, messageService *service.TalkMessageService, authPermission *service.AuthPermissionService, muteService *service.GroupMuteService) *Group {
+	return &Group{service: service, memberService: memberService, talkListService: talkListService, userService: userService, redisLock: redisLock, contactService: contactService, groupNoticeService: groupNoticeService, messageService: messageService, authPermission: authPermission, muteService: muteService}
+}
+
+type GroupRemoveMemberRequest struct {
+	GroupId        int    `form:"group_id" json:"group_id" binding:"required,min=1"`
+	MembersIds     string `form:"members_ids" json:"members_ids" binding:"required,ids"`
+	RevokeMessages int    `form:"revoke_messages" json:"revoke_messages" binding:"oneof=0 1"` // 是否撤回成员近 24 小时内的消息
+}
+
+type GroupNoSpeakRequest struct {
+	GroupId  int `form:"group_id" json:"group_id" binding:"required,min=1"`
+	UserId   int `form:"user_id" json:"user_id" binding:"required,min=1"`
+	Mode     int `form:"mode" json:"mode" binding:"required,oneof=1 2"`        // [1:禁言;2:解除禁言;]
+	Duration int `form:"duration" json:"duration" binding:"min=0,max=2592000"` // 禁言时长(单位秒)，0 为永久禁言
+}
+
+type GroupMuteRequest struct {
+	GroupId  int `form:"group_id" json:"group_id" binding:"required,min=1"`
+	Mode     int `form:"mode" json:"mode" binding:"required,oneof=1 2"`        // [1:开启全员禁言;2:关闭全员禁言;]
+	Duration int `form:"duration" json:"duration" binding:"min=0,max=2592000"` // 禁言时长(单位秒)，0 为永久禁言
+}
+
+type GroupMuteStatusRequest struct {
+	GroupId int `form:"group_id" binding:"required,min=1"`
+}
+
+type GroupUpgradeRequest struct {
+	GroupId int `json:"group_id" binding:"required,min=1"`
+}
+
+type GroupCreateRequest struct {
+	Name            string `form:"name" json:"name" binding:"required"`
+	Ids             string `form:"ids" json:"ids" binding:"ids"`
+	ContactGroupIds string `form:"contact_group_ids" json:"contact_group_ids" binding:"ids"` // 联系人分组ID，邀请分组内的所有好友
+	Avatar          string `form:"avatar" json:"avatar"`
+}
+
+type GroupBroadcastRequest struct {
+	GroupId int `json:"group_id" binding:"required,min=1"`
+	Mode    int `json:"mode" binding:"required,oneof=1 2"` // [1:仅群主及管理员可发言;2:所有成员可发言;]
+}
+
+type GroupShareHistoryRequest struct {
+	GroupId int `json:"group_id" binding:"required,min=1"`
+	Mode    int `json:"mode" binding:"required,oneof=1 2"` // [1:新成员可查看历史消息;2:新成员仅可查看入群后的消息;]
+}
+
+type GroupMemberPageRequest struct {
+	GroupId int    `form:"group_id" binding:"required,min=1"`
+	Keyword string `form:"keyword" binding:"max=30"` // 按昵称或群名片搜索
+	Page    int    `form:"page" binding:"required,min=1"`
+	Size    int    `form:"size" binding:"required,min=1,max=100"`
 }
 
 // Create 创建群聊分组
 func (c *Group) Create(ctx *ichat.Context) error {
 
-	params := &web.GroupCreateRequest{}
+	params := &GroupCreateRequest{}
 	if err := ctx.Context.ShouldBind(params); err != nil {
 		return ctx.InvalidParams(err)
 	}
 
+	if params.Ids == "" && params.ContactGroupIds == "" {
+		return ctx.InvalidParams("ids 和 contact_group_ids 不能都为空！")
+	}
+
+	uid := ctx.UserId()
+
+	memberIds := sliceutil.ParseIds(params.Ids)
+	if params.ContactGroupIds != "" {
+		memberIds = append(memberIds, c.contactService.GetGroupContactIds(ctx.Ctx(), uid, sliceutil.ParseIds(params.ContactGroupIds))...)
+	}
+
+	if len(memberIds) == 0 {
+		return ctx.ErrorBusiness("所选联系人分组中暂无好友！")
+	}
+
 	gid, err := c.service.Create(ctx.Ctx(), &service.CreateGroupOpt{
-		UserId:    ctx.UserId(),
+		UserId:    uid,
 		Name:      params.Name,
 		Avatar:    params.Avatar,
-		MemberIds: sliceutil.ParseIds(params.GetIds()),
+		MemberIds: sliceutil.Unique(memberIds),
 	})
 	if err != nil {
 		return ctx.ErrorBusiness("创建群聊失败，请稍后再试！" + err.Error())
@@ -59,21 +128,14 @@ func (c *Group) Dismiss(ctx *ichat.Context) error {
 	}
 
 	uid := ctx.UserId()
-	if !c.memberService.Dao().IsMaster(ctx.Ctx(), int(params.GroupId), uid) {
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), int(params.GroupId), uid, entity.GroupPermDismiss); err != nil {
 		return ctx.ErrorBusiness("暂无权限解散群组！")
 	}
 
-	if err := c.service.Dismiss(ctx.Ctx(), int(params.GroupId), ctx.UserId()); err != nil {
+	if err := c.service.Dismiss(ctx.Ctx(), int(params.GroupId), uid); err != nil {
 		return ctx.ErrorBusiness("群组解散失败！")
 	}
 
-	_ = c.messageService.SendSysMessage(ctx.Ctx(), &service.SysTextMessageOpt{
-		UserId:     uid,
-		TalkType:   entity.ChatGroupMode,
-		ReceiverId: int(params.GroupId),
-		Text:       "群组已被群主或管理员解散！",
-	})
-
 	return ctx.Success(nil)
 }
 
@@ -99,8 +161,12 @@ func (c *Group) Invite(ctx *ichat.Context) error {
 		return ctx.ErrorBusiness("邀请好友列表不能为空！")
 	}
 
-	if !c.memberService.Dao().IsMember(ctx.Ctx(), int(params.GroupId), uid, true) {
-		return ctx.ErrorBusiness("非群组成员，无权邀请好友！")
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), int(params.GroupId), uid, entity.GroupPermInvite); err != nil {
+		if errors.Is(err, service.ErrGroupNotMember) {
+			return ctx.ErrorBusiness("非群组成员，无权邀请好友！")
+		}
+
+		return ctx.ErrorBusiness(err.Error())
 	}
 
 	if err := c.service.InviteMembers(ctx.Ctx(), &service.InviteGroupMembersOpt{
@@ -143,7 +209,7 @@ func (c *Group) Setting(ctx *ichat.Context) error {
 	}
 
 	uid := ctx.UserId()
-	if !c.memberService.Dao().IsLeader(ctx.Ctx(), int(params.GroupId), uid) {
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), int(params.GroupId), uid, entity.GroupPermSetting); err != nil {
 		return ctx.ErrorBusiness("无权限操作")
 	}
 
@@ -169,21 +235,30 @@ func (c *Group) Setting(ctx *ichat.Context) error {
 // RemoveMembers 移除指定成员(群组&管理员权限)
 func (c *Group) RemoveMembers(ctx *ichat.Context) error {
 
-	params := &web.GroupRemoveMemberRequest{}
+	params := &GroupRemoveMemberRequest{}
 	if err := ctx.Context.ShouldBind(params); err != nil {
 		return ctx.InvalidParams(err)
 	}
 
 	uid := ctx.UserId()
+	memberIds := sliceutil.ParseIds(params.MembersIds)
 
-	if !c.memberService.Dao().IsLeader(ctx.Ctx(), int(params.GroupId), uid) {
+	if err := c.authPermission.GroupAuthorizeMember(ctx.Ctx(), params.GroupId, uid, entity.GroupPermRemove, memberIds...); err != nil {
 		return ctx.ErrorBusiness("无权限操作")
 	}
 
+	// 同时撤回成员近期消息需要撤回权限
+	if params.RevokeMessages == 1 {
+		if err := c.authPermission.GroupAuthorizeMember(ctx.Ctx(), params.GroupId, uid, entity.GroupPermRevoke, memberIds...); err != nil {
+			return ctx.ErrorBusiness("无权限撤回成员消息")
+		}
+	}
+
 	err := c.service.RemoveMembers(ctx.Ctx(), &service.RemoveMembersOpt{
-		UserId:    uid,
-		GroupId:   int(params.GroupId),
-		MemberIds: sliceutil.ParseIds(params.MembersIds),
+		UserId:       uid,
+		GroupId:      params.GroupId,
+		MemberIds:    memberIds,
+		RevokeRecent: params.RevokeMessages == 1,
 	})
 
 	if err != nil {
@@ -316,9 +391,119 @@ func (c *Group) Members(ctx *ichat.Context) error {
 		return ctx.ErrorBusiness("非群成员无权查看成员列表！")
 	}
 
+	group, err := c.service.Dao().FindById(ctx.Ctx(), int(params.GroupId))
+	if err != nil {
+		return ctx.ErrorBusiness("群组不存在！")
+	}
+
+	// 超级群成员较多，仅返回首页成员，完整列表请使用分页接口
+	if group.IsSuper == 1 {
+		items, _, err := c.memberService.Dao().Paginate(ctx.Ctx(), group.Id, "", 1, model.GroupMemberMaxNum)
+		if err != nil {
+			return ctx.ErrorBusiness("获取群成员列表失败！")
+		}
+
+		return ctx.Success(items)
+	}
+
 	return ctx.Success(c.memberService.Dao().GetMembers(ctx.Ctx(), int(params.GroupId)))
 }
 
+// MemberPage 群成员分页列表
+func (c *Group) MemberPage(ctx *ichat.Context) error {
+
+	params := &GroupMemberPageRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if !c.memberService.Dao().IsMember(ctx.Ctx(), params.GroupId, ctx.UserId(), true) {
+		return ctx.ErrorBusiness("非群成员无权查看成员列表！")
+	}
+
+	items, total, err := c.memberService.Dao().Paginate(ctx.Ctx(), params.GroupId, params.Keyword, params.Page, params.Size)
+	if err != nil {
+		return ctx.ErrorBusiness("获取群成员列表失败！")
+	}
+
+	return ctx.Success(entity.H{
+		"items": items,
+		"total": total,
+		"page":  params.Page,
+		"size":  params.Size,
+	})
+}
+
+// Upgrade 升级为超级群(群主权限)
+func (c *Group) Upgrade(ctx *ichat.Context) error {
+
+	params := &GroupUpgradeRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), params.GroupId, ctx.UserId(), entity.GroupPermSetting); err != nil {
+		return ctx.ErrorBusiness("暂无权限！")
+	}
+
+	if err := c.service.Upgrade(ctx.Ctx(), params.GroupId); err != nil {
+		return ctx.ErrorBusiness("升级超级群失败！")
+	}
+
+	return ctx.Success(entity.H{"max_num": model.SuperGroupMemberMaxNum})
+}
+
+// Broadcast 设置是否仅群主及管理员可发言
+func (c *Group) Broadcast(ctx *ichat.Context) error {
+
+	params := &GroupBroadcastRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	uid := ctx.UserId()
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), params.GroupId, uid, entity.GroupPermSetting); err != nil {
+		return ctx.ErrorBusiness("暂无权限！")
+	}
+
+	if err := c.service.SetBroadcast(ctx.Ctx(), params.GroupId, params.Mode == 1); err != nil {
+		return ctx.ErrorBusiness("设置发言权限失败！")
+	}
+
+	text := "群主或管理员开启了仅管理员可发言"
+	if params.Mode == 2 {
+		text = "群主或管理员关闭了仅管理员可发言"
+	}
+
+	_ = c.messageService.SendSysMessage(ctx.Ctx(), &service.SysTextMessageOpt{
+		UserId:     uid,
+		TalkType:   entity.ChatGroupMode,
+		ReceiverId: params.GroupId,
+		Text:       text,
+	})
+
+	return ctx.Success(nil)
+}
+
+// ShareHistory 设置新成员是否可查看入群前的历史消息
+func (c *Group) ShareHistory(ctx *ichat.Context) error {
+
+	params := &GroupShareHistoryRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), params.GroupId, ctx.UserId(), entity.GroupPermSetting); err != nil {
+		return ctx.ErrorBusiness("暂无权限！")
+	}
+
+	if err := c.service.SetShareHistory(ctx.Ctx(), params.GroupId, params.Mode == 1); err != nil {
+		return ctx.ErrorBusiness("设置历史消息权限失败！")
+	}
+
+	return ctx.Success(nil)
+}
+
 // OvertList 公开群列表
 func (c *Group) OvertList(ctx *ichat.Context) error {
 
@@ -391,7 +576,7 @@ func (c *Group) Handover(ctx *ichat.Context) error {
 	}
 
 	uid := ctx.UserId()
-	if !c.memberService.Dao().IsMaster(ctx.Ctx(), int(params.GroupId), uid) {
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), int(params.GroupId), uid, entity.GroupPermHandover); err != nil {
 		return ctx.ErrorBusiness("暂无权限！")
 	}
 
@@ -399,7 +584,7 @@ func (c *Group) Handover(ctx *ichat.Context) error {
 		return ctx.ErrorBusiness("暂无权限！")
 	}
 
-	err := c.memberService.Handover(ctx.Ctx(), int(params.GroupId), uid, int(params.UserId))
+	err := c.service.Handover(ctx.Ctx(), int(params.GroupId), uid, int(params.UserId))
 	if err != nil {
 		return ctx.ErrorBusiness("转让群主失败！")
 	}
@@ -416,7 +601,7 @@ func (c *Group) AssignAdmin(ctx *ichat.Context) error {
 	}
 
 	uid := ctx.UserId()
-	if !c.memberService.Dao().IsMaster(ctx.Ctx(), int(params.GroupId), uid) {
+	if err := c.authPermission.GroupAuthorizeMember(ctx.Ctx(), int(params.GroupId), uid, entity.GroupPermRole, int(params.UserId)); err != nil {
 		return ctx.ErrorBusiness("暂无权限！")
 	}
 
@@ -437,25 +622,88 @@ func (c *Group) AssignAdmin(ctx *ichat.Context) error {
 // NoSpeak 禁止发言
 func (c *Group) NoSpeak(ctx *ichat.Context) error {
 
-	params := &web.GroupNoSpeakRequest{}
+	params := &GroupNoSpeakRequest{}
 	if err := ctx.Context.ShouldBind(params); err != nil {
 		return ctx.InvalidParams(err)
 	}
 
 	uid := ctx.UserId()
-	if !c.memberService.Dao().IsLeader(ctx.Ctx(), int(params.GroupId), uid) {
+	if err := c.authPermission.GroupAuthorizeMember(ctx.Ctx(), params.GroupId, uid, entity.GroupPermMute, params.UserId); err != nil {
 		return ctx.ErrorBusiness("暂无权限！")
 	}
 
-	status := 1
-	if params.Mode == 2 {
-		status = 0
+	err := c.muteService.Mute(ctx.Ctx(), &service.GroupMuteOpts{
+		GroupId:  params.GroupId,
+		UserId:   uid,
+		MemberId: params.UserId,
+		Mute:     params.Mode == 1,
+		Duration: params.Duration,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness("设置群成员禁言状态失败！")
+	}
+
+	return ctx.Success(nil)
+}
+
+// Mute 开启或关闭全员禁言
+func (c *Group) Mute(ctx *ichat.Context) error {
+
+	params := &GroupMuteRequest{}
+	if err := ctx.Context.ShouldBind(params); err != nil {
+		return ctx.InvalidParams(err)
 	}
 
-	err := c.memberService.UpdateMuteStatus(int(params.GroupId), int(params.UserId), status)
+	uid := ctx.UserId()
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), params.GroupId, uid, entity.GroupPermMute); err != nil {
+		return ctx.ErrorBusiness("暂无权限！")
+	}
+
+	err := c.muteService.Mute(ctx.Ctx(), &service.GroupMuteOpts{
+		GroupId:  params.GroupId,
+		UserId:   uid,
+		Mute:     params.Mode == 1,
+		Duration: params.Duration,
+	})
 	if err != nil {
-		return ctx.ErrorBusiness("设置群成员禁言状态失败！")
+		return ctx.ErrorBusiness("设置全员禁言状态失败！")
 	}
 
 	return ctx.Success(nil)
 }
+
+// MuteStatus 获取全员禁言及当前用户的禁言状态
+func (c *Group) MuteStatus(ctx *ichat.Context) error {
+
+	params := &GroupMuteStatusRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	group, err := c.service.Dao().FindById(ctx.Ctx(), params.GroupId)
+	if err != nil {
+		return ctx.ErrorBusiness("群组不存在！")
+	}
+
+	member, err := c.memberService.Dao().FindByWhere(ctx.Ctx(), "group_id = ? and user_id = ? and is_quit = 0", params.GroupId, ctx.UserId())
+	if err != nil {
+		return ctx.ErrorBusiness("暂无权限！")
+	}
+
+	data := entity.H{
+		"is_mute":           group.IsMuted(),
+		"mute_until":        "",
+		"member_is_mute":    member.IsMuted(),
+		"member_mute_until": "",
+	}
+
+	if group.IsMuted() && group.MuteUntil != nil {
+		data["mute_until"] = timeutil.FormatDatetime(*group.MuteUntil)
+	}
+
+	if member.IsMuted() && member.MuteUntil != nil {
+		data["member_mute_until"] = timeutil.FormatDatetime(*member.MuteUntil)
+	}
+
+	return ctx.Success(data)
+}
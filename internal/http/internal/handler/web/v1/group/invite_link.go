@@ -0,0 +1,155 @@
+package group
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/service"
+)
+
+type InviteLink struct {
+	service *service.GroupInviteLinkService
+}
+
+func NewInviteLink(service *service.GroupInviteLinkService) *InviteLink {
+	return &InviteLink{service: service}
+}
+
+type GroupInviteLinkCreateRequest struct {
+	GroupId      int `json:"group_id" binding:"required,min=1"`
+	ExpireSecond int `json:"expire_second" binding:"min=0,max=31536000"` // 有效时长(单位秒)，0 为永久有效
+	MaxUses      int `json:"max_uses" binding:"min=0,max=10000"`         // 最大使用次数，0 为不限
+	NeedApproval int `json:"need_approval" binding:"oneof=0 1"`          // 入群是否需要审核
+}
+
+type GroupInviteLinkListRequest struct {
+	GroupId int `form:"group_id" binding:"required,min=1"`
+}
+
+type GroupInviteLinkRevokeRequest struct {
+	LinkId int `json:"link_id" binding:"required,min=1"`
+}
+
+type GroupInviteTokenRequest struct {
+	Token string `form:"token" json:"token" binding:"required,max=64"`
+}
+
+type GroupInviteLinkItem struct {
+	Id           int    `json:"id"`
+	Token        string `json:"token"`
+	CreatorId    int    `json:"creator_id"`
+	Nickname     string `json:"nickname"`
+	MaxUses      int    `json:"max_uses"`
+	UsedNum      int    `json:"used_num"`
+	NeedApproval int    `json:"need_approval"`
+	ExpiredAt    string `json:"expired_at"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// Create 创建群邀请链接
+func (c *InviteLink) Create(ctx *ichat.Context) error {
+
+	params := &GroupInviteLinkCreateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	link, err := c.service.Create(ctx.Ctx(), &service.GroupInviteLinkCreateOpts{
+		GroupId:      params.GroupId,
+		UserId:       ctx.UserId(),
+		ExpireSecond: params.ExpireSecond,
+		MaxUses:      params.MaxUses,
+		NeedApproval: params.NeedApproval == 1,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{
+		"id":    link.Id,
+		"token": c.service.Token(link),
+	})
+}
+
+// List 群邀请链接列表
+func (c *InviteLink) List(ctx *ichat.Context) error {
+
+	params := &GroupInviteLinkListRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	list, err := c.service.List(ctx.Ctx(), params.GroupId, ctx.UserId())
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	items := make([]*GroupInviteLinkItem, 0, len(list))
+	for _, item := range list {
+		data := &GroupInviteLinkItem{
+			Id:           item.Id,
+			Token:        c.service.Token(&item.GroupInviteLink),
+			CreatorId:    item.CreatorId,
+			Nickname:     item.Nickname,
+			MaxUses:      item.MaxUses,
+			UsedNum:      item.UsedNum,
+			NeedApproval: item.NeedApproval,
+			CreatedAt:    timeutil.FormatDatetime(item.CreatedAt),
+		}
+
+		if item.ExpiredAt != nil {
+			data.ExpiredAt = timeutil.FormatDatetime(*item.ExpiredAt)
+		}
+
+		items = append(items, data)
+	}
+
+	return ctx.Success(entity.H{"items": items})
+}
+
+// Revoke 作废群邀请链接
+func (c *InviteLink) Revoke(ctx *ichat.Context) error {
+
+	params := &GroupInviteLinkRevokeRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.Revoke(ctx.Ctx(), params.LinkId, ctx.UserId()); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// Preview 预览邀请链接的群信息
+func (c *InviteLink) Preview(ctx *ichat.Context) error {
+
+	params := &GroupInviteTokenRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	info, err := c.service.Preview(ctx.Ctx(), params.Token)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(info)
+}
+
+// Join 通过邀请链接加入群聊
+func (c *InviteLink) Join(ctx *ichat.Context) error {
+
+	params := &GroupInviteTokenRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	status, err := c.service.Redeem(ctx.Ctx(), params.Token, ctx.UserId())
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{"status": status})
+}
@@ -2,19 +2,26 @@ package group
 
 import (
 	"go-chat/api/pb/web/v1"
+	"go-chat/internal/entity"
 	"go-chat/internal/pkg/ichat"
 	"go-chat/internal/pkg/timeutil"
 
 	"go-chat/internal/service"
 )
 
+type GroupNoticeConfirmRequest struct {
+	GroupId  int `form:"group_id" json:"group_id" binding:"required,min=1"`
+	NoticeId int `form:"notice_id" json:"notice_id" binding:"required,min=1"`
+}
+
 type Notice struct {
-	service *service.GroupNoticeService
-	member  *service.GroupMemberService
+	service        *service.GroupNoticeService
+	member         *service.GroupMemberService
+	authPermission *service.AuthPermissionService
 }
 
-func NewNotice(service *service.GroupNoticeService, member *service.GroupMemberService) *Notice {
-	return &Notice{service: service, member: member}
+func NewNotice(service *service.GroupNoticeService, member *service.GroupMemberService, authPermission *service.AuthPermissionService) *Notice {
+	return &Notice{service: service, member: member, authPermission: authPermission}
 }
 
 // CreateAndUpdate 添加或编辑群公告
@@ -32,10 +39,27 @@ func (c *Notice) CreateAndUpdate(ctx *ichat.Context) error {
 
 	uid := ctx.UserId()
 
-	if !c.member.Dao().IsLeader(ctx.Ctx(), int(params.GroupId), uid) {
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), int(params.GroupId), uid, entity.GroupPermNotice); err != nil {
 		return ctx.ErrorBusiness("无权限操作")
 	}
 
+	// 修改置顶状态需要置顶权限
+	isTop := 0
+	if params.NoticeId > 0 {
+		notice, err := c.service.Dao().FindById(ctx.Ctx(), int(params.NoticeId))
+		if err != nil || notice.GroupId != int(params.GroupId) {
+			return ctx.ErrorBusiness("群公告不存在！")
+		}
+
+		isTop = notice.IsTop
+	}
+
+	if int(params.IsTop) != isTop {
+		if err := c.authPermission.GroupAuthorize(ctx.Ctx(), int(params.GroupId), uid, entity.GroupPermPin); err != nil {
+			return ctx.ErrorBusiness("无权限置顶群公告")
+		}
+	}
+
 	if params.NoticeId == 0 {
 		err = c.service.Create(ctx.Ctx(), &service.GroupNoticeEditOpt{
 			UserId:    uid,
@@ -49,6 +73,7 @@ func (c *Notice) CreateAndUpdate(ctx *ichat.Context) error {
 		msg = "添加群公告成功！"
 	} else {
 		err = c.service.Update(ctx.Ctx(), &service.GroupNoticeEditOpt{
+			UserId:    uid,
 			GroupId:   int(params.GroupId),
 			NoticeId:  int(params.NoticeId),
 			Title:     params.Title,
@@ -74,6 +99,10 @@ func (c *Notice) Delete(ctx *ichat.Context) error {
 		return ctx.InvalidParams(err)
 	}
 
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), int(params.GroupId), ctx.UserId(), entity.GroupPermNotice); err != nil {
+		return ctx.ErrorBusiness("无权限操作")
+	}
+
 	if err := c.service.Delete(ctx.Ctx(), int(params.GroupId), int(params.NoticeId)); err != nil {
 		return ctx.ErrorBusiness(err.Error())
 	}
@@ -116,3 +145,45 @@ func (c *Notice) List(ctx *ichat.Context) error {
 		Items: rows,
 	})
 }
+
+// Confirm 确认群公告
+func (c *Notice) Confirm(ctx *ichat.Context) error {
+
+	params := &GroupNoticeConfirmRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if !c.member.Dao().IsMember(ctx.Ctx(), params.GroupId, ctx.UserId(), true) {
+		return ctx.ErrorBusiness("暂无权限操作！")
+	}
+
+	if err := c.service.Confirm(ctx.Ctx(), params.GroupId, params.NoticeId, ctx.UserId()); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil, "群公告已确认！")
+}
+
+// ConfirmList 群公告确认情况
+func (c *Notice) ConfirmList(ctx *ichat.Context) error {
+
+	params := &GroupNoticeConfirmRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), params.GroupId, ctx.UserId(), entity.GroupPermNotice); err != nil {
+		return ctx.ErrorBusiness("无权限操作")
+	}
+
+	confirmed, unconfirmed, err := c.service.ConfirmList(ctx.Ctx(), params.GroupId, params.NoticeId)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{
+		"confirmed":   confirmed,
+		"unconfirmed": unconfirmed,
+	})
+}
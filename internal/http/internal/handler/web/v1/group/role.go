@@ -0,0 +1,188 @@
+package group
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/service"
+)
+
+type Role struct {
+	service        *service.GroupRoleService
+	authPermission *service.AuthPermissionService
+}
+
+func NewRole(service *service.GroupRoleService, authPermission *service.AuthPermissionService) *Role {
+	return &Role{service: service, authPermission: authPermission}
+}
+
+type GroupRoleListRequest struct {
+	GroupId int `form:"group_id" binding:"required,min=1"`
+}
+
+type GroupRoleCreateRequest struct {
+	GroupId     int      `json:"group_id" binding:"required,min=1"`
+	Name        string   `json:"name" binding:"required,max=20"`
+	Permissions []string `json:"permissions" binding:"max=20"`
+}
+
+type GroupRoleUpdateRequest struct {
+	GroupId     int      `json:"group_id" binding:"required,min=1"`
+	RoleId      int      `json:"role_id" binding:"required,min=1"`
+	Name        string   `json:"name" binding:"required,max=20"`
+	Permissions []string `json:"permissions" binding:"max=20"`
+}
+
+type GroupRoleDeleteRequest struct {
+	GroupId int `json:"group_id" binding:"required,min=1"`
+	RoleId  int `json:"role_id" binding:"required,min=1"`
+}
+
+type GroupRoleAssignRequest struct {
+	GroupId int   `json:"group_id" binding:"required,min=1"`
+	RoleId  int   `json:"role_id" binding:"min=0"` // 为 0 时取消成员的自定义角色
+	UserIds []int `json:"user_ids" binding:"required,min=1,max=100"`
+}
+
+type GroupRoleItem struct {
+	Id          int      `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// List 群组自定义角色列表
+func (c *Role) List(ctx *ichat.Context) error {
+
+	params := &GroupRoleListRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if _, _, err := c.authPermission.GroupPermissions(ctx.Ctx(), params.GroupId, ctx.UserId()); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	list, err := c.service.Dao().List(ctx.Ctx(), params.GroupId)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	items := make([]*GroupRoleItem, 0, len(list))
+	for _, item := range list {
+		items = append(items, &GroupRoleItem{
+			Id:          item.Id,
+			Name:        item.Name,
+			Permissions: item.PermissionList(),
+			CreatedAt:   timeutil.FormatDatetime(item.CreatedAt),
+		})
+	}
+
+	return ctx.Success(entity.H{"items": items, "permissions": entity.GroupGrantablePerms})
+}
+
+// Permissions 当前用户在群组内拥有的权限
+func (c *Role) Permissions(ctx *ichat.Context) error {
+
+	params := &GroupRoleListRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	member, perms, err := c.authPermission.GroupPermissions(ctx.Ctx(), params.GroupId, ctx.UserId())
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{
+		"leader":      member.Leader,
+		"role_id":     member.RoleId,
+		"permissions": perms,
+	})
+}
+
+// Create 创建自定义角色
+func (c *Role) Create(ctx *ichat.Context) error {
+
+	params := &GroupRoleCreateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), params.GroupId, ctx.UserId(), entity.GroupPermRole); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	role, err := c.service.Create(ctx.Ctx(), &service.GroupRoleEditOpt{
+		GroupId:     params.GroupId,
+		Name:        params.Name,
+		Permissions: params.Permissions,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{"role_id": role.Id})
+}
+
+// Update 修改自定义角色
+func (c *Role) Update(ctx *ichat.Context) error {
+
+	params := &GroupRoleUpdateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), params.GroupId, ctx.UserId(), entity.GroupPermRole); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	if err := c.service.Update(ctx.Ctx(), &service.GroupRoleEditOpt{
+		GroupId:     params.GroupId,
+		RoleId:      params.RoleId,
+		Name:        params.Name,
+		Permissions: params.Permissions,
+	}); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// Delete 删除自定义角色
+func (c *Role) Delete(ctx *ichat.Context) error {
+
+	params := &GroupRoleDeleteRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.authPermission.GroupAuthorize(ctx.Ctx(), params.GroupId, ctx.UserId(), entity.GroupPermRole); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	if err := c.service.Delete(ctx.Ctx(), params.GroupId, params.RoleId); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// Assign 设置群成员的自定义角色
+func (c *Role) Assign(ctx *ichat.Context) error {
+
+	params := &GroupRoleAssignRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.authPermission.GroupAuthorizeMember(ctx.Ctx(), params.GroupId, ctx.UserId(), entity.GroupPermRole, params.UserIds...); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	if err := c.service.Assign(ctx.Ctx(), params.GroupId, params.RoleId, params.UserIds); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
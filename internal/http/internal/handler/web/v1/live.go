@@ -0,0 +1,206 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+)
+
+type Live struct {
+	service *service.LiveRoomService
+}
+
+func NewLive(service *service.LiveRoomService) *Live {
+	return &Live{service: service}
+}
+
+type LiveCreateRequest struct {
+	Name     string `json:"name" binding:"required,max=50"`
+	SlowMode int    `json:"slow_mode" binding:"min=0,max=3600"` // 慢速模式发言间隔(单位秒)
+}
+
+type LiveRoomRequest struct {
+	Room string `form:"room" json:"room" binding:"required,max=32"`
+}
+
+type LiveHistoryRequest struct {
+	Room  string `form:"room" binding:"required,max=32"`
+	Limit int    `form:"limit" binding:"required,min=1,max=200"`
+}
+
+type LiveGuestTokenRequest struct {
+	Room     string `json:"room" binding:"required,max=32"`
+	Nickname string `json:"nickname" binding:"required,max=20"`
+}
+
+type LiveSlowModeRequest struct {
+	Room     string `json:"room" binding:"required,max=32"`
+	SlowMode int    `json:"slow_mode" binding:"min=0,max=3600"`
+}
+
+type LiveModeratorRequest struct {
+	Room   string `json:"room" binding:"required,max=32"`
+	UserId int    `json:"user_id" binding:"required,min=1"`
+	Mode   int    `json:"mode" binding:"oneof=0 1"` // 0:取消管理员 1:设置管理员
+}
+
+type LiveMessageDeleteRequest struct {
+	Room  string `json:"room" binding:"required,max=32"`
+	MsgId string `json:"msg_id" binding:"required"`
+}
+
+type LiveBanRequest struct {
+	Room     string `json:"room" binding:"required,max=32"`
+	MemberId string `json:"member_id" binding:"required"`
+}
+
+// Create 创建聊天室
+func (c *Live) Create(ctx *ichat.Context) error {
+
+	params := &LiveCreateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	room, err := c.service.Create(ctx.Ctx(), &service.LiveRoomCreateOpts{
+		UserId:   ctx.UserId(),
+		Name:     params.Name,
+		SlowMode: params.SlowMode,
+	})
+	if err != nil {
+		return ctx.ErrorBusiness("创建聊天室失败，请稍后再试！")
+	}
+
+	return ctx.Success(room)
+}
+
+// Detail 聊天室详情
+func (c *Live) Detail(ctx *ichat.Context) error {
+
+	params := &LiveRoomRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	room, err := c.service.Storage().Get(ctx.Ctx(), params.Room)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{
+		"room":       room,
+		"online":     c.service.Storage().Online(ctx.Ctx(), params.Room),
+		"moderators": c.service.Storage().Moderators(ctx.Ctx(), params.Room),
+	})
+}
+
+// History 聊天室最近消息
+func (c *Live) History(ctx *ichat.Context) error {
+
+	params := &LiveHistoryRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if _, err := c.service.Storage().Get(ctx.Ctx(), params.Room); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{
+		"items": c.service.Storage().History(ctx.Ctx(), params.Room, params.Limit),
+	})
+}
+
+// GuestToken 获取游客令牌
+func (c *Live) GuestToken(ctx *ichat.Context) error {
+
+	params := &LiveGuestTokenRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	token, err := c.service.GuestToken(ctx.Ctx(), params.Room, params.Nickname)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{
+		"type":         "Bearer",
+		"access_token": token,
+	})
+}
+
+// Close 关闭聊天室
+func (c *Live) Close(ctx *ichat.Context) error {
+
+	params := &LiveRoomRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.Close(ctx.Ctx(), params.Room, ctx.UserId()); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// SlowMode 设置慢速模式
+func (c *Live) SlowMode(ctx *ichat.Context) error {
+
+	params := &LiveSlowModeRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.SetSlowMode(ctx.Ctx(), params.Room, ctx.UserId(), params.SlowMode); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// Moderator 设置或取消管理员
+func (c *Live) Moderator(ctx *ichat.Context) error {
+
+	params := &LiveModeratorRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.SetModerator(ctx.Ctx(), params.Room, ctx.UserId(), params.UserId, params.Mode == 1); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// DeleteMessage 删除聊天室消息
+func (c *Live) DeleteMessage(ctx *ichat.Context) error {
+
+	params := &LiveMessageDeleteRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.DeleteMessage(ctx.Ctx(), params.Room, ctx.UserId(), params.MsgId); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// Ban 禁止成员发言
+func (c *Live) Ban(ctx *ichat.Context) error {
+
+	params := &LiveBanRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.Ban(ctx.Ctx(), params.Room, ctx.UserId(), params.MemberId); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+)
+
+type Privacy struct {
+	service *service.UserPrivacyService
+}
+
+func NewPrivacy(service *service.UserPrivacyService) *Privacy {
+	return &Privacy{service: service}
+}
+
+type PrivacyUpdateRequest struct {
+	AddContact   int `json:"add_contact" binding:"oneof=0 1 2"` // 谁可以添加我[0:所有人;1:仅企业成员;2:不允许;]
+	OnlineStatus int `json:"online_status" binding:"oneof=0 1"` // 在线状态可见范围[0:联系人可见;1:不可见;]
+	MobileSearch int `json:"mobile_search" binding:"oneof=0 1"` // 通过手机号搜索[0:允许;1:不允许;]
+}
+
+type BlockRequest struct {
+	UserId int `json:"user_id" binding:"required,min=1"`
+}
+
+// Setting 获取隐私设置
+func (c *Privacy) Setting(ctx *ichat.Context) error {
+
+	privacy := c.service.Get(ctx.Ctx(), ctx.UserId())
+
+	return ctx.Success(entity.H{
+		"add_contact":   privacy.AddContact,
+		"online_status": privacy.OnlineStatus,
+		"mobile_search": privacy.MobileSearch,
+	})
+}
+
+// Update 修改隐私设置
+func (c *Privacy) Update(ctx *ichat.Context) error {
+
+	params := &PrivacyUpdateRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.Update(ctx.Ctx(), ctx.UserId(), &service.UserPrivacyUpdateOpts{
+		AddContact:   params.AddContact,
+		OnlineStatus: params.OnlineStatus,
+		MobileSearch: params.MobileSearch,
+	}); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// BlockList 黑名单列表
+func (c *Privacy) BlockList(ctx *ichat.Context) error {
+
+	items, err := c.service.BlockList(ctx.Ctx(), ctx.UserId())
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{"items": items})
+}
+
+// Block 加入黑名单
+func (c *Privacy) Block(ctx *ichat.Context) error {
+
+	params := &BlockRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.Block(ctx.Ctx(), ctx.UserId(), params.UserId); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// Unblock 移出黑名单
+func (c *Privacy) Unblock(ctx *ichat.Context) error {
+
+	params := &BlockRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.Unblock(ctx.Ctx(), ctx.UserId(), params.UserId); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
@@ -1,14 +1,9 @@
 package talk
 
 import (
-	"errors"
-
 	"go-chat/api/pb/message/v1"
 	"go-chat/internal/pkg/ichat"
-	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
-	"go-chat/internal/service/organize"
-	"gorm.io/gorm"
 
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/sliceutil"
@@ -23,13 +18,12 @@ type Message struct {
 	splitUploadService *service.SplitUploadService
 	contactService     *service.ContactService
 	groupMemberService *service.GroupMemberService
-	organizeService    *organize.OrganizeService
 	auth               *service.TalkAuthService
 	message            *service.MessageService
 }
 
-func NewMessage(service *service.TalkMessageService, talkService *service.TalkService, talkRecordsVoteDao *repo.TalkRecordsVote, splitUploadService *service.SplitUploadService, contactService *service.ContactService, groupMemberService *service.GroupMemberService, organizeService *organize.OrganizeService, auth *service.TalkAuthService, message *service.MessageService) *Message {
-	return &Message{service: service, talkService: talkService, talkRecordsVoteDao: talkRecordsVoteDao, splitUploadService: splitUploadService, contactService: contactService, groupMemberService: groupMemberService, organizeService: organizeService, auth: auth, message: message}
+func NewMessage(service *service.TalkMessageService, talkService *service.TalkService, talkRecordsVoteDao *repo.TalkRecordsVote, splitUploadService *service.SplitUploadService, contactService *service.ContactService, groupMemberService *service.GroupMemberService, auth *service.TalkAuthService, message *service.MessageService) *Message {
+	return &Message{service: service, talkService: talkService, talkRecordsVoteDao: talkRecordsVoteDao, splitUploadService: splitUploadService, contactService: contactService, groupMemberService: groupMemberService, auth: auth, message: message}
 }
 
 type AuthorityOpts struct {
@@ -40,42 +34,11 @@ type AuthorityOpts struct {
 
 // 权限验证
 func (c *Message) authority(ctx *ichat.Context, opt *AuthorityOpts) error {
-
-	if opt.TalkType == entity.ChatPrivateMode {
-		// 这里需要判断双方是否都是企业成员，如果是则无需添加好友即可聊天
-		if isOk, err := c.organizeService.Dao().IsQiyeMember(ctx.Ctx(), opt.UserId, opt.ReceiverId); err != nil {
-			return errors.New("系统繁忙，请稍后再试！！！")
-		} else if isOk {
-			return nil
-		}
-
-		isOk := c.contactService.Dao().IsFriend(ctx.Ctx(), opt.UserId, opt.ReceiverId, false)
-		if isOk {
-			return nil
-		}
-
-		return errors.New("暂无权限发送消息！")
-	} else {
-		groupMemberInfo := &model.GroupMember{}
-		err := c.groupMemberService.Db().First(groupMemberInfo, "group_id = ? and user_id = ?", opt.ReceiverId, opt.UserId).Error
-		if err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return errors.New("暂无权限发送消息！")
-			}
-
-			return errors.New("系统繁忙，请稍后再试！！！")
-		}
-
-		if groupMemberInfo.IsQuit == 1 {
-			return errors.New("暂无权限发送消息！")
-		}
-
-		if groupMemberInfo.IsMute == 1 {
-			return errors.New("已被群主或管理员禁言！")
-		}
-	}
-
-	return nil
+	return c.auth.IsAuth(ctx.Ctx(), &service.TalkAuthOption{
+		TalkType:   opt.TalkType,
+		UserId:     opt.UserId,
+		ReceiverId: opt.ReceiverId,
+	})
 }
 
 type TextMessageRequest struct {
@@ -321,12 +284,13 @@ func (c *Message) Emoticon(ctx *ichat.Context) error {
 }
 
 type ForwardMessageRequest struct {
-	TalkType        int    `form:"talk_type" json:"talk_type" binding:"required,oneof=1 2" label:"talk_type"`
-	ReceiverId      int    `form:"receiver_id" json:"receiver_id" binding:"required,numeric,gt=0" label:"receiver_id"`
-	ForwardMode     int    `form:"forward_mode" json:"forward_mode" binding:"required,oneof=1 2"`
-	RecordsIds      string `form:"records_ids" json:"records_ids" binding:"required,ids"`
-	ReceiveUserIds  string `form:"receive_user_ids" json:"receive_user_ids" binding:"ids"`
-	ReceiveGroupIds string `form:"receive_group_ids" json:"receive_group_ids" binding:"ids"`
+	TalkType               int    `form:"talk_type" json:"talk_type" binding:"required,oneof=1 2" label:"talk_type"`
+	ReceiverId             int    `form:"receiver_id" json:"receiver_id" binding:"required,numeric,gt=0" label:"receiver_id"`
+	ForwardMode            int    `form:"forward_mode" json:"forward_mode" binding:"required,oneof=1 2"`
+	RecordsIds             string `form:"records_ids" json:"records_ids" binding:"required,ids"`
+	ReceiveUserIds         string `form:"receive_user_ids" json:"receive_user_ids" binding:"ids"`
+	ReceiveGroupIds        string `form:"receive_group_ids" json:"receive_group_ids" binding:"ids"`
+	ReceiveContactGroupIds string `form:"receive_contact_group_ids" json:"receive_contact_group_ids" binding:"ids"` // 联系人分组ID，转发给分组内的所有好友
 }
 
 // Forward 发送转发消息
@@ -337,8 +301,8 @@ func (c *Message) Forward(ctx *ichat.Context) error {
 		return ctx.InvalidParams(err)
 	}
 
-	if params.ReceiveGroupIds == "" && params.ReceiveUserIds == "" {
-		return ctx.InvalidParams("receive_user_ids 和 receive_group_ids 不能都为空！")
+	if params.ReceiveGroupIds == "" && params.ReceiveUserIds == "" && params.ReceiveContactGroupIds == "" {
+		return ctx.InvalidParams("receive_user_ids、receive_group_ids 和 receive_contact_group_ids 不能都为空！")
 	}
 
 	uid := ctx.UserId()
@@ -365,7 +329,12 @@ func (c *Message) Forward(ctx *ichat.Context) error {
 		data.MessageIds = append(data.MessageIds, int32(id))
 	}
 
-	for _, id := range sliceutil.ParseIds(params.ReceiveUserIds) {
+	uids := sliceutil.ParseIds(params.ReceiveUserIds)
+	if params.ReceiveContactGroupIds != "" {
+		uids = append(uids, c.contactService.GetGroupContactIds(ctx.Ctx(), uid, sliceutil.ParseIds(params.ReceiveContactGroupIds))...)
+	}
+
+	for _, id := range sliceutil.Unique(uids) {
 		data.Uids = append(data.Uids, int32(id))
 	}
 
@@ -199,19 +199,22 @@ func (c *Records) Download(ctx *ichat.Context) error {
 				return ctx.Forbidden("无访问权限！")
 			}
 		} else {
-			if !c.groupMemberService.Dao().IsMember(ctx.Ctx(), resp.Record.ReceiverId, uid, false) {
+			if !c.groupMemberService.Dao().IsRecordVisible(ctx.Ctx(), resp.Record.ReceiverId, uid, resp.Record.Id) {
 				return ctx.Forbidden("无访问权限！")
 			}
 		}
 	}
 
-	switch resp.FileInfo.Drive {
-	case entity.FileDriveLocal:
-		ctx.Context.FileAttachment(c.fileSystem.Local.Path(resp.FileInfo.Path), resp.FileInfo.OriginalName)
-	case entity.FileDriveCos:
-		ctx.Context.Redirect(http.StatusFound, c.fileSystem.Cos.PrivateUrl(resp.FileInfo.Path, 60))
-	default:
-		return ctx.ErrorBusiness("未知文件驱动类型")
+	adapter, err := c.fileSystem.Drive(entity.FileDriveName(resp.FileInfo.Drive))
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	// 本地存储直接输出文件，对象存储跳转至临时访问地址
+	if local, ok := adapter.(*filesystem.LocalFilesystem); ok {
+		ctx.Context.FileAttachment(local.Path(resp.FileInfo.Path), resp.FileInfo.OriginalName)
+	} else {
+		ctx.Context.Redirect(http.StatusFound, adapter.PrivateUrl(resp.FileInfo.Path, 60))
 	}
 
 	return nil
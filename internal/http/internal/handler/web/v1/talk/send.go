@@ -5,16 +5,18 @@ import (
 	"go-chat/api/pb/message/v1"
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/pkg/sliceutil"
 	"go-chat/internal/service"
 )
 
 type SendMessage struct {
 	auth    *service.TalkAuthService
 	message *service.MessageService
+	contact *service.ContactService
 }
 
-func NewSendMessage(auth *service.TalkAuthService, message *service.MessageService) *SendMessage {
-	return &SendMessage{auth: auth, message: message}
+func NewSendMessage(auth *service.TalkAuthService, message *service.MessageService, contact *service.ContactService) *SendMessage {
+	return &SendMessage{auth: auth, message: message, contact: contact}
 }
 
 type SendBaseMessageRequest struct {
@@ -155,6 +157,23 @@ func (c *SendMessage) onSendForward(ctx *ichat.Context) error {
 		return ctx.InvalidParams(err)
 	}
 
+	if len(params.ContactGroupIds) > 0 {
+		groupIds := make([]int, 0, len(params.ContactGroupIds))
+		for _, id := range params.ContactGroupIds {
+			groupIds = append(groupIds, int(id))
+		}
+
+		for _, id := range c.contact.GetGroupContactIds(ctx.Ctx(), ctx.UserId(), groupIds) {
+			params.Uids = append(params.Uids, int32(id))
+		}
+
+		params.Uids = sliceutil.Unique(params.Uids)
+	}
+
+	if len(params.Uids) == 0 && len(params.Gids) == 0 {
+		return ctx.InvalidParams("uids、gids 和 contact_group_ids 不能都为空！")
+	}
+
 	err := c.message.SendForward(ctx.Ctx(), ctx.UserId(), params)
 	if err != nil {
 		return ctx.ErrorBusiness(err.Error())
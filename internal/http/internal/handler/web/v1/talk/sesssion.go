@@ -26,11 +26,11 @@ type Session struct {
 	unreadTalkCache    *cache.UnreadStorage
 	contactRemarkCache *cache.ContactRemark
 	groupService       *service.GroupService
-	authPermission     *service.AuthPermissionService
+	talkAuthService    *service.TalkAuthService
 }
 
-func NewSession(service *service.TalkService, talkListService *service.TalkSessionService, redisLock *cache.RedisLock, userService *service.UserService, wsClient *cache.ClientStorage, lastMessage *cache.MessageStorage, contactService *service.ContactService, unreadTalkCache *cache.UnreadStorage, contactRemarkCache *cache.ContactRemark, groupService *service.GroupService, authPermission *service.AuthPermissionService) *Session {
-	return &Session{service: service, talkListService: talkListService, redisLock: redisLock, userService: userService, wsClient: wsClient, lastMessage: lastMessage, contactService: contactService, unreadTalkCache: unreadTalkCache, contactRemarkCache: contactRemarkCache, groupService: groupService, authPermission: authPermission}
+func NewSession(service *service.TalkService, talkListService *service.TalkSessionService, redisLock *cache.RedisLock, userService *service.UserService, wsClient *cache.ClientStorage, lastMessage *cache.MessageStorage, contactService *service.ContactService, unreadTalkCache *cache.UnreadStorage, contactRemarkCache *cache.ContactRemark, groupService *service.GroupService, talkAuthService *service.TalkAuthService) *Session {
+	return &Session{service: service, talkListService: talkListService, redisLock: redisLock, userService: userService, wsClient: wsClient, lastMessage: lastMessage, contactService: contactService, unreadTalkCache: unreadTalkCache, contactRemarkCache: contactRemarkCache, groupService: groupService, talkAuthService: talkAuthService}
 }
 
 // Create 创建会话列表
@@ -61,12 +61,12 @@ func (c *Session) Create(ctx *ichat.Context) error {
 	}
 
 	// 暂无权限
-	if !c.authPermission.IsAuth(ctx.Ctx(), &service.AuthPermission{
+	if err := c.talkAuthService.IsAuth(ctx.Ctx(), &service.TalkAuthOption{
 		TalkType:   int(params.TalkType),
 		UserId:     uid,
 		ReceiverId: int(params.ReceiverId),
-	}) {
-		return ctx.ErrorBusiness("暂无权限！")
+	}); err != nil {
+		return ctx.ErrorBusiness(err.Error())
 	}
 
 	result, err := c.talkListService.Create(ctx.Ctx(), &service.TalkSessionCreateOpt{
@@ -203,6 +203,9 @@ func (c *Session) List(ctx *ichat.Context) error {
 	// 获取好友备注
 	remarks, _ := c.contactService.Dao().Remarks(ctx.Ctx(), uid, friends)
 
+	// 隐藏在线状态的好友
+	invisible := c.contactService.OnlineInvisibleIds(ctx.Ctx(), uid, friends)
+
 	items := make([]*web.TalkSessionItem, 0)
 	for _, item := range data {
 		value := &web.TalkSessionItem{
@@ -225,7 +228,12 @@ func (c *Session) List(ctx *ichat.Context) error {
 			value.Name = item.Nickname
 			value.Avatar = item.UserAvatar
 			value.RemarkName = remarks[item.ReceiverId]
-			value.IsOnline = int32(strutil.BoolToInt(c.wsClient.IsOnline(ctx.Ctx(), entity.ImChannelChat, strconv.Itoa(int(value.ReceiverId)))))
+			if !invisible[item.ReceiverId] {
+				value.IsOnline = int32(strutil.BoolToInt(c.wsClient.IsOnline(ctx.Ctx(), entity.ImChannelChat, strconv.Itoa(int(value.ReceiverId)))))
+			}
+		} else if item.TalkType == entity.ChatChannelMode {
+			value.Name = item.ChannelName
+			value.Avatar = item.ChannelAvatar
 		} else {
 			value.Name = item.GroupName
 			value.Avatar = item.GroupAvatar
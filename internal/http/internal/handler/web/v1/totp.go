@@ -0,0 +1,94 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+)
+
+type Totp struct {
+	service *service.UserTotpService
+}
+
+func NewTotp(service *service.UserTotpService) *Totp {
+	return &Totp{service: service}
+}
+
+type TotpConfirmRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TotpVerifyRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"` // 验证码或恢复码
+}
+
+// Status 两步验证状态
+func (c *Totp) Status(ctx *ichat.Context) error {
+
+	enabled, forced, recovery := c.service.Status(ctx.Ctx(), ctx.UserId())
+
+	return ctx.Success(entity.H{
+		"is_enabled":     enabled,
+		"is_forced":      forced,
+		"recovery_codes": recovery,
+	})
+}
+
+// Enroll 生成认证器绑定二维码
+func (c *Totp) Enroll(ctx *ichat.Context) error {
+
+	data, err := c.service.Enroll(ctx.Ctx(), ctx.UserId())
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(data)
+}
+
+// Confirm 确认绑定认证器并开启两步验证
+func (c *Totp) Confirm(ctx *ichat.Context) error {
+
+	params := &TotpConfirmRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	codes, err := c.service.Confirm(ctx.Ctx(), ctx.UserId(), params.Code)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{"recovery_codes": codes})
+}
+
+// Disable 关闭两步验证
+func (c *Totp) Disable(ctx *ichat.Context) error {
+
+	params := &TotpVerifyRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.service.Disable(ctx.Ctx(), ctx.UserId(), params.Password, params.Code); err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(nil)
+}
+
+// RecoveryCodes 重新生成恢复码
+func (c *Totp) RecoveryCodes(ctx *ichat.Context) error {
+
+	params := &TotpVerifyRequest{}
+	if err := ctx.Context.ShouldBindJSON(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	codes, err := c.service.RegenerateRecoveryCodes(ctx.Ctx(), ctx.UserId(), params.Password, params.Code)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(entity.H{"recovery_codes": codes})
+}
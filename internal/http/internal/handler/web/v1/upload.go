@@ -8,7 +8,6 @@ import (
 	"go-chat/config"
 	"go-chat/internal/pkg/filesystem"
 	"go-chat/internal/pkg/ichat"
-	"go-chat/internal/pkg/strutil"
 	"go-chat/internal/service"
 )
 
@@ -16,10 +15,11 @@ type Upload struct {
 	config     *config.Config
 	filesystem *filesystem.Filesystem
 	service    *service.SplitUploadService
+	media      *service.MediaService
 }
 
-func NewUpload(config *config.Config, filesystem *filesystem.Filesystem, service *service.SplitUploadService) *Upload {
-	return &Upload{config: config, filesystem: filesystem, service: service}
+func NewUpload(config *config.Config, filesystem *filesystem.Filesystem, service *service.SplitUploadService, media *service.MediaService) *Upload {
+	return &Upload{config: config, filesystem: filesystem, service: service, media: media}
 }
 
 // Avatar 头像上传上传
@@ -30,15 +30,24 @@ func (u *Upload) Avatar(ctx *ichat.Context) error {
 		return ctx.InvalidParams("文件上传失败！")
 	}
 
-	stream, _ := filesystem.ReadMultipartStream(file)
-	object := fmt.Sprintf("public/media/image/avatar/%s/%s", time.Now().Format("20060102"), strutil.GenImageName("png", 200, 200))
+	// 判断上传文件大小（5M）
+	if file.Size > 5<<20 {
+		return ctx.InvalidParams("上传文件大小不能超过5M！")
+	}
+
+	stream, err := filesystem.ReadMultipartStream(file)
+	if err != nil {
+		return ctx.ErrorBusiness("文件上传失败")
+	}
 
-	if err := u.filesystem.Default.Write(stream, object); err != nil {
+	image, err := u.media.ProcessImage(stream, fmt.Sprintf("public/media/image/avatar/%s", time.Now().Format("20060102")))
+	if err != nil {
 		return ctx.ErrorBusiness("文件上传失败")
 	}
 
-	return ctx.Success(web.UploadAvatarResponse{
-		Avatar: u.filesystem.Default.PublicUrl(object),
+	return ctx.Success(&web.UploadAvatarResponse{
+		Avatar:     image.Url,
+		Renditions: toImageRenditions(image.Renditions),
 	})
 }
 
@@ -61,7 +70,7 @@ func (u *Upload) InitiateMultipart(ctx *ichat.Context) error {
 
 	return ctx.Success(&web.UploadInitiateMultipartResponse{
 		UploadId:  info.UploadId,
-		SplitSize: 2 << 20,
+		SplitSize: service.SplitUploadPartSize,
 	})
 }
 
@@ -78,18 +87,24 @@ func (u *Upload) MultipartUpload(ctx *ichat.Context) error {
 		return ctx.InvalidParams("文件上传失败！")
 	}
 
-	err = u.service.MultipartUpload(ctx.Ctx(), &service.MultipartUploadOpts{
+	stream, err := filesystem.ReadMultipartStream(file)
+	if err != nil {
+		return ctx.ErrorBusiness("文件上传失败")
+	}
+
+	isMerge, err := u.service.MultipartUpload(ctx.Ctx(), &service.MultipartUploadOpts{
 		UserId:     ctx.UserId(),
 		UploadId:   params.UploadId,
 		SplitIndex: int(params.SplitIndex),
-		SplitNum:   int(params.SplitNum),
-		File:       file,
+		Stream:     stream,
+		Md5:        ctx.Context.PostForm("md5"),
+		Crc32:      ctx.Context.PostForm("crc32"),
 	})
 	if err != nil {
 		return ctx.ErrorBusiness(err.Error())
 	}
 
-	if params.SplitIndex != params.SplitNum-1 {
+	if !isMerge {
 		return ctx.Success(&web.UploadMultipartResponse{
 			IsMerge: false,
 		})
@@ -100,3 +115,34 @@ func (u *Upload) MultipartUpload(ctx *ichat.Context) error {
 		IsMerge:  true,
 	})
 }
+
+// MultipartInfo 查询分片上传进度，客户端据此跳过已上传的分片实现断点续传
+func (u *Upload) MultipartInfo(ctx *ichat.Context) error {
+
+	uploadId := ctx.Context.Query("upload_id")
+	if uploadId == "" {
+		return ctx.InvalidParams("upload_id 字段必传！")
+	}
+
+	info, err := u.service.UploadInfo(ctx.Ctx(), ctx.UserId(), uploadId)
+	if err != nil {
+		return ctx.ErrorBusiness(err.Error())
+	}
+
+	return ctx.Success(info)
+}
+
+// toImageRenditions 转换图片缩略图信息
+func toImageRenditions(items []*service.ImageRendition) []*web.ImageRendition {
+	renditions := make([]*web.ImageRendition, 0, len(items))
+	for _, item := range items {
+		renditions = append(renditions, &web.ImageRendition{
+			Name:   item.Name,
+			Width:  int32(item.Width),
+			Height: int32(item.Height),
+			Url:    item.Url,
+		})
+	}
+
+	return renditions
+}
@@ -0,0 +1,217 @@
+package v1
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/service"
+)
+
+// tus 断点续传协议 https://tus.io/protocols/resumable-upload.html
+// 支持 core、creation 及 checksum 扩展，上传完成后 upload_id 可直接用于发送文件消息
+const (
+	tusVersion           = "1.0.0"
+	tusExtension         = "creation,checksum"
+	tusChecksumAlgorithm = "md5,sha1"
+	tusStatusChecksum    = 460 // 校验失败
+)
+
+// tusHeaders 设置 tus 协议公共响应头
+// 注:OPTIONS 请求由跨域中间件统一处理，协议信息随每个响应返回
+func tusHeaders(ctx *ichat.Context) {
+	header := ctx.Context.Writer.Header()
+	header.Set("Tus-Resumable", tusVersion)
+	header.Set("Tus-Version", tusVersion)
+	header.Set("Tus-Extension", tusExtension)
+	header.Set("Tus-Checksum-Algorithm", tusChecksumAlgorithm)
+	header.Set("Access-Control-Expose-Headers", "Location,Upload-Offset,Upload-Length,Tus-Resumable,Tus-Version,Tus-Extension,Tus-Checksum-Algorithm")
+}
+
+// TusCreate 创建上传任务(creation 扩展)
+func (u *Upload) TusCreate(ctx *ichat.Context) error {
+	tusHeaders(ctx)
+
+	if err := u.tusCheckVersion(ctx); err != nil {
+		return err
+	}
+
+	size, err := strconv.ParseInt(ctx.Context.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || size <= 0 {
+		return u.tusError(ctx, http.StatusBadRequest, "Upload-Length 不正确")
+	}
+
+	metadata := parseTusMetadata(ctx.Context.GetHeader("Upload-Metadata"))
+
+	name := metadata["filename"]
+	if name == "" {
+		name = metadata["name"]
+	}
+
+	if name == "" {
+		return u.tusError(ctx, http.StatusBadRequest, "Upload-Metadata 缺少 filename")
+	}
+
+	info, err := u.service.InitiateMultipartUpload(ctx.Ctx(), &service.MultipartInitiateOpts{
+		Name:   name,
+		Size:   size,
+		UserId: ctx.UserId(),
+	})
+	if err != nil {
+		return u.tusError(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	ctx.Context.Header("Location", fmt.Sprintf("%s/%s", strings.TrimRight(ctx.Context.Request.URL.Path, "/"), info.UploadId))
+	ctx.Context.Header("Upload-Offset", "0")
+	ctx.Context.Status(http.StatusCreated)
+
+	return nil
+}
+
+// TusHead 查询上传偏移量
+func (u *Upload) TusHead(ctx *ichat.Context) error {
+	tusHeaders(ctx)
+	ctx.Context.Header("Cache-Control", "no-store")
+
+	info, err := u.service.UploadInfo(ctx.Ctx(), ctx.UserId(), ctx.Context.Param("upload_id"))
+	if err != nil {
+		ctx.Context.Status(http.StatusNotFound)
+		return nil
+	}
+
+	ctx.Context.Header("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	ctx.Context.Header("Upload-Length", strconv.FormatInt(info.FileSize, 10))
+	ctx.Context.Status(http.StatusOK)
+
+	return nil
+}
+
+// TusPatch 按偏移量追加上传内容
+func (u *Upload) TusPatch(ctx *ichat.Context) error {
+	tusHeaders(ctx)
+
+	if err := u.tusCheckVersion(ctx); err != nil {
+		return err
+	}
+
+	if ctx.Context.ContentType() != "application/offset+octet-stream" {
+		return u.tusError(ctx, http.StatusUnsupportedMediaType, "Content-Type 不正确")
+	}
+
+	offset, err := strconv.ParseInt(ctx.Context.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		return u.tusError(ctx, http.StatusBadRequest, "Upload-Offset 不正确")
+	}
+
+	// 单次请求内容不能超过分片大小，需完整读取后才能校验 Upload-Checksum
+	if ctx.Context.Request.ContentLength > service.SplitUploadPartSize {
+		return u.tusError(ctx, http.StatusRequestEntityTooLarge, "上传内容超出分片大小")
+	}
+
+	stream, err := io.ReadAll(io.LimitReader(ctx.Context.Request.Body, service.SplitUploadPartSize+1))
+	if err != nil {
+		return u.tusError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	if len(stream) > service.SplitUploadPartSize {
+		return u.tusError(ctx, http.StatusRequestEntityTooLarge, "上传内容超出分片大小")
+	}
+
+	if err := verifyTusChecksum(ctx.Context.GetHeader("Upload-Checksum"), stream); err != nil {
+		return u.tusError(ctx, tusStatusChecksum, err.Error())
+	}
+
+	current, err := u.service.AppendUpload(ctx.Ctx(), ctx.UserId(), ctx.Context.Param("upload_id"), offset, stream)
+	switch {
+	case errors.Is(err, service.ErrSplitUploadNotFound):
+		return u.tusError(ctx, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrSplitUploadOffset), errors.Is(err, service.ErrSplitUploadMerged), errors.Is(err, service.ErrSplitUploadBusy):
+		return u.tusError(ctx, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrSplitUploadPartSize):
+		return u.tusError(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrSplitUploadOverflow):
+		return u.tusError(ctx, http.StatusRequestEntityTooLarge, err.Error())
+	case err != nil:
+		return u.tusError(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	ctx.Context.Header("Upload-Offset", strconv.FormatInt(current, 10))
+	ctx.Context.Status(http.StatusNoContent)
+
+	return nil
+}
+
+func (u *Upload) tusCheckVersion(ctx *ichat.Context) error {
+	if ctx.Context.GetHeader("Tus-Resumable") != tusVersion {
+		return u.tusError(ctx, http.StatusPreconditionFailed, "不支持的 tus 协议版本")
+	}
+
+	return nil
+}
+
+func (u *Upload) tusError(ctx *ichat.Context, status int, message string) error {
+	ctx.Context.AbortWithStatus(status)
+	_, _ = ctx.Context.Writer.WriteString(message)
+
+	return errors.New(message)
+}
+
+// parseTusMetadata 解析 Upload-Metadata，格式为逗号分隔的 "key base64(value)"
+func parseTusMetadata(value string) map[string]string {
+	metadata := make(map[string]string)
+
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), " ", 2)
+		if parts[0] == "" {
+			continue
+		}
+
+		if len(parts) == 1 {
+			metadata[parts[0]] = ""
+			continue
+		}
+
+		if data, err := base64.StdEncoding.DecodeString(parts[1]); err == nil {
+			metadata[parts[0]] = string(data)
+		}
+	}
+
+	return metadata
+}
+
+// verifyTusChecksum 校验 Upload-Checksum，格式为 "算法 base64(摘要)"
+func verifyTusChecksum(value string, stream []byte) error {
+	if value == "" {
+		return nil
+	}
+
+	parts := strings.SplitN(value, " ", 2)
+	if len(parts) != 2 {
+		return errors.New("Upload-Checksum 不正确")
+	}
+
+	var sum []byte
+	switch strings.ToLower(parts[0]) {
+	case "md5":
+		value := md5.Sum(stream)
+		sum = value[:]
+	case "sha1":
+		value := sha1.Sum(stream)
+		sum = value[:]
+	default:
+		return errors.New("不支持的校验算法")
+	}
+
+	if base64.StdEncoding.EncodeToString(sum) != parts[1] {
+		return service.ErrSplitUploadChecksum
+	}
+
+	return nil
+}
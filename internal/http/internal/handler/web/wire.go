@@ -13,13 +13,19 @@ var ProviderSet = wire.NewSet(
 	v1.NewAuth,
 	v1.NewCommon,
 	v1.NewUser,
+	v1.NewTotp,
+	v1.NewPrivacy,
 	v1.NewOrganize,
+	v1.NewChannel,
+	v1.NewLive,
 	contact.NewContact,
 	contact.NewApply,
 	contact.NewGroup,
 	group.NewGroup,
 	group.NewApply,
 	group.NewNotice,
+	group.NewRole,
+	group.NewInviteLink,
 	talk.NewSession,
 	talk.NewMessage,
 	v1.NewUpload,
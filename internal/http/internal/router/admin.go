@@ -1,33 +1,118 @@
 package router
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"go-chat/internal/entity"
 	"go-chat/internal/http/internal/handler/admin"
 	"go-chat/internal/pkg/ichat"
 	"go-chat/internal/pkg/ichat/middleware"
+	"go-chat/internal/pkg/jwt"
 	"go-chat/internal/repository/cache"
+	"go-chat/internal/service"
 )
 
 // RegisterAdminRoute 注册 Admin 路由
-func RegisterAdminRoute(secret string, router *gin.Engine, handler *admin.Handler, session *cache.TokenSessionStorage) {
+func RegisterAdminRoute(secret string, keys jwt.KeyFinder, router *gin.Engine, handler *admin.Handler, session *cache.TokenSessionStorage, adminService *service.AdminService) {
 
 	// 授权验证中间件
-	authorize := middleware.Auth(secret, "admin", session)
+	authorize := middleware.Auth(secret, keys, "admin", session)
+
+	// 角色权限中间件，超级管理员拥有全部权限
+	anyone := adminRole(adminService)
+	operator := adminRole(adminService, entity.AdminRoleOperator)
+	auditor := adminRole(adminService, entity.AdminRoleAuditor)
+	super := adminRole(adminService, entity.AdminRoleSuper)
 
 	// v1 接口
 	v1 := router.Group("/admin/v1")
 	{
-		index := v1.Group("/index")
+		index := v1.Group("/index").Use(authorize, anyone)
 		{
-			index.GET("", ichat.HandlerFunc(handler.V1.Index.Index))
+			index.GET("", ichat.HandlerFunc(handler.V1.Index.Index)) // 系统统计数据
 		}
 
 		auth := v1.Group("/auth")
 		{
 			auth.POST("/login", ichat.HandlerFunc(handler.V1.Auth.Login))
 			auth.GET("/captcha", ichat.HandlerFunc(handler.V1.Auth.Captcha))
-			auth.GET("/logout", ichat.HandlerFunc(handler.V1.Auth.Logout))
-			auth.POST("/refresh", authorize, ichat.HandlerFunc(handler.V1.Auth.Refresh))
+			auth.GET("/logout", authorize, ichat.HandlerFunc(handler.V1.Auth.Logout))
+			auth.POST("/refresh", authorize, anyone, ichat.HandlerFunc(handler.V1.Auth.Refresh))
+		}
+
+		user := v1.Group("/user").Use(authorize)
+		{
+			user.GET("/list", anyone, ichat.HandlerFunc(handler.V1.User.List))          // 用户搜索
+			user.POST("/disable", operator, ichat.HandlerFunc(handler.V1.User.Disable)) // 禁用用户
+			user.POST("/enable", operator, ichat.HandlerFunc(handler.V1.User.Enable))   // 启用用户
+			user.POST("/logout", operator, ichat.HandlerFunc(handler.V1.User.Logout))   // 强制退出登录
+		}
+
+		group := v1.Group("/group").Use(authorize)
+		{
+			group.GET("/list", anyone, ichat.HandlerFunc(handler.V1.Group.List))          // 群组搜索
+			group.POST("/dismiss", operator, ichat.HandlerFunc(handler.V1.Group.Dismiss)) // 解散群组
+		}
+
+		emoticon := v1.Group("/emoticon").Use(authorize)
+		{
+			emoticon.GET("/list", anyone, ichat.HandlerFunc(handler.V1.Emoticon.List))        // 系统表情包列表
+			emoticon.POST("/status", operator, ichat.HandlerFunc(handler.V1.Emoticon.Status)) // 修改表情包状态
+		}
+
+		robot := v1.Group("/robot").Use(authorize)
+		{
+			robot.GET("/list", anyone, ichat.HandlerFunc(handler.V1.Robot.List))        // 机器人列表
+			robot.POST("/update", operator, ichat.HandlerFunc(handler.V1.Robot.Update)) // 修改机器人
+		}
+
+		organize := v1.Group("/organize").Use(authorize, operator)
+		{
+			organize.POST("/force-totp", ichat.HandlerFunc(handler.V1.Organize.ForceTotp)) // 设置成员强制开启两步验证
 		}
+
+		account := v1.Group("/account").Use(authorize, super)
+		{
+			account.GET("/list", ichat.HandlerFunc(handler.V1.Account.List))      // 管理员列表
+			account.POST("/create", ichat.HandlerFunc(handler.V1.Account.Create)) // 创建管理员
+			account.POST("/update", ichat.HandlerFunc(handler.V1.Account.Update)) // 修改管理员
+		}
+
+		moderation := v1.Group("/moderation").Use(authorize)
+		{
+			moderation.GET("/word/list", anyone, ichat.HandlerFunc(handler.V1.Moderation.WordList))            // 敏感词列表
+			moderation.POST("/word/create", operator, ichat.HandlerFunc(handler.V1.Moderation.WordCreate))     // 添加敏感词
+			moderation.POST("/word/delete", operator, ichat.HandlerFunc(handler.V1.Moderation.WordDelete))     // 删除敏感词
+			moderation.GET("/link/list", anyone, ichat.HandlerFunc(handler.V1.Moderation.LinkList))            // 链接域名名单
+			moderation.POST("/link/create", operator, ichat.HandlerFunc(handler.V1.Moderation.LinkCreate))     // 添加链接域名
+			moderation.POST("/link/delete", operator, ichat.HandlerFunc(handler.V1.Moderation.LinkDelete))     // 删除链接域名
+			moderation.GET("/review/list", anyone, ichat.HandlerFunc(handler.V1.Moderation.ReviewList))        // 人工审核队列
+			moderation.POST("/review/handle", operator, ichat.HandlerFunc(handler.V1.Moderation.ReviewHandle)) // 处理审核记录
+		}
+
+		audit := v1.Group("/audit").Use(authorize, auditor)
+		{
+			audit.GET("/list", ichat.HandlerFunc(handler.V1.Audit.List)) // 审计日志
+		}
+	}
+}
+
+// adminRole 校验管理员账号状态及角色，roles 为空时仅校验账号状态
+func adminRole(adminService *service.AdminService, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, err := adminService.Authorize(c.Request.Context(), ichat.New(c).UserId(), roles...)
+		if err == nil {
+			c.Next()
+			return
+		}
+
+		if errors.Is(err, service.ErrAdminForbidden) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"code": 403, "message": err.Error()})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": err.Error()})
 	}
 }
@@ -16,5 +16,7 @@ func RegisterOpenRoute(router *gin.Engine, handler *open.Handler) {
 		{
 			index.GET("", ichat.HandlerFunc(handler.V1.Index.Index))
 		}
+
+		v1.GET("/.well-known/jwks.json", ichat.HandlerFunc(handler.V1.Jwks.Keys)) // JWT 验签公钥
 	}
 }
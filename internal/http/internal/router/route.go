@@ -10,10 +10,11 @@ import (
 	"go-chat/internal/http/internal/handler"
 	"go-chat/internal/pkg/ichat/middleware"
 	"go-chat/internal/repository/cache"
+	"go-chat/internal/service"
 )
 
 // NewRouter 初始化配置路由
-func NewRouter(conf *config.Config, handler *handler.Handler, session *cache.TokenSessionStorage) *gin.Engine {
+func NewRouter(conf *config.Config, handler *handler.Handler, session *cache.TokenSessionStorage, keys *service.JwtKeyService, adminService *service.AdminService) *gin.Engine {
 	router := gin.New()
 
 	router.Use(gin.Logger())
@@ -32,8 +33,8 @@ func NewRouter(conf *config.Config, handler *handler.Handler, session *cache.Tok
 		c.JSON(200, entity.H{"status": "ok"})
 	})
 
-	RegisterWebRoute(conf.Jwt.Secret, router, handler.Api, session)
-	RegisterAdminRoute(conf.Jwt.Secret, router, handler.Admin, session)
+	RegisterWebRoute(conf.Jwt.Secret, keys, router, handler.Api, session)
+	RegisterAdminRoute(conf.Jwt.Secret, keys, router, handler.Admin, session, adminService)
 	RegisterOpenRoute(router, handler.Open)
 
 	// 注册 debug 路由
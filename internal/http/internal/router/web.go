@@ -6,14 +6,15 @@ import (
 	"go-chat/internal/http/internal/handler/web"
 	"go-chat/internal/pkg/ichat"
 	"go-chat/internal/pkg/ichat/middleware"
+	"go-chat/internal/pkg/jwt"
 	"go-chat/internal/repository/cache"
 )
 
 // RegisterWebRoute 注册 Web 路由
-func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, session *cache.TokenSessionStorage) {
+func RegisterWebRoute(secret string, keys jwt.KeyFinder, router *gin.Engine, handler *web.Handler, session *cache.TokenSessionStorage) {
 
 	// 授权验证中间件
-	authorize := middleware.Auth(secret, "api", session)
+	authorize := middleware.Auth(secret, keys, "api", session)
 
 	// v1 接口
 	v1 := router.Group("/api/v1")
@@ -28,11 +29,20 @@ func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, s
 		// 授权相关分组
 		auth := v1.Group("/auth")
 		{
-			auth.POST("/login", ichat.HandlerFunc(handler.V1.Auth.Login))                // 登录
-			auth.POST("/register", ichat.HandlerFunc(handler.V1.Auth.Register))          // 注册
-			auth.POST("/refresh", authorize, ichat.HandlerFunc(handler.V1.Auth.Refresh)) // 刷新 Token
-			auth.POST("/logout", authorize, ichat.HandlerFunc(handler.V1.Auth.Logout))   // 退出登录
-			auth.POST("/forget", ichat.HandlerFunc(handler.V1.Auth.Forget))              // 找回密码
+			auth.POST("/login", ichat.HandlerFunc(handler.V1.Auth.Login))                 // 登录
+			auth.POST("/register", ichat.HandlerFunc(handler.V1.Auth.Register))           // 注册
+			auth.POST("/refresh", ichat.HandlerFunc(handler.V1.Auth.Refresh))             // 刷新 Token
+			auth.POST("/logout", authorize, ichat.HandlerFunc(handler.V1.Auth.Logout))    // 退出登录
+			auth.POST("/forget", ichat.HandlerFunc(handler.V1.Auth.Forget))               // 找回密码
+			auth.GET("/captcha", ichat.HandlerFunc(handler.V1.Auth.Captcha))              // 登录图形验证码
+			auth.GET("/oidc/authorize", ichat.HandlerFunc(handler.V1.Auth.OidcAuthorize)) // 单点登录授权地址
+			auth.POST("/oidc/login", ichat.HandlerFunc(handler.V1.Auth.OidcLogin))        // 单点登录
+			auth.POST("/totp/setup", ichat.HandlerFunc(handler.V1.Auth.TotpSetup))        // 两步验证登录绑定认证器
+			auth.POST("/totp/verify", ichat.HandlerFunc(handler.V1.Auth.TotpVerify))      // 两步验证登录
+
+			auth.GET("/sessions", authorize, ichat.HandlerFunc(handler.V1.Auth.Sessions))                           // 已登录设备列表
+			auth.POST("/sessions/revoke", authorize, ichat.HandlerFunc(handler.V1.Auth.RevokeSession))              // 注销指定设备
+			auth.POST("/sessions/revoke-others", authorize, ichat.HandlerFunc(handler.V1.Auth.RevokeOtherSessions)) // 注销其它设备
 		}
 
 		// 用户相关分组
@@ -44,6 +54,18 @@ func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, s
 			user.POST("/change/password", ichat.HandlerFunc(handler.V1.User.ChangePassword)) // 修改用户密码
 			user.POST("/change/mobile", ichat.HandlerFunc(handler.V1.User.ChangeMobile))     // 修改用户手机号
 			user.POST("/change/email", ichat.HandlerFunc(handler.V1.User.ChangeEmail))       // 修改用户邮箱
+
+			user.GET("/totp/status", ichat.HandlerFunc(handler.V1.Totp.Status))                 // 两步验证状态
+			user.POST("/totp/enroll", ichat.HandlerFunc(handler.V1.Totp.Enroll))                // 绑定认证器
+			user.POST("/totp/confirm", ichat.HandlerFunc(handler.V1.Totp.Confirm))              // 确认开启两步验证
+			user.POST("/totp/disable", ichat.HandlerFunc(handler.V1.Totp.Disable))              // 关闭两步验证
+			user.POST("/totp/recovery-codes", ichat.HandlerFunc(handler.V1.Totp.RecoveryCodes)) // 重新生成恢复码
+
+			user.GET("/privacy", ichat.HandlerFunc(handler.V1.Privacy.Setting))        // 获取隐私设置
+			user.POST("/privacy/update", ichat.HandlerFunc(handler.V1.Privacy.Update)) // 修改隐私设置
+			user.GET("/block/list", ichat.HandlerFunc(handler.V1.Privacy.BlockList))   // 黑名单列表
+			user.POST("/block/create", ichat.HandlerFunc(handler.V1.Privacy.Block))    // 加入黑名单
+			user.POST("/block/delete", ichat.HandlerFunc(handler.V1.Privacy.Unblock))  // 移出黑名单
 		}
 
 		contact := v1.Group("/contact").Use(authorize)
@@ -53,6 +75,7 @@ func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, s
 			contact.GET("/detail", ichat.HandlerFunc(handler.V1.Contact.Detail))           // 搜索联系人
 			contact.POST("/delete", ichat.HandlerFunc(handler.V1.Contact.Delete))          // 删除联系人
 			contact.POST("/edit-remark", ichat.HandlerFunc(handler.V1.Contact.EditRemark)) // 编辑联系人备注
+			contact.POST("/edit-group", ichat.HandlerFunc(handler.V1.Contact.EditGroup))   // 修改联系人分组
 
 			// 联系人申请相关
 			contact.GET("/apply/records", ichat.HandlerFunc(handler.V1.ContactApply.List))              // 联系人申请列表
@@ -62,44 +85,104 @@ func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, s
 			contact.GET("/apply/unread-num", ichat.HandlerFunc(handler.V1.ContactApply.ApplyUnreadNum)) // 联系人申请未读数
 
 			// 联系人分组
-			contact.GET("/group/list", ichat.HandlerFunc(handler.V1.ContactGroup.List))      // 联系人分组列表
-			contact.POST("/group/create", ichat.HandlerFunc(handler.V1.ContactGroup.Create)) // 联系人分组添加
-			contact.POST("/group/update", ichat.HandlerFunc(handler.V1.ContactGroup.Update)) // 联系人分组更新
-			contact.POST("/group/delete", ichat.HandlerFunc(handler.V1.ContactGroup.Delete)) // 联系人分组删除
-			contact.POST("/group/sort", ichat.HandlerFunc(handler.V1.ContactGroup.Sort))     // 联系人分组排序
+			contact.GET("/group/list", ichat.HandlerFunc(handler.V1.ContactGroup.List))          // 联系人分组列表
+			contact.POST("/group/create", ichat.HandlerFunc(handler.V1.ContactGroup.Create))     // 联系人分组添加
+			contact.POST("/group/update", ichat.HandlerFunc(handler.V1.ContactGroup.Update))     // 联系人分组更新
+			contact.POST("/group/delete", ichat.HandlerFunc(handler.V1.ContactGroup.Delete))     // 联系人分组删除
+			contact.POST("/group/sort", ichat.HandlerFunc(handler.V1.ContactGroup.Sort))         // 联系人分组排序
+			contact.POST("/group/assign", ichat.HandlerFunc(handler.V1.ContactGroup.Assign))     // 批量移动联系人到分组
+			contact.POST("/group/unassign", ichat.HandlerFunc(handler.V1.ContactGroup.Unassign)) // 批量将联系人移出分组
 		}
 
 		// 聊天群相关分组
 		userGroup := v1.Group("/group").Use(authorize)
 		{
-			userGroup.GET("/list", ichat.HandlerFunc(handler.V1.Group.GroupList))            // 群组列表
-			userGroup.GET("/overt/list", ichat.HandlerFunc(handler.V1.Group.OvertList))      // 公开群组列表
-			userGroup.GET("/detail", ichat.HandlerFunc(handler.V1.Group.Detail))             // 群组详情
-			userGroup.POST("/create", ichat.HandlerFunc(handler.V1.Group.Create))            // 创建群组
-			userGroup.POST("/dismiss", ichat.HandlerFunc(handler.V1.Group.Dismiss))          // 解散群组
-			userGroup.POST("/invite", ichat.HandlerFunc(handler.V1.Group.Invite))            // 邀请加入群组
-			userGroup.POST("/secede", ichat.HandlerFunc(handler.V1.Group.SignOut))           // 退出群组
-			userGroup.POST("/setting", ichat.HandlerFunc(handler.V1.Group.Setting))          // 设置群组信息
-			userGroup.POST("/handover", ichat.HandlerFunc(handler.V1.Group.Handover))        // 群主转让
-			userGroup.POST("/assign-admin", ichat.HandlerFunc(handler.V1.Group.AssignAdmin)) // 分配管理员
-			userGroup.POST("/no-speak", ichat.HandlerFunc(handler.V1.Group.NoSpeak))         // 修改禁言状态
+			userGroup.GET("/list", ichat.HandlerFunc(handler.V1.Group.GroupList))              // 群组列表
+			userGroup.GET("/overt/list", ichat.HandlerFunc(handler.V1.Group.OvertList))        // 公开群组列表
+			userGroup.GET("/detail", ichat.HandlerFunc(handler.V1.Group.Detail))               // 群组详情
+			userGroup.POST("/create", ichat.HandlerFunc(handler.V1.Group.Create))              // 创建群组
+			userGroup.POST("/dismiss", ichat.HandlerFunc(handler.V1.Group.Dismiss))            // 解散群组
+			userGroup.POST("/invite", ichat.HandlerFunc(handler.V1.Group.Invite))              // 邀请加入群组
+			userGroup.POST("/secede", ichat.HandlerFunc(handler.V1.Group.SignOut))             // 退出群组
+			userGroup.POST("/setting", ichat.HandlerFunc(handler.V1.Group.Setting))            // 设置群组信息
+			userGroup.POST("/handover", ichat.HandlerFunc(handler.V1.Group.Handover))          // 群主转让
+			userGroup.POST("/assign-admin", ichat.HandlerFunc(handler.V1.Group.AssignAdmin))   // 分配管理员
+			userGroup.POST("/no-speak", ichat.HandlerFunc(handler.V1.Group.NoSpeak))           // 修改禁言状态
+			userGroup.POST("/mute", ichat.HandlerFunc(handler.V1.Group.Mute))                  // 修改全员禁言状态
+			userGroup.GET("/mute/status", ichat.HandlerFunc(handler.V1.Group.MuteStatus))      // 获取禁言状态
+			userGroup.POST("/upgrade", ichat.HandlerFunc(handler.V1.Group.Upgrade))            // 升级为超级群
+			userGroup.POST("/broadcast", ichat.HandlerFunc(handler.V1.Group.Broadcast))        // 设置仅管理员可发言
+			userGroup.POST("/share-history", ichat.HandlerFunc(handler.V1.Group.ShareHistory)) // 设置新成员可查看历史消息
 
 			// 群成员相关
 			userGroup.GET("/member/list", ichat.HandlerFunc(handler.V1.Group.Members))               // 群成员列表
 			userGroup.GET("/member/invites", ichat.HandlerFunc(handler.V1.Group.GetInviteFriends))   // 群成员列表
+			userGroup.GET("/member/page", ichat.HandlerFunc(handler.V1.Group.MemberPage))            // 群成员分页列表
 			userGroup.POST("/member/remove", ichat.HandlerFunc(handler.V1.Group.RemoveMembers))      // 移出指定群成员
 			userGroup.POST("/member/remark", ichat.HandlerFunc(handler.V1.Group.UpdateMemberRemark)) // 设置群名片
 
 			// 群公告相关
-			userGroup.GET("/notice/list", ichat.HandlerFunc(handler.V1.GroupNotice.List))             // 群公告列表
-			userGroup.POST("/notice/edit", ichat.HandlerFunc(handler.V1.GroupNotice.CreateAndUpdate)) // 添加或编辑群公告
-			userGroup.POST("/notice/delete", ichat.HandlerFunc(handler.V1.GroupNotice.Delete))        // 删除群公告
+			userGroup.GET("/notice/list", ichat.HandlerFunc(handler.V1.GroupNotice.List))                // 群公告列表
+			userGroup.POST("/notice/edit", ichat.HandlerFunc(handler.V1.GroupNotice.CreateAndUpdate))    // 添加或编辑群公告
+			userGroup.POST("/notice/delete", ichat.HandlerFunc(handler.V1.GroupNotice.Delete))           // 删除群公告
+			userGroup.POST("/notice/confirm", ichat.HandlerFunc(handler.V1.GroupNotice.Confirm))         // 确认群公告
+			userGroup.GET("/notice/confirm-list", ichat.HandlerFunc(handler.V1.GroupNotice.ConfirmList)) // 群公告确认情况
 
 			// 群申请
-			userGroup.POST("/apply/create", ichat.HandlerFunc(handler.V1.GroupApply.Create)) // 提交入群申请
-			userGroup.POST("/apply/delete", ichat.HandlerFunc(handler.V1.GroupApply.Delete)) // 申请入群申请
-			userGroup.POST("/apply/agree", ichat.HandlerFunc(handler.V1.GroupApply.Agree))   // 同意入群申请
-			userGroup.GET("/apply/list", ichat.HandlerFunc(handler.V1.GroupApply.List))      // 入群申请列表
+			userGroup.POST("/apply/create", ichat.HandlerFunc(handler.V1.GroupApply.Create))             // 提交入群申请
+			userGroup.POST("/apply/delete", ichat.HandlerFunc(handler.V1.GroupApply.Delete))             // 申请入群申请
+			userGroup.POST("/apply/agree", ichat.HandlerFunc(handler.V1.GroupApply.Agree))               // 同意入群申请
+			userGroup.POST("/apply/decline", ichat.HandlerFunc(handler.V1.GroupApply.Decline))           // 拒绝入群申请
+			userGroup.GET("/apply/list", ichat.HandlerFunc(handler.V1.GroupApply.List))                  // 入群申请列表
+			userGroup.GET("/apply/setting", ichat.HandlerFunc(handler.V1.GroupApply.JoinSetting))        // 入群方式
+			userGroup.POST("/apply/setting", ichat.HandlerFunc(handler.V1.GroupApply.UpdateJoinSetting)) // 修改入群方式
+
+			// 群邀请链接
+			userGroup.POST("/invite-link/create", ichat.HandlerFunc(handler.V1.GroupInvite.Create))  // 创建邀请链接
+			userGroup.GET("/invite-link/list", ichat.HandlerFunc(handler.V1.GroupInvite.List))       // 邀请链接列表
+			userGroup.POST("/invite-link/revoke", ichat.HandlerFunc(handler.V1.GroupInvite.Revoke))  // 作废邀请链接
+			userGroup.GET("/invite-link/preview", ichat.HandlerFunc(handler.V1.GroupInvite.Preview)) // 预览邀请链接
+			userGroup.POST("/invite-link/join", ichat.HandlerFunc(handler.V1.GroupInvite.Join))      // 通过邀请链接入群
+
+			// 群角色权限
+			userGroup.GET("/permissions", ichat.HandlerFunc(handler.V1.GroupRole.Permissions)) // 当前用户的群权限
+			userGroup.GET("/role/list", ichat.HandlerFunc(handler.V1.GroupRole.List))          // 自定义角色列表
+			userGroup.POST("/role/create", ichat.HandlerFunc(handler.V1.GroupRole.Create))     // 创建自定义角色
+			userGroup.POST("/role/update", ichat.HandlerFunc(handler.V1.GroupRole.Update))     // 修改自定义角色
+			userGroup.POST("/role/delete", ichat.HandlerFunc(handler.V1.GroupRole.Delete))     // 删除自定义角色
+			userGroup.POST("/role/assign", ichat.HandlerFunc(handler.V1.GroupRole.Assign))     // 设置成员角色
+		}
+
+		// 频道相关分组
+		channel := v1.Group("/channel").Use(authorize)
+		{
+			channel.POST("/create", ichat.HandlerFunc(handler.V1.Channel.Create))           // 创建频道
+			channel.POST("/update", ichat.HandlerFunc(handler.V1.Channel.Update))           // 修改频道信息
+			channel.POST("/dismiss", ichat.HandlerFunc(handler.V1.Channel.Dismiss))         // 解散频道
+			channel.GET("/detail", ichat.HandlerFunc(handler.V1.Channel.Detail))            // 频道详情
+			channel.GET("/list", ichat.HandlerFunc(handler.V1.Channel.List))                // 已订阅频道列表
+			channel.GET("/overt/list", ichat.HandlerFunc(handler.V1.Channel.OvertList))     // 公开频道列表
+			channel.POST("/subscribe", ichat.HandlerFunc(handler.V1.Channel.Subscribe))     // 订阅频道
+			channel.POST("/unsubscribe", ichat.HandlerFunc(handler.V1.Channel.Unsubscribe)) // 退订频道
+			channel.POST("/admin", ichat.HandlerFunc(handler.V1.Channel.Admin))             // 设置频道管理员
+			channel.POST("/open", ichat.HandlerFunc(handler.V1.Channel.Open))               // 打开频道会话
+			channel.POST("/post", ichat.HandlerFunc(handler.V1.Channel.Post))               // 发布频道消息
+			channel.GET("/posts", ichat.HandlerFunc(handler.V1.Channel.Posts))              // 频道消息列表
+			channel.POST("/react", ichat.HandlerFunc(handler.V1.Channel.React))             // 频道消息互动
+		}
+
+		// 直播聊天室分组
+		live := v1.Group("/live")
+		{
+			live.GET("/detail", ichat.HandlerFunc(handler.V1.Live.Detail))                            // 聊天室详情
+			live.GET("/history", ichat.HandlerFunc(handler.V1.Live.History))                          // 聊天室最近消息
+			live.POST("/guest-token", ichat.HandlerFunc(handler.V1.Live.GuestToken))                  // 获取游客令牌
+			live.POST("/create", authorize, ichat.HandlerFunc(handler.V1.Live.Create))                // 创建聊天室
+			live.POST("/close", authorize, ichat.HandlerFunc(handler.V1.Live.Close))                  // 关闭聊天室
+			live.POST("/slow-mode", authorize, ichat.HandlerFunc(handler.V1.Live.SlowMode))           // 设置慢速模式
+			live.POST("/moderator", authorize, ichat.HandlerFunc(handler.V1.Live.Moderator))          // 设置管理员
+			live.POST("/message/delete", authorize, ichat.HandlerFunc(handler.V1.Live.DeleteMessage)) // 删除聊天室消息
+			live.POST("/ban", authorize, ichat.HandlerFunc(handler.V1.Live.Ban))                      // 禁止成员发言
 		}
 
 		talk := v1.Group("/talk").Use(authorize)
@@ -150,6 +233,12 @@ func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, s
 			upload.POST("/avatar", ichat.HandlerFunc(handler.V1.Upload.Avatar))
 			upload.POST("/multipart/initiate", ichat.HandlerFunc(handler.V1.Upload.InitiateMultipart))
 			upload.POST("/multipart", ichat.HandlerFunc(handler.V1.Upload.MultipartUpload))
+			upload.GET("/multipart/info", ichat.HandlerFunc(handler.V1.Upload.MultipartInfo))
+
+			// tus 断点续传协议
+			upload.POST("/tus", ichat.HandlerFunc(handler.V1.Upload.TusCreate))
+			upload.HEAD("/tus/:upload_id", ichat.HandlerFunc(handler.V1.Upload.TusHead))
+			upload.PATCH("/tus/:upload_id", ichat.HandlerFunc(handler.V1.Upload.TusPatch))
 		}
 
 		note := v1.Group("/note").Use(authorize)
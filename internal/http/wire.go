@@ -58,6 +58,10 @@ var cacheProviderSet = wire.NewSet(
 	cache.NewContactRemark,
 	cache.NewSequence,
 	cache.NewCaptchaStorage,
+	cache.NewJwtKeyStorage,
+	cache.NewRateLimitStorage,
+	cache.NewChannelViewStorage,
+	cache.NewLiveRoomStorage,
 )
 
 var daoProviderSet = wire.NewSet(
@@ -73,6 +77,7 @@ var daoProviderSet = wire.NewSet(
 	repo.NewEmoticon,
 	repo.NewTalkRecordsVote,
 	repo.NewFileSplitUpload,
+	repo.NewFileBlob,
 	note3.NewArticleClass,
 	note3.NewArticleAnnex,
 	organize3.NewDepartment,
@@ -81,6 +86,22 @@ var daoProviderSet = wire.NewSet(
 	repo.NewRobot,
 	repo.NewTest,
 	repo.NewSequence,
+	repo.NewUserSession,
+	repo.NewUserTotp,
+	repo.NewUserIdentity,
+	repo.NewAdmin,
+	repo.NewAdminAuditLog,
+	repo.NewGroupRole,
+	repo.NewGroupInviteLink,
+	repo.NewModerationWord,
+	repo.NewModerationLink,
+	repo.NewModerationReview,
+	repo.NewUserPrivacy,
+	repo.NewUserBlock,
+	repo.NewChannel,
+	repo.NewChannelMember,
+	repo.NewChannelReaction,
+	repo.NewChannelView,
 )
 
 var serviceProviderSet = wire.NewSet(
@@ -103,6 +124,21 @@ var serviceProviderSet = wire.NewSet(
 	service.NewIpAddressService,
 	service.NewAuthPermissionService,
 	service.NewMessageService,
+	service.NewMediaService,
+	service.NewFileBlobService,
+	service.NewUserSessionService,
+	service.NewUserTotpService,
+	service.NewJwtKeyService,
+	service.NewLoginLimitService,
+	service.NewUserOidcService,
+	service.NewAdminService,
+	service.NewGroupRoleService,
+	service.NewGroupInviteLinkService,
+	service.NewGroupMuteService,
+	service.NewModerationService,
+	service.NewUserPrivacyService,
+	service.NewChannelService,
+	service.NewLiveRoomService,
 	note.NewArticleService,
 	note.NewArticleTagService,
 	note.NewArticleClassService,
@@ -41,7 +41,6 @@ func Initialize(conf *config.Config) *AppProvider {
 	db := provider.NewMySQLClient(conf)
 	users := repo.NewUsers(db)
 	userService := service.NewUserService(users)
-	common := v1.NewCommon(conf, smsService, userService)
 	tokenSessionStorage := cache.NewTokenSessionStorage(client)
 	redisLock := cache.NewRedisLock(client)
 	baseService := service.NewBaseService(db, client)
@@ -55,7 +54,14 @@ func Initialize(conf *config.Config) *AppProvider {
 	clientStorage := cache.NewClientStorage(client, conf, serverStorage)
 	filesystem := provider.NewFilesystem(conf)
 	splitUpload := repo.NewFileSplitUpload(db)
-	talkMessageService := service.NewTalkMessageService(baseService, conf, unreadStorage, messageStorage, talkRecordsVote, groupMember, serverStorage, clientStorage, filesystem, splitUpload)
+	fileBlob := repo.NewFileBlob(db)
+	fileBlobService := service.NewFileBlobService(baseService, fileBlob, filesystem)
+	mediaService := service.NewMediaService(conf, filesystem, fileBlobService)
+	organizeOrganize := organize.NewOrganize(db)
+	contactRemark := cache.NewContactRemark(client)
+	repoContact := repo.NewContact(db, contactRemark, relation)
+	groupRole := repo.NewGroupRole(db)
+	authPermissionService := service.NewAuthPermissionService(repoContact, groupMember, organizeOrganize, groupRole)
 	httpClient := provider.NewHttpClient()
 	requestClient := provider.NewRequestClient(httpClient)
 	ipAddressService := service.NewIpAddressService(baseService, conf, requestClient)
@@ -66,62 +72,98 @@ func Initialize(conf *config.Config) *AppProvider {
 	robot := repo.NewRobot(db)
 	sequence := cache.NewSequence(client)
 	repoSequence := repo.NewSequence(db, sequence)
-	messageForwardLogic := logic.NewMessageForwardLogic(db, repoSequence)
-	messageService := service.NewMessageService(baseService, messageForwardLogic, groupMember, splitUpload, filesystem, unreadStorage, messageStorage, serverStorage, clientStorage, repoSequence)
-	auth := v1.NewAuth(conf, userService, smsService, tokenSessionStorage, redisLock, talkMessageService, ipAddressService, talkSessionService, articleClassService, robot, messageService)
-	organizeOrganize := organize.NewOrganize(db)
+	messageForwardLogic := logic.NewMessageForwardLogic(db, repoSequence, fileBlob)
+	rateLimitStorage := cache.NewRateLimitStorage(client)
+	moderationWord := repo.NewModerationWord(db)
+	moderationLink := repo.NewModerationLink(db)
+	moderationReview := repo.NewModerationReview(db)
+	moderationService := service.NewModerationService(baseService, conf, httpClient, rateLimitStorage, moderationWord, moderationLink, moderationReview)
+	talkMessageService := service.NewTalkMessageService(baseService, conf, unreadStorage, messageStorage, talkRecordsVote, groupMember, serverStorage, clientStorage, filesystem, splitUpload, mediaService, fileBlobService, authPermissionService, moderationService)
+	messageService := service.NewMessageService(baseService, messageForwardLogic, groupMember, splitUpload, filesystem, mediaService, fileBlobService, unreadStorage, messageStorage, serverStorage, clientStorage, repoSequence, moderationService)
+	userSession := repo.NewUserSession(db)
+	userSessionService := service.NewUserSessionService(baseService, userSession, conf, tokenSessionStorage, ipAddressService)
+	userTotp := repo.NewUserTotp(db)
+	userTotpService := service.NewUserTotpService(baseService, userTotp, conf, users, organizeOrganize, rateLimitStorage)
+	jwtKeyStorage := cache.NewJwtKeyStorage(client)
+	jwtKeyService := service.NewJwtKeyService(conf, jwtKeyStorage, redisLock)
+	loginLimitService := service.NewLoginLimitService(rateLimitStorage, messageService, ipAddressService)
+	common := v1.NewCommon(conf, smsService, userService, loginLimitService)
+	captchaStorage := cache.NewCaptchaStorage(client)
+	userIdentity := repo.NewUserIdentity(db)
+	userOidcService := service.NewUserOidcService(baseService, conf, httpClient, users, userIdentity, organizeOrganize)
+	auth := v1.NewAuth(conf, userService, smsService, tokenSessionStorage, redisLock, talkMessageService, ipAddressService, talkSessionService, articleClassService, robot, messageService, userSessionService, userTotpService, jwtKeyService, captchaStorage, loginLimitService, userOidcService)
 	organizeService := organize2.NewOrganizeService(baseService, organizeOrganize)
 	user := v1.NewUser(userService, smsService, organizeService)
+	totp := v1.NewTotp(userTotpService)
+	userPrivacy := repo.NewUserPrivacy(db)
+	userBlock := repo.NewUserBlock(db)
+	userPrivacyService := service.NewUserPrivacyService(baseService, userPrivacy, userBlock)
+	privacy := v1.NewPrivacy(userPrivacyService)
 	department := organize.NewDepartment(db)
 	deptService := organize2.NewOrganizeDeptService(baseService, department)
 	position := organize.NewPosition(db)
 	positionService := organize2.NewPositionService(baseService, position)
 	v1Organize := v1.NewOrganize(deptService, organizeService, positionService)
-	talkService := service.NewTalkService(baseService, groupMember)
-	contactRemark := cache.NewContactRemark(client)
-	repoContact := repo.NewContact(db, contactRemark, relation)
-	contactService := service.NewContactService(baseService, repoContact)
+	talkService := service.NewTalkService(baseService, groupMember, fileBlob)
+	contactService := service.NewContactService(baseService, repoContact, userPrivacy, userBlock)
 	repoGroup := repo.NewGroup(db)
-	groupService := service.NewGroupService(baseService, repoGroup, groupMember, relation)
-	authPermissionService := service.NewAuthPermissionService(repoContact, groupMember, organizeOrganize)
-	session := talk.NewSession(talkService, talkSessionService, redisLock, userService, clientStorage, messageStorage, contactService, unreadStorage, contactRemark, groupService, authPermissionService)
-	splitUploadService := service.NewSplitUploadService(baseService, splitUpload, conf, filesystem)
+	groupService := service.NewGroupService(baseService, repoGroup, groupMember, relation, userBlock, unreadStorage, authPermissionService, talkMessageService)
+	talkAuthService := service.NewTalkAuthService(organizeOrganize, repoContact, userBlock, repoGroup, authPermissionService)
+	session := talk.NewSession(talkService, talkSessionService, redisLock, userService, clientStorage, messageStorage, contactService, unreadStorage, contactRemark, groupService, talkAuthService)
+	splitUploadService := service.NewSplitUploadService(baseService, splitUpload, conf, filesystem, redisLock)
 	groupMemberService := service.NewGroupMemberService(baseService, groupMember)
-	talkAuthService := service.NewTalkAuthService(organizeOrganize, repoContact)
-	message := talk.NewMessage(talkMessageService, talkService, talkRecordsVote, splitUploadService, contactService, groupMemberService, organizeService, talkAuthService, messageService)
+	message := talk.NewMessage(talkMessageService, talkService, talkRecordsVote, splitUploadService, contactService, groupMemberService, talkAuthService, messageService)
 	talkRecords := repo.NewTalkRecords(db)
 	talkRecordsService := service.NewTalkRecordsService(baseService, talkVote, talkRecordsVote, groupMember, talkRecords)
 	records := talk.NewRecords(talkRecordsService, groupMemberService, filesystem, authPermissionService)
 	emoticon := repo.NewEmoticon(db)
-	emoticonService := service.NewEmoticonService(baseService, emoticon, filesystem)
-	v1Emoticon := v1.NewEmoticon(filesystem, emoticonService, redisLock)
-	upload := v1.NewUpload(conf, filesystem, splitUploadService)
+	emoticonService := service.NewEmoticonService(baseService, emoticon, filesystem, fileBlob)
+	v1Emoticon := v1.NewEmoticon(filesystem, emoticonService, redisLock, mediaService)
+	upload := v1.NewUpload(conf, filesystem, splitUploadService, mediaService)
 	groupNotice := repo.NewGroupNotice(db)
-	groupNoticeService := service.NewGroupNoticeService(groupNotice)
-	groupGroup := group.NewGroup(groupService, groupMemberService, talkSessionService, userService, redisLock, contactService, groupNoticeService, talkMessageService)
-	notice := group.NewNotice(groupNoticeService, groupMemberService)
+	groupNoticeService := service.NewGroupNoticeService(baseService, groupNotice, groupMember, messageService)
+	groupMuteService := service.NewGroupMuteService(baseService, repoGroup, groupMember, talkMessageService)
+	groupGroup := group.NewGroup(groupService, groupMemberService, talkSessionService, userService, redisLock, contactService, groupNoticeService, talkMessageService, authPermissionService, groupMuteService)
+	notice := group.NewNotice(groupNoticeService, groupMemberService, authPermissionService)
 	groupApply := repo.NewGroupApply(db)
-	groupApplyService := service.NewGroupApplyService(baseService, groupApply)
-	apply := group.NewApply(groupApplyService, groupMemberService, groupService)
-	contactContact := contact.NewContact(contactService, clientStorage, userService, talkSessionService, talkMessageService, organizeService)
-	contactApplyService := service.NewContactApplyService(baseService)
-	contactApply := contact.NewApply(contactApplyService, userService, talkMessageService, contactService)
+	groupApplyService := service.NewGroupApplyService(baseService, groupApply, repoGroup, groupMember, groupService, authPermissionService)
+	apply := group.NewApply(groupApplyService, groupMemberService, groupService, authPermissionService)
+	groupRoleService := service.NewGroupRoleService(baseService, groupRole)
+	role := group.NewRole(groupRoleService, authPermissionService)
+	groupInviteLink := repo.NewGroupInviteLink(db)
+	groupInviteLinkService := service.NewGroupInviteLinkService(baseService, conf, groupInviteLink, repoGroup, groupMember, groupService, groupApplyService, authPermissionService)
+	inviteLink := group.NewInviteLink(groupInviteLinkService)
+	channel := repo.NewChannel(db)
+	channelMember := repo.NewChannelMember(db)
+	channelReaction := repo.NewChannelReaction(db)
+	channelView := repo.NewChannelView(db)
+	channelViewStorage := cache.NewChannelViewStorage(client)
+	channelService := service.NewChannelService(baseService, channel, channelMember, channelReaction, channelView, channelViewStorage, talkRecordsService, talkSessionService, messageService)
+	v1Channel := v1.NewChannel(channelService)
+	liveRoomStorage := cache.NewLiveRoomStorage(client, serverStorage)
+	liveRoomService := service.NewLiveRoomService(baseService, liveRoomStorage, users, jwtKeyService, moderationService)
+	live := v1.NewLive(liveRoomService)
 	contactGroup := repo.NewContactGroup(db)
 	contactGroupService := service.NewContactGroupService(baseService, contactGroup)
+	contactContact := contact.NewContact(contactService, contactGroupService, clientStorage, userService, talkSessionService, talkMessageService, organizeService)
+	contactApplyService := service.NewContactApplyService(baseService, organizeOrganize, userPrivacy, userBlock)
+	contactApply := contact.NewApply(contactApplyService, userService, talkMessageService, contactService)
 	group2 := contact.NewGroup(contactGroupService, contactService)
 	articleService := note2.NewArticleService(baseService)
 	articleAnnex := note.NewArticleAnnex(db)
-	articleAnnexService := note2.NewArticleAnnexService(baseService, articleAnnex, filesystem)
+	articleAnnexService := note2.NewArticleAnnexService(baseService, articleAnnex, filesystem, fileBlobService)
 	articleArticle := article.NewArticle(articleService, filesystem, articleAnnexService)
-	annex := article.NewAnnex(articleAnnexService, filesystem)
+	annex := article.NewAnnex(articleAnnexService, filesystem, fileBlobService)
 	class := article.NewClass(articleClassService)
 	articleTagService := note2.NewArticleTagService(baseService)
 	tag := article.NewTag(articleTagService)
-	sendMessage := talk.NewSendMessage(talkAuthService, messageService)
+	sendMessage := talk.NewSendMessage(talkAuthService, messageService, contactService)
 	webV1 := &web.V1{
 		Common:       common,
 		Auth:         auth,
 		User:         user,
+		Totp:         totp,
+		Privacy:      privacy,
 		Organize:     v1Organize,
 		Talk:         session,
 		TalkMessage:  message,
@@ -131,6 +173,10 @@ func Initialize(conf *config.Config) *AppProvider {
 		Group:        groupGroup,
 		GroupNotice:  notice,
 		GroupApply:   apply,
+		GroupRole:    role,
+		GroupInvite:  inviteLink,
+		Channel:      v1Channel,
+		Live:         live,
 		Contact:      contactContact,
 		ContactApply: contactApply,
 		ContactGroup: group2,
@@ -143,13 +189,30 @@ func Initialize(conf *config.Config) *AppProvider {
 	webHandler := &web.Handler{
 		V1: webV1,
 	}
-	index := v1_2.NewIndex()
-	captchaStorage := cache.NewCaptchaStorage(client)
-	test := repo.NewTest(db)
-	v1Auth := v1_2.NewAuth(conf, captchaStorage, test)
+	repoAdmin := repo.NewAdmin(db)
+	adminAuditLog := repo.NewAdminAuditLog(db)
+	adminService := service.NewAdminService(baseService, repoAdmin, adminAuditLog)
+	index := v1_2.NewIndex(adminService)
+	v1Auth := v1_2.NewAuth(conf, captchaStorage, tokenSessionStorage, jwtKeyService, loginLimitService, adminService)
+	v1_2Organize := v1_2.NewOrganize(userTotpService, adminService)
+	v1User := v1_2.NewUser(userService, userSessionService, adminService)
+	v1Group := v1_2.NewGroup(groupService, talkMessageService, adminService)
+	v1_2Emoticon := v1_2.NewEmoticon(emoticonService, adminService)
+	v1Robot := v1_2.NewRobot(robot, adminService)
+	account := v1_2.NewAccount(adminService)
+	audit := v1_2.NewAudit(adminService)
+	moderation := v1_2.NewModeration(moderationService, adminService)
 	adminV1 := &admin.V1{
-		Index: index,
-		Auth:  v1Auth,
+		Index:      index,
+		Auth:       v1Auth,
+		Organize:   v1_2Organize,
+		User:       v1User,
+		Group:      v1Group,
+		Emoticon:   v1_2Emoticon,
+		Robot:      v1Robot,
+		Account:    account,
+		Audit:      audit,
+		Moderation: moderation,
 	}
 	v2 := &admin.V2{}
 	adminHandler := &admin.Handler{
@@ -157,8 +220,10 @@ func Initialize(conf *config.Config) *AppProvider {
 		V2: v2,
 	}
 	v1Index := v1_3.NewIndex()
+	jwks := v1_3.NewJwks(jwtKeyService)
 	openV1 := &open.V1{
 		Index: v1Index,
+		Jwks:  jwks,
 	}
 	openHandler := &open.Handler{
 		V1: openV1,
@@ -168,7 +233,7 @@ func Initialize(conf *config.Config) *AppProvider {
 		Admin: adminHandler,
 		Open:  openHandler,
 	}
-	engine := router.NewRouter(conf, handlerHandler, tokenSessionStorage)
+	engine := router.NewRouter(conf, handlerHandler, tokenSessionStorage, jwtKeyService, adminService)
 	httpServer := provider.NewHttpServer(conf, engine)
 	appProvider := &AppProvider{
 		Config: conf,
@@ -181,8 +246,8 @@ func Initialize(conf *config.Config) *AppProvider {
 
 var providerSet = wire.NewSet(provider.NewMySQLClient, provider.NewRedisClient, provider.NewHttpClient, provider.NewEmailClient, provider.NewHttpServer, provider.NewFilesystem, provider.NewRequestClient, router.NewRouter, wire.Struct(new(web.Handler), "*"), wire.Struct(new(admin.Handler), "*"), wire.Struct(new(open.Handler), "*"), wire.Struct(new(handler.Handler), "*"), wire.Struct(new(AppProvider), "*"))
 
-var cacheProviderSet = wire.NewSet(cache.NewTokenSessionStorage, cache.NewSidStorage, cache.NewUnreadStorage, cache.NewRedisLock, cache.NewClientStorage, cache.NewMessageStorage, cache.NewTalkVote, cache.NewRoomStorage, cache.NewRelation, cache.NewSmsCodeCache, cache.NewContactRemark, cache.NewSequence, cache.NewCaptchaStorage)
+var cacheProviderSet = wire.NewSet(cache.NewTokenSessionStorage, cache.NewSidStorage, cache.NewUnreadStorage, cache.NewRedisLock, cache.NewClientStorage, cache.NewMessageStorage, cache.NewTalkVote, cache.NewRoomStorage, cache.NewRelation, cache.NewSmsCodeCache, cache.NewContactRemark, cache.NewSequence, cache.NewCaptchaStorage, cache.NewJwtKeyStorage, cache.NewRateLimitStorage, cache.NewChannelViewStorage, cache.NewLiveRoomStorage)
 
-var daoProviderSet = wire.NewSet(repo.NewContact, repo.NewContactGroup, repo.NewGroupMember, repo.NewUsers, repo.NewGroup, repo.NewGroupApply, repo.NewTalkRecords, repo.NewGroupNotice, repo.NewTalkSession, repo.NewEmoticon, repo.NewTalkRecordsVote, repo.NewFileSplitUpload, note.NewArticleClass, note.NewArticleAnnex, organize.NewDepartment, organize.NewOrganize, organize.NewPosition, repo.NewRobot, repo.NewTest, repo.NewSequence)
+var daoProviderSet = wire.NewSet(repo.NewContact, repo.NewContactGroup, repo.NewGroupMember, repo.NewUsers, repo.NewGroup, repo.NewGroupApply, repo.NewTalkRecords, repo.NewGroupNotice, repo.NewTalkSession, repo.NewEmoticon, repo.NewTalkRecordsVote, repo.NewFileSplitUpload, repo.NewFileBlob, note.NewArticleClass, note.NewArticleAnnex, organize.NewDepartment, organize.NewOrganize, organize.NewPosition, repo.NewRobot, repo.NewTest, repo.NewSequence, repo.NewUserSession, repo.NewUserTotp, repo.NewUserIdentity, repo.NewAdmin, repo.NewAdminAuditLog, repo.NewGroupRole, repo.NewGroupInviteLink, repo.NewModerationWord, repo.NewModerationLink, repo.NewModerationReview, repo.NewUserPrivacy, repo.NewUserBlock, repo.NewChannel, repo.NewChannelMember, repo.NewChannelReaction, repo.NewChannelView)
 
-var serviceProviderSet = wire.NewSet(service.NewBaseService, service.NewUserService, service.NewSmsService, service.NewTalkService, service.NewTalkMessageService, service.NewGroupService, service.NewGroupMemberService, service.NewGroupNoticeService, service.NewGroupApplyService, service.NewTalkSessionService, service.NewEmoticonService, service.NewTalkRecordsService, service.NewContactService, service.NewContactApplyService, service.NewContactGroupService, service.NewSplitUploadService, service.NewIpAddressService, service.NewAuthPermissionService, service.NewMessageService, note2.NewArticleService, note2.NewArticleTagService, note2.NewArticleClassService, note2.NewArticleAnnexService, organize2.NewOrganizeDeptService, organize2.NewOrganizeService, organize2.NewPositionService, service.NewTemplateService, service.NewTalkAuthService, logic.NewMessageForwardLogic)
+var serviceProviderSet = wire.NewSet(service.NewBaseService, service.NewUserService, service.NewSmsService, service.NewTalkService, service.NewTalkMessageService, service.NewGroupService, service.NewGroupMemberService, service.NewGroupNoticeService, service.NewGroupApplyService, service.NewTalkSessionService, service.NewEmoticonService, service.NewTalkRecordsService, service.NewContactService, service.NewContactApplyService, service.NewContactGroupService, service.NewSplitUploadService, service.NewIpAddressService, service.NewAuthPermissionService, service.NewMessageService, service.NewMediaService, service.NewFileBlobService, service.NewUserSessionService, service.NewUserTotpService, service.NewJwtKeyService, service.NewLoginLimitService, service.NewUserOidcService, service.NewAdminService, service.NewGroupRoleService, service.NewGroupInviteLinkService, service.NewGroupMuteService, service.NewModerationService, service.NewUserPrivacyService, service.NewChannelService, service.NewLiveRoomService, note2.NewArticleService, note2.NewArticleTagService, note2.NewArticleClassService, note2.NewArticleAnnexService, organize2.NewOrganizeDeptService, organize2.NewOrganizeService, organize2.NewPositionService, service.NewTemplateService, service.NewTalkAuthService, logic.NewMessageForwardLogic)
@@ -18,10 +18,11 @@ import (
 type MessageForwardLogic struct {
 	db       *gorm.DB
 	sequence *repo.Sequence
+	blob     *repo.FileBlob
 }
 
-func NewMessageForwardLogic(db *gorm.DB, sequence *repo.Sequence) *MessageForwardLogic {
-	return &MessageForwardLogic{db: db, sequence: sequence}
+func NewMessageForwardLogic(db *gorm.DB, sequence *repo.Sequence, blob *repo.FileBlob) *MessageForwardLogic {
+	return &MessageForwardLogic{db: db, sequence: sequence, blob: blob}
 }
 
 type ForwardRecord struct {
@@ -40,6 +41,11 @@ func (m *MessageForwardLogic) Verify(ctx context.Context, uid int, req *message.
 		subWhere := m.db.Where("user_id = ? and receiver_id = ?", uid, req.Receiver.ReceiverId)
 		subWhere.Or("user_id = ? and receiver_id = ?", req.Receiver.ReceiverId, uid)
 		query.Where(subWhere)
+	} else {
+		// 仅可转发本群中自己可查看的消息
+		minRecordId := m.db.Table("group_member").Select("min_record_id").
+			Where("group_id = ? and user_id = ? and is_quit = 0", req.Receiver.ReceiverId, uid)
+		query.Where("receiver_id = ? and id >= (?)", req.Receiver.ReceiverId, minRecordId)
 	}
 
 	query.Where("talk_type = ?", req.Receiver.TalkType)
@@ -58,6 +64,20 @@ func (m *MessageForwardLogic) Verify(ctx context.Context, uid int, req *message.
 	return nil
 }
 
+// Contents 获取被转发的文本消息内容
+func (m *MessageForwardLogic) Contents(ctx context.Context, req *message.ForwardMessageRequest) ([]string, error) {
+
+	contents := make([]string, 0)
+	err := m.db.WithContext(ctx).Model(&model.TalkRecords{}).
+		Where("id in ? and msg_type = ?", req.MessageIds, entity.MsgTypeText).
+		Pluck("content", &contents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return contents, nil
+}
+
 // MultiMergeForward 批量合并转发
 func (m *MessageForwardLogic) MultiMergeForward(ctx context.Context, uid int, req *message.ForwardMessageRequest) ([]*ForwardRecord, error) {
 	var (
@@ -103,11 +123,7 @@ func (m *MessageForwardLogic) MultiMergeForward(ctx context.Context, uid int, re
 				ReceiverId: item["id"],
 			}
 
-			if data.TalkType == entity.ChatGroupMode {
-				data.Sequence = m.sequence.Get(ctx, 0, data.ReceiverId)
-			} else {
-				data.Sequence = m.sequence.Get(ctx, uid, data.ReceiverId)
-			}
+			data.Sequence = m.sequence.Get(ctx, data.TalkType, uid, data.ReceiverId)
 
 			records = append(records, data)
 		}
@@ -207,12 +223,7 @@ func (m *MessageForwardLogic) MultiSplitForward(ctx context.Context, uid int, re
 	err := db.Transaction(func(tx *gorm.DB) error {
 
 		for _, v := range receives {
-			var sequences []int64
-			if v["type"] == entity.ChatGroupMode {
-				sequences = m.sequence.BatchGet(ctx, 0, v["id"], int64(len(records)))
-			} else {
-				sequences = m.sequence.BatchGet(ctx, uid, v["id"], int64(len(records)))
-			}
+			sequences := m.sequence.BatchGet(ctx, v["type"], uid, v["id"], int64(len(records)))
 
 			items := make([]*model.TalkRecords, 0, len(receives))
 			files := make([]*model.TalkRecordsFile, 0)
@@ -262,6 +273,7 @@ func (m *MessageForwardLogic) MultiSplitForward(ctx context.Context, uid int, re
 							Suffix:       file.Suffix,
 							Size:         file.Size,
 							Path:         file.Path,
+							BlobId:       file.BlobId,
 						})
 					}
 				case entity.MsgTypeCode:
@@ -280,6 +292,15 @@ func (m *MessageForwardLogic) MultiSplitForward(ctx context.Context, uid int, re
 				if err := tx.Create(files).Error; err != nil {
 					return err
 				}
+
+				blobIds := make([]int, 0, len(files))
+				for _, file := range files {
+					blobIds = append(blobIds, file.BlobId)
+				}
+
+				if err := m.blob.Retain(tx, blobIds...); err != nil {
+					return err
+				}
 			}
 
 			if len(codes) > 0 {
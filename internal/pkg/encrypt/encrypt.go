@@ -1,7 +1,9 @@
 package encrypt
 
 import (
+	"crypto/hmac"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 
@@ -15,6 +17,21 @@ func Md5(str string) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// Sha256 计算数据的 SHA-256 摘要(十六进制)
+func Sha256(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// HmacSha256 计算数据的 HMAC-SHA256 签名(十六进制)
+func HmacSha256(key, data string) string {
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write([]byte(data))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func HashPassword(value string) string {
 	hashedBytes, _ := bcrypt.GenerateFromPassword([]byte(value), bcrypt.DefaultCost)
 	return string(hashedBytes)
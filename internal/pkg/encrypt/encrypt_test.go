@@ -24,9 +24,18 @@ func TestMd5(t *testing.T) {
 	assert.Equal(t, "c069d1fbc7bf8e994de8299110e68bc5", Md5("s6hqzp6j0kdfzh4n_cjq6b180000gn"))
 }
 
+func TestHmacSha256(t *testing.T) {
+	assert.Equal(t, "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", HmacSha256("key", "The quick brown fox jumps over the lazy dog"))
+	assert.NotEqual(t, HmacSha256("key", "data"), HmacSha256("key2", "data"))
+}
+
 func TestVerifyPassword(t *testing.T) {
 	pwd := HashPassword("admin123")
 
 	assert.Equal(t, true, VerifyPassword(pwd, "admin123"))
 	assert.Equal(t, false, VerifyPassword(pwd, "admin1234453"))
 }
+
+func TestSha256(t *testing.T) {
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", Sha256([]byte("hello")))
+}
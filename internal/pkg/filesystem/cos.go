@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -142,9 +143,27 @@ func (c *CosFilesystem) PrivateUrl(filePath string, timeout int) string {
 
 // ReadStream 读取文件流信息
 func (c *CosFilesystem) ReadStream(filePath string) ([]byte, error) {
-	return nil, nil
+	reader, err := c.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	return io.ReadAll(reader)
 }
 
+// Open 打开文件读取流
+func (c *CosFilesystem) Open(filePath string) (io.ReadCloser, error) {
+	resp, err := c.client.Object.Get(context.Background(), filePath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// InitiateMultipartUpload 初始化分片上传
 func (c *CosFilesystem) InitiateMultipartUpload(filePath string, fileName string) (string, error) {
 	opt := &cos.InitiateMultipartUploadOptions{
 		ObjectPutHeaderOptions: &cos.ObjectPutHeaderOptions{
@@ -160,6 +179,7 @@ func (c *CosFilesystem) InitiateMultipartUpload(filePath string, fileName string
 	return resp.UploadID, nil
 }
 
+// UploadPart 上传分片
 func (c *CosFilesystem) UploadPart(filePath string, uploadID string, num int, stream []byte) (string, error) {
 	resp, err := c.client.Object.UploadPart(context.Background(), filePath, uploadID, num, bytes.NewBuffer(stream), nil)
 
@@ -170,8 +190,24 @@ func (c *CosFilesystem) UploadPart(filePath string, uploadID string, num int, st
 	return strings.Trim(resp.Header.Get("ETag"), `"`), nil
 }
 
-func (c *CosFilesystem) CompleteMultipartUpload(filePath string, uploadID string, opt *cos.CompleteMultipartUploadOptions) error {
+// CompleteMultipartUpload 合并分片
+func (c *CosFilesystem) CompleteMultipartUpload(filePath string, uploadID string, parts []MultipartPart) error {
+	opt := &cos.CompleteMultipartUploadOptions{}
+	for _, part := range parts {
+		opt.Parts = append(opt.Parts, cos.Object{
+			PartNumber: part.Num,
+			ETag:       part.ETag,
+		})
+	}
+
 	_, _, err := c.client.Object.CompleteMultipartUpload(context.Background(), filePath, uploadID, opt)
 
 	return err
 }
+
+// AbortMultipartUpload 取消分片上传
+func (c *CosFilesystem) AbortMultipartUpload(filePath string, uploadID string) error {
+	_, err := c.client.Object.AbortMultipartUpload(context.Background(), filePath, uploadID)
+
+	return err
+}
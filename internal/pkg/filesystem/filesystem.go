@@ -1,6 +1,8 @@
 package filesystem
 
 import (
+	"fmt"
+	"io"
 	"time"
 
 	"go-chat/config"
@@ -37,7 +39,26 @@ type IAdapter interface {
 	// ReadStream 读取文件内容
 	ReadStream(filePath string) ([]byte, error)
 
+	// Open 打开文件读取流，调用方需关闭
+	Open(filePath string) (io.ReadCloser, error)
+
+	// InitiateMultipartUpload 初始化分片上传，返回上传ID
 	InitiateMultipartUpload(filePath string, fileName string) (string, error)
+
+	// UploadPart 上传分片(分片序号从 1 开始)，返回分片 ETag
+	UploadPart(filePath string, uploadId string, num int, stream []byte) (string, error)
+
+	// CompleteMultipartUpload 按分片序号合并分片
+	CompleteMultipartUpload(filePath string, uploadId string, parts []MultipartPart) error
+
+	// AbortMultipartUpload 取消分片上传并清理已上传的分片
+	AbortMultipartUpload(filePath string, uploadId string) error
+}
+
+// MultipartPart 已上传的分片信息
+type MultipartPart struct {
+	Num  int    // 分片序号
+	ETag string // 分片 ETag
 }
 
 // FileStat 文件信息
@@ -54,6 +75,9 @@ type Filesystem struct {
 	Default IAdapter
 	Local   *LocalFilesystem
 	Cos     *CosFilesystem
+	S3      *S3Filesystem
+	Oss     *OssFilesystem
+	Qiniu   *QiniuFilesystem
 }
 
 func NewFilesystem(conf *config.Config) *Filesystem {
@@ -63,11 +87,13 @@ func NewFilesystem(conf *config.Config) *Filesystem {
 
 	s.Local = NewLocalFilesystem(conf)
 	s.Cos = NewCosFilesystem(conf)
+	s.S3 = NewS3Filesystem(conf)
+	s.Oss = NewOssFilesystem(conf)
+	s.Qiniu = NewQiniuFilesystem(conf)
 
-	switch s.driver {
-	case "cos":
-		s.Default = s.Cos
-	default:
+	if adapter, err := s.Drive(s.driver); err == nil {
+		s.Default = adapter
+	} else {
 		s.Default = s.Local
 	}
 
@@ -81,3 +107,21 @@ func (f *Filesystem) Driver() string {
 func (f *Filesystem) SetDriver(value string) {
 	f.driver = value
 }
+
+// Drive 根据驱动名称获取文件系统，用于访问非默认驱动存储的文件
+func (f *Filesystem) Drive(driver string) (IAdapter, error) {
+	switch driver {
+	case "local":
+		return f.Local, nil
+	case "cos":
+		return f.Cos, nil
+	case "s3":
+		return f.S3, nil
+	case "oss":
+		return f.Oss, nil
+	case "qiniu":
+		return f.Qiniu, nil
+	}
+
+	return nil, fmt.Errorf("未知文件驱动类型[%s]", driver)
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -52,11 +53,13 @@ func (s *LocalFilesystem) Write(data []byte, filePath string) error {
 		}
 	}
 
-	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR, 0755)
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0755)
 	if err != nil {
 		return err
 	}
 
+	defer f.Close()
+
 	_, err = f.Write(data)
 	return err
 }
@@ -107,6 +110,8 @@ func (s *LocalFilesystem) AppendWrite(data []byte, filePath string) error {
 		return err
 	}
 
+	defer f.Close()
+
 	_, err = f.Write(data)
 	return err
 }
@@ -167,8 +172,60 @@ func (s *LocalFilesystem) ReadStream(filePath string) ([]byte, error) {
 	return os.ReadFile(s.Path(filePath))
 }
 
+// Open 打开文件读取流
+func (s *LocalFilesystem) Open(filePath string) (io.ReadCloser, error) {
+	return os.Open(s.Path(filePath))
+}
+
+// InitiateMultipartUpload 初始化分片上传
 func (s *LocalFilesystem) InitiateMultipartUpload(_ string, _ string) (string, error) {
 	str := fmt.Sprintf("%d%s", time.Now().Unix(), encrypt.Md5(strutil.Random(20)))
 
 	return str, nil
 }
+
+// UploadPart 上传分片，分片暂存于目标文件同级目录
+func (s *LocalFilesystem) UploadPart(filePath string, uploadId string, num int, stream []byte) (string, error) {
+	if err := s.Write(stream, s.partPath(filePath, uploadId, num)); err != nil {
+		return "", err
+	}
+
+	return encrypt.Md5(string(stream)), nil
+}
+
+// CompleteMultipartUpload 按分片序号合并分片
+func (s *LocalFilesystem) CompleteMultipartUpload(filePath string, uploadId string, parts []MultipartPart) error {
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].Num < parts[j].Num
+	})
+
+	// 清理上次合并失败残留的文件
+	_ = os.Remove(s.Path(filePath))
+
+	for _, part := range parts {
+		stream, err := s.ReadStream(s.partPath(filePath, uploadId, part.Num))
+		if err != nil {
+			return err
+		}
+
+		if err := s.AppendWrite(stream, filePath); err != nil {
+			return err
+		}
+	}
+
+	return s.AbortMultipartUpload(filePath, uploadId)
+}
+
+// AbortMultipartUpload 删除已上传的分片
+func (s *LocalFilesystem) AbortMultipartUpload(filePath string, uploadId string) error {
+	return os.RemoveAll(s.Path(s.partDir(filePath, uploadId)))
+}
+
+func (s *LocalFilesystem) partDir(filePath string, uploadId string) string {
+	return fmt.Sprintf("%s.parts/%s", filePath, uploadId)
+}
+
+func (s *LocalFilesystem) partPath(filePath string, uploadId string, num int) string {
+	return fmt.Sprintf("%s/%d.tmp", s.partDir(filePath, uploadId), num)
+}
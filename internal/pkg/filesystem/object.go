@@ -0,0 +1,353 @@
+package filesystem
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// objectSigner 对象存储请求签名
+type objectSigner interface {
+	// Sign 请求签名
+	Sign(req *http.Request, payload []byte) error
+
+	// Presign 生成带签名的临时访问地址
+	Presign(req *http.Request, expires time.Duration) (string, error)
+}
+
+// objectStorage S3 风格对象存储 REST 客户端，S3 兼容协议及阿里云 OSS 共用
+type objectStorage struct {
+	client     *http.Client
+	signer     objectSigner
+	bucket     string
+	endpoint   *url.URL // 存储服务地址
+	pathStyle  bool     // 是否使用路径风格访问 endpoint/bucket/key
+	domain     string   // 公开访问域名
+	copyHeader string   // 拷贝文件请求头，如 x-amz-copy-source
+}
+
+type objectInitiateResult struct {
+	UploadId string `xml:"UploadId"`
+}
+
+type objectCompletePart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type objectCompleteRequest struct {
+	XMLName xml.Name              `xml:"CompleteMultipartUpload"`
+	Parts   []*objectCompletePart `xml:"Part"`
+}
+
+type objectError struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// objectURL 获取对象访问地址
+func (o *objectStorage) objectURL(filePath string, query url.Values) *url.URL {
+	u := *o.endpoint
+
+	key := "/" + strings.TrimLeft(filePath, "/")
+	if o.pathStyle {
+		u.Path = "/" + o.bucket + key
+	} else {
+		u.Host = o.bucket + "." + u.Host
+		u.Path = key
+	}
+
+	u.RawPath = objectEscape(u.Path, true)
+	u.RawQuery = objectQuery(query)
+
+	return &u
+}
+
+func (o *objectStorage) do(method string, filePath string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, o.objectURL(filePath, query).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	req.ContentLength = int64(len(body))
+
+	if err := o.signer.Sign(req, body); err != nil {
+		return nil, err
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+
+		data, _ := io.ReadAll(resp.Body)
+
+		e := &objectError{}
+		if xml.Unmarshal(data, e) == nil && e.Code != "" {
+			return nil, fmt.Errorf("%s %s: %s", method, filePath, e.Code)
+		}
+
+		return nil, fmt.Errorf("%s %s: %s", method, filePath, resp.Status)
+	}
+
+	return resp, nil
+}
+
+func (o *objectStorage) doClose(method string, filePath string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	resp, err := o.do(method, filePath, query, header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return resp, nil
+}
+
+// Write 文件写入
+func (o *objectStorage) Write(data []byte, filePath string) error {
+	header := http.Header{}
+	header.Set("Content-Type", http.DetectContentType(data))
+
+	_, err := o.doClose(http.MethodPut, filePath, nil, header, data)
+
+	return err
+}
+
+// WriteLocal 本地文件上传
+func (o *objectStorage) WriteLocal(localFile string, filePath string) error {
+	data, err := os.ReadFile(localFile)
+	if err != nil {
+		return err
+	}
+
+	return o.Write(data, filePath)
+}
+
+// Copy 文件拷贝
+func (o *objectStorage) Copy(srcPath, filePath string) error {
+	header := http.Header{}
+	header.Set(o.copyHeader, "/"+o.bucket+"/"+objectEscape(strings.TrimLeft(srcPath, "/"), true))
+
+	_, err := o.doClose(http.MethodPut, filePath, nil, header, nil)
+
+	return err
+}
+
+// Delete 删除一个文件或空文件夹
+func (o *objectStorage) Delete(filePath string) error {
+	_, err := o.doClose(http.MethodDelete, filePath, nil, nil, nil)
+
+	return err
+}
+
+// DeleteDir 删除文件夹
+func (o *objectStorage) DeleteDir(path string) error {
+	return o.Delete(strings.TrimSuffix(path, "/") + "/")
+}
+
+// CreateDir 递归创建文件夹
+func (o *objectStorage) CreateDir(path string) error {
+	_, err := o.doClose(http.MethodPut, strings.TrimSuffix(path, "/")+"/", nil, nil, nil)
+
+	return err
+}
+
+// Stat 文件信息
+func (o *objectStorage) Stat(filePath string) (*FileStat, error) {
+	resp, err := o.doClose(http.MethodHead, filePath, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	size, _ := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+
+	return &FileStat{
+		Name:        path.Base(filePath),
+		Size:        size,
+		Ext:         path.Ext(filePath),
+		LastModTime: modTime,
+		MimeType:    resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+// PublicUrl 获取公开文件的访问地址
+func (o *objectStorage) PublicUrl(filePath string) string {
+	if o.domain != "" {
+		return fmt.Sprintf("%s/%s", strings.TrimRight(o.domain, "/"), objectEscape(strings.TrimLeft(filePath, "/"), true))
+	}
+
+	return o.objectURL(filePath, nil).String()
+}
+
+// PrivateUrl 获取私有文件的临时访问地址
+func (o *objectStorage) PrivateUrl(filePath string, timeout int) string {
+	req, err := http.NewRequest(http.MethodGet, o.objectURL(filePath, nil).String(), nil)
+	if err != nil {
+		return ""
+	}
+
+	value, err := o.signer.Presign(req, time.Duration(timeout)*time.Second)
+	if err != nil {
+		return ""
+	}
+
+	return value
+}
+
+// ReadStream 读取文件内容
+func (o *objectStorage) ReadStream(filePath string) ([]byte, error) {
+	reader, err := o.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// Open 打开文件读取流
+func (o *objectStorage) Open(filePath string) (io.ReadCloser, error) {
+	resp, err := o.do(http.MethodGet, filePath, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// InitiateMultipartUpload 初始化分片上传
+func (o *objectStorage) InitiateMultipartUpload(filePath string, fileName string) (string, error) {
+	header := http.Header{}
+	header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+
+	resp, err := o.do(http.MethodPost, filePath, url.Values{"uploads": {""}}, header, nil)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	result := &objectInitiateResult{}
+	if err := xml.NewDecoder(resp.Body).Decode(result); err != nil {
+		return "", err
+	}
+
+	if result.UploadId == "" {
+		return "", errors.New("初始化分片上传失败")
+	}
+
+	return result.UploadId, nil
+}
+
+// UploadPart 上传分片
+func (o *objectStorage) UploadPart(filePath string, uploadId string, num int, stream []byte) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(num)}, "uploadId": {uploadId}}
+
+	resp, err := o.doClose(http.MethodPut, filePath, query, nil, stream)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Trim(resp.Header.Get("ETag"), `"`), nil
+}
+
+// CompleteMultipartUpload 合并分片
+func (o *objectStorage) CompleteMultipartUpload(filePath string, uploadId string, parts []MultipartPart) error {
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].Num < parts[j].Num
+	})
+
+	data := &objectCompleteRequest{}
+	for _, part := range parts {
+		data.Parts = append(data.Parts, &objectCompletePart{PartNumber: part.Num, ETag: `"` + part.ETag + `"`})
+	}
+
+	body, err := xml.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/xml")
+
+	resp, err := o.do(http.MethodPost, filePath, url.Values{"uploadId": {uploadId}}, header, body)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	// 合并失败时服务端可能返回 200 状态码及错误信息
+	result, _ := io.ReadAll(resp.Body)
+
+	e := &objectError{}
+	if xml.Unmarshal(result, e) == nil && e.Code != "" {
+		return fmt.Errorf("合并分片失败: %s", e.Code)
+	}
+
+	return nil
+}
+
+// AbortMultipartUpload 取消分片上传
+func (o *objectStorage) AbortMultipartUpload(filePath string, uploadId string) error {
+	_, err := o.doClose(http.MethodDelete, filePath, url.Values{"uploadId": {uploadId}}, nil, nil)
+
+	return err
+}
+
+// objectEscape 按 RFC 3986 编码，keepSlash 为 true 时保留路径分隔符
+func objectEscape(value string, keepSlash bool) string {
+	var buf strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' || (keepSlash && c == '/') {
+			buf.WriteByte(c)
+		} else {
+			fmt.Fprintf(&buf, "%%%02X", c)
+		}
+	}
+
+	return buf.String()
+}
+
+// objectQuery 按参数名排序并编码查询参数，与签名使用的规范查询串保持一致
+func objectQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	items := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			items = append(items, objectEscape(key, false)+"="+objectEscape(value, false))
+		}
+	}
+
+	return strings.Join(items, "&")
+}
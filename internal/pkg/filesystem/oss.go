@@ -0,0 +1,152 @@
+package filesystem
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-chat/config"
+)
+
+// ossSubResources 参与签名的子资源
+var ossSubResources = map[string]struct{}{
+	"acl": {}, "uploads": {}, "uploadId": {}, "partNumber": {}, "location": {},
+	"security-token": {}, "response-content-type": {}, "response-content-disposition": {},
+}
+
+// OssFilesystem 阿里云 OSS 文件存储
+type OssFilesystem struct {
+	*objectStorage
+	conf *config.Config
+}
+
+// NewOssFilesystem ...
+// See: https://help.aliyun.com/document_detail/31951.html
+func NewOssFilesystem(conf *config.Config) *OssFilesystem {
+	opts := conf.Filesystem.Oss
+
+	endpoint, _ := url.Parse(opts.Endpoint)
+	if endpoint == nil || endpoint.Host == "" {
+		endpoint, _ = url.Parse("https://oss-cn-hangzhou.aliyuncs.com")
+	}
+
+	return &OssFilesystem{
+		conf: conf,
+		objectStorage: &objectStorage{
+			client: &http.Client{},
+			signer: &ossSigner{
+				bucket:        opts.Bucket,
+				accessId:      opts.AccessID,
+				accessSecret:  opts.AccessSecret,
+				securityToken: opts.SecurityToken,
+				now:           time.Now,
+			},
+			bucket:     opts.Bucket,
+			endpoint:   endpoint,
+			domain:     opts.Domain,
+			copyHeader: "x-oss-copy-source",
+		},
+	}
+}
+
+// ossSigner OSS V1 签名
+type ossSigner struct {
+	bucket        string
+	accessId      string
+	accessSecret  string
+	securityToken string
+	now           func() time.Time
+}
+
+// Sign 请求头签名
+func (s *ossSigner) Sign(req *http.Request, _ []byte) error {
+	date := s.now().UTC().Format(http.TimeFormat)
+
+	req.Header.Set("Date", date)
+
+	if s.securityToken != "" {
+		req.Header.Set("X-Oss-Security-Token", s.securityToken)
+	}
+
+	req.Header.Set("Authorization", "OSS "+s.accessId+":"+s.signature(req, date, req.URL.Query()))
+
+	return nil
+}
+
+// Presign 生成带签名的临时访问地址
+func (s *ossSigner) Presign(req *http.Request, expires time.Duration) (string, error) {
+	deadline := strconv.FormatInt(s.now().Add(expires).Unix(), 10)
+
+	query := req.URL.Query()
+	if s.securityToken != "" {
+		query.Set("security-token", s.securityToken)
+	}
+
+	signature := s.signature(req, deadline, query)
+
+	query.Set("OSSAccessKeyId", s.accessId)
+	query.Set("Expires", deadline)
+	query.Set("Signature", signature)
+
+	u := *req.URL
+	u.RawQuery = objectQuery(query)
+
+	return u.String(), nil
+}
+
+func (s *ossSigner) signature(req *http.Request, date string, query url.Values) string {
+	headers := make([]string, 0)
+	for key := range req.Header {
+		if key = strings.ToLower(key); strings.HasPrefix(key, "x-oss-") {
+			headers = append(headers, key)
+		}
+	}
+
+	sort.Strings(headers)
+
+	var buf strings.Builder
+
+	buf.WriteString(req.Method + "\n")
+	buf.WriteString(req.Header.Get("Content-MD5") + "\n")
+	buf.WriteString(req.Header.Get("Content-Type") + "\n")
+	buf.WriteString(date + "\n")
+
+	for _, key := range headers {
+		buf.WriteString(key + ":" + strings.TrimSpace(req.Header.Get(key)) + "\n")
+	}
+
+	buf.WriteString("/" + s.bucket + req.URL.Path)
+
+	keys := make([]string, 0)
+	for key := range query {
+		if _, ok := ossSubResources[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		if i == 0 {
+			buf.WriteString("?")
+		} else {
+			buf.WriteString("&")
+		}
+
+		buf.WriteString(key)
+		if value := query.Get(key); value != "" {
+			buf.WriteString("=" + value)
+		}
+	}
+
+	h := hmac.New(sha1.New, []byte(s.accessSecret))
+	h.Write([]byte(buf.String()))
+
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
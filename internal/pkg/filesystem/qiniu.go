@@ -0,0 +1,323 @@
+package filesystem
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"go-chat/config"
+)
+
+// QiniuFilesystem 七牛云文件存储
+type QiniuFilesystem struct {
+	conf   *config.Config
+	client *http.Client
+	upHost string
+	rsHost string
+}
+
+type qiniuStat struct {
+	Fsize    int64  `json:"fsize"`
+	MimeType string `json:"mimeType"`
+	PutTime  int64  `json:"putTime"` // 单位 100 纳秒
+}
+
+type qiniuError struct {
+	Error string `json:"error"`
+}
+
+// NewQiniuFilesystem ...
+// See: https://developer.qiniu.com/kodo/1312/upload
+func NewQiniuFilesystem(conf *config.Config) *QiniuFilesystem {
+	upHost := strings.TrimRight(conf.Filesystem.Qiniu.UpHost, "/")
+	if upHost == "" {
+		upHost = "https://up.qiniup.com"
+	}
+
+	rsHost := strings.TrimRight(conf.Filesystem.Qiniu.RsHost, "/")
+	if rsHost == "" {
+		rsHost = "https://rs.qbox.me"
+	}
+
+	return &QiniuFilesystem{conf: conf, client: &http.Client{}, upHost: upHost, rsHost: rsHost}
+}
+
+// Write 文件写入
+func (q *QiniuFilesystem) Write(data []byte, filePath string) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	_ = writer.WriteField("token", q.uploadToken(filePath))
+	_ = writer.WriteField("key", filePath)
+
+	part, err := writer.CreateFormFile("file", path.Base(filePath))
+	if err != nil {
+		return err
+	}
+
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", writer.FormDataContentType())
+
+	return q.do(http.MethodPost, q.upHost, header, body.Bytes(), nil)
+}
+
+// WriteLocal 本地文件上传
+func (q *QiniuFilesystem) WriteLocal(localFile string, filePath string) error {
+	data, err := os.ReadFile(localFile)
+	if err != nil {
+		return err
+	}
+
+	return q.Write(data, filePath)
+}
+
+// Copy 文件拷贝
+func (q *QiniuFilesystem) Copy(srcPath, filePath string) error {
+	return q.manage(fmt.Sprintf("/copy/%s/%s/force/true", q.entry(srcPath), q.entry(filePath)), nil)
+}
+
+// Delete 删除一个文件或空文件夹
+func (q *QiniuFilesystem) Delete(filePath string) error {
+	return q.manage("/delete/"+q.entry(filePath), nil)
+}
+
+// DeleteDir 删除文件夹，七牛云无目录概念
+func (q *QiniuFilesystem) DeleteDir(path string) error {
+	return nil
+}
+
+// CreateDir 递归创建文件夹，七牛云无目录概念
+func (q *QiniuFilesystem) CreateDir(path string) error {
+	return nil
+}
+
+// Stat 文件信息
+func (q *QiniuFilesystem) Stat(filePath string) (*FileStat, error) {
+	stat := &qiniuStat{}
+	if err := q.manage("/stat/"+q.entry(filePath), stat); err != nil {
+		return nil, err
+	}
+
+	return &FileStat{
+		Name:        path.Base(filePath),
+		Size:        stat.Fsize,
+		Ext:         path.Ext(filePath),
+		LastModTime: time.Unix(0, stat.PutTime*100),
+		MimeType:    stat.MimeType,
+	}, nil
+}
+
+// PublicUrl 获取公开文件的访问地址
+func (q *QiniuFilesystem) PublicUrl(filePath string) string {
+	return fmt.Sprintf("%s/%s", strings.TrimRight(q.conf.Filesystem.Qiniu.Domain, "/"), objectEscape(strings.TrimLeft(filePath, "/"), true))
+}
+
+// PrivateUrl 获取私有文件的临时访问地址
+// See: https://developer.qiniu.com/kodo/1202/download-token
+func (q *QiniuFilesystem) PrivateUrl(filePath string, timeout int) string {
+	value := fmt.Sprintf("%s?e=%d", q.PublicUrl(filePath), time.Now().Add(time.Duration(timeout)*time.Second).Unix())
+
+	return value + "&token=" + q.conf.Filesystem.Qiniu.AccessKey + ":" + q.sign([]byte(value))
+}
+
+// ReadStream 读取文件内容
+func (q *QiniuFilesystem) ReadStream(filePath string) ([]byte, error) {
+	reader, err := q.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// Open 打开文件读取流
+func (q *QiniuFilesystem) Open(filePath string) (io.ReadCloser, error) {
+	resp, err := q.client.Get(q.PrivateUrl(filePath, 60))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", filePath, resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// InitiateMultipartUpload 初始化分片上传
+// See: https://developer.qiniu.com/kodo/6364/multipartupload-interface
+func (q *QiniuFilesystem) InitiateMultipartUpload(filePath string, fileName string) (string, error) {
+	result := &struct {
+		UploadId string `json:"uploadId"`
+	}{}
+
+	if err := q.do(http.MethodPost, q.uploadUrl(filePath, ""), q.uploadHeader(filePath), nil, result); err != nil {
+		return "", err
+	}
+
+	if result.UploadId == "" {
+		return "", errors.New("初始化分片上传失败")
+	}
+
+	return result.UploadId, nil
+}
+
+// UploadPart 上传分片
+func (q *QiniuFilesystem) UploadPart(filePath string, uploadId string, num int, stream []byte) (string, error) {
+	result := &struct {
+		Etag string `json:"etag"`
+	}{}
+
+	header := q.uploadHeader(filePath)
+	header.Set("Content-Type", "application/octet-stream")
+
+	if err := q.do(http.MethodPut, fmt.Sprintf("%s/%d", q.uploadUrl(filePath, uploadId), num), header, stream, result); err != nil {
+		return "", err
+	}
+
+	return result.Etag, nil
+}
+
+// CompleteMultipartUpload 合并分片
+func (q *QiniuFilesystem) CompleteMultipartUpload(filePath string, uploadId string, parts []MultipartPart) error {
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].Num < parts[j].Num
+	})
+
+	type item struct {
+		PartNumber int    `json:"partNumber"`
+		Etag       string `json:"etag"`
+	}
+
+	items := make([]item, 0, len(parts))
+	for _, part := range parts {
+		items = append(items, item{PartNumber: part.Num, Etag: part.ETag})
+	}
+
+	body, err := json.Marshal(map[string]any{"parts": items, "fname": path.Base(filePath)})
+	if err != nil {
+		return err
+	}
+
+	header := q.uploadHeader(filePath)
+	header.Set("Content-Type", "application/json")
+
+	return q.do(http.MethodPost, q.uploadUrl(filePath, uploadId), header, body, nil)
+}
+
+// AbortMultipartUpload 取消分片上传
+func (q *QiniuFilesystem) AbortMultipartUpload(filePath string, uploadId string) error {
+	return q.do(http.MethodDelete, q.uploadUrl(filePath, uploadId), q.uploadHeader(filePath), nil, nil)
+}
+
+func (q *QiniuFilesystem) uploadUrl(filePath string, uploadId string) string {
+	value := fmt.Sprintf("%s/buckets/%s/objects/%s/uploads", q.upHost, q.conf.Filesystem.Qiniu.Bucket, base64.URLEncoding.EncodeToString([]byte(filePath)))
+	if uploadId != "" {
+		value += "/" + uploadId
+	}
+
+	return value
+}
+
+func (q *QiniuFilesystem) uploadHeader(filePath string) http.Header {
+	header := http.Header{}
+	header.Set("Authorization", "UpToken "+q.uploadToken(filePath))
+
+	return header
+}
+
+// uploadToken 生成上传凭证，有效期一小时
+// See: https://developer.qiniu.com/kodo/1208/upload-token
+func (q *QiniuFilesystem) uploadToken(filePath string) string {
+	policy, _ := json.Marshal(map[string]any{
+		"scope":    q.conf.Filesystem.Qiniu.Bucket + ":" + filePath,
+		"deadline": time.Now().Add(time.Hour).Unix(),
+	})
+
+	encoded := base64.URLEncoding.EncodeToString(policy)
+
+	return q.conf.Filesystem.Qiniu.AccessKey + ":" + q.sign([]byte(encoded)) + ":" + encoded
+}
+
+// manage 调用资源管理接口
+// See: https://developer.qiniu.com/kodo/1201/access-token
+func (q *QiniuFilesystem) manage(uri string, result any) error {
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header.Set("Authorization", "QBox "+q.conf.Filesystem.Qiniu.AccessKey+":"+q.sign([]byte(uri+"\n")))
+
+	return q.do(http.MethodPost, q.rsHost+uri, header, nil, result)
+}
+
+func (q *QiniuFilesystem) entry(filePath string) string {
+	return base64.URLEncoding.EncodeToString([]byte(q.conf.Filesystem.Qiniu.Bucket + ":" + filePath))
+}
+
+func (q *QiniuFilesystem) sign(data []byte) string {
+	h := hmac.New(sha1.New, []byte(q.conf.Filesystem.Qiniu.SecretKey))
+	h.Write(data)
+
+	return base64.URLEncoding.EncodeToString(h.Sum(nil))
+}
+
+func (q *QiniuFilesystem) do(method string, uri string, header http.Header, body []byte, result any) error {
+	req, err := http.NewRequest(method, uri, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		e := &qiniuError{}
+		if json.Unmarshal(data, e) == nil && e.Error != "" {
+			return fmt.Errorf("%s %s: %s", method, uri, e.Error)
+		}
+
+		return fmt.Errorf("%s %s: %s", method, uri, resp.Status)
+	}
+
+	if result != nil && len(data) > 0 {
+		return json.Unmarshal(data, result)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package filesystem
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-chat/config"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// S3Filesystem S3 兼容协议文件存储(AWS S3、MinIO 等)
+type S3Filesystem struct {
+	*objectStorage
+	conf *config.Config
+}
+
+// NewS3Filesystem ...
+// See: https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
+func NewS3Filesystem(conf *config.Config) *S3Filesystem {
+	opts := conf.Filesystem.S3
+
+	endpoint, _ := url.Parse(opts.Endpoint)
+	if endpoint == nil || endpoint.Host == "" {
+		endpoint, _ = url.Parse("https://s3.amazonaws.com")
+	}
+
+	region := opts.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return &S3Filesystem{
+		conf: conf,
+		objectStorage: &objectStorage{
+			client: &http.Client{},
+			signer: &s3Signer{
+				accessKey:    opts.AccessKey,
+				secretKey:    opts.SecretKey,
+				sessionToken: opts.SessionToken,
+				region:       region,
+				now:          time.Now,
+			},
+			bucket:     opts.Bucket,
+			endpoint:   endpoint,
+			pathStyle:  opts.PathStyle,
+			domain:     opts.Domain,
+			copyHeader: "x-amz-copy-source",
+		},
+	}
+}
+
+// s3Signer AWS Signature Version 4 签名
+type s3Signer struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	region       string
+	now          func() time.Time
+}
+
+// Sign 请求头签名
+func (s *s3Signer) Sign(req *http.Request, payload []byte) error {
+	now := s.now().UTC()
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", s3Hash(payload))
+
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	headers := make([]string, 0)
+	for key := range req.Header {
+		key = strings.ToLower(key)
+		if key == "host" || key == "content-type" || key == "content-md5" || strings.HasPrefix(key, "x-amz-") {
+			headers = append(headers, key)
+		}
+	}
+
+	sort.Strings(headers)
+
+	canonical := s.canonicalRequest(req, headers, req.URL.RawQuery, req.Header.Get("X-Amz-Content-Sha256"))
+
+	scope := s.scope(now)
+
+	req.Header.Del("Host")
+	req.Header.Set("Authorization", s3Algorithm+" Credential="+s.accessKey+"/"+scope+", SignedHeaders="+strings.Join(headers, ";")+", Signature="+s.signature(now, scope, canonical))
+
+	return nil
+}
+
+// Presign 生成带签名的临时访问地址
+func (s *s3Signer) Presign(req *http.Request, expires time.Duration) (string, error) {
+	now := s.now().UTC()
+
+	scope := s.scope(now)
+
+	query := req.URL.Query()
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	if s.sessionToken != "" {
+		query.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	req.Header.Set("Host", req.URL.Host)
+
+	rawQuery := objectQuery(query)
+
+	canonical := s.canonicalRequest(req, []string{"host"}, rawQuery, s3UnsignedPayload)
+
+	u := *req.URL
+	u.RawQuery = rawQuery + "&X-Amz-Signature=" + s.signature(now, scope, canonical)
+
+	return u.String(), nil
+}
+
+func (s *s3Signer) scope(now time.Time) string {
+	return now.Format(s3DateFormat) + "/" + s.region + "/s3/aws4_request"
+}
+
+func (s *s3Signer) canonicalRequest(req *http.Request, headers []string, rawQuery string, payloadHash string) string {
+	items := make([]string, 0, len(headers))
+	for _, key := range headers {
+		items = append(items, key+":"+strings.TrimSpace(req.Header.Get(key))+"\n")
+	}
+
+	return strings.Join([]string{
+		req.Method,
+		objectEscape(req.URL.Path, true),
+		rawQuery,
+		strings.Join(items, ""),
+		strings.Join(headers, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+func (s *s3Signer) signature(now time.Time, scope string, canonical string) string {
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format(s3TimeFormat),
+		scope,
+		s3Hash([]byte(canonical)),
+	}, "\n")
+
+	key := s3Hmac([]byte("AWS4"+s.secretKey), now.Format(s3DateFormat))
+	key = s3Hmac(key, s.region)
+	key = s3Hmac(key, "s3")
+	key = s3Hmac(key, "aws4_request")
+
+	return hex.EncodeToString(s3Hmac(key, stringToSign))
+}
+
+func s3Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func s3Hmac(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -20,20 +20,24 @@ var (
 type IStore interface {
 	// IsBlackList 判断是否是黑名单
 	IsBlackList(ctx context.Context, token string) bool
+
+	// IsRevoked 判断设备会话是否已注销
+	IsRevoked(ctx context.Context, sessionId string) bool
 }
 
 type JSession struct {
 	Uid       int    `json:"uid"`
 	Token     string `json:"token"`
+	SessionId string `json:"session_id"` // 设备会话标识
 	ExpiresAt int64  `json:"expires_at"`
 }
 
-// Auth 授权中间件
-func Auth(secret string, guard string, store IStore) gin.HandlerFunc {
+// Auth 授权中间件，携带 kid 的令牌通过 keys 查找验签公钥
+func Auth(secret string, keys jwt.KeyFinder, guard string, store IStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := QueryToken(c)
 
-		claims, err := verify(guard, secret, token)
+		claims, err := verify(c.Request.Context(), guard, secret, keys, token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"code": 401, "message": err.Error()})
 			c.Abort()
@@ -41,7 +45,7 @@ func Auth(secret string, guard string, store IStore) gin.HandlerFunc {
 		}
 
 		// 这里还需要验证 token 黑名单
-		if store.IsBlackList(c.Request.Context(), token) {
+		if store.IsBlackList(c.Request.Context(), token) || (claims.Subject != "" && store.IsRevoked(c.Request.Context(), claims.Subject)) {
 			c.JSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "请登录再试."})
 			c.Abort()
 			return
@@ -59,6 +63,7 @@ func Auth(secret string, guard string, store IStore) gin.HandlerFunc {
 		c.Set(JWTSessionConst, &JSession{
 			Uid:       uid,
 			Token:     token,
+			SessionId: claims.Subject,
 			ExpiresAt: claims.ExpiresAt.Unix(),
 		})
 
@@ -79,13 +84,13 @@ func QueryToken(c *gin.Context) string {
 	return token
 }
 
-func verify(guard string, secret string, token string) (*jwt.AuthClaims, error) {
+func verify(ctx context.Context, guard string, secret string, keys jwt.KeyFinder, token string) (*jwt.AuthClaims, error) {
 
 	if token == "" {
 		return nil, ErrorNoLogin
 	}
 
-	claims, err := jwt.ParseToken(token, secret)
+	claims, err := jwt.ParseTokenWithKey(ctx, token, secret, keys)
 	if err != nil {
 		return nil, err
 	}
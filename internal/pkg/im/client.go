@@ -41,6 +41,7 @@ type Client struct {
 	conn     IConn                  // 客户端连接
 	cid      int64                  // 客户端ID/客户端唯一标识
 	uid      int                    // 用户ID
+	device   string                 // 设备会话标识
 	lastTime int64                  // 客户端最后心跳时间/心跳检测
 	channel  *Channel               // 渠道分组
 	isClosed bool                   // 客户端是否关闭连接
@@ -51,6 +52,7 @@ type Client struct {
 
 type ClientOption struct {
 	Uid     int      // 用户识别ID
+	Device  string   // 设备会话标识，用于注销设备时断开连接
 	Channel *Channel // 渠道信息
 	Storage IStorage // 自定义缓存组件，用于绑定用户与客户端的关系
 	Buffer  int      // 缓冲区大小根据业务，自行调整
@@ -72,6 +74,7 @@ func NewClient(ctx context.Context, conn IConn, opt *ClientOption, callBack ICal
 		cid:      Counter.GenID(),
 		lastTime: time.Now().Unix(),
 		uid:      opt.Uid,
+		device:   opt.Device,
 		channel:  opt.Channel,
 		storage:  opt.Storage,
 		outChan:  make(chan *ClientOutContent, opt.Buffer),
@@ -108,6 +111,11 @@ func (c *Client) Uid() int {
 	return c.uid
 }
 
+// Device 获取客户端关联的设备会话标识
+func (c *Client) Device() string {
+	return c.device
+}
+
 // Close 关闭客户端连接
 func (c *Client) Close(code int, message string) {
 	defer func() {
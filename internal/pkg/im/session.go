@@ -17,6 +17,7 @@ var once sync.Once
 type session struct {
 	Chat    *Channel // 默认分组
 	Example *Channel // 案例分组
+	Live    *Channel // 直播聊天室分组
 
 	// 可自行注册其它渠道...
 }
@@ -31,6 +32,7 @@ func initialize(ctx context.Context, eg *errgroup.Group) {
 	Session = &session{
 		Chat:    NewChannel("chat", NewNode(10), make(chan *SenderContent, 5<<20)),
 		Example: NewChannel("example", NewNode(1), make(chan *SenderContent, 100)),
+		Live:    NewChannel("live", NewNode(5), make(chan *SenderContent, 10000)),
 	}
 
 	// 延时启动守护协程
@@ -46,5 +48,9 @@ func initialize(ctx context.Context, eg *errgroup.Group) {
 		eg.Go(func() error {
 			return Session.Example.Start(ctx)
 		})
+
+		eg.Go(func() error {
+			return Session.Live.Start(ctx)
+		})
 	})
 }
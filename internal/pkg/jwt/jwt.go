@@ -1,14 +1,23 @@
 package jwt
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
+var ErrKeyNotFound = errors.New("jwt: signing key not found")
+
 type Options jwt.RegisteredClaims
 
+// KeyFinder 根据 kid 查找验签密钥
+type KeyFinder interface {
+	FindKey(ctx context.Context, kid string) (*Key, error)
+}
+
 type AuthClaims struct {
 	Guard string `json:"guard"` // 授权守卫
 	jwt.RegisteredClaims
@@ -18,10 +27,8 @@ func NewNumericDate(t time.Time) *jwt.NumericDate {
 	return jwt.NewNumericDate(t)
 }
 
-// GenerateToken 生成 JWT 令牌
-func GenerateToken(guard string, secret string, ops *Options) string {
-
-	claims := AuthClaims{
+func newClaims(guard string, ops *Options) AuthClaims {
+	return AuthClaims{
 		Guard: guard,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Audience:  ops.Audience,
@@ -33,8 +40,12 @@ func GenerateToken(guard string, secret string, ops *Options) string {
 			Subject:   ops.Subject,
 		},
 	}
+}
+
+// GenerateToken 生成 JWT 令牌
+func GenerateToken(guard string, secret string, ops *Options) string {
 
-	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims(guard, ops)).SignedString([]byte(secret))
 
 	return tokenString
 }
@@ -56,3 +67,60 @@ func ParseToken(token string, secret string) (*AuthClaims, error) {
 
 	return nil, err
 }
+
+// GenerateTokenWithKey 使用非对称密钥生成 JWT 令牌，header 中携带 kid
+func GenerateTokenWithKey(guard string, key *Key, ops *Options) (string, error) {
+
+	method, err := key.method()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(method, newClaims(guard, ops))
+	token.Header["kid"] = key.Kid
+
+	return token.SignedString(key.PrivateKey)
+}
+
+// ParseTokenWithKey 解析 JWT Token，携带 kid 的令牌通过 finder 查找验签公钥，
+// 未携带 kid 的令牌按 HS256 使用 secret 验签，secret 为空时不再接受此类令牌
+func ParseTokenWithKey(ctx context.Context, token string, secret string, finder KeyFinder) (*AuthClaims, error) {
+
+	data, err := jwt.ParseWithClaims(token, &AuthClaims{}, func(token *jwt.Token) (interface{}, error) {
+
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || secret == "" {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+
+			return []byte(secret), nil
+		}
+
+		if finder == nil {
+			return nil, ErrKeyNotFound
+		}
+
+		key, err := finder.FindKey(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		// 算法必须与密钥一致，防止算法混淆
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		return key.PublicKey, nil
+	})
+
+	if data == nil {
+		return nil, err
+	}
+
+	if claims, ok := data.Claims.(*AuthClaims); ok && data.Valid {
+		return claims, nil
+	}
+
+	return nil, err
+}
@@ -0,0 +1,214 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var ErrUnsupportedAlgorithm = errors.New("jwt: unsupported algorithm")
+
+// Key 非对称签名密钥，通过 kid 区分
+type Key struct {
+	Kid        string
+	Algorithm  string
+	PrivateKey crypto.Signer // 签名私钥，仅签发方需要
+	PublicKey  crypto.PublicKey
+	CreatedAt  time.Time
+	RetiredAt  time.Time // 退役时间，零值表示当前签名密钥
+}
+
+type keyJSON struct {
+	Kid        string `json:"kid"`
+	Algorithm  string `json:"alg"`
+	PrivateKey string `json:"private_key,omitempty"`
+	PublicKey  string `json:"public_key"`
+	CreatedAt  int64  `json:"created_at"`
+	RetiredAt  int64  `json:"retired_at,omitempty"`
+}
+
+// GenerateKey 生成签名密钥
+func GenerateKey(alg string) (*Key, error) {
+
+	var (
+		private crypto.Signer
+		err     error
+	)
+
+	switch alg {
+	case AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{
+		Algorithm:  alg,
+		PrivateKey: private,
+		PublicKey:  private.Public(),
+		CreatedAt:  time.Now(),
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// kid 取公钥指纹，便于排查
+	sum := sha256.Sum256(der)
+	key.Kid = hex.EncodeToString(sum[:8])
+
+	return key, nil
+}
+
+// IsRetired 是否已退役
+func (k *Key) IsRetired() bool {
+	return !k.RetiredAt.IsZero()
+}
+
+// IsExpired 退役密钥超过宽限期后不再用于验签
+func (k *Key) IsExpired(grace time.Duration) bool {
+	return k.IsRetired() && time.Since(k.RetiredAt) > grace
+}
+
+func (k *Key) method() (jwt.SigningMethod, error) {
+	switch k.Algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, ErrUnsupportedAlgorithm
+}
+
+// JWK 公钥的 JWK 表示
+func (k *Key) JWK() map[string]string {
+
+	data := map[string]string{
+		"kid": k.Kid,
+		"alg": k.Algorithm,
+		"use": "sig",
+	}
+
+	switch value := k.PublicKey.(type) {
+	case *rsa.PublicKey:
+		data["kty"] = "RSA"
+		data["n"] = base64.RawURLEncoding.EncodeToString(value.N.Bytes())
+		data["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(value.E)).Bytes())
+	case ed25519.PublicKey:
+		data["kty"] = "OKP"
+		data["crv"] = "Ed25519"
+		data["x"] = base64.RawURLEncoding.EncodeToString(value)
+	}
+
+	return data
+}
+
+// MarshalJSON 以 PEM 格式序列化密钥
+func (k *Key) MarshalJSON() ([]byte, error) {
+
+	data := keyJSON{
+		Kid:       k.Kid,
+		Algorithm: k.Algorithm,
+		CreatedAt: k.CreatedAt.Unix(),
+	}
+
+	if k.IsRetired() {
+		data.RetiredAt = k.RetiredAt.Unix()
+	}
+
+	public, err := x509.MarshalPKIXPublicKey(k.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	data.PublicKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public}))
+
+	if k.PrivateKey != nil {
+		private, err := x509.MarshalPKCS8PrivateKey(k.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		data.PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: private}))
+	}
+
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON 解析 PEM 格式的密钥
+func (k *Key) UnmarshalJSON(value []byte) error {
+
+	data := keyJSON{}
+	if err := json.Unmarshal(value, &data); err != nil {
+		return err
+	}
+
+	block, _ := pem.Decode([]byte(data.PublicKey))
+	if block == nil {
+		return fmt.Errorf("jwt: invalid public key of kid %s", data.Kid)
+	}
+
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+
+	k.Kid = data.Kid
+	k.Algorithm = data.Algorithm
+	k.PublicKey = public
+	k.PrivateKey = nil
+	k.CreatedAt = time.Unix(data.CreatedAt, 0)
+	k.RetiredAt = time.Time{}
+
+	if data.RetiredAt > 0 {
+		k.RetiredAt = time.Unix(data.RetiredAt, 0)
+	}
+
+	if data.PrivateKey != "" {
+		block, _ := pem.Decode([]byte(data.PrivateKey))
+		if block == nil {
+			return fmt.Errorf("jwt: invalid private key of kid %s", data.Kid)
+		}
+
+		private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return ErrUnsupportedAlgorithm
+		}
+
+		k.PrivateKey = signer
+	}
+
+	return nil
+}
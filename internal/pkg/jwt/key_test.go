@@ -0,0 +1,90 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type keys map[string]*Key
+
+func (k keys) FindKey(_ context.Context, kid string) (*Key, error) {
+	if key, ok := k[kid]; ok {
+		return key, nil
+	}
+
+	return nil, ErrKeyNotFound
+}
+
+func TestGenerateTokenWithKey(t *testing.T) {
+	for _, alg := range []string{AlgorithmRS256, AlgorithmEdDSA} {
+		key, err := GenerateKey(alg)
+		assert.NoError(t, err)
+
+		token, err := GenerateTokenWithKey("api", key, &Options{
+			ID:        "1",
+			ExpiresAt: NewNumericDate(time.Now().Add(time.Minute)),
+		})
+		assert.NoError(t, err)
+
+		claims, err := ParseTokenWithKey(context.Background(), token, "", keys{key.Kid: key})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", claims.ID)
+		assert.Equal(t, "api", claims.Guard)
+
+		_, err = ParseTokenWithKey(context.Background(), token, "", keys{})
+		assert.Error(t, err)
+	}
+}
+
+func TestParseTokenWithKey_Legacy(t *testing.T) {
+	token := GenerateToken("api", "secret", &Options{ID: "1"})
+
+	claims, err := ParseTokenWithKey(context.Background(), token, "secret", keys{})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", claims.ID)
+
+	_, err = ParseTokenWithKey(context.Background(), token, "", keys{})
+	assert.Error(t, err)
+
+	_, err = ParseTokenWithKey(context.Background(), "invalid", "secret", keys{})
+	assert.Error(t, err)
+}
+
+// 使用公钥作为 HMAC 秘钥伪造的令牌不能通过验签
+func TestParseTokenWithKey_AlgorithmConfusion(t *testing.T) {
+	key, _ := GenerateKey(AlgorithmEdDSA)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims("api", &Options{ID: "1"}))
+	token.Header["kid"] = key.Kid
+
+	value, _ := token.SignedString([]byte(key.JWK()["x"]))
+
+	_, err := ParseTokenWithKey(context.Background(), value, "", keys{key.Kid: key})
+	assert.Error(t, err)
+}
+
+func TestKey_MarshalJSON(t *testing.T) {
+	key, _ := GenerateKey(AlgorithmRS256)
+	key.RetiredAt = time.Now()
+
+	value, err := json.Marshal(key)
+	assert.NoError(t, err)
+
+	data := &Key{}
+	assert.NoError(t, json.Unmarshal(value, data))
+	assert.Equal(t, key.Kid, data.Kid)
+	assert.Equal(t, key.JWK(), data.JWK())
+	assert.True(t, data.IsRetired())
+	assert.NotNil(t, data.PrivateKey)
+
+	token, err := GenerateTokenWithKey("api", data, &Options{ID: "2"})
+	assert.NoError(t, err)
+
+	_, err = ParseTokenWithKey(context.Background(), token, "", keys{key.Kid: key})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,28 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+func isImage(data []byte) bool {
+	return hasPrefix(data, "\x89PNG\r\n\x1a\n") || hasPrefix(data, "\xff\xd8\xff") || hasPrefix(data, "GIF87a") || hasPrefix(data, "GIF89a")
+}
+
+func probeImage(data []byte) (*Meta, error) {
+	c, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Meta{
+		Kind:   KindImage,
+		Format: format,
+		Codec:  format,
+		Width:  c.Width,
+		Height: c.Height,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"time"
+)
+
+// 媒体类型
+const (
+	KindImage = "image" // 图片
+	KindAudio = "audio" // 音频
+	KindVideo = "video" // 视频
+)
+
+var ErrUnsupportedFormat = errors.New("无法识别的媒体文件格式")
+
+// Meta 媒体文件信息
+type Meta struct {
+	Kind     string        // 媒体类型
+	Format   string        // 容器格式[png,jpeg,gif,mp4,webm,ogg,wav,mp3]
+	Codec    string        // 编码格式
+	Width    int           // 宽度(像素)
+	Height   int           // 高度(像素)
+	Duration time.Duration // 播放时长
+}
+
+// Seconds 播放时长(秒)，不足一秒按一秒计算
+func (m *Meta) Seconds() int {
+	if m.Duration <= 0 {
+		return 0
+	}
+
+	return int((m.Duration + time.Second - 1) / time.Second)
+}
+
+type prober struct {
+	match func(data []byte) bool
+	probe func(data []byte) (*Meta, error)
+}
+
+var probers = []prober{
+	{match: isImage, probe: probeImage},
+	{match: isMP4, probe: probeMP4},
+	{match: isWebM, probe: probeWebM},
+	{match: isOgg, probe: probeOgg},
+	{match: isWav, probe: probeWav},
+	{match: isMP3, probe: probeMP3},
+}
+
+// Probe 根据文件内容探测媒体信息，不依赖文件后缀及客户端上报的数据
+func Probe(data []byte) (*Meta, error) {
+	for _, p := range probers {
+		if p.match(data) {
+			return p.probe(data)
+		}
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+func hasPrefix(data []byte, prefix string) bool {
+	return bytes.HasPrefix(data, []byte(prefix))
+}
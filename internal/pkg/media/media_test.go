@@ -0,0 +1,207 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func box(typ string, body ...[]byte) []byte {
+	data := bytes.Join(body, nil)
+
+	buf := make([]byte, 8, 8+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(8+len(data)))
+	copy(buf[4:], typ)
+
+	return append(buf, data...)
+}
+
+func ebml(id []byte, body ...[]byte) []byte {
+	data := bytes.Join(body, nil)
+
+	return append(append(append([]byte{}, id...), 0x01, 0, 0, 0, 0, 0, 0, byte(len(data))), data...)
+}
+
+func oggPageBytes(serial uint32, granule uint64, payload []byte) []byte {
+	page := make([]byte, 27, 28+len(payload))
+	copy(page, "OggS")
+	binary.LittleEndian.PutUint64(page[6:], granule)
+	binary.LittleEndian.PutUint32(page[14:], serial)
+	page[26] = 1
+	page = append(page, byte(len(payload)))
+
+	return append(page, payload...)
+}
+
+func TestProbe_Image(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 40, 20))))
+
+	meta, err := Probe(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, KindImage, meta.Kind)
+	assert.Equal(t, "png", meta.Format)
+	assert.Equal(t, 40, meta.Width)
+	assert.Equal(t, 20, meta.Height)
+}
+
+func TestProbe_Wav(t *testing.T) {
+	data := make([]byte, 44+16000)
+	copy(data, "RIFF")
+	copy(data[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(data[16:], 16)
+	binary.LittleEndian.PutUint16(data[20:], 1)
+	binary.LittleEndian.PutUint16(data[22:], 1)
+	binary.LittleEndian.PutUint32(data[24:], 8000)
+	binary.LittleEndian.PutUint32(data[28:], 16000)
+	copy(data[36:], "data")
+	binary.LittleEndian.PutUint32(data[40:], 16000)
+
+	meta, err := Probe(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "pcm", meta.Codec)
+	assert.Equal(t, time.Second, meta.Duration)
+}
+
+func TestProbe_MP4(t *testing.T) {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:], 2500)
+
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], 320<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], 240<<16)
+
+	hdlr := append(make([]byte, 8), "vide"...)
+	stsd := append(make([]byte, 8), box("avc1", make([]byte, 8))...)
+
+	data := append(box("ftyp", []byte("isom")), box("moov",
+		box("mvhd", mvhd),
+		box("trak", box("tkhd", tkhd), box("mdia", box("hdlr", hdlr), box("minf", box("stbl", box("stsd", stsd))))),
+	)...)
+
+	meta, err := Probe(data)
+	assert.NoError(t, err)
+	assert.Equal(t, KindVideo, meta.Kind)
+	assert.Equal(t, "avc1", meta.Codec)
+	assert.Equal(t, 320, meta.Width)
+	assert.Equal(t, 240, meta.Height)
+	assert.Equal(t, 2500*time.Millisecond, meta.Duration)
+	assert.Equal(t, 3, meta.Seconds())
+}
+
+func TestProbe_WebM(t *testing.T) {
+	duration := make([]byte, 8)
+	binary.BigEndian.PutUint64(duration, math.Float64bits(1500))
+
+	data := append(ebml([]byte{0x1A, 0x45, 0xDF, 0xA3}, ebml([]byte{0x42, 0x82}, []byte("webm"))), ebml([]byte{0x18, 0x53, 0x80, 0x67},
+		ebml([]byte{0x15, 0x49, 0xA9, 0x66}, ebml([]byte{0x2A, 0xD7, 0xB1}, []byte{0x0F, 0x42, 0x40}), ebml([]byte{0x44, 0x89}, duration)),
+		ebml([]byte{0x16, 0x54, 0xAE, 0x6B}, ebml([]byte{0xAE},
+			ebml([]byte{0x83}, []byte{1}),
+			ebml([]byte{0x86}, []byte("V_VP8")),
+			ebml([]byte{0xE0}, ebml([]byte{0xB0}, []byte{0x02, 0x80}), ebml([]byte{0xBA}, []byte{0x01, 0x68})),
+		)),
+	)...)
+
+	meta, err := Probe(data)
+	assert.NoError(t, err)
+	assert.Equal(t, KindVideo, meta.Kind)
+	assert.Equal(t, "V_VP8", meta.Codec)
+	assert.Equal(t, 640, meta.Width)
+	assert.Equal(t, 360, meta.Height)
+	assert.Equal(t, 1500*time.Millisecond, meta.Duration)
+}
+
+func TestProbe_Ogg(t *testing.T) {
+	head := append([]byte("\x01vorbis"), make([]byte, 22)...)
+	binary.LittleEndian.PutUint32(head[12:], 44100)
+
+	data := append(oggPageBytes(7, 0, head), oggPageBytes(7, 44100*3, []byte("audio"))...)
+
+	meta, err := Probe(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "vorbis", meta.Codec)
+	assert.Equal(t, 3*time.Second, meta.Duration)
+}
+
+func TestProbe_MP3(t *testing.T) {
+	// MPEG-1 Layer III 128kbps 44.1kHz，单帧 417 字节
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+
+	data := bytes.Repeat(frame, 100)
+
+	meta, err := Probe(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "mp3", meta.Codec)
+	assert.InDelta(t, 2.6, meta.Duration.Seconds(), 0.01)
+}
+
+func TestProbe_Unsupported(t *testing.T) {
+	_, err := Probe([]byte("hello world"))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestProcessImage(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 800, 400))))
+
+	result, err := ProcessImage(buf.Bytes(), &ImageOptions{
+		MaxDimension: 600,
+		Renditions:   []Rendition{{Name: "thumb", Size: 200}, {Name: "large", Size: 1000}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "png", result.Original.Ext)
+	assert.Equal(t, 600, result.Original.Width)
+	assert.Equal(t, 300, result.Original.Height)
+
+	assert.Len(t, result.Renditions, 2)
+	assert.Equal(t, "thumb", result.Renditions[0].Name)
+	assert.Equal(t, 200, result.Renditions[0].Width)
+	assert.Equal(t, 100, result.Renditions[0].Height)
+	assert.NotNil(t, result.Renditions[0].Data)
+	assert.Nil(t, result.Renditions[1].Data)
+}
+
+func TestProcessImage_Orientation(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, jpeg.Encode(buf, image.NewRGBA(image.Rect(0, 0, 40, 20)), nil))
+
+	// 写入 EXIF 方向信息(6: 顺时针旋转90度)
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00\x00\x00\x00\x00")
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+	segment := append([]byte{0xFF, 0xE1, 0, byte(len(app1) + 2)}, app1...)
+
+	data := append(append([]byte{0xFF, 0xD8}, segment...), buf.Bytes()[2:]...)
+	assert.Equal(t, 6, exifOrientation(data))
+
+	result, err := ProcessImage(data, &ImageOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "jpg", result.Original.Ext)
+	assert.Equal(t, 20, result.Original.Width)
+	assert.Equal(t, 40, result.Original.Height)
+	assert.Equal(t, 1, exifOrientation(result.Original.Data))
+}
+
+func TestProcessImage_TooLarge(t *testing.T) {
+	// 仅构造 PNG 头信息，声明的尺寸远超限制
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], 100000)
+	binary.BigEndian.PutUint32(ihdr[8:], 100000)
+	ihdr[12], ihdr[13] = 8, 6
+
+	data := append([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0d"), ihdr...)
+	data = binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(ihdr))
+
+	_, err := ProcessImage(data, &ImageOptions{})
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+}
@@ -0,0 +1,158 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var errInvalidMP3 = errors.New("mp3 文件格式错误")
+
+// 比特率表(kbps)，索引为 [MPEG-1/MPEG-2][Layer I/II/III][bitrate index]
+var mp3Bitrates = [2][3][16]int{
+	{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+	},
+	{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	},
+}
+
+var mp3SampleRates = [3]int{44100, 48000, 32000}
+
+type mp3Frame struct {
+	version    int // 1:MPEG-1 2:MPEG-2 3:MPEG-2.5
+	layer      int
+	bitrate    int // bps
+	sampleRate int
+	mono       bool
+}
+
+func (f *mp3Frame) samples() int {
+	switch {
+	case f.layer == 1:
+		return 384
+	case f.layer == 3 && f.version != 1:
+		return 576
+	}
+
+	return 1152
+}
+
+func parseMP3Frame(h []byte) (*mp3Frame, bool) {
+	if len(h) < 4 || h[0] != 0xFF || h[1]&0xE0 != 0xE0 {
+		return nil, false
+	}
+
+	version := map[byte]int{3: 1, 2: 2, 0: 3}[(h[1]>>3)&0x03]
+	layer := 4 - int((h[1]>>1)&0x03)
+	bitrateIndex, rateIndex := int(h[2]>>4), int((h[2]>>2)&0x03)
+
+	if version == 0 || layer == 4 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return nil, false
+	}
+
+	table := 0
+	if version != 1 {
+		table = 1
+	}
+
+	frame := &mp3Frame{
+		version:    version,
+		layer:      layer,
+		bitrate:    mp3Bitrates[table][layer-1][bitrateIndex] * 1000,
+		sampleRate: mp3SampleRates[rateIndex] >> uint(version-1),
+		mono:       h[3]>>6 == 3,
+	}
+
+	return frame, true
+}
+
+// id3Size 返回文件头部 ID3v2 标签所占字节数
+func id3Size(data []byte) int {
+	if len(data) < 10 || !hasPrefix(data, "ID3") {
+		return 0
+	}
+
+	size := int(data[6]&0x7F)<<21 | int(data[7]&0x7F)<<14 | int(data[8]&0x7F)<<7 | int(data[9]&0x7F)
+	size += 10
+	if data[5]&0x10 != 0 {
+		size += 10
+	}
+
+	return size
+}
+
+func isMP3(data []byte) bool {
+	if hasPrefix(data, "ID3") {
+		return true
+	}
+
+	_, ok := parseMP3Frame(data)
+
+	return ok
+}
+
+func probeMP3(data []byte) (*Meta, error) {
+	offset := id3Size(data)
+
+	// 跳过标签后的填充字节，定位第一个帧同步头
+	var frame *mp3Frame
+	for ; offset+4 <= len(data); offset++ {
+		if f, ok := parseMP3Frame(data[offset:]); ok {
+			frame = f
+			break
+		}
+	}
+
+	if frame == nil {
+		return nil, errInvalidMP3
+	}
+
+	meta := &Meta{Kind: KindAudio, Format: "mp3", Codec: "mp3"}
+
+	if frames := mp3VbrFrames(data[offset:], frame); frames > 0 {
+		meta.Duration = time.Duration(uint64(frames) * uint64(frame.samples()) * uint64(time.Second) / uint64(frame.sampleRate))
+		return meta, nil
+	}
+
+	size := len(data) - offset
+	if len(data) >= 128 && string(data[len(data)-128:len(data)-125]) == "TAG" {
+		size -= 128
+	}
+
+	// 固定码率文件按码率估算时长
+	meta.Duration = time.Duration(uint64(size) * 8 * uint64(time.Second) / uint64(frame.bitrate))
+
+	return meta, nil
+}
+
+// mp3VbrFrames 读取 Xing/Info 或 VBRI 头中记录的总帧数
+func mp3VbrFrames(data []byte, frame *mp3Frame) uint32 {
+	side := 32
+	switch {
+	case frame.version == 1 && frame.mono:
+		side = 17
+	case frame.version != 1 && !frame.mono:
+		side = 17
+	case frame.version != 1 && frame.mono:
+		side = 9
+	}
+
+	if xing := 4 + side; len(data) >= xing+12 {
+		tag := string(data[xing : xing+4])
+		if (tag == "Xing" || tag == "Info") && binary.BigEndian.Uint32(data[xing+4:xing+8])&0x01 != 0 {
+			return binary.BigEndian.Uint32(data[xing+8 : xing+12])
+		}
+	}
+
+	if vbri := 4 + 32; len(data) >= vbri+18 && string(data[vbri:vbri+4]) == "VBRI" {
+		return binary.BigEndian.Uint32(data[vbri+14 : vbri+18])
+	}
+
+	return 0
+}
@@ -0,0 +1,144 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var errInvalidMP4 = errors.New("mp4 文件格式错误")
+
+func isMP4(data []byte) bool {
+	return len(data) >= 12 && string(data[4:8]) == "ftyp"
+}
+
+type mp4Box struct {
+	typ  string
+	body []byte
+}
+
+// readBoxes 解析同一层级的 Box 列表
+func readBoxes(data []byte) ([]mp4Box, error) {
+	boxes := make([]mp4Box, 0)
+
+	for len(data) >= 8 {
+		size := uint64(binary.BigEndian.Uint32(data[0:4]))
+		typ := string(data[4:8])
+		header := uint64(8)
+
+		switch size {
+		case 0:
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return nil, errInvalidMP4
+			}
+
+			size, header = binary.BigEndian.Uint64(data[8:16]), 16
+		}
+
+		if size < header || size > uint64(len(data)) {
+			return nil, errInvalidMP4
+		}
+
+		boxes = append(boxes, mp4Box{typ: typ, body: data[header:size]})
+		data = data[size:]
+	}
+
+	return boxes, nil
+}
+
+func findBox(data []byte, path ...string) []byte {
+	for _, name := range path {
+		boxes, err := readBoxes(data)
+		if err != nil {
+			return nil
+		}
+
+		found := false
+		for _, box := range boxes {
+			if box.typ == name {
+				data, found = box.body, true
+				break
+			}
+		}
+
+		if !found {
+			return nil
+		}
+	}
+
+	return data
+}
+
+func probeMP4(data []byte) (*Meta, error) {
+	moov := findBox(data, "moov")
+	if moov == nil {
+		return nil, errInvalidMP4
+	}
+
+	meta := &Meta{Kind: KindAudio, Format: "mp4"}
+
+	if mvhd := findBox(moov, "mvhd"); len(mvhd) >= 20 {
+		var timescale, duration uint64
+		if mvhd[0] == 1 && len(mvhd) >= 32 {
+			timescale, duration = uint64(binary.BigEndian.Uint32(mvhd[20:24])), binary.BigEndian.Uint64(mvhd[24:32])
+		} else {
+			timescale, duration = uint64(binary.BigEndian.Uint32(mvhd[12:16])), uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+		}
+
+		if timescale > 0 {
+			meta.Duration = time.Duration(duration * uint64(time.Second) / timescale)
+		}
+	}
+
+	boxes, err := readBoxes(moov)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, box := range boxes {
+		if box.typ != "trak" {
+			continue
+		}
+
+		hdlr := findBox(box.body, "mdia", "hdlr")
+		if len(hdlr) < 12 {
+			continue
+		}
+
+		codec := ""
+		if stsd := findBox(box.body, "mdia", "minf", "stbl", "stsd"); len(stsd) >= 16 {
+			codec = string(stsd[12:16])
+		}
+
+		switch string(hdlr[8:12]) {
+		case "vide":
+			meta.Kind, meta.Codec = KindVideo, codec
+			meta.Width, meta.Height = mp4TrackSize(findBox(box.body, "tkhd"))
+		case "soun":
+			if meta.Codec == "" {
+				meta.Codec = codec
+			}
+		}
+	}
+
+	return meta, nil
+}
+
+// mp4TrackSize 读取 tkhd 中的宽高信息(16.16 定点数)
+func mp4TrackSize(tkhd []byte) (int, int) {
+	offset := 76
+	if len(tkhd) > 0 && tkhd[0] == 1 {
+		offset = 88
+	}
+
+	if len(tkhd) < offset+8 {
+		return 0, 0
+	}
+
+	width := binary.BigEndian.Uint32(tkhd[offset : offset+4])
+	height := binary.BigEndian.Uint32(tkhd[offset+4 : offset+8])
+
+	return int(width >> 16), int(height >> 16)
+}
@@ -0,0 +1,96 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var errInvalidOgg = errors.New("ogg 文件格式错误")
+
+func isOgg(data []byte) bool {
+	return hasPrefix(data, "OggS")
+}
+
+type oggPage struct {
+	granule uint64
+	serial  uint32
+	payload []byte
+}
+
+func readOggPage(data []byte) (*oggPage, bool) {
+	if len(data) < 27 || !hasPrefix(data, "OggS") {
+		return nil, false
+	}
+
+	segments := int(data[26])
+	if len(data) < 27+segments {
+		return nil, false
+	}
+
+	size := 0
+	for _, n := range data[27 : 27+segments] {
+		size += int(n)
+	}
+
+	start := 27 + segments
+	if len(data) < start+size {
+		size = len(data) - start
+	}
+
+	return &oggPage{
+		granule: binary.LittleEndian.Uint64(data[6:14]),
+		serial:  binary.LittleEndian.Uint32(data[14:18]),
+		payload: data[start : start+size],
+	}, true
+}
+
+func probeOgg(data []byte) (*Meta, error) {
+	first, ok := readOggPage(data)
+	if !ok {
+		return nil, errInvalidOgg
+	}
+
+	var (
+		meta    = &Meta{Kind: KindAudio, Format: "ogg"}
+		rate    uint64
+		preSkip uint64
+		head    = first.payload
+	)
+
+	switch {
+	case hasPrefix(head, "\x01vorbis") && len(head) >= 16:
+		meta.Codec, rate = "vorbis", uint64(binary.LittleEndian.Uint32(head[12:16]))
+	case hasPrefix(head, "OpusHead") && len(head) >= 12:
+		// Opus 的 granule position 固定以 48kHz 计数
+		meta.Codec, rate, preSkip = "opus", 48000, uint64(binary.LittleEndian.Uint16(head[10:12]))
+	case hasPrefix(head, "\x80theora") && len(head) >= 20:
+		meta.Kind, meta.Codec = KindVideo, "theora"
+		meta.Width = int(head[14])<<16 | int(head[15])<<8 | int(head[16])
+		meta.Height = int(head[17])<<16 | int(head[18])<<8 | int(head[19])
+
+		return meta, nil
+	default:
+		return nil, errInvalidOgg
+	}
+
+	// 从文件末尾查找同一逻辑流的最后一页，其 granule position 即为总采样数
+	for i := len(data); i > 0; {
+		i = bytes.LastIndex(data[:i], []byte("OggS"))
+		if i < 0 {
+			break
+		}
+
+		page, ok := readOggPage(data[i:])
+		if ok && page.serial == first.serial && page.granule != ^uint64(0) {
+			if rate > 0 && page.granule > preSkip {
+				meta.Duration = time.Duration((page.granule - preSkip) * uint64(time.Second) / rate)
+			}
+
+			break
+		}
+	}
+
+	return meta, nil
+}
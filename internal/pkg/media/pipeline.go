@@ -0,0 +1,163 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// ImageMaxPixels 允许解码的图片最大像素数，避免构造的超大尺寸图片解码时耗尽内存
+const ImageMaxPixels = 40_000_000
+
+var ErrImageTooLarge = errors.New("图片尺寸过大")
+
+// Rendition 图片规格
+type Rendition struct {
+	Name string // 规格名称
+	Size int    // 长边像素
+}
+
+// ImageOptions 图片处理参数
+type ImageOptions struct {
+	MaxDimension int         // 原图长边最大像素，0 表示不限制
+	Quality      int         // JPEG 压缩质量
+	Renditions   []Rendition // 需要生成的多尺寸规格
+}
+
+// ImageOutput 图片处理结果
+type ImageOutput struct {
+	Name   string // 规格名称，原图为空
+	Ext    string // 文件后缀
+	Width  int
+	Height int
+	Data   []byte // 文件内容，规格图尺寸不小于原图时为 nil，表示直接使用原图
+}
+
+// ProcessedImage 图片处理结果
+type ProcessedImage struct {
+	Original   *ImageOutput
+	Renditions []*ImageOutput
+}
+
+// ProcessImage 图片处理流水线
+// 1. 按 EXIF 方向信息自动旋转
+// 2. 重新编码去除 EXIF(含 GPS 定位)等元数据
+// 3. 原图按最大尺寸等比压缩，并生成多尺寸规格图
+// GIF 图片保留原文件以免丢失动画，仅生成规格图
+func ProcessImage(data []byte, opts *ImageOptions) (*ProcessedImage, error) {
+	// 解码前先读取图片头信息校验尺寸
+	conf, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Width <= 0 || conf.Height <= 0 || conf.Width*conf.Height > ImageMaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProcessedImage{}
+
+	if format == "gif" {
+		bounds := src.Bounds()
+		result.Original = &ImageOutput{Ext: "gif", Width: bounds.Dx(), Height: bounds.Dy(), Data: data}
+	} else {
+		if format == "jpeg" {
+			src = orient(src, exifOrientation(data))
+		}
+
+		src = resize(src, opts.MaxDimension)
+
+		if result.Original, err = encodeImage(src, format, opts.Quality); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, rendition := range opts.Renditions {
+		output := &ImageOutput{Name: rendition.Name, Ext: result.Original.Ext}
+
+		bounds := src.Bounds()
+		if bounds.Dx() <= rendition.Size && bounds.Dy() <= rendition.Size {
+			output.Width, output.Height = bounds.Dx(), bounds.Dy()
+		} else if output, err = encodeImage(resize(src, rendition.Size), format, opts.Quality); err != nil {
+			return nil, err
+		} else {
+			output.Name = rendition.Name
+		}
+
+		result.Renditions = append(result.Renditions, output)
+	}
+
+	return result, nil
+}
+
+// encodeImage 编码图片，带透明通道的格式(png/gif)输出 png，其它输出 jpeg
+func encodeImage(img image.Image, format string, quality int) (*ImageOutput, error) {
+	bounds := img.Bounds()
+	output := &ImageOutput{Width: bounds.Dx(), Height: bounds.Dy()}
+
+	buf := &bytes.Buffer{}
+	if format == "png" || format == "gif" {
+		output.Ext = "png"
+		if err := png.Encode(buf, img); err != nil {
+			return nil, err
+		}
+	} else {
+		if quality <= 0 || quality > 100 {
+			quality = jpeg.DefaultQuality
+		}
+
+		output.Ext = "jpg"
+		if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, err
+		}
+	}
+
+	output.Data = buf.Bytes()
+
+	return output, nil
+}
+
+// resize 等比缩放图片，使长边不超过 maxSize，小图不放大
+func resize(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+
+	width, height := fitSize(bounds.Dx(), bounds.Dy(), maxSize)
+	if maxSize <= 0 || width == bounds.Dx() && height == bounds.Dy() {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+
+	return dst
+}
+
+// fitSize 按比例缩放宽高，使长边不超过 maxSize
+func fitSize(width, height, maxSize int) (int, int) {
+	if width <= maxSize && height <= maxSize || width == 0 || height == 0 {
+		return width, height
+	}
+
+	if width >= height {
+		return maxSize, max(1, height*maxSize/width)
+	}
+
+	return max(1, width*maxSize/height), maxSize
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
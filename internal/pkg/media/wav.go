@@ -0,0 +1,74 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var errInvalidWav = errors.New("wav 文件格式错误")
+
+var wavCodecs = map[uint16]string{
+	1:    "pcm",
+	3:    "pcm_float",
+	6:    "alaw",
+	7:    "mulaw",
+	0x55: "mp3",
+}
+
+func isWav(data []byte) bool {
+	return len(data) >= 12 && hasPrefix(data, "RIFF") && string(data[8:12]) == "WAVE"
+}
+
+func probeWav(data []byte) (*Meta, error) {
+	var (
+		meta     = &Meta{Kind: KindAudio, Format: "wav"}
+		byteRate uint32
+		dataSize uint32
+	)
+
+	for chunk := data[12:]; len(chunk) >= 8; {
+		id, size := string(chunk[0:4]), binary.LittleEndian.Uint32(chunk[4:8])
+		body := chunk[8:]
+		if uint64(size) < uint64(len(body)) {
+			body = body[:size]
+		}
+
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return nil, errInvalidWav
+			}
+
+			format := binary.LittleEndian.Uint16(body[0:2])
+			if codec, ok := wavCodecs[format]; ok {
+				meta.Codec = codec
+			} else {
+				meta.Codec = fmt.Sprintf("0x%04x", format)
+			}
+
+			byteRate = binary.LittleEndian.Uint32(body[8:12])
+		case "data":
+			dataSize = size
+		}
+
+		// RIFF 块按偶数字节对齐
+		next := 8 + uint64(size) + uint64(size&1)
+		if next > uint64(len(chunk)) {
+			break
+		}
+
+		chunk = chunk[next:]
+	}
+
+	if meta.Codec == "" {
+		return nil, errInvalidWav
+	}
+
+	if byteRate > 0 {
+		meta.Duration = time.Duration(uint64(dataSize) * uint64(time.Second) / uint64(byteRate))
+	}
+
+	return meta, nil
+}
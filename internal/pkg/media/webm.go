@@ -0,0 +1,200 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+)
+
+var errInvalidWebM = errors.New("webm 文件格式错误")
+
+// EBML 元素ID
+const (
+	ebmlHeader        = 0x1A45DFA3
+	ebmlSegment       = 0x18538067
+	ebmlInfo          = 0x1549A966
+	ebmlTimecodeScale = 0x2AD7B1
+	ebmlDuration      = 0x4489
+	ebmlTracks        = 0x1654AE6B
+	ebmlTrackEntry    = 0xAE
+	ebmlTrackType     = 0x83
+	ebmlCodecID       = 0x86
+	ebmlVideo         = 0xE0
+	ebmlPixelWidth    = 0xB0
+	ebmlPixelHeight   = 0xBA
+	ebmlCluster       = 0x1F43B675
+)
+
+func isWebM(data []byte) bool {
+	return hasPrefix(data, "\x1a\x45\xdf\xa3")
+}
+
+type ebmlElement struct {
+	id   uint64
+	body []byte
+}
+
+// readVint 读取 EBML 变长整数，keepMarker 为 true 时保留长度标记位(用于元素ID)
+func readVint(data []byte, keepMarker bool) (uint64, int, bool) {
+	if len(data) == 0 || data[0] == 0 {
+		return 0, 0, false
+	}
+
+	length := 1
+	for mask := byte(0x80); data[0]&mask == 0; mask >>= 1 {
+		length++
+	}
+
+	if len(data) < length {
+		return 0, 0, false
+	}
+
+	value := uint64(data[0])
+	if !keepMarker {
+		value &= uint64(0xFF >> length)
+	}
+
+	unknown := value == uint64(0xFF>>length)
+	for i := 1; i < length; i++ {
+		value = value<<8 | uint64(data[i])
+		unknown = unknown && data[i] == 0xFF
+	}
+
+	if !keepMarker && unknown {
+		return math.MaxUint64, length, true
+	}
+
+	return value, length, true
+}
+
+// readElements 解析同一层级的 EBML 元素，未知长度的元素将截取至数据末尾
+func readElements(data []byte) []ebmlElement {
+	elements := make([]ebmlElement, 0)
+
+	for len(data) > 0 {
+		id, n, ok := readVint(data, true)
+		if !ok {
+			break
+		}
+
+		size, m, ok := readVint(data[n:], false)
+		if !ok {
+			break
+		}
+
+		data = data[n+m:]
+		if size > uint64(len(data)) {
+			size = uint64(len(data))
+		}
+
+		elements = append(elements, ebmlElement{id: id, body: data[:size]})
+		data = data[size:]
+	}
+
+	return elements
+}
+
+func ebmlUint(data []byte) uint64 {
+	var value uint64
+	for _, b := range data {
+		value = value<<8 | uint64(b)
+	}
+
+	return value
+}
+
+func ebmlFloat(data []byte) float64 {
+	switch len(data) {
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data)))
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(data))
+	}
+
+	return 0
+}
+
+func probeWebM(data []byte) (*Meta, error) {
+	var segment []byte
+	for _, el := range readElements(data) {
+		if el.id == ebmlSegment {
+			segment = el.body
+			break
+		}
+	}
+
+	if segment == nil {
+		return nil, errInvalidWebM
+	}
+
+	meta := &Meta{Kind: KindAudio, Format: "webm"}
+
+	var (
+		scale    uint64 = 1000000
+		duration float64
+	)
+
+	for _, el := range readElements(segment) {
+		switch el.id {
+		case ebmlInfo:
+			for _, item := range readElements(el.body) {
+				switch item.id {
+				case ebmlTimecodeScale:
+					scale = ebmlUint(item.body)
+				case ebmlDuration:
+					duration = ebmlFloat(item.body)
+				}
+			}
+		case ebmlTracks:
+			for _, track := range readElements(el.body) {
+				if track.id == ebmlTrackEntry {
+					webmTrack(meta, track.body)
+				}
+			}
+		}
+
+		if el.id == ebmlCluster {
+			break
+		}
+	}
+
+	meta.Duration = time.Duration(duration * float64(scale))
+
+	return meta, nil
+}
+
+func webmTrack(meta *Meta, data []byte) {
+	var (
+		trackType     uint64
+		codec         string
+		width, height int
+	)
+
+	for _, item := range readElements(data) {
+		switch item.id {
+		case ebmlTrackType:
+			trackType = ebmlUint(item.body)
+		case ebmlCodecID:
+			codec = string(item.body)
+		case ebmlVideo:
+			for _, v := range readElements(item.body) {
+				switch v.id {
+				case ebmlPixelWidth:
+					width = int(ebmlUint(v.body))
+				case ebmlPixelHeight:
+					height = int(ebmlUint(v.body))
+				}
+			}
+		}
+	}
+
+	switch trackType {
+	case 1:
+		meta.Kind, meta.Codec, meta.Width, meta.Height = KindVideo, codec, width, height
+	case 2:
+		if meta.Codec == "" {
+			meta.Codec = codec
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Classification 外部分类结果
+type Classification struct {
+	Label string  `json:"label"` // 违规类型
+	Score float64 `json:"score"` // 违规概率 0-1
+}
+
+// Classifier 外部内容分类服务
+type Classifier interface {
+	Classify(ctx context.Context, text string) (*Classification, error)
+}
+
+// ClassifierFunc 函数形式的分类器，便于接入本地实现或测试桩
+type ClassifierFunc func(ctx context.Context, text string) (*Classification, error)
+
+func (f ClassifierFunc) Classify(ctx context.Context, text string) (*Classification, error) {
+	return f(ctx, text)
+}
+
+// ClassifierHook 根据分类分值判定审核结果
+type ClassifierHook struct {
+	classifier Classifier
+	flagScore  float64
+	blockScore float64
+	failOpen   bool // 分类服务异常时是否放行
+}
+
+func NewClassifierHook(classifier Classifier, flagScore float64, blockScore float64, failOpen bool) *ClassifierHook {
+	return &ClassifierHook{classifier: classifier, flagScore: flagScore, blockScore: blockScore, failOpen: failOpen}
+}
+
+func (h *ClassifierHook) Name() string {
+	return "classifier"
+}
+
+func (h *ClassifierHook) Check(ctx context.Context, msg *Message) (*Result, error) {
+	res := &Result{Action: ActionPass, Content: msg.Content}
+
+	c, err := h.classifier.Classify(ctx, msg.Content)
+	if err != nil {
+		if h.failOpen {
+			return res, nil
+		}
+
+		return nil, err
+	}
+
+	if c == nil {
+		return res, nil
+	}
+
+	if h.blockScore > 0 && c.Score >= h.blockScore {
+		res.Action = ActionBlock
+	} else if h.flagScore > 0 && c.Score >= h.flagScore {
+		res.Action = ActionFlag
+	} else {
+		return res, nil
+	}
+
+	res.Reasons = []string{fmt.Sprintf("分类结果[%s]分值%.2f", c.Label, c.Score)}
+
+	return res, nil
+}
+
+// HttpClassifier 通过 HTTP 调用外部分类服务
+//
+// 请求体为 {"text": "..."}，响应体为 {"label": "...", "score": 0.9}
+type HttpClassifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewHttpClassifier(url string, client *http.Client) *HttpClassifier {
+	return &HttpClassifier{url: url, client: client}
+}
+
+func (c *HttpClassifier) Classify(ctx context.Context, text string) (*Classification, error) {
+	body, _ := json.Marshal(map[string]string{"text": text})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("classifier response status %d", resp.StatusCode)
+	}
+
+	result := &Classification{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Word 敏感词及其处理动作
+type Word struct {
+	Word   string
+	Action Action
+}
+
+// KeywordFilter 基于 Aho-Corasick 的敏感词过滤，支持在运行时重新加载词库
+type KeywordFilter struct {
+	mu       sync.RWMutex
+	mask     rune
+	matchers map[Action]*Matcher
+}
+
+func NewKeywordFilter(words []Word) *KeywordFilter {
+	f := &KeywordFilter{mask: '*'}
+	f.Load(words)
+	return f
+}
+
+// Load 重新加载词库
+func (f *KeywordFilter) Load(words []Word) {
+	groups := make(map[Action][]string)
+	for _, word := range words {
+		if word.Action == ActionPass {
+			continue
+		}
+
+		groups[word.Action] = append(groups[word.Action], word.Word)
+	}
+
+	matchers := make(map[Action]*Matcher, len(groups))
+	for action, items := range groups {
+		matchers[action] = NewMatcher(items)
+	}
+
+	f.mu.Lock()
+	f.matchers = matchers
+	f.mu.Unlock()
+}
+
+func (f *KeywordFilter) Name() string {
+	return "keyword"
+}
+
+func (f *KeywordFilter) Check(_ context.Context, msg *Message) (*Result, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	res := &Result{Action: ActionPass, Content: msg.Content}
+
+	// 先判定拦截与审核，最后执行替换
+	for _, action := range []Action{ActionBlock, ActionFlag} {
+		matcher, ok := f.matchers[action]
+		if !ok {
+			continue
+		}
+
+		if items := matcher.FindAll(msg.Content); len(items) > 0 {
+			res.Action = action
+			res.Reasons = append(res.Reasons, fmt.Sprintf("命中敏感词[%s]", items[0].Word))
+			return res, nil
+		}
+	}
+
+	if matcher, ok := f.matchers[ActionReplace]; ok {
+		content, items := matcher.Replace(msg.Content, f.mask)
+		if len(items) > 0 {
+			res.Action = ActionReplace
+			res.Content = content
+			res.Reasons = append(res.Reasons, fmt.Sprintf("替换敏感词[%s]", items[0].Word))
+		}
+	}
+
+	return res, nil
+}
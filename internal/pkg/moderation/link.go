@@ -0,0 +1,103 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var linkRegexp = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']+`)
+
+// LinkFilter 链接白名单、黑名单过滤
+//
+// 命中黑名单的链接直接拦截；配置了白名单时，不在白名单内的链接进入人工审核。
+// 域名规则同时匹配其子域名。
+type LinkFilter struct {
+	mu    sync.RWMutex
+	allow []string
+	deny  []string
+}
+
+func NewLinkFilter(allow []string, deny []string) *LinkFilter {
+	f := &LinkFilter{}
+	f.Load(allow, deny)
+	return f
+}
+
+// Load 重新加载域名名单
+func (f *LinkFilter) Load(allow []string, deny []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.allow = normalizeDomains(allow)
+	f.deny = normalizeDomains(deny)
+}
+
+func (f *LinkFilter) Name() string {
+	return "link"
+}
+
+func (f *LinkFilter) Check(_ context.Context, msg *Message) (*Result, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	res := &Result{Action: ActionPass, Content: msg.Content}
+
+	for _, link := range linkRegexp.FindAllString(msg.Content, -1) {
+		host := linkHost(link)
+		if host == "" {
+			continue
+		}
+
+		if matchDomain(host, f.deny) {
+			res.Action = ActionBlock
+			res.Reasons = []string{fmt.Sprintf("链接域名[%s]已被禁止", host)}
+			return res, nil
+		}
+
+		if len(f.allow) > 0 && !matchDomain(host, f.allow) && res.Action < ActionFlag {
+			res.Action = ActionFlag
+			res.Reasons = append(res.Reasons, fmt.Sprintf("链接域名[%s]不在白名单内", host))
+		}
+	}
+
+	return res, nil
+}
+
+func linkHost(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(u.Hostname())
+}
+
+func matchDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeDomains(domains []string) []string {
+	items := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "*.")
+		if domain != "" {
+			items = append(items, domain)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,153 @@
+package moderation
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Match 关键词命中位置，Start、End 为 rune 下标(左闭右开)
+type Match struct {
+	Word  string
+	Start int
+	End   int
+}
+
+type acNode struct {
+	next   map[rune]int
+	fail   int
+	output []int // 以当前节点结尾的关键词下标
+}
+
+// Matcher Aho-Corasick 多模式匹配器，匹配时忽略大小写
+type Matcher struct {
+	nodes []*acNode
+	words []string
+	runes []int // 关键词 rune 长度
+}
+
+// NewMatcher 根据关键词构建匹配器
+func NewMatcher(words []string) *Matcher {
+	m := &Matcher{nodes: []*acNode{{next: map[rune]int{}}}}
+
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+
+		m.insert(word)
+	}
+
+	m.build()
+
+	return m
+}
+
+func (m *Matcher) insert(word string) {
+	cur := 0
+	for _, r := range normalize(word) {
+		next, ok := m.nodes[cur].next[r]
+		if !ok {
+			m.nodes = append(m.nodes, &acNode{next: map[rune]int{}})
+			next = len(m.nodes) - 1
+			m.nodes[cur].next[r] = next
+		}
+
+		cur = next
+	}
+
+	m.nodes[cur].output = append(m.nodes[cur].output, len(m.words))
+	m.words = append(m.words, word)
+	m.runes = append(m.runes, len([]rune(word)))
+}
+
+// build 广度优先构建失败指针
+func (m *Matcher) build() {
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for r, child := range m.nodes[cur].next {
+			fail := m.nodes[cur].fail
+			for fail > 0 {
+				if _, ok := m.nodes[fail].next[r]; ok {
+					break
+				}
+
+				fail = m.nodes[fail].fail
+			}
+
+			if next, ok := m.nodes[fail].next[r]; ok && next != child {
+				m.nodes[child].fail = next
+			}
+
+			m.nodes[child].output = append(m.nodes[child].output, m.nodes[m.nodes[child].fail].output...)
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Len 关键词数量
+func (m *Matcher) Len() int {
+	return len(m.words)
+}
+
+// FindAll 查找文本中所有命中的关键词
+func (m *Matcher) FindAll(text string) []Match {
+	items := make([]Match, 0)
+	if len(m.words) == 0 {
+		return items
+	}
+
+	cur := 0
+	for i, r := range normalize(text) {
+		for cur > 0 {
+			if _, ok := m.nodes[cur].next[r]; ok {
+				break
+			}
+
+			cur = m.nodes[cur].fail
+		}
+
+		if next, ok := m.nodes[cur].next[r]; ok {
+			cur = next
+		}
+
+		for _, idx := range m.nodes[cur].output {
+			items = append(items, Match{Word: m.words[idx], Start: i + 1 - m.runes[idx], End: i + 1})
+		}
+	}
+
+	return items
+}
+
+// Replace 将命中的关键词替换为掩码字符
+func (m *Matcher) Replace(text string, mask rune) (string, []Match) {
+	items := m.FindAll(text)
+	if len(items) == 0 {
+		return text, items
+	}
+
+	runes := []rune(text)
+	for _, item := range items {
+		for i := item.Start; i < item.End; i++ {
+			runes[i] = mask
+		}
+	}
+
+	return string(runes), items
+}
+
+func normalize(text string) []rune {
+	runes := []rune(text)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+
+	return runes
+}
@@ -0,0 +1,38 @@
+package moderation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcher_FindAll(t *testing.T) {
+	m := NewMatcher([]string{"he", "she", "his", "hers", " "})
+
+	items := m.FindAll("ushers")
+
+	words := make([]string, 0)
+	for _, item := range items {
+		words = append(words, item.Word)
+	}
+
+	assert.ElementsMatch(t, []string{"she", "he", "hers"}, words)
+	assert.Equal(t, 4, m.Len())
+}
+
+func TestMatcher_Replace(t *testing.T) {
+	m := NewMatcher([]string{"傻瓜", "Spam"})
+
+	text, items := m.Replace("你这个傻瓜，别发SPAM了", '*')
+	assert.Equal(t, "你这个**，别发****了", text)
+	assert.Len(t, items, 2)
+
+	text, items = m.Replace("正常消息", '*')
+	assert.Equal(t, "正常消息", text)
+	assert.Empty(t, items)
+}
+
+func TestMatcher_Empty(t *testing.T) {
+	m := NewMatcher(nil)
+	assert.Empty(t, m.FindAll("anything"))
+}
@@ -0,0 +1,126 @@
+package moderation
+
+import (
+	"context"
+	"strings"
+)
+
+// Action 审核处理动作，数值越大越严格
+type Action int
+
+const (
+	ActionPass    Action = 0 // 放行
+	ActionReplace Action = 1 // 替换违规内容后放行
+	ActionFlag    Action = 2 // 放行并进入人工审核队列
+	ActionBlock   Action = 3 // 拦截
+)
+
+// ParseAction 解析处理动作，无法识别时返回 ActionPass
+func ParseAction(value string) Action {
+	switch value {
+	case "replace":
+		return ActionReplace
+	case "flag":
+		return ActionFlag
+	case "block":
+		return ActionBlock
+	}
+
+	return ActionPass
+}
+
+func (a Action) String() string {
+	switch a {
+	case ActionReplace:
+		return "replace"
+	case ActionFlag:
+		return "flag"
+	case ActionBlock:
+		return "block"
+	}
+
+	return "pass"
+}
+
+// Message 待审核的消息
+type Message struct {
+	UserId     int
+	TalkType   int
+	ReceiverId int
+	Content    string
+}
+
+// Result 审核结果
+type Result struct {
+	Action  Action
+	Content string   // 审核后的消息内容
+	Hook    string   // 作出最终判定的审核项
+	Reasons []string // 命中原因
+}
+
+// Hook 审核项
+type Hook interface {
+	Name() string
+	Check(ctx context.Context, msg *Message) (*Result, error)
+}
+
+// Pipeline 按顺序执行的审核链
+//
+// 替换类结果会改写消息内容后继续执行后续审核项，拦截结果会立即终止审核链，
+// 其它结果取最严格的动作作为最终结果。
+type Pipeline struct {
+	hooks []Hook
+}
+
+func NewPipeline(hooks ...Hook) *Pipeline {
+	return &Pipeline{hooks: hooks}
+}
+
+// Use 追加审核项
+func (p *Pipeline) Use(hooks ...Hook) *Pipeline {
+	p.hooks = append(p.hooks, hooks...)
+	return p
+}
+
+// Run 执行审核链
+func (p *Pipeline) Run(ctx context.Context, msg *Message) (*Result, error) {
+	final := &Result{Action: ActionPass, Content: msg.Content, Reasons: make([]string, 0)}
+
+	for _, hook := range p.hooks {
+		res, err := hook.Check(ctx, &Message{
+			UserId:     msg.UserId,
+			TalkType:   msg.TalkType,
+			ReceiverId: msg.ReceiverId,
+			Content:    final.Content,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if res == nil || res.Action == ActionPass {
+			continue
+		}
+
+		if res.Action == ActionReplace {
+			final.Content = res.Content
+		}
+
+		final.Reasons = append(final.Reasons, res.Reasons...)
+
+		if res.Action >= final.Action {
+			final.Action = res.Action
+			final.Hook = hook.Name()
+		}
+
+		if res.Action == ActionBlock {
+			break
+		}
+	}
+
+	return final, nil
+}
+
+// Reason 命中原因描述
+func (r *Result) Reason() string {
+	return strings.Join(r.Reasons, ";")
+}
@@ -0,0 +1,164 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryCounter struct {
+	mu    sync.Mutex
+	items map[string]int64
+}
+
+func (c *memoryCounter) Incr(_ context.Context, key string, _ time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[key]++
+	return c.items[key], nil
+}
+
+func TestKeywordFilter(t *testing.T) {
+	f := NewKeywordFilter([]Word{
+		{Word: "傻瓜", Action: ActionReplace},
+		{Word: "赌博", Action: ActionBlock},
+		{Word: "代购", Action: ActionFlag},
+	})
+
+	ctx := context.Background()
+
+	res, _ := f.Check(ctx, &Message{Content: "你个傻瓜"})
+	assert.Equal(t, ActionReplace, res.Action)
+	assert.Equal(t, "你个**", res.Content)
+
+	res, _ = f.Check(ctx, &Message{Content: "傻瓜来赌博"})
+	assert.Equal(t, ActionBlock, res.Action)
+
+	res, _ = f.Check(ctx, &Message{Content: "专业代购"})
+	assert.Equal(t, ActionFlag, res.Action)
+
+	f.Load(nil)
+	res, _ = f.Check(ctx, &Message{Content: "傻瓜来赌博"})
+	assert.Equal(t, ActionPass, res.Action)
+}
+
+func TestLinkFilter(t *testing.T) {
+	f := NewLinkFilter([]string{"example.com"}, []string{"*.bad.com"})
+
+	ctx := context.Background()
+
+	res, _ := f.Check(ctx, &Message{Content: "看这里 https://docs.example.com/a?b=1"})
+	assert.Equal(t, ActionPass, res.Action)
+
+	res, _ = f.Check(ctx, &Message{Content: "点击 http://www.bad.com/x"})
+	assert.Equal(t, ActionBlock, res.Action)
+
+	res, _ = f.Check(ctx, &Message{Content: "www.other.org"})
+	assert.Equal(t, ActionFlag, res.Action)
+
+	f.Load(nil, nil)
+	res, _ = f.Check(ctx, &Message{Content: "www.other.org"})
+	assert.Equal(t, ActionPass, res.Action)
+}
+
+func TestSpamDetector(t *testing.T) {
+	d := NewSpamDetector(&memoryCounter{items: map[string]int64{}}, time.Minute, 2)
+
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		res, err := d.Check(ctx, &Message{UserId: 1, Content: "hello"})
+		assert.NoError(t, err)
+		assert.Equal(t, ActionPass, res.Action)
+	}
+
+	res, _ := d.Check(ctx, &Message{UserId: 1, Content: "hello"})
+	assert.Equal(t, ActionBlock, res.Action)
+
+	res, _ = d.Check(ctx, &Message{UserId: 2, Content: "hello"})
+	assert.Equal(t, ActionPass, res.Action)
+}
+
+func TestClassifierHook(t *testing.T) {
+	stub := ClassifierFunc(func(_ context.Context, text string) (*Classification, error) {
+		switch {
+		case strings.Contains(text, "fail"):
+			return nil, errors.New("unavailable")
+		case strings.Contains(text, "ad"):
+			return &Classification{Label: "ad", Score: 0.95}, nil
+		case strings.Contains(text, "maybe"):
+			return &Classification{Label: "ad", Score: 0.6}, nil
+		}
+
+		return &Classification{Label: "normal", Score: 0.01}, nil
+	})
+
+	ctx := context.Background()
+	h := NewClassifierHook(stub, 0.5, 0.9, false)
+
+	res, _ := h.Check(ctx, &Message{Content: "ad"})
+	assert.Equal(t, ActionBlock, res.Action)
+
+	res, _ = h.Check(ctx, &Message{Content: "maybe"})
+	assert.Equal(t, ActionFlag, res.Action)
+
+	res, _ = h.Check(ctx, &Message{Content: "hi"})
+	assert.Equal(t, ActionPass, res.Action)
+
+	_, err := h.Check(ctx, &Message{Content: "fail"})
+	assert.Error(t, err)
+
+	res, err = NewClassifierHook(stub, 0.5, 0.9, true).Check(ctx, &Message{Content: "fail"})
+	assert.NoError(t, err)
+	assert.Equal(t, ActionPass, res.Action)
+}
+
+func TestHttpClassifier(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		_ = json.NewEncoder(w).Encode(&Classification{Label: body["text"], Score: 0.7})
+	}))
+	defer srv.Close()
+
+	c, err := NewHttpClassifier(srv.URL, srv.Client()).Classify(context.Background(), "porn")
+	assert.NoError(t, err)
+	assert.Equal(t, "porn", c.Label)
+	assert.Equal(t, 0.7, c.Score)
+}
+
+func TestPipeline(t *testing.T) {
+	p := NewPipeline(
+		NewKeywordFilter([]Word{{Word: "傻瓜", Action: ActionReplace}, {Word: "代购", Action: ActionFlag}}),
+		NewLinkFilter(nil, []string{"bad.com"}),
+	)
+
+	ctx := context.Background()
+
+	res, err := p.Run(ctx, &Message{Content: "傻瓜"})
+	assert.NoError(t, err)
+	assert.Equal(t, ActionReplace, res.Action)
+	assert.Equal(t, "**", res.Content)
+
+	res, _ = p.Run(ctx, &Message{Content: "代购 www.bad.com"})
+	assert.Equal(t, ActionBlock, res.Action)
+	assert.Equal(t, "link", res.Hook)
+
+	res, _ = p.Run(ctx, &Message{Content: "代购"})
+	assert.Equal(t, ActionFlag, res.Action)
+	assert.Equal(t, "keyword", res.Hook)
+
+	res, _ = p.Run(ctx, &Message{Content: "你好"})
+	assert.Equal(t, ActionPass, res.Action)
+	assert.Equal(t, "你好", res.Content)
+}
@@ -0,0 +1,52 @@
+package moderation
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Counter 滑动窗口计数器
+type Counter interface {
+	Incr(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+// SpamDetector 重复消息检测，同一用户在窗口期内发送相同内容超过限制次数时拦截
+type SpamDetector struct {
+	counter Counter
+	window  time.Duration
+	limit   int64
+}
+
+func NewSpamDetector(counter Counter, window time.Duration, limit int64) *SpamDetector {
+	return &SpamDetector{counter: counter, window: window, limit: limit}
+}
+
+func (d *SpamDetector) Name() string {
+	return "spam"
+}
+
+func (d *SpamDetector) Check(ctx context.Context, msg *Message) (*Result, error) {
+	res := &Result{Action: ActionPass, Content: msg.Content}
+
+	content := strings.TrimSpace(msg.Content)
+	if d.limit <= 0 || content == "" {
+		return res, nil
+	}
+
+	hash := md5.Sum([]byte(content))
+	num, err := d.counter.Incr(ctx, fmt.Sprintf("spam:%d:%s", msg.UserId, hex.EncodeToString(hash[:])), d.window)
+	if err != nil {
+		return nil, err
+	}
+
+	if num > d.limit {
+		res.Action = ActionBlock
+		res.Reasons = []string{fmt.Sprintf("%s内重复发送相同内容%d次", d.window, num)}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,223 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidIdToken = errors.New("oidc: invalid id_token")
+	ErrKeyNotFound    = errors.New("oidc: signing key not found")
+)
+
+const (
+	discoveryCacheTime = time.Hour        // 发现文档缓存时长
+	keysCacheTime      = time.Hour        // JWKS 缓存时长
+	keysRefreshTime    = 30 * time.Second // 未找到 kid 时重新拉取 JWKS 的最小间隔
+)
+
+type Config struct {
+	Issuer       string   // IdP 标识，发现文档地址为 {Issuer}/.well-known/openid-configuration
+	ClientId     string   // 客户端ID
+	ClientSecret string   // 客户端秘钥
+	RedirectUrl  string   // 授权回调地址
+	Scopes       []string // 授权范围，默认 openid email profile
+}
+
+// Discovery OpenID Provider 发现文档
+type Discovery struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	UserinfoEndpoint      string   `json:"userinfo_endpoint"`
+	JwksUri               string   `json:"jwks_uri"`
+	CodeChallengeMethods  []string `json:"code_challenge_methods_supported"`
+}
+
+// Token 授权码换取的令牌
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IdToken     string `json:"id_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// Provider OpenID Connect 授权码模式客户端(PKCE)
+type Provider struct {
+	conf         *Config
+	client       *http.Client
+	mu           sync.RWMutex
+	discovery    *Discovery
+	discoveredAt time.Time
+	keys         map[string]interface{}
+	keysAt       time.Time
+	now          func() time.Time
+}
+
+func NewProvider(conf *Config, client *http.Client) *Provider {
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Provider{conf: conf, client: client, now: time.Now}
+}
+
+// Discover 获取发现文档
+func (p *Provider) Discover(ctx context.Context) (*Discovery, error) {
+
+	p.mu.RLock()
+	discovery, discoveredAt := p.discovery, p.discoveredAt
+	p.mu.RUnlock()
+
+	if discovery != nil && p.now().Sub(discoveredAt) < discoveryCacheTime {
+		return discovery, nil
+	}
+
+	value := &Discovery{}
+	if err := p.get(ctx, strings.TrimSuffix(p.conf.Issuer, "/")+"/.well-known/openid-configuration", value); err != nil {
+		return nil, err
+	}
+
+	// 发现文档中的 issuer 必须与配置一致
+	if strings.TrimSuffix(value.Issuer, "/") != strings.TrimSuffix(p.conf.Issuer, "/") {
+		return nil, fmt.Errorf("oidc: issuer mismatch, expected %s got %s", p.conf.Issuer, value.Issuer)
+	}
+
+	if value.AuthorizationEndpoint == "" || value.TokenEndpoint == "" || value.JwksUri == "" {
+		return nil, errors.New("oidc: incomplete discovery document")
+	}
+
+	p.mu.Lock()
+	p.discovery, p.discoveredAt = value, p.now()
+	p.mu.Unlock()
+
+	return value, nil
+}
+
+// AuthCodeURL 生成授权地址
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+
+	discovery, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	scopes := p.conf.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.conf.ClientId)
+	query.Set("redirect_uri", p.conf.RedirectUrl)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+// Exchange 授权码换取令牌
+func (p *Provider) Exchange(ctx context.Context, code string, verifier string) (*Token, error) {
+
+	discovery, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.conf.RedirectUrl)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.conf.ClientId), url.QueryEscape(p.conf.ClientSecret))
+
+	token := &Token{}
+	if err := p.do(req, token); err != nil {
+		return nil, err
+	}
+
+	if token.IdToken == "" {
+		return nil, errors.New("oidc: id_token missing in token response")
+	}
+
+	return token, nil
+}
+
+func (p *Provider) get(ctx context.Context, uri string, value interface{}) error {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	return p.do(req, value)
+}
+
+func (p *Provider) do(req *http.Request, value interface{}) error {
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: %s %s responded %d: %s", req.Method, req.URL.Path, resp.StatusCode, body)
+	}
+
+	return json.Unmarshal(body, value)
+}
+
+// RandomString 生成 URL 安全的随机串，用于 state、nonce 及 code_verifier
+func RandomString() (string, error) {
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// CodeChallenge 计算 PKCE S256 code_challenge
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,202 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubProvider 本地模拟的 IdP
+type stubProvider struct {
+	server     *httptest.Server
+	key        *rsa.PrivateKey
+	challenges map[string]string
+	nonces     map[string]string
+	claims     jwt.MapClaims
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	stub := &stubProvider{key: key, challenges: map[string]string{}, nonces: map[string]string{}, claims: jwt.MapClaims{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                           stub.server.URL,
+			"authorization_endpoint":           stub.server.URL + "/authorize",
+			"token_endpoint":                   stub.server.URL + "/token",
+			"jwks_uri":                         stub.server.URL + "/jwks",
+			"code_challenge_methods_supported": []string{"S256"},
+		})
+	})
+
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "stub",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+
+	// 模拟用户同意授权后返回授权码
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		code := "code-" + r.URL.Query().Get("state")
+		stub.challenges[code] = r.URL.Query().Get("code_challenge")
+		stub.nonces[code] = r.URL.Query().Get("nonce")
+		_, _ = w.Write([]byte(code))
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+
+		code := r.PostForm.Get("code")
+		if challenge, ok := stub.challenges[code]; !ok || challenge != CodeChallenge(r.PostForm.Get("code_verifier")) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		if id, secret, _ := r.BasicAuth(); id != "chat" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     stub.sign(stub.nonces[code]),
+			"expires_in":   3600,
+		})
+	})
+
+	stub.server = httptest.NewServer(mux)
+	t.Cleanup(stub.server.Close)
+
+	return stub
+}
+
+func (s *stubProvider) sign(nonce string) string {
+
+	claims := jwt.MapClaims{
+		"iss":            s.server.URL,
+		"aud":            "chat",
+		"sub":            "10001",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"nonce":          nonce,
+		"department":     []string{"engineering"},
+		"exp":            time.Now().Add(time.Minute).Unix(),
+		"iat":            time.Now().Unix(),
+	}
+
+	for key, value := range s.claims {
+		claims[key] = value
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "stub"
+
+	value, _ := token.SignedString(s.key)
+
+	return value
+}
+
+func (s *stubProvider) authorize(t *testing.T, uri string) string {
+
+	resp, err := http.Get(uri)
+	assert.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	buf := make([]byte, 128)
+	n, _ := resp.Body.Read(buf)
+
+	return string(buf[:n])
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+
+	stub := newStubProvider(t)
+
+	provider := NewProvider(&Config{
+		Issuer:       stub.server.URL,
+		ClientId:     "chat",
+		ClientSecret: "secret",
+		RedirectUrl:  "http://localhost/sso/callback",
+	}, stub.server.Client())
+
+	verifier, _ := RandomString()
+
+	uri, err := provider.AuthCodeURL(context.Background(), "state", "nonce", verifier)
+	assert.NoError(t, err)
+
+	value, _ := url.Parse(uri)
+	assert.Equal(t, "S256", value.Query().Get("code_challenge_method"))
+	assert.Equal(t, "openid email profile", value.Query().Get("scope"))
+
+	code := stub.authorize(t, uri)
+
+	// code_verifier 不匹配
+	_, err = provider.Exchange(context.Background(), code, "invalid")
+	assert.Error(t, err)
+
+	token, err := provider.Exchange(context.Background(), code, verifier)
+	assert.NoError(t, err)
+
+	claims, err := provider.VerifyIdToken(context.Background(), token.IdToken, "nonce")
+	assert.NoError(t, err)
+	assert.Equal(t, "10001", claims.Subject)
+	assert.Equal(t, "alice@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, "engineering", claims.String("department"))
+
+	_, err = provider.VerifyIdToken(context.Background(), token.IdToken, "other")
+	assert.ErrorIs(t, err, ErrInvalidIdToken)
+}
+
+func TestProvider_VerifyIdToken(t *testing.T) {
+
+	stub := newStubProvider(t)
+
+	provider := NewProvider(&Config{Issuer: stub.server.URL, ClientId: "chat"}, stub.server.Client())
+
+	cases := map[string]jwt.MapClaims{
+		"audience": {"aud": "other"},
+		"issuer":   {"iss": "https://evil.example.com"},
+		"expired":  {"exp": time.Now().Add(-time.Minute).Unix()},
+		"azp":      {"aud": []string{"chat", "other"}, "azp": "other"},
+	}
+
+	for name, claims := range cases {
+		stub.claims = claims
+
+		_, err := provider.VerifyIdToken(context.Background(), stub.sign("nonce"), "nonce")
+		assert.ErrorIs(t, err, ErrInvalidIdToken, name)
+	}
+
+	// 对称签名的令牌不被接受
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": stub.server.URL, "aud": "chat", "sub": "1", "nonce": "nonce"})
+	token.Header["kid"] = "stub"
+	value, _ := token.SignedString([]byte("secret"))
+
+	_, err := provider.VerifyIdToken(context.Background(), value, "nonce")
+	assert.ErrorIs(t, err, ErrInvalidIdToken)
+}
@@ -0,0 +1,257 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Claims ID Token 中的用户信息
+type Claims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	PhoneNumber       string
+	Picture           string
+	Raw               jwt.MapClaims // 全部声明，用于读取自定义声明
+}
+
+// String 读取字符串类型的自定义声明，数组类型取第一个元素
+func (c *Claims) String(name string) string {
+	switch value := c.Raw[name].(type) {
+	case string:
+		return value
+	case []interface{}:
+		if len(value) > 0 {
+			if item, ok := value[0].(string); ok {
+				return item
+			}
+		}
+	}
+
+	return ""
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// 仅接受非对称签名算法
+var idTokenMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// VerifyIdToken 使用 IdP 的 JWKS 校验 ID Token 的签名、issuer、audience、有效期及 nonce
+func (p *Provider) VerifyIdToken(ctx context.Context, raw string, nonce string) (*Claims, error) {
+
+	discovery, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := jwt.NewParser(jwt.WithValidMethods(idTokenMethods))
+
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.findKey(ctx, discovery, kid)
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIdToken, err.Error())
+	}
+
+	if !claims.VerifyIssuer(discovery.Issuer, true) {
+		return nil, fmt.Errorf("%w: issuer mismatch", ErrInvalidIdToken)
+	}
+
+	if !claims.VerifyAudience(p.conf.ClientId, true) {
+		return nil, fmt.Errorf("%w: audience mismatch", ErrInvalidIdToken)
+	}
+
+	// 多个 audience 时 azp 必须为当前客户端
+	if azp, ok := claims["azp"].(string); ok && azp != p.conf.ClientId {
+		return nil, fmt.Errorf("%w: authorized party mismatch", ErrInvalidIdToken)
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return nil, fmt.Errorf("%w: exp missing", ErrInvalidIdToken)
+	}
+
+	if value, _ := claims["nonce"].(string); value != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIdToken)
+	}
+
+	data := &Claims{Raw: claims}
+	data.Subject, _ = claims["sub"].(string)
+	data.Email, _ = claims["email"].(string)
+	data.Name, _ = claims["name"].(string)
+	data.PreferredUsername, _ = claims["preferred_username"].(string)
+	data.PhoneNumber, _ = claims["phone_number"].(string)
+	data.Picture, _ = claims["picture"].(string)
+
+	switch value := claims["email_verified"].(type) {
+	case bool:
+		data.EmailVerified = value
+	case string:
+		data.EmailVerified = value == "true"
+	}
+
+	if data.Subject == "" {
+		return nil, fmt.Errorf("%w: sub missing", ErrInvalidIdToken)
+	}
+
+	return data, nil
+}
+
+// findKey 根据 kid 查找验签公钥，未找到时重新拉取 JWKS 以支持 IdP 密钥轮换
+func (p *Provider) findKey(ctx context.Context, discovery *Discovery, kid string) (interface{}, error) {
+
+	p.mu.RLock()
+	keys, keysAt := p.keys, p.keysAt
+	p.mu.RUnlock()
+
+	if keys != nil && p.now().Sub(keysAt) < keysCacheTime {
+		if key, ok := lookupKey(keys, kid); ok {
+			return key, nil
+		}
+
+		if p.now().Sub(keysAt) < keysRefreshTime {
+			return nil, ErrKeyNotFound
+		}
+	}
+
+	keys, err := p.fetchKeys(ctx, discovery.JwksUri)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.keys, p.keysAt = keys, p.now()
+	p.mu.Unlock()
+
+	if key, ok := lookupKey(keys, kid); ok {
+		return key, nil
+	}
+
+	return nil, ErrKeyNotFound
+}
+
+// lookupKey 未指定 kid 时仅在 JWKS 只有一个密钥时使用该密钥
+func lookupKey(keys map[string]interface{}, kid string) (interface{}, bool) {
+
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+
+	key, ok := keys[kid]
+
+	return key, ok
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, uri string) (map[string]interface{}, error) {
+
+	value := &struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}{}
+
+	if err := p.get(ctx, uri, value); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{}, len(value.Keys))
+	for _, item := range value.Keys {
+		if item.Use != "" && item.Use != "sig" {
+			continue
+		}
+
+		if key, err := item.publicKey(); err == nil {
+			keys[item.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("oidc: unsupported curve %s", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("oidc: invalid ec key")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("oidc: unsupported curve %s", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("oidc: invalid ed25519 key")
+		}
+
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("oidc: unsupported key type %s", k.Kty)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+
+	buf, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(buf), nil
+}
@@ -0,0 +1,55 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// See: https://www.rfc-editor.org/rfc/rfc6238#appendix-B
+func TestHotp(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	items := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+
+	for ts, code := range items {
+		assert.Equal(t, code, hotp(key, ts/Period, 8))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	now := time.Unix(59, 0)
+
+	code, err := GenerateCode(secret, Counter(now))
+	assert.NoError(t, err)
+	assert.Equal(t, "287082", code)
+
+	counter, ok := Validate(secret, code, now.Add(Period*time.Second), 1)
+	assert.True(t, ok)
+	assert.Equal(t, Counter(now), counter)
+
+	_, ok = Validate(secret, code, now.Add(3*Period*time.Second), 1)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	value := ProvisioningURI("LumenIM", "13800000000", "JBSWY3DPEHPK3PXP")
+	assert.Equal(t, "otpauth://totp/LumenIM:13800000000?algorithm=SHA1&digits=6&issuer=LumenIM&period=30&secret=JBSWY3DPEHPK3PXP", value)
+}
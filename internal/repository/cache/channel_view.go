@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type ChannelViewStorage struct {
+	rds *redis.Client
+}
+
+func NewChannelViewStorage(rds *redis.Client) *ChannelViewStorage {
+	return &ChannelViewStorage{rds: rds}
+}
+
+func (c *ChannelViewStorage) name(recordId int) string {
+	return fmt.Sprintf("talk:channel:view:%d", recordId)
+}
+
+// Add 记录用户浏览频道消息，返回用户首次浏览的消息ID，同一用户重复浏览只统计一次
+func (c *ChannelViewStorage) Add(ctx context.Context, uid int, recordIds []int) ([]int, error) {
+
+	pipe := c.rds.Pipeline()
+
+	cmds := make([]*redis.IntCmd, 0, len(recordIds))
+	for _, recordId := range recordIds {
+		cmds = append(cmds, pipe.PFAdd(ctx, c.name(recordId), strconv.Itoa(uid)))
+		pipe.Expire(ctx, c.name(recordId), 90*24*time.Hour)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0)
+	for i, cmd := range cmds {
+		if cmd.Val() == 1 {
+			ids = append(ids, recordIds[i])
+		}
+	}
+
+	return ids, nil
+}
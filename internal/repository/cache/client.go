@@ -94,6 +94,32 @@ func (w *ClientStorage) GetUidFromClientIds(ctx context.Context, sid, channel, u
 	return cids
 }
 
+// BatchGetUidFromClientIds 批量获取当前节点用户ID关联的客户端ID
+// @params sid      服务ID
+// @params channel  渠道分组
+// @params uids     用户ID
+func (w *ClientStorage) BatchGetUidFromClientIds(ctx context.Context, sid, channel string, uids []int) []int64 {
+	cids := make([]int64, 0)
+
+	pipeline := w.redis.Pipeline()
+	cmds := make([]*redis.StringSliceCmd, 0, len(uids))
+	for _, uid := range uids {
+		cmds = append(cmds, pipeline.SMembers(ctx, w.getUserKey(sid, channel, strconv.Itoa(uid))))
+	}
+
+	_, _ = pipeline.Exec(ctx)
+
+	for _, cmd := range cmds {
+		for _, cid := range cmd.Val() {
+			if cid, err := strconv.ParseInt(cid, 10, 64); err == nil {
+				cids = append(cids, cid)
+			}
+		}
+	}
+
+	return cids
+}
+
 // GetClientIdFromUid 获取客户端ID关联的用户ID
 // @params sid     服务节点ID
 // @params channel 渠道分组
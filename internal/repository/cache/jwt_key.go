@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-redis/redis/v8"
+	"go-chat/internal/pkg/jwt"
+)
+
+const (
+	jwtKeysName       = "jwt:keys"
+	jwtCurrentKeyName = "jwt:keys:current"
+)
+
+// JwtKeyStorage JWT 签名密钥存储，HTTP 服务与网关共享
+type JwtKeyStorage struct {
+	rds *redis.Client
+}
+
+func NewJwtKeyStorage(rds *redis.Client) *JwtKeyStorage {
+	return &JwtKeyStorage{rds}
+}
+
+// Get 获取指定 kid 的密钥
+func (s *JwtKeyStorage) Get(ctx context.Context, kid string) (*jwt.Key, error) {
+
+	value, err := s.rds.HGet(ctx, jwtKeysName, kid).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &jwt.Key{}
+	if err := json.Unmarshal([]byte(value), key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// All 获取全部密钥
+func (s *JwtKeyStorage) All(ctx context.Context) ([]*jwt.Key, error) {
+
+	values, err := s.rds.HGetAll(ctx, jwtKeysName).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*jwt.Key, 0, len(values))
+	for _, value := range values {
+		key := &jwt.Key{}
+		if err := json.Unmarshal([]byte(value), key); err == nil {
+			items = append(items, key)
+		}
+	}
+
+	return items, nil
+}
+
+// Save 保存密钥
+func (s *JwtKeyStorage) Save(ctx context.Context, keys ...*jwt.Key) error {
+
+	values := make([]interface{}, 0, len(keys)*2)
+	for _, key := range keys {
+		value, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+
+		values = append(values, key.Kid, string(value))
+	}
+
+	return s.rds.HSet(ctx, jwtKeysName, values...).Err()
+}
+
+// Del 删除密钥
+func (s *JwtKeyStorage) Del(ctx context.Context, kids ...string) error {
+	return s.rds.HDel(ctx, jwtKeysName, kids...).Err()
+}
+
+// GetCurrent 获取当前签名密钥的 kid
+func (s *JwtKeyStorage) GetCurrent(ctx context.Context) string {
+	return s.rds.Get(ctx, jwtCurrentKeyName).Val()
+}
+
+// SetCurrent 设置当前签名密钥的 kid
+func (s *JwtKeyStorage) SetCurrent(ctx context.Context, kid string) error {
+	return s.rds.Set(ctx, jwtCurrentKeyName, kid, 0).Err()
+}
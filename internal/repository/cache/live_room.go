@@ -0,0 +1,356 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/sliceutil"
+)
+
+// 聊天室数据有效期，每次有成员进入或发言时续期
+const liveRoomTTL = 24 * time.Hour
+
+var ErrLiveRoomNotFound = errors.New("聊天室不存在或已关闭")
+
+// LiveRoom 直播聊天室信息
+type LiveRoom struct {
+	Code      string `json:"code" redis:"code"`             // 聊天室邀请码
+	Name      string `json:"name" redis:"name"`             // 聊天室名称
+	OwnerId   int    `json:"owner_id" redis:"owner_id"`     // 创建者ID
+	SlowMode  int    `json:"slow_mode" redis:"slow_mode"`   // 慢速模式发言间隔(单位秒)，0 为不限制
+	CreatedAt string `json:"created_at" redis:"created_at"` // 创建时间
+}
+
+// LiveMember 聊天室成员，登录用户及游客共用
+type LiveMember struct {
+	Id       string `json:"id"`       // 成员标识，登录用户为 u_{uid}，游客为 g_{随机串}
+	Uid      int    `json:"uid"`      // 用户ID，游客为 0
+	Nickname string `json:"nickname"` // 昵称
+	Avatar   string `json:"avatar"`   // 头像
+}
+
+// LiveMessage 聊天室消息
+type LiveMessage struct {
+	MsgId     string      `json:"msg_id"`
+	Room      string      `json:"room"`
+	Sender    *LiveMember `json:"sender"`
+	Content   string      `json:"content"`
+	CreatedAt string      `json:"created_at"`
+}
+
+// 客户端进入聊天室，聊天室已销毁时返回 -1，否则返回在线网关下的客户端总数
+// KEYS[1] 聊天室信息 KEYS[2] 聊天室管理员 KEYS[3] 当前网关的客户端列表 KEYS[4...] 在线网关的客户端列表
+var liveJoinScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+redis.call("SADD", KEYS[3], ARGV[1])
+redis.call("EXPIRE", KEYS[3], ARGV[2])
+redis.call("EXPIRE", KEYS[1], ARGV[2])
+redis.call("EXPIRE", KEYS[2], ARGV[2])
+local online = 0
+for i = 4, #KEYS do
+	online = online + redis.call("SCARD", KEYS[i])
+end
+return online
+`)
+
+// 客户端离开聊天室，在线网关下已无客户端时销毁聊天室，返回剩余在线客户端数
+// KEYS[1] 当前网关的客户端列表 KEYS[2...5] 聊天室数据 KEYS[6...] 在线网关的客户端列表
+var liveLeaveScript = redis.NewScript(`
+redis.call("SREM", KEYS[1], ARGV[1])
+local online = 0
+for i = 6, #KEYS do
+	online = online + redis.call("SCARD", KEYS[i])
+end
+if online == 0 then
+	redis.call("DEL", KEYS[2], KEYS[3], KEYS[4], KEYS[5])
+end
+return online
+`)
+
+type LiveRoomStorage struct {
+	rds    *redis.Client
+	server *ServerStorage
+}
+
+func NewLiveRoomStorage(rds *redis.Client, server *ServerStorage) *LiveRoomStorage {
+	return &LiveRoomStorage{rds: rds, server: server}
+}
+
+// 聊天室的全部数据
+func (l *LiveRoomStorage) roomNames(code string) []string {
+	return []string{
+		l.name(code, ""),
+		l.name(code, "moderators"),
+		l.name(code, "history"),
+		l.name(code, "banned"),
+	}
+}
+
+// 在线网关下聊天室的客户端列表，在线客户端数由网关心跳及客户端列表实时统计，网关异常退出时不计入
+func (l *LiveRoomStorage) onlineNames(ctx context.Context, sid string, code string) []string {
+
+	sids := l.server.All(ctx, 1)
+	if sid != "" && !sliceutil.Include(sid, sids) {
+		sids = append(sids, sid)
+	}
+
+	names := make([]string, 0, len(sids))
+	for _, item := range sids {
+		names = append(names, l.clientName(item, code))
+	}
+
+	return names
+}
+
+func (l *LiveRoomStorage) name(code string, suffix string) string {
+	if suffix == "" {
+		return fmt.Sprintf("live:room:%s", code)
+	}
+
+	return fmt.Sprintf("live:room:%s:%s", code, suffix)
+}
+
+// 网关本地的聊天室客户端列表 [ws:sid:live:邀请码]
+func (l *LiveRoomStorage) clientName(sid string, code string) string {
+	return fmt.Sprintf("ws:%s:live:%s", sid, code)
+}
+
+// Create 创建聊天室
+func (l *LiveRoomStorage) Create(ctx context.Context, room *LiveRoom) error {
+
+	pipe := l.rds.TxPipeline()
+	pipe.HSet(ctx, l.name(room.Code, ""), map[string]interface{}{
+		"code":       room.Code,
+		"name":       room.Name,
+		"owner_id":   room.OwnerId,
+		"slow_mode":  room.SlowMode,
+		"created_at": room.CreatedAt,
+	})
+	pipe.Expire(ctx, l.name(room.Code, ""), liveRoomTTL)
+	pipe.SAdd(ctx, l.name(room.Code, "moderators"), room.OwnerId)
+	pipe.Expire(ctx, l.name(room.Code, "moderators"), liveRoomTTL)
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Get 获取聊天室信息
+func (l *LiveRoomStorage) Get(ctx context.Context, code string) (*LiveRoom, error) {
+
+	cmd := l.rds.HGetAll(ctx, l.name(code, ""))
+	if err := cmd.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(cmd.Val()) == 0 {
+		return nil, ErrLiveRoomNotFound
+	}
+
+	room := &LiveRoom{}
+	if err := cmd.Scan(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// SetSlowMode 修改慢速模式发言间隔
+func (l *LiveRoomStorage) SetSlowMode(ctx context.Context, code string, second int) error {
+	return l.rds.HSet(ctx, l.name(code, ""), "slow_mode", second).Err()
+}
+
+// Delete 删除聊天室的全部数据
+func (l *LiveRoomStorage) Delete(ctx context.Context, code string) error {
+	return l.rds.Del(ctx, l.roomNames(code)...).Err()
+}
+
+// SetModerator 设置或取消聊天室管理员
+func (l *LiveRoomStorage) SetModerator(ctx context.Context, code string, uid int, isModerator bool) error {
+
+	if isModerator {
+		return l.rds.SAdd(ctx, l.name(code, "moderators"), uid).Err()
+	}
+
+	return l.rds.SRem(ctx, l.name(code, "moderators"), uid).Err()
+}
+
+// IsModerator 判断是否是聊天室管理员
+func (l *LiveRoomStorage) IsModerator(ctx context.Context, code string, uid int) bool {
+
+	if uid <= 0 {
+		return false
+	}
+
+	return l.rds.SIsMember(ctx, l.name(code, "moderators"), uid).Val()
+}
+
+// Moderators 聊天室管理员列表
+func (l *LiveRoomStorage) Moderators(ctx context.Context, code string) []int {
+
+	uids := make([]int, 0)
+	for _, val := range l.rds.SMembers(ctx, l.name(code, "moderators")).Val() {
+		if uid, err := strconv.Atoi(val); err == nil {
+			uids = append(uids, uid)
+		}
+	}
+
+	return uids
+}
+
+// Ban 禁止成员在聊天室发言
+func (l *LiveRoomStorage) Ban(ctx context.Context, code string, memberId string) error {
+
+	pipe := l.rds.Pipeline()
+	pipe.SAdd(ctx, l.name(code, "banned"), memberId)
+	pipe.Expire(ctx, l.name(code, "banned"), liveRoomTTL)
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// IsBanned 判断成员是否已被禁止发言
+func (l *LiveRoomStorage) IsBanned(ctx context.Context, code string, memberId string) bool {
+	return l.rds.SIsMember(ctx, l.name(code, "banned"), memberId).Val()
+}
+
+// Allow 慢速模式下判断成员是否可以发言
+func (l *LiveRoomStorage) Allow(ctx context.Context, code string, memberId string, second int) bool {
+
+	if second <= 0 {
+		return true
+	}
+
+	return l.rds.SetNX(ctx, l.name(code, "slow:"+memberId), 1, time.Duration(second)*time.Second).Val()
+}
+
+// Push 保存聊天室消息，仅保留最近的消息
+func (l *LiveRoomStorage) Push(ctx context.Context, message *LiveMessage) error {
+
+	key := l.name(message.Room, "history")
+
+	pipe := l.rds.TxPipeline()
+	pipe.LPush(ctx, key, jsonutil.Encode(message))
+	pipe.LTrim(ctx, key, 0, entity.LiveHistoryMaxNum-1)
+	pipe.Expire(ctx, key, liveRoomTTL)
+	pipe.Expire(ctx, l.name(message.Room, ""), liveRoomTTL)
+	pipe.Expire(ctx, l.name(message.Room, "moderators"), liveRoomTTL)
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// History 获取聊天室最近的消息，按发送时间正序返回
+func (l *LiveRoomStorage) History(ctx context.Context, code string, limit int) []*LiveMessage {
+
+	values := l.rds.LRange(ctx, l.name(code, "history"), 0, int64(limit-1)).Val()
+
+	items := make([]*LiveMessage, 0, len(values))
+	for i := len(values) - 1; i >= 0; i-- {
+		message := &LiveMessage{}
+		if err := jsonutil.Decode(values[i], message); err == nil {
+			items = append(items, message)
+		}
+	}
+
+	return items
+}
+
+// DelMessage 删除聊天室消息
+func (l *LiveRoomStorage) DelMessage(ctx context.Context, code string, msgId string) bool {
+
+	key := l.name(code, "history")
+	for _, value := range l.rds.LRange(ctx, key, 0, -1).Val() {
+		message := &LiveMessage{}
+		if err := jsonutil.Decode(value, message); err != nil || message.MsgId != msgId {
+			continue
+		}
+
+		return l.rds.LRem(ctx, key, 1, value).Val() > 0
+	}
+
+	return false
+}
+
+// Join 客户端进入聊天室，返回聊天室在线客户端数
+func (l *LiveRoomStorage) Join(ctx context.Context, sid string, code string, cid int64) (int64, error) {
+
+	keys := append([]string{l.name(code, ""), l.name(code, "moderators"), l.clientName(sid, code)}, l.onlineNames(ctx, sid, code)...)
+
+	online, err := liveJoinScript.Run(ctx, l.rds, keys, cid, int64(liveRoomTTL/time.Second)).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	if online < 0 {
+		return 0, ErrLiveRoomNotFound
+	}
+
+	return online, nil
+}
+
+// Leave 客户端离开聊天室，聊天室无人在线时销毁聊天室，返回聊天室剩余在线客户端数
+func (l *LiveRoomStorage) Leave(ctx context.Context, sid string, code string, cid int64) (int64, error) {
+
+	keys := append([]string{l.clientName(sid, code)}, l.roomNames(code)...)
+	keys = append(keys, l.onlineNames(ctx, sid, code)...)
+
+	return liveLeaveScript.Run(ctx, l.rds, keys, cid).Int64()
+}
+
+// Online 聊天室在线客户端数
+func (l *LiveRoomStorage) Online(ctx context.Context, code string) int64 {
+
+	pipe := l.rds.Pipeline()
+
+	cmds := make([]*redis.IntCmd, 0)
+	for _, name := range l.onlineNames(ctx, "", code) {
+		cmds = append(cmds, pipe.SCard(ctx, name))
+	}
+
+	_, _ = pipe.Exec(ctx)
+
+	var num int64
+	for _, cmd := range cmds {
+		num += cmd.Val()
+	}
+
+	return num
+}
+
+// Clients 获取当前网关下聊天室的客户端ID
+func (l *LiveRoomStorage) Clients(ctx context.Context, sid string, code string) []int64 {
+
+	arr := l.rds.SMembers(ctx, l.clientName(sid, code)).Val()
+
+	cids := make([]int64, 0, len(arr))
+	for _, val := range arr {
+		if cid, err := strconv.ParseInt(val, 10, 64); err == nil {
+			cids = append(cids, cid)
+		}
+	}
+
+	return cids
+}
+
+// DelClients 删除当前网关下聊天室的客户端列表
+func (l *LiveRoomStorage) DelClients(ctx context.Context, sid string, code string) {
+	l.rds.Del(ctx, l.clientName(sid, code))
+}
+
+// SetGuest 保存游客昵称
+func (l *LiveRoomStorage) SetGuest(ctx context.Context, guestId string, nickname string, expire time.Duration) error {
+	return l.rds.Set(ctx, fmt.Sprintf("live:guest:%s", guestId), nickname, expire).Err()
+}
+
+// GetGuest 获取游客昵称
+func (l *LiveRoomStorage) GetGuest(ctx context.Context, guestId string) (string, error) {
+	return l.rds.Get(ctx, fmt.Sprintf("live:guest:%s", guestId)).Result()
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go-chat/testutil"
+)
+
+func TestLiveRoomStorage_Online(t *testing.T) {
+	ctx := context.Background()
+	rds := testutil.TestRedisClient()
+	server := NewSidStorage(rds)
+	storage := NewLiveRoomStorage(rds, server)
+
+	assert.NoError(t, server.Set(ctx, "sid-1", time.Now().Unix()))
+	assert.NoError(t, server.Set(ctx, "sid-2", time.Now().Unix()))
+	assert.NoError(t, storage.Create(ctx, &LiveRoom{Code: "room", Name: "test", OwnerId: 1}))
+
+	online, err := storage.Join(ctx, "sid-1", "room", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), online)
+
+	online, err = storage.Join(ctx, "sid-2", "room", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), online)
+
+	// 网关心跳超时后不再统计其下的客户端
+	assert.NoError(t, server.Set(ctx, "sid-2", time.Now().Unix()-ServerOverTime))
+	assert.Equal(t, int64(1), storage.Online(ctx, "room"))
+
+	online, err = storage.Leave(ctx, "sid-1", "room", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), online)
+
+	// 无人在线时聊天室已销毁，不能再进入
+	_, err = storage.Get(ctx, "room")
+	assert.ErrorIs(t, err, ErrLiveRoomNotFound)
+
+	_, err = storage.Join(ctx, "sid-1", "room", 3)
+	assert.ErrorIs(t, err, ErrLiveRoomNotFound)
+}
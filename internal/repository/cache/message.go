@@ -24,7 +24,8 @@ func NewMessageStorage(rds *redis.Client) *MessageStorage {
 }
 
 func (m *MessageStorage) name(talkType int, sender int, receive int) string {
-	if talkType == 2 {
+	// 群聊及频道的最后一条消息所有成员共用
+	if talkType != 1 {
 		sender = 0
 	}
 
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go-chat/internal/pkg/strutil"
+)
+
+// 滑动窗口：清理窗口外的记录后统计窗口内的次数，limit 大于 0 时超出限制则不记录
+var rateLimitScript = redis.NewScript(`
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[1] - ARGV[2])
+local count = redis.call("ZCARD", KEYS[1])
+if tonumber(ARGV[3]) > 0 and count >= tonumber(ARGV[3]) then
+	return -1
+end
+redis.call("ZADD", KEYS[1], ARGV[1], ARGV[4])
+redis.call("PEXPIRE", KEYS[1], ARGV[2])
+return count + 1
+`)
+
+// RateLimitStorage 基于 Redis 有序集合的滑动窗口计数
+type RateLimitStorage struct {
+	rds *redis.Client
+}
+
+func NewRateLimitStorage(rds *redis.Client) *RateLimitStorage {
+	return &RateLimitStorage{rds}
+}
+
+func (s *RateLimitStorage) name(key string) string {
+	return fmt.Sprintf("rate-limit:%s", key)
+}
+
+func (s *RateLimitStorage) lockName(key string) string {
+	return fmt.Sprintf("rate-limit:lock:%s", key)
+}
+
+// Incr 记录一次并返回窗口内的次数
+func (s *RateLimitStorage) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return s.eval(ctx, key, window, 0)
+}
+
+// Allow 窗口内次数未达到 limit 时记录一次并返回 true
+func (s *RateLimitStorage) Allow(ctx context.Context, key string, limit int64, window time.Duration) (bool, error) {
+
+	count, err := s.eval(ctx, key, window, limit)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Count 获取窗口内的次数
+func (s *RateLimitStorage) Count(ctx context.Context, key string, window time.Duration) int64 {
+
+	now := time.Now().UnixMilli()
+
+	return s.rds.ZCount(ctx, s.name(key), strconv.FormatInt(now-window.Milliseconds(), 10), "+inf").Val()
+}
+
+// Clear 清空计数
+func (s *RateLimitStorage) Clear(ctx context.Context, key string) error {
+	return s.rds.Del(ctx, s.name(key)).Err()
+}
+
+// Lock 锁定指定时长，已有更长的锁定时保持不变
+func (s *RateLimitStorage) Lock(ctx context.Context, key string, expire time.Duration) error {
+
+	if s.LockTTL(ctx, key) >= expire {
+		return nil
+	}
+
+	return s.rds.Set(ctx, s.lockName(key), 1, expire).Err()
+}
+
+// LockTTL 获取剩余锁定时长，未锁定时返回 0
+func (s *RateLimitStorage) LockTTL(ctx context.Context, key string) time.Duration {
+
+	ttl := s.rds.PTTL(ctx, s.lockName(key)).Val()
+	if ttl < 0 {
+		return 0
+	}
+
+	return ttl
+}
+
+// UnLock 解除锁定
+func (s *RateLimitStorage) UnLock(ctx context.Context, key string) error {
+	return s.rds.Del(ctx, s.lockName(key)).Err()
+}
+
+func (s *RateLimitStorage) eval(ctx context.Context, key string, window time.Duration, limit int64) (int64, error) {
+
+	now := time.Now().UnixMilli()
+
+	return rateLimitScript.Run(ctx, s.rds, []string{s.name(key)}, now, window.Milliseconds(), limit, fmt.Sprintf("%d-%s", now, strutil.Random(8))).Int64()
+}
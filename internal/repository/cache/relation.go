@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -28,6 +29,10 @@ func (r *Relation) keyGroupRelation(uid, gid int) string {
 	return fmt.Sprintf("redis:contact:relation:%d_%d", uid, gid)
 }
 
+func (r *Relation) keyGroupMembers(gid int) string {
+	return fmt.Sprintf("redis:group:members:%d", gid)
+}
+
 func (r *Relation) IsContactRelation(ctx context.Context, uid, uid2 int) error {
 	return r.rds.Get(ctx, r.keyContactRelation(uid, uid2)).Err()
 }
@@ -57,3 +62,51 @@ func (r *Relation) BatchDelGroupRelation(ctx context.Context, uids []int, gid in
 		r.DelGroupRelation(ctx, uid, gid)
 	}
 }
+
+// GetGroupMemberIds 获取缓存的群成员ID
+func (r *Relation) GetGroupMemberIds(ctx context.Context, gid int) ([]int, error) {
+
+	items, err := r.rds.SMembers(ctx, r.keyGroupMembers(gid)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, redis.Nil
+	}
+
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		if id, err := strconv.Atoi(item); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// SetGroupMemberIds 缓存群成员ID
+func (r *Relation) SetGroupMemberIds(ctx context.Context, gid int, ids []int) {
+
+	if len(ids) == 0 {
+		return
+	}
+
+	members := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, id)
+	}
+
+	key := r.keyGroupMembers(gid)
+
+	pipeline := r.rds.TxPipeline()
+	pipeline.Del(ctx, key)
+	pipeline.SAdd(ctx, key, members...)
+	pipeline.Expire(ctx, key, time.Hour*1)
+	_, _ = pipeline.Exec(ctx)
+}
+
+// DelGroupMemberIds 群成员变动时删除缓存
+func (r *Relation) DelGroupMemberIds(ctx context.Context, gid int) {
+	r.rds.Del(ctx, r.keyGroupMembers(gid))
+}
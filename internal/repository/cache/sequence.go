@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"go-chat/internal/entity"
 )
 
 type Sequence struct {
@@ -20,36 +21,40 @@ func (s *Sequence) Redis() *redis.Client {
 	return s.redis
 }
 
-func (s *Sequence) Name(userId int, receiverId int) string {
+// Name 发号器键名，私聊按双方用户ID计数，群聊等其它会话按会话类型及接收者ID计数
+func (s *Sequence) Name(talkType int, userId int, receiverId int) string {
 
-	if userId == 0 {
-		return fmt.Sprintf("im:sequence:msg:%d", receiverId)
-	}
+	switch talkType {
+	case entity.ChatPrivateMode:
+		if receiverId < userId {
+			receiverId, userId = userId, receiverId
+		}
 
-	if receiverId < userId {
-		receiverId, userId = userId, receiverId
+		return fmt.Sprintf("im:sequence:msg:%d_%d", userId, receiverId)
+	case entity.ChatGroupMode:
+		return fmt.Sprintf("im:sequence:msg:%d", receiverId)
+	default:
+		return fmt.Sprintf("im:sequence:msg:%d:%d", talkType, receiverId)
 	}
-
-	return fmt.Sprintf("im:sequence:msg:%d_%d", userId, receiverId)
 }
 
 // Init 初始化发号器
-func (s *Sequence) Init(ctx context.Context, userId int, receiverId int, value int64) error {
-	return s.redis.SetEX(ctx, s.Name(userId, receiverId), value, 12*time.Hour).Err()
+func (s *Sequence) Init(ctx context.Context, talkType int, userId int, receiverId int, value int64) error {
+	return s.redis.SetEX(ctx, s.Name(talkType, userId, receiverId), value, 12*time.Hour).Err()
 }
 
 // Get 获取消息时序ID
-func (s *Sequence) Get(ctx context.Context, userId int, receiverId int) int64 {
+func (s *Sequence) Get(ctx context.Context, talkType int, userId int, receiverId int) int64 {
 
-	name := s.Name(userId, receiverId)
+	name := s.Name(talkType, userId, receiverId)
 
 	return s.redis.Incr(ctx, name).Val()
 }
 
 // BatchGet 批量获取消息时序ID
-func (s *Sequence) BatchGet(ctx context.Context, userId int, receiverId int, num int64) []int64 {
+func (s *Sequence) BatchGet(ctx context.Context, talkType int, userId int, receiverId int, num int64) []int64 {
 
-	value := s.redis.IncrBy(ctx, s.Name(userId, receiverId), num).Val()
+	value := s.redis.IncrBy(ctx, s.Name(talkType, userId, receiverId), num).Val()
 
 	items := make([]int64, 0, num)
 	for i := num; i > 0; i-- {
@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go-chat/internal/entity"
+	"go-chat/testutil"
+)
+
+func TestSequence_Name(t *testing.T) {
+	seq := NewSequence(testutil.TestRedisClient())
+
+	assert.Equal(t, seq.Name(entity.ChatPrivateMode, 2, 1), seq.Name(entity.ChatPrivateMode, 1, 2))
+	assert.NotEqual(t, seq.Name(entity.ChatGroupMode, 0, 1), seq.Name(entity.ChatChannelMode, 0, 1))
+}
+
+func TestSequence_Get(t *testing.T) {
+	ctx := context.Background()
+	seq := NewSequence(testutil.TestRedisClient())
+
+	assert.NoError(t, seq.Init(ctx, entity.ChatGroupMode, 0, 1000, 10))
+
+	// 频道与群聊ID相同时不共享发号器
+	assert.Equal(t, int64(11), seq.Get(ctx, entity.ChatGroupMode, 0, 1000))
+	assert.Equal(t, int64(1), seq.Get(ctx, entity.ChatChannelMode, 0, 1000))
+	assert.Equal(t, []int64{2, 3}, seq.BatchGet(ctx, entity.ChatChannelMode, 0, 1000, 2))
+	assert.Equal(t, int64(12), seq.Get(ctx, entity.ChatGroupMode, 0, 1000))
+}
@@ -34,3 +34,17 @@ func (s *TokenSessionStorage) DelBlackList(ctx context.Context, token string) er
 func (s *TokenSessionStorage) IsBlackList(ctx context.Context, token string) bool {
 	return s.rds.Get(ctx, s.name(token)).Val() != ""
 }
+
+func (s *TokenSessionStorage) revokedName(sessionId string) string {
+	return fmt.Sprintf("jwt:revoked-session:%s", sessionId)
+}
+
+// SetRevoked 标记设备会话已注销，有效期需覆盖该会话已签发的访问令牌
+func (s *TokenSessionStorage) SetRevoked(ctx context.Context, sessionId string, expire time.Duration) error {
+	return s.rds.Set(ctx, s.revokedName(sessionId), 1, expire).Err()
+}
+
+// IsRevoked 判断设备会话是否已注销
+func (s *TokenSessionStorage) IsRevoked(ctx context.Context, sessionId string) bool {
+	return s.rds.Get(ctx, s.revokedName(sessionId)).Val() != ""
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/go-redis/redis/v8"
 )
@@ -20,6 +21,16 @@ func (u *UnreadStorage) name(receive int) string {
 	return fmt.Sprintf("talk:unread:uid_%d", receive)
 }
 
+// 群消息计数器，群成员未读数 = 群消息计数 - 成员已读游标
+func (u *UnreadStorage) groupName(gid int) string {
+	return fmt.Sprintf("talk:unread:gid_%d", gid)
+}
+
+// 用户在各群的已读游标
+func (u *UnreadStorage) cursorName(uid int) string {
+	return fmt.Sprintf("talk:unread:cursor:uid_%d", uid)
+}
+
 // Incr 消息未读数自增
 // @params mode    对话模式 1私信 2群聊
 // @params sender  发送者ID
@@ -33,6 +44,11 @@ func (u *UnreadStorage) Incr(ctx context.Context, mode, sender, receive int) {
 // @params sender  发送者ID
 // @params receive 接收者ID
 func (u *UnreadStorage) Get(ctx context.Context, mode, sender, receive int) int {
+
+	if mode == 2 {
+		return u.GetGroup(ctx, sender, receive)
+	}
+
 	val, _ := u.rds.HGet(ctx, u.name(receive), fmt.Sprintf("%d_%d", mode, sender)).Int()
 
 	return val
@@ -43,6 +59,11 @@ func (u *UnreadStorage) Get(ctx context.Context, mode, sender, receive int) int
 // @params sender  发送者ID
 // @params receive 接收者ID
 func (u *UnreadStorage) Del(ctx context.Context, mode, sender, receive int) {
+
+	if mode == 2 {
+		u.rds.HDel(ctx, u.cursorName(receive), strconv.Itoa(sender))
+	}
+
 	u.rds.HDel(ctx, u.name(receive), fmt.Sprintf("%d_%d", mode, sender))
 }
 
@@ -51,6 +72,12 @@ func (u *UnreadStorage) Del(ctx context.Context, mode, sender, receive int) {
 // @params sender  发送者ID
 // @params receive 接收者ID
 func (u *UnreadStorage) Reset(ctx context.Context, mode, sender, receive int) {
+
+	if mode == 2 {
+		u.ResetGroup(ctx, sender, receive)
+		return
+	}
+
 	u.rds.HSet(ctx, u.name(receive), fmt.Sprintf("%d_%d", mode, sender), 0)
 }
 
@@ -60,5 +87,153 @@ func (u *UnreadStorage) All(ctx context.Context, receive int) map[string]int {
 		items[k], _ = strconv.Atoi(v)
 	}
 
+	groups := u.groupAll(ctx, receive)
+	for gid, num := range groups {
+		items[fmt.Sprintf("2_%d", gid)] = num
+	}
+
+	// 旧版按成员累加的群未读数，首次读取时转换为已读游标
+	for key := range items {
+		if !strings.HasPrefix(key, "2_") {
+			continue
+		}
+
+		if gid, err := strconv.Atoi(strings.TrimPrefix(key, "2_")); err == nil {
+			if _, ok := groups[gid]; !ok {
+				items[key] = u.GetGroup(ctx, gid, receive)
+			}
+		}
+	}
+
+	return items
+}
+
+// IncrGroup 群消息计数自增，发送者的已读游标同步到最新
+// @params gid     群ID
+// @params sender  发送者ID
+func (u *UnreadStorage) IncrGroup(ctx context.Context, gid, sender int) {
+
+	total, err := u.rds.Incr(ctx, u.groupName(gid)).Result()
+	if err != nil || sender == 0 {
+		return
+	}
+
+	u.rds.HSet(ctx, u.cursorName(sender), strconv.Itoa(gid), total)
+}
+
+// InitGroup 初始化群成员的已读游标，入群前的消息不计入未读数
+// @params gid   群ID
+// @params uids  群成员ID
+func (u *UnreadStorage) InitGroup(ctx context.Context, gid int, uids []int) {
+
+	total, _ := u.rds.Get(ctx, u.groupName(gid)).Int64()
+
+	pipeline := u.rds.Pipeline()
+	for _, uid := range uids {
+		pipeline.HSet(ctx, u.cursorName(uid), strconv.Itoa(gid), total)
+	}
+
+	_, _ = pipeline.Exec(ctx)
+}
+
+// ResetGroup 重置群消息未读数
+// @params gid      群ID
+// @params receive  群成员ID
+func (u *UnreadStorage) ResetGroup(ctx context.Context, gid, receive int) {
+	u.InitGroup(ctx, gid, []int{receive})
+}
+
+// DelGroup 删除群成员的已读游标
+// @params gid   群ID
+// @params uids  群成员ID
+func (u *UnreadStorage) DelGroup(ctx context.Context, gid int, uids []int) {
+
+	pipeline := u.rds.Pipeline()
+	for _, uid := range uids {
+		pipeline.HDel(ctx, u.cursorName(uid), strconv.Itoa(gid))
+	}
+
+	_, _ = pipeline.Exec(ctx)
+}
+
+// GetGroup 获取群消息未读数，未初始化已读游标时由旧版未读数补全游标
+// @params gid      群ID
+// @params receive  群成员ID
+func (u *UnreadStorage) GetGroup(ctx context.Context, gid, receive int) int {
+
+	total, _ := u.rds.Get(ctx, u.groupName(gid)).Int64()
+
+	cursor, err := u.rds.HGet(ctx, u.cursorName(receive), strconv.Itoa(gid)).Int64()
+	if err != nil {
+		cursor = u.backfillGroup(ctx, gid, receive, total)
+	}
+
+	return groupUnread(total, cursor)
+}
+
+// backfillGroup 根据旧版按成员累加的未读数补全已读游标，并删除旧版未读数
+func (u *UnreadStorage) backfillGroup(ctx context.Context, gid, receive int, total int64) int64 {
+
+	field := fmt.Sprintf("2_%d", gid)
+
+	legacy, _ := u.rds.HGet(ctx, u.name(receive), field).Int64()
+
+	cursor := legacyGroupCursor(total, legacy)
+
+	// 并发补全时以先写入的游标为准
+	if ok, err := u.rds.HSetNX(ctx, u.cursorName(receive), strconv.Itoa(gid), cursor).Result(); err == nil && !ok {
+		cursor, _ = u.rds.HGet(ctx, u.cursorName(receive), strconv.Itoa(gid)).Int64()
+	}
+
+	u.rds.HDel(ctx, u.name(receive), field)
+
+	return cursor
+}
+
+// groupUnread 群未读数 = 群消息计数 - 已读游标
+func groupUnread(total, cursor int64) int {
+	if total <= cursor {
+		return 0
+	}
+
+	return int(total - cursor)
+}
+
+// legacyGroupCursor 由旧版未读数推算已读游标，群消息计数落后于旧版未读数时游标为负数
+func legacyGroupCursor(total, unread int64) int64 {
+	if unread < 0 {
+		unread = 0
+	}
+
+	return total - unread
+}
+
+// groupAll 获取用户所有群的未读数
+func (u *UnreadStorage) groupAll(ctx context.Context, receive int) map[int]int {
+
+	items := make(map[int]int)
+
+	cursors := u.rds.HGetAll(ctx, u.cursorName(receive)).Val()
+	if len(cursors) == 0 {
+		return items
+	}
+
+	pipeline := u.rds.Pipeline()
+	cmds := make(map[int]*redis.StringCmd, len(cursors))
+	for key := range cursors {
+		if gid, err := strconv.Atoi(key); err == nil {
+			cmds[gid] = pipeline.Get(ctx, u.groupName(gid))
+		}
+	}
+
+	_, _ = pipeline.Exec(ctx)
+
+	for gid, cmd := range cmds {
+		total, _ := cmd.Int64()
+		cursor, _ := strconv.ParseInt(cursors[strconv.Itoa(gid)], 10, 64)
+
+		items[gid] = groupUnread(total, cursor)
+	}
+
 	return items
 }
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go-chat/testutil"
+)
+
+func TestGroupUnread(t *testing.T) {
+	assert.Equal(t, 0, groupUnread(0, 0))
+	assert.Equal(t, 3, groupUnread(10, 7))
+	assert.Equal(t, 0, groupUnread(7, 10))
+	assert.Equal(t, 5, groupUnread(2, -3))
+
+	assert.Equal(t, int64(7), legacyGroupCursor(10, 3))
+	assert.Equal(t, int64(-3), legacyGroupCursor(0, 3))
+	assert.Equal(t, int64(10), legacyGroupCursor(10, -1))
+}
+
+func TestUnreadStorage_Group(t *testing.T) {
+	ctx := context.Background()
+	storage := NewUnreadStorage(testutil.TestRedisClient())
+
+	storage.IncrGroup(ctx, 1, 100)
+	storage.InitGroup(ctx, 1, []int{200})
+
+	// 发送者的游标同步到最新，入群前的消息不计入未读数
+	storage.IncrGroup(ctx, 1, 100)
+	storage.IncrGroup(ctx, 1, 100)
+	assert.Equal(t, 0, storage.GetGroup(ctx, 1, 100))
+	assert.Equal(t, 2, storage.GetGroup(ctx, 1, 200))
+	assert.Equal(t, 2, storage.All(ctx, 200)["2_1"])
+
+	storage.ResetGroup(ctx, 1, 200)
+	assert.Equal(t, 0, storage.GetGroup(ctx, 1, 200))
+
+	storage.IncrGroup(ctx, 1, 100)
+	assert.Equal(t, 1, storage.GetGroup(ctx, 1, 200))
+}
+
+func TestUnreadStorage_GroupLegacy(t *testing.T) {
+	ctx := context.Background()
+	storage := NewUnreadStorage(testutil.TestRedisClient())
+
+	// 旧版按成员累加的未读数
+	storage.Incr(ctx, 2, 1, 300)
+	storage.Incr(ctx, 2, 1, 300)
+	storage.Incr(ctx, 2, 2, 300)
+
+	assert.Equal(t, 2, storage.GetGroup(ctx, 1, 300))
+
+	storage.IncrGroup(ctx, 1, 100)
+	assert.Equal(t, 3, storage.GetGroup(ctx, 1, 300))
+
+	items := storage.All(ctx, 300)
+	assert.Equal(t, 3, items["2_1"])
+	assert.Equal(t, 1, items["2_2"])
+
+	storage.IncrGroup(ctx, 2, 100)
+	assert.Equal(t, 2, storage.All(ctx, 300)["2_2"])
+}
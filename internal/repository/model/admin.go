@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type Admin struct {
+	Id          int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`     // 管理员ID
+	Username    string    `gorm:"column:username;NOT NULL" json:"username"`           // 登录账号
+	Password    string    `gorm:"column:password;NOT NULL" json:"-"`                  // 登录密码
+	Nickname    string    `gorm:"column:nickname;NOT NULL" json:"nickname"`           // 昵称
+	Role        string    `gorm:"column:role;NOT NULL" json:"role"`                   // 角色[super:超级管理员;operator:运营;auditor:审计;]
+	Status      int       `gorm:"column:status;default:0;NOT NULL" json:"status"`     // 状态[0:正常;1:已禁用;]
+	LastLoginIp string    `gorm:"column:last_login_ip;NOT NULL" json:"last_login_ip"` // 最后登录IP
+	LastLoginAt time.Time `gorm:"column:last_login_at;NOT NULL" json:"last_login_at"` // 最后登录时间
+	CreatedAt   time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`       // 创建时间
+	UpdatedAt   time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`       // 更新时间
+}
+
+func (Admin) TableName() string {
+	return "admin"
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type AdminAuditLog struct {
+	Id         int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`       // 自增ID
+	AdminId    int       `gorm:"column:admin_id;default:0;NOT NULL" json:"admin_id"`   // 管理员ID
+	Action     string    `gorm:"column:action;NOT NULL" json:"action"`                 // 操作类型
+	TargetType string    `gorm:"column:target_type;NOT NULL" json:"target_type"`       // 操作对象类型
+	TargetId   int       `gorm:"column:target_id;default:0;NOT NULL" json:"target_id"` // 操作对象ID
+	Detail     string    `gorm:"column:detail;NOT NULL" json:"detail"`                 // 操作详情(JSON)
+	Ip         string    `gorm:"column:ip;NOT NULL" json:"ip"`                         // 操作IP
+	Agent      string    `gorm:"column:agent;NOT NULL" json:"agent"`                   // 设备信息
+	CreatedAt  time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`         // 操作时间
+}
+
+func (AdminAuditLog) TableName() string {
+	return "admin_audit_log"
+}
@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+type Channel struct {
+	Id            int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`                 // 频道ID
+	CreatorId     int       `gorm:"column:creator_id;default:0;NOT NULL" json:"creator_id"`         // 创建者ID
+	Name          string    `gorm:"column:name;NOT NULL" json:"name"`                               // 频道名称
+	Avatar        string    `gorm:"column:avatar;NOT NULL" json:"avatar"`                           // 频道头像
+	Profile       string    `gorm:"column:profile;NOT NULL" json:"profile"`                         // 频道简介
+	IsOvert       int       `gorm:"column:is_overt;default:0;NOT NULL" json:"is_overt"`             // 是否公开可见[0:否;1:是;]
+	SubscriberNum int       `gorm:"column:subscriber_num;default:0;NOT NULL" json:"subscriber_num"` // 订阅人数
+	IsDismiss     int       `gorm:"column:is_dismiss;default:0;NOT NULL" json:"is_dismiss"`         // 是否已解散[0:否;1:是;]
+	CreatedAt     time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`                   // 创建时间
+	UpdatedAt     time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`                   // 更新时间
+}
+
+func (Channel) TableName() string {
+	return "channel"
+}
+
+type ChannelMember struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`         // 自增ID
+	ChannelId int       `gorm:"column:channel_id;default:0;NOT NULL" json:"channel_id"` // 频道ID
+	UserId    int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`       // 用户ID
+	Role      int       `gorm:"column:role;default:0;NOT NULL" json:"role"`             // 成员身份[0:订阅者;1:管理员;2:创建者;]
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`           // 订阅时间
+}
+
+func (ChannelMember) TableName() string {
+	return "channel_member"
+}
+
+type ChannelReaction struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`         // 自增ID
+	ChannelId int       `gorm:"column:channel_id;default:0;NOT NULL" json:"channel_id"` // 频道ID
+	RecordId  int       `gorm:"column:record_id;default:0;NOT NULL" json:"record_id"`   // 频道消息ID
+	UserId    int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`       // 用户ID
+	Emoji     string    `gorm:"column:emoji;NOT NULL" json:"emoji"`                     // 互动表情
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`           // 创建时间
+}
+
+func (ChannelReaction) TableName() string {
+	return "channel_reaction"
+}
+
+type ChannelView struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`         // 自增ID
+	ChannelId int       `gorm:"column:channel_id;default:0;NOT NULL" json:"channel_id"` // 频道ID
+	RecordId  int       `gorm:"column:record_id;default:0;NOT NULL" json:"record_id"`   // 频道消息ID
+	ViewNum   int64     `gorm:"column:view_num;default:0;NOT NULL" json:"view_num"`     // 浏览量
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`           // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`           // 更新时间
+}
+
+func (ChannelView) TableName() string {
+	return "channel_view"
+}
+
+type ChannelReactionCount struct {
+	RecordId int    `json:"record_id"`
+	Emoji    string `json:"emoji"`
+	Count    int    `json:"count"`
+}
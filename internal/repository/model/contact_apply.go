@@ -8,7 +8,10 @@ type ContactApply struct {
 	UserId    int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`     // 申请人ID
 	FriendId  int       `gorm:"column:friend_id;default:0;NOT NULL" json:"friend_id"` // 被申请人
 	Remark    string    `gorm:"column:remark;NOT NULL" json:"remark"`                 // 申请备注
+	Status    int       `gorm:"column:status;default:0;NOT NULL" json:"status"`       // 申请状态[0:待处理;1:已同意;2:已拒绝;3:已过期;]
+	Reason    string    `gorm:"column:reason;NOT NULL" json:"reason"`                 // 拒绝原因
 	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`         // 申请时间
+	UpdatedAt time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`         // 更新时间
 }
 
 func (ContactApply) TableName() string {
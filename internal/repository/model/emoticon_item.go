@@ -10,6 +10,7 @@ type EmoticonItem struct {
 	Url        string    `gorm:"column:url;NOT NULL" json:"url"`                           // 图片链接
 	FileSuffix string    `gorm:"column:file_suffix;NOT NULL" json:"file_suffix"`           // 文件后缀名
 	FileSize   int       `gorm:"column:file_size;default:0;NOT NULL" json:"file_size"`     // 文件大小（单位字节）
+	BlobId     int       `gorm:"column:blob_id;default:0;NOT NULL" json:"blob_id"`         // 文件存储ID
 	CreatedAt  time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`             // 创建时间
 	UpdatedAt  time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`             // 更新时间
 }
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type FileBlob struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`       // 文件ID
+	Drive     int       `gorm:"column:drive;default:1;NOT NULL" json:"drive"`         // 驱动类型[1:local;2:cos;3:s3;4:oss;5:qiniu;]
+	Hash      string    `gorm:"column:hash;NOT NULL" json:"hash"`                     // 文件内容 SHA-256
+	IsPublic  int       `gorm:"column:is_public;default:0;NOT NULL" json:"is_public"` // 是否公开文件[0:否;1:是;]
+	Path      string    `gorm:"column:path;NOT NULL" json:"path"`                     // 文件地址(相对地址)
+	Size      int64     `gorm:"column:size;default:0;NOT NULL" json:"size"`           // 文件大小
+	RefCount  int       `gorm:"column:ref_count;default:0;NOT NULL" json:"ref_count"` // 引用次数
+	Derived   string    `gorm:"column:derived;NOT NULL" json:"derived"`               // 衍生文件地址(JSON)，如图片规格图
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`         // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`         // 更新时间
+}
+
+func (FileBlob) TableName() string {
+	return "file_blob"
+}
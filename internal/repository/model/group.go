@@ -5,28 +5,41 @@ import (
 )
 
 const (
-	GroupMemberMaxNum = 200 // 最大成员数量
+	GroupMemberMaxNum      = 200   // 最大成员数量
+	SuperGroupMemberMaxNum = 10000 // 超级群最大成员数量
 )
 
 type Group struct {
-	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`         // 群ID
-	Type      int       `gorm:"column:type;default:1;NOT NULL" json:"type"`             // 群类型[1:普通群;2:企业群;]
-	CreatorId int       `gorm:"column:creator_id;default:0;NOT NULL" json:"creator_id"` // 创建者ID(群主ID)
-	Name      string    `gorm:"column:group_name;NOT NULL" json:"group_name"`           // 群名称
-	Profile   string    `gorm:"column:profile;NOT NULL" json:"profile"`                 // 群介绍
-	IsDismiss int       `gorm:"column:is_dismiss;default:0;NOT NULL" json:"is_dismiss"` // 是否已解散[0:否;1:是;]
-	Avatar    string    `gorm:"column:avatar;NOT NULL" json:"avatar"`                   // 群头像
-	MaxNum    int       `gorm:"column:max_num;default:200;NOT NULL" json:"max_num"`     // 最大群成员数量
-	IsOvert   int       `gorm:"column:is_overt;default:0;NOT NULL" json:"is_overt"`     // 是否公开可见[0:否;1:是;]
-	IsMute    int       `gorm:"column:is_mute;default:0;NOT NULL" json:"is_mute"`       // 是否全员禁言 [0:否;1:是;]，提示:不包含群主或管理员
-	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`           // 创建时间
-	UpdatedAt time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`           // 更新时间
+	Id             int        `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`                     // 群ID
+	Type           int        `gorm:"column:type;default:1;NOT NULL" json:"type"`                         // 群类型[1:普通群;2:企业群;]
+	CreatorId      int        `gorm:"column:creator_id;default:0;NOT NULL" json:"creator_id"`             // 创建者ID(群主ID)
+	Name           string     `gorm:"column:group_name;NOT NULL" json:"group_name"`                       // 群名称
+	Profile        string     `gorm:"column:profile;NOT NULL" json:"profile"`                             // 群介绍
+	IsDismiss      int        `gorm:"column:is_dismiss;default:0;NOT NULL" json:"is_dismiss"`             // 是否已解散[0:否;1:是;]
+	Avatar         string     `gorm:"column:avatar;NOT NULL" json:"avatar"`                               // 群头像
+	MaxNum         int        `gorm:"column:max_num;default:200;NOT NULL" json:"max_num"`                 // 最大群成员数量
+	IsOvert        int        `gorm:"column:is_overt;default:0;NOT NULL" json:"is_overt"`                 // 是否公开可见[0:否;1:是;]
+	IsMute         int        `gorm:"column:is_mute;default:0;NOT NULL" json:"is_mute"`                   // 是否全员禁言 [0:否;1:是;]，提示:不包含群主或管理员
+	MuteUntil      *time.Time `gorm:"column:mute_until" json:"mute_until"`                                // 全员禁言截止时间，为空时永久禁言
+	IsSuper        int        `gorm:"column:is_super;default:0;NOT NULL" json:"is_super"`                 // 是否超级群[0:否;1:是;]
+	IsBroadcast    int        `gorm:"column:is_broadcast;default:0;NOT NULL" json:"is_broadcast"`         // 是否仅群主及管理员可发言[0:否;1:是;]
+	JoinMode       int        `gorm:"column:join_mode;default:2;NOT NULL" json:"join_mode"`               // 入群方式[1:直接加入;2:需要审核;3:仅限邀请;4:回答问题;]
+	Question       string     `gorm:"column:join_question;NOT NULL" json:"join_question"`                 // 入群问题
+	Answer         string     `gorm:"column:join_answer;NOT NULL" json:"-"`                               // 入群问题答案，为空时由管理员审核
+	IsShareHistory int        `gorm:"column:is_share_history;default:0;NOT NULL" json:"is_share_history"` // 新成员是否可查看入群前的历史消息[0:否;1:是;]
+	CreatedAt      time.Time  `gorm:"column:created_at;NOT NULL" json:"created_at"`                       // 创建时间
+	UpdatedAt      time.Time  `gorm:"column:updated_at;NOT NULL" json:"updated_at"`                       // 更新时间
 }
 
 func (Group) TableName() string {
 	return "group"
 }
 
+// IsMuted 是否处于全员禁言中，已过期的禁言视为未禁言
+func (g *Group) IsMuted() bool {
+	return g.IsMute == 1 && (g.MuteUntil == nil || g.MuteUntil.After(time.Now()))
+}
+
 type GroupItem struct {
 	Id        int    `json:"id"`
 	GroupName string `json:"group_name"`
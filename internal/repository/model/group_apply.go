@@ -3,12 +3,16 @@ package model
 import "time"
 
 type GroupApply struct {
-	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`     // 自增ID
-	GroupId   int       `gorm:"column:group_id;default:0;NOT NULL" json:"group_id"` // 群组ID
-	UserId    int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`   // 用户ID
-	Remark    string    `gorm:"column:remark;NOT NULL" json:"remark"`               // 备注信息
-	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`       // 创建时间
-	UpdatedAt time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`       // 更新时间
+	Id         int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`           // 自增ID
+	GroupId    int       `gorm:"column:group_id;default:0;NOT NULL" json:"group_id"`       // 群组ID
+	UserId     int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`         // 用户ID
+	Remark     string    `gorm:"column:remark;NOT NULL" json:"remark"`                     // 备注信息
+	Answer     string    `gorm:"column:answer;NOT NULL" json:"answer"`                     // 入群问题回答
+	Status     int       `gorm:"column:status;default:0;NOT NULL" json:"status"`           // 申请状态[0:待审核;1:已通过;2:已拒绝;]
+	ReviewerId int       `gorm:"column:reviewer_id;default:0;NOT NULL" json:"reviewer_id"` // 审核人ID，自动通过时为 0
+	Reason     string    `gorm:"column:reason;NOT NULL" json:"reason"`                     // 拒绝原因
+	CreatedAt  time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`             // 创建时间
+	UpdatedAt  time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`             // 更新时间
 }
 
 func (GroupApply) TableName() string {
@@ -16,11 +20,16 @@ func (GroupApply) TableName() string {
 }
 
 type GroupApplyList struct {
-	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`     // 自增ID
-	GroupId   int       `gorm:"column:group_id;default:0;NOT NULL" json:"group_id"` // 群组ID
-	UserId    int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`   // 用户ID
-	Remark    string    `gorm:"column:remark;NOT NULL" json:"remark"`               // 备注信息
-	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`       // 创建时间
-	Nickname  string    `gorm:"column:nickname;NOT NULL" json:"nickname"`           // 用户昵称
-	Avatar    string    `gorm:"column:avatar;NOT NULL" json:"avatar"`               // 用户头像地址
+	Id         int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`           // 自增ID
+	GroupId    int       `gorm:"column:group_id;default:0;NOT NULL" json:"group_id"`       // 群组ID
+	UserId     int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`         // 用户ID
+	Remark     string    `gorm:"column:remark;NOT NULL" json:"remark"`                     // 备注信息
+	Answer     string    `gorm:"column:answer;NOT NULL" json:"answer"`                     // 入群问题回答
+	Status     int       `gorm:"column:status;default:0;NOT NULL" json:"status"`           // 申请状态[0:待审核;1:已通过;2:已拒绝;]
+	ReviewerId int       `gorm:"column:reviewer_id;default:0;NOT NULL" json:"reviewer_id"` // 审核人ID
+	Reason     string    `gorm:"column:reason;NOT NULL" json:"reason"`                     // 拒绝原因
+	CreatedAt  time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`             // 创建时间
+	UpdatedAt  time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`             // 处理时间
+	Nickname   string    `gorm:"column:nickname;NOT NULL" json:"nickname"`                 // 用户昵称
+	Avatar     string    `gorm:"column:avatar;NOT NULL" json:"avatar"`                     // 用户头像地址
 }
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+type GroupInviteLink struct {
+	Id           int        `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`               // 自增ID
+	GroupId      int        `gorm:"column:group_id;default:0;NOT NULL" json:"group_id"`           // 群组ID
+	CreatorId    int        `gorm:"column:creator_id;default:0;NOT NULL" json:"creator_id"`       // 创建人ID
+	Code         string     `gorm:"column:code;NOT NULL" json:"code"`                             // 邀请码
+	MaxUses      int        `gorm:"column:max_uses;default:0;NOT NULL" json:"max_uses"`           // 最大使用次数，0 为不限
+	UsedNum      int        `gorm:"column:used_num;default:0;NOT NULL" json:"used_num"`           // 已使用次数
+	NeedApproval int        `gorm:"column:need_approval;default:0;NOT NULL" json:"need_approval"` // 是否需要审核[0:否;1:是;]
+	IsRevoke     int        `gorm:"column:is_revoke;default:0;NOT NULL" json:"is_revoke"`         // 是否已作废[0:否;1:是;]
+	ExpiredAt    *time.Time `gorm:"column:expired_at" json:"expired_at"`                          // 过期时间，为空时永久有效
+	CreatedAt    time.Time  `gorm:"column:created_at;NOT NULL" json:"created_at"`                 // 创建时间
+	UpdatedAt    time.Time  `gorm:"column:updated_at;NOT NULL" json:"updated_at"`                 // 更新时间
+}
+
+func (GroupInviteLink) TableName() string {
+	return "group_invite_link"
+}
+
+type GroupInviteLinkItem struct {
+	GroupInviteLink
+	Nickname string `gorm:"column:nickname" json:"nickname"` // 创建人昵称
+}
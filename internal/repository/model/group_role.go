@@ -0,0 +1,29 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+type GroupRole struct {
+	Id          int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`     // 角色ID
+	GroupId     int       `gorm:"column:group_id;default:0;NOT NULL" json:"group_id"` // 群组ID
+	Name        string    `gorm:"column:name;NOT NULL" json:"name"`                   // 角色名称
+	Permissions string    `gorm:"column:permissions;NOT NULL" json:"permissions"`     // 权限列表(逗号分隔)
+	CreatedAt   time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`       // 创建时间
+	UpdatedAt   time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`       // 更新时间
+}
+
+func (GroupRole) TableName() string {
+	return "group_role"
+}
+
+// PermissionList 角色权限列表
+func (g *GroupRole) PermissionList() []string {
+
+	if g.Permissions == "" {
+		return []string{}
+	}
+
+	return strings.Split(g.Permissions, ",")
+}
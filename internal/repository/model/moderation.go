@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+type ModerationWord struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 自增ID
+	Word      string    `gorm:"column:word;NOT NULL" json:"word"`               // 敏感词
+	Action    string    `gorm:"column:action;NOT NULL" json:"action"`           // 处理动作[replace:替换;flag:人工审核;block:拦截;]
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`   // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`   // 更新时间
+}
+
+func (ModerationWord) TableName() string {
+	return "moderation_word"
+}
+
+type ModerationLink struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 自增ID
+	Domain    string    `gorm:"column:domain;NOT NULL" json:"domain"`           // 域名(包含子域名)
+	Type      int       `gorm:"column:type;default:0;NOT NULL" json:"type"`     // 名单类型[1:白名单;2:黑名单;]
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`   // 创建时间
+}
+
+func (ModerationLink) TableName() string {
+	return "moderation_link"
+}
+
+type ModerationReview struct {
+	Id         int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`           // 自增ID
+	RecordId   int       `gorm:"column:record_id;default:0;NOT NULL" json:"record_id"`     // 消息记录ID
+	UserId     int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`         // 发送者ID
+	TalkType   int       `gorm:"column:talk_type;default:0;NOT NULL" json:"talk_type"`     // 对话类型[1:私信;2:群聊;]
+	ReceiverId int       `gorm:"column:receiver_id;default:0;NOT NULL" json:"receiver_id"` // 接收者ID(用户ID或群ID)
+	Content    string    `gorm:"column:content;NOT NULL" json:"content"`                   // 消息内容
+	Hook       string    `gorm:"column:hook;NOT NULL" json:"hook"`                         // 命中的审核项
+	Reason     string    `gorm:"column:reason;NOT NULL" json:"reason"`                     // 命中原因
+	Status     int       `gorm:"column:status;default:0;NOT NULL" json:"status"`           // 审核状态[0:待审核;1:通过;2:驳回;]
+	AdminId    int       `gorm:"column:admin_id;default:0;NOT NULL" json:"admin_id"`       // 审核管理员ID
+	CreatedAt  time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`             // 创建时间
+	UpdatedAt  time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`             // 更新时间
+}
+
+func (ModerationReview) TableName() string {
+	return "moderation_review"
+}
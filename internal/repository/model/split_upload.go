@@ -7,7 +7,7 @@ import (
 type SplitUpload struct {
 	Id           int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`           // 临时文件ID
 	Type         int       `gorm:"column:type;default:1;NOT NULL" json:"type"`               // 文件属性[1:合并文件;2:拆分文件]
-	Drive        int       `gorm:"column:drive;default:1;NOT NULL" json:"drive"`             // 驱动类型[1:local;2:cos;]
+	Drive        int       `gorm:"column:drive;default:1;NOT NULL" json:"drive"`             // 驱动类型[1:local;2:cos;3:s3;4:oss;5:qiniu;]
 	UploadId     string    `gorm:"column:upload_id;NOT NULL" json:"upload_id"`               // 临时文件hash名
 	UserId       int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`         // 上传的用户ID
 	OriginalName string    `gorm:"column:original_name;NOT NULL" json:"original_name"`       // 原文件名
@@ -17,6 +17,7 @@ type SplitUpload struct {
 	FileExt      string    `gorm:"column:file_ext;NOT NULL" json:"file_ext"`                 // 文件后缀名
 	FileSize     int64     `gorm:"column:file_size;NOT NULL" json:"file_size"`               // 文件大小
 	IsDelete     int       `gorm:"column:is_delete;default:0;NOT NULL" json:"is_delete"`     // 文件是否删除[0:否;1:是;]
+	Hash         string    `gorm:"column:hash;NOT NULL" json:"hash"`                         // 合并文件内容 SHA-256
 	Attr         string    `gorm:"column:attr;NOT NULL" json:"attr"`                         // 额外参数json
 	CreatedAt    time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`             // 更新时间
 	UpdatedAt    time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`             // 创建时间
@@ -8,11 +8,18 @@ type TalkRecordsFile struct {
 	UserId       int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`     // 上传文件的用户ID
 	Source       int       `gorm:"column:source;default:1;NOT NULL" json:"source"`       // 文件来源[1:用户上传;2:表情包;]
 	Type         int       `gorm:"column:type;default:1;NOT NULL" json:"type"`           // 文件类型[1:图片;2:音频文件;3:视频文件;4:其它文件;]
-	Drive        int       `gorm:"column:drive;default:1;NOT NULL" json:"drive"`         // 驱动类型[1:local;2:cos;]
+	Drive        int       `gorm:"column:drive;default:1;NOT NULL" json:"drive"`         // 驱动类型[1:local;2:cos;3:s3;4:oss;5:qiniu;]
 	OriginalName string    `gorm:"column:original_name;NOT NULL" json:"original_name"`   // 原文件名
 	Suffix       string    `gorm:"column:suffix;NOT NULL" json:"suffix"`                 // 文件后缀
 	Size         int       `gorm:"column:size;default:0;NOT NULL" json:"size"`           // 文件大小
 	Path         string    `gorm:"column:path;NOT NULL" json:"path"`                     // 文件地址(相对地址)
 	Url          string    `gorm:"column:url;NOT NULL" json:"url"`                       // 网络地址(公开文件地址)
+	Duration     int       `gorm:"column:duration;default:0;NOT NULL" json:"duration"`   // 媒体时长(秒)
+	Width        int       `gorm:"column:width;default:0;NOT NULL" json:"width"`         // 媒体宽度(像素)
+	Height       int       `gorm:"column:height;default:0;NOT NULL" json:"height"`       // 媒体高度(像素)
+	Codec        string    `gorm:"column:codec;NOT NULL" json:"codec"`                   // 媒体编码格式
+	Thumbnail    string    `gorm:"column:thumbnail;NOT NULL" json:"thumbnail"`           // 缩略图地址(相对地址)
+	Renditions   string    `gorm:"column:renditions;NOT NULL" json:"-"`                  // 多尺寸规格图信息(JSON)
+	BlobId       int       `gorm:"column:blob_id;default:0;NOT NULL" json:"-"`           // 文件存储ID
 	CreatedAt    time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`         // 创建时间
 }
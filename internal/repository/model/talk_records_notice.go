@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+type TalkRecordsNotice struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`         // 自增ID
+	RecordId  int       `gorm:"column:record_id;default:0;NOT NULL" json:"record_id"`   // 消息记录ID
+	NoticeId  int       `gorm:"column:notice_id;default:0;NOT NULL" json:"notice_id"`   // 群公告ID
+	Title     string    `gorm:"column:title;NOT NULL" json:"title"`                     // 公告标题
+	Content   string    `gorm:"column:content;NOT NULL" json:"content"`                 // 公告内容
+	IsConfirm int       `gorm:"column:is_confirm;default:0;NOT NULL" json:"is_confirm"` // 是否需群成员确认公告[0:否;1:是;]
+	IsUpdate  int       `gorm:"column:is_update;default:0;NOT NULL" json:"is_update"`   // 是否为更新公告[0:否;1:是;]
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`           // 创建时间
+}
+
+func (TalkRecordsNotice) TableName() string {
+	return "talk_records_notice"
+}
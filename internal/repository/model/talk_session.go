@@ -4,7 +4,7 @@ import "time"
 
 type TalkSession struct {
 	Id         int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`           // 聊天列表ID
-	TalkType   int       `gorm:"column:talk_type;default:1;NOT NULL" json:"talk_type"`     // 聊天类型[1:私信;2:群聊;]
+	TalkType   int       `gorm:"column:talk_type;default:1;NOT NULL" json:"talk_type"`     // 聊天类型[1:私信;2:群聊;3:频道;]
 	UserId     int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`         // 用户ID
 	ReceiverId int       `gorm:"column:receiver_id;default:0;NOT NULL" json:"receiver_id"` // 接收者ID（用户ID 或 群ID）
 	IsTop      int       `gorm:"column:is_top;default:0;NOT NULL" json:"is_top"`           // 是否置顶[0:否;1:是;]
@@ -20,16 +20,18 @@ func (TalkSession) TableName() string {
 }
 
 type SearchTalkSession struct {
-	Id          int       `json:"id" `
-	TalkType    int       `json:"talk_type" `
-	ReceiverId  int       `json:"receiver_id" `
-	IsDelete    int       `json:"is_delete"`
-	IsTop       int       `json:"is_top"`
-	IsRobot     int       `json:"is_robot"`
-	IsDisturb   int       `json:"is_disturb"`
-	UserAvatar  string    `json:"user_avatar"`
-	Nickname    string    `json:"nickname"`
-	GroupName   string    `json:"group_name"`
-	GroupAvatar string    `json:"group_avatar"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id            int       `json:"id" `
+	TalkType      int       `json:"talk_type" `
+	ReceiverId    int       `json:"receiver_id" `
+	IsDelete      int       `json:"is_delete"`
+	IsTop         int       `json:"is_top"`
+	IsRobot       int       `json:"is_robot"`
+	IsDisturb     int       `json:"is_disturb"`
+	UserAvatar    string    `json:"user_avatar"`
+	Nickname      string    `json:"nickname"`
+	GroupName     string    `json:"group_name"`
+	GroupAvatar   string    `json:"group_avatar"`
+	ChannelName   string    `json:"channel_name"`
+	ChannelAvatar string    `json:"channel_avatar"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type UserIdentity struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`   // 自增ID
+	UserId    int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"` // 用户ID
+	Provider  string    `gorm:"column:provider;NOT NULL" json:"provider"`         // 身份提供方(issuer)
+	Subject   string    `gorm:"column:subject;NOT NULL" json:"subject"`           // 身份提供方的用户标识(sub)
+	Email     string    `gorm:"column:email;NOT NULL" json:"email"`               // 身份提供方的邮箱
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`     // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`     // 更新时间
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identity"
+}
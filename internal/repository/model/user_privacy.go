@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+type UserPrivacy struct {
+	Id           int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`               // 自增ID
+	UserId       int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`             // 用户ID
+	AddContact   int       `gorm:"column:add_contact;default:0;NOT NULL" json:"add_contact"`     // 谁可以添加我[0:所有人;1:仅企业成员;2:不允许;]
+	OnlineStatus int       `gorm:"column:online_status;default:0;NOT NULL" json:"online_status"` // 在线状态可见范围[0:联系人可见;1:不可见;]
+	MobileSearch int       `gorm:"column:mobile_search;default:0;NOT NULL" json:"mobile_search"` // 通过手机号搜索[0:允许;1:不允许;]
+	CreatedAt    time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`                 // 创建时间
+	UpdatedAt    time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`                 // 更新时间
+}
+
+func (UserPrivacy) TableName() string {
+	return "user_privacy"
+}
+
+type UserBlock struct {
+	Id          int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`               // 自增ID
+	UserId      int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`             // 用户ID
+	BlockUserId int       `gorm:"column:block_user_id;default:0;NOT NULL" json:"block_user_id"` // 被拉黑的用户ID
+	CreatedAt   time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`                 // 拉黑时间
+}
+
+func (UserBlock) TableName() string {
+	return "user_block"
+}
+
+type UserBlockItem struct {
+	UserId    int       `json:"user_id"`
+	Nickname  string    `json:"nickname"`
+	Avatar    string    `json:"avatar"`
+	CreatedAt time.Time `json:"created_at"`
+}
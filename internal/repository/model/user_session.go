@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type UserSession struct {
+	Id               int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`               // 会话ID
+	UserId           int       `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`             // 用户ID
+	SessionId        string    `gorm:"column:session_id;NOT NULL" json:"session_id"`                 // 设备会话标识
+	Platform         string    `gorm:"column:platform;NOT NULL" json:"platform"`                     // 登录平台
+	Ip               string    `gorm:"column:ip;NOT NULL" json:"ip"`                                 // 登录IP
+	Address          string    `gorm:"column:address;NOT NULL" json:"address"`                       // 登录地址
+	Agent            string    `gorm:"column:agent;NOT NULL" json:"agent"`                           // 设备信息
+	RefreshToken     string    `gorm:"column:refresh_token;NOT NULL" json:"-"`                       // 当前刷新令牌 SHA-256
+	RefreshExpiresAt time.Time `gorm:"column:refresh_expires_at;NOT NULL" json:"refresh_expires_at"` // 刷新令牌过期时间
+	IsRevoked        int       `gorm:"column:is_revoked;default:0;NOT NULL" json:"is_revoked"`       // 是否已注销[0:否;1:是;]
+	LastActiveAt     time.Time `gorm:"column:last_active_at;NOT NULL" json:"last_active_at"`         // 最后活跃时间
+	CreatedAt        time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`                 // 创建时间
+	UpdatedAt        time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`                 // 更新时间
+}
+
+func (UserSession) TableName() string {
+	return "user_session"
+}
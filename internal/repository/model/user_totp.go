@@ -0,0 +1,22 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type UserTotp struct {
+	Id            int          `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`         // 自增ID
+	UserId        int          `gorm:"column:user_id;default:0;NOT NULL" json:"user_id"`       // 用户ID
+	Secret        string       `gorm:"column:secret;NOT NULL" json:"-"`                        // TOTP 秘钥(Base32)
+	IsEnabled     int          `gorm:"column:is_enabled;default:0;NOT NULL" json:"is_enabled"` // 是否已启用[0:否;1:是;]
+	RecoveryCodes string       `gorm:"column:recovery_codes;NOT NULL" json:"-"`                // 恢复码 SHA-256 列表(JSON)
+	LastCounter   int64        `gorm:"column:last_counter;default:0;NOT NULL" json:"-"`        // 最后使用的时间步
+	EnabledAt     sql.NullTime `gorm:"column:enabled_at" json:"enabled_at"`                    // 启用时间
+	CreatedAt     time.Time    `gorm:"column:created_at;NOT NULL" json:"created_at"`           // 创建时间
+	UpdatedAt     time.Time    `gorm:"column:updated_at;NOT NULL" json:"updated_at"`           // 更新时间
+}
+
+func (UserTotp) TableName() string {
+	return "user_totp"
+}
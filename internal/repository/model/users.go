@@ -4,7 +4,7 @@ import "time"
 
 type Users struct {
 	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`     // 用户ID
-	Mobile    string    `gorm:"column:mobile;NOT NULL" json:"mobile"`               // 手机号
+	Mobile    string    `gorm:"column:mobile" json:"mobile"`                        // 手机号(单点登录创建的账号可为空)
 	Nickname  string    `gorm:"column:nickname;NOT NULL" json:"nickname"`           // 用户昵称
 	Avatar    string    `gorm:"column:avatar;NOT NULL" json:"avatar"`               // 用户头像地址
 	Gender    int       `gorm:"column:gender;default:0;NOT NULL" json:"gender"`     // 用户性别  0:未知  1:男   2:女
@@ -13,6 +13,7 @@ type Users struct {
 	Email     string    `gorm:"column:email;NOT NULL" json:"email"`                 // 用户邮箱
 	Birthday  string    `gorm:"column:birthday;NOT NULL" json:"birthday"`           // 生日
 	IsRobot   int       `gorm:"column:is_robot;default:0;NOT NULL" json:"is_robot"` // 是否机器人[0:否;1:是;]
+	Status    int       `gorm:"column:status;default:0;NOT NULL" json:"status"`     // 账号状态[0:正常;1:已禁用;]
 	CreatedAt time.Time `gorm:"column:created_at;NOT NULL" json:"created_at"`       // 注册时间
 	UpdatedAt time.Time `gorm:"column:updated_at;NOT NULL" json:"updated_at"`       // 更新时间
 }
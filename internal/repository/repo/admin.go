@@ -0,0 +1,30 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type Admin struct {
+	ichat.Repo[model.Admin]
+}
+
+func NewAdmin(db *gorm.DB) *Admin {
+	return &Admin{Repo: ichat.NewRepo[model.Admin](db)}
+}
+
+// FindByUsername 登录账号查询
+func (a *Admin) FindByUsername(ctx context.Context, username string) (*model.Admin, error) {
+	return a.FindByWhere(ctx, "username = ?", username)
+}
+
+// IsUsernameExist 判断登录账号是否存在
+func (a *Admin) IsUsernameExist(ctx context.Context, username string) bool {
+
+	exist, _ := a.QueryExist(ctx, "username = ?", username)
+
+	return exist
+}
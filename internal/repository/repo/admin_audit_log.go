@@ -0,0 +1,15 @@
+package repo
+
+import (
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type AdminAuditLog struct {
+	ichat.Repo[model.AdminAuditLog]
+}
+
+func NewAdminAuditLog(db *gorm.DB) *AdminAuditLog {
+	return &AdminAuditLog{Repo: ichat.NewRepo[model.AdminAuditLog](db)}
+}
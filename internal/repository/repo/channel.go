@@ -0,0 +1,192 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Channel struct {
+	ichat.Repo[model.Channel]
+}
+
+func NewChannel(db *gorm.DB) *Channel {
+	return &Channel{Repo: ichat.NewRepo[model.Channel](db)}
+}
+
+// SearchOvertList 公开频道列表，按名称搜索
+func (c *Channel) SearchOvertList(ctx context.Context, name string, page, size int) ([]*model.Channel, int64, error) {
+
+	where := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("is_overt = 1 and is_dismiss = 0")
+
+		if name != "" {
+			db = db.Where("name LIKE ?", "%"+name+"%")
+		}
+
+		return db
+	}
+
+	var total int64
+	if err := where(c.Model(ctx)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items, err := c.FindAll(ctx, func(db *gorm.DB) {
+		where(db).Order("subscriber_num desc, id desc").Offset((page - 1) * size).Limit(size)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// UserChannels 用户订阅的频道列表
+func (c *Channel) UserChannels(ctx context.Context, uid int) ([]*model.Channel, error) {
+	return c.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("is_dismiss = 0 and id in (?)", c.Db.Table("channel_member").Select("channel_id").Where("user_id = ?", uid))
+		db.Order("id desc")
+	})
+}
+
+// IncrSubscriber 更新订阅人数，需与订阅记录在同一事务中调用
+func (c *Channel) IncrSubscriber(tx *gorm.DB, id int, num int) error {
+	return tx.Model(&model.Channel{}).Where("id = ?", id).UpdateColumn("subscriber_num", gorm.Expr("greatest(cast(subscriber_num as signed) + ?, 0)", num)).Error
+}
+
+type ChannelMember struct {
+	ichat.Repo[model.ChannelMember]
+}
+
+func NewChannelMember(db *gorm.DB) *ChannelMember {
+	return &ChannelMember{Repo: ichat.NewRepo[model.ChannelMember](db)}
+}
+
+// FindMember 获取频道成员信息
+func (c *ChannelMember) FindMember(ctx context.Context, channelId, uid int) (*model.ChannelMember, error) {
+	return c.FindByWhere(ctx, "channel_id = ? and user_id = ?", channelId, uid)
+}
+
+// IsSubscriber 判断是否已订阅频道
+func (c *ChannelMember) IsSubscriber(ctx context.Context, channelId, uid int) bool {
+
+	exist, err := c.QueryExist(ctx, "channel_id = ? and user_id = ?", channelId, uid)
+	if err != nil {
+		return false
+	}
+
+	return exist
+}
+
+// IsAdmin 判断是否是频道创建者或管理员
+func (c *ChannelMember) IsAdmin(ctx context.Context, channelId, uid int) bool {
+
+	exist, err := c.QueryExist(ctx, "channel_id = ? and user_id = ? and role in (1,2)", channelId, uid)
+	if err != nil {
+		return false
+	}
+
+	return exist
+}
+
+// GetUserChannelIds 获取用户订阅的频道ID
+func (c *ChannelMember) GetUserChannelIds(ctx context.Context, uid int) []int {
+
+	var ids []int
+	_ = c.Db.WithContext(ctx).Table("channel_member").
+		Joins("join channel on channel.id = channel_member.channel_id and channel.is_dismiss = 0").
+		Where("channel_member.user_id = ?", uid).Pluck("channel_member.channel_id", &ids)
+
+	return ids
+}
+
+// GetSubscribedIds 获取用户已订阅的频道ID
+func (c *ChannelMember) GetSubscribedIds(ctx context.Context, uid int, channelIds []int) []int {
+
+	var ids []int
+	_ = c.Model(ctx).Where("user_id = ? and channel_id in ?", uid, channelIds).Pluck("channel_id", &ids)
+
+	return ids
+}
+
+type ChannelReaction struct {
+	ichat.Repo[model.ChannelReaction]
+}
+
+func NewChannelReaction(db *gorm.DB) *ChannelReaction {
+	return &ChannelReaction{Repo: ichat.NewRepo[model.ChannelReaction](db)}
+}
+
+// Counts 统计频道消息的互动表情数量
+func (c *ChannelReaction) Counts(ctx context.Context, recordIds []int) ([]*model.ChannelReactionCount, error) {
+
+	items := make([]*model.ChannelReactionCount, 0)
+	err := c.Model(ctx).Select("record_id, emoji, count(*) as count").
+		Where("record_id in ?", recordIds).Group("record_id, emoji").Scan(&items).Error
+
+	return items, err
+}
+
+// UserReactions 获取用户对频道消息的互动表情
+func (c *ChannelReaction) UserReactions(ctx context.Context, uid int, recordIds []int) ([]*model.ChannelReaction, error) {
+	return c.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("user_id = ? and record_id in ?", uid, recordIds)
+	})
+}
+
+type ChannelView struct {
+	ichat.Repo[model.ChannelView]
+}
+
+func NewChannelView(db *gorm.DB) *ChannelView {
+	return &ChannelView{Repo: ichat.NewRepo[model.ChannelView](db)}
+}
+
+// Incr 频道消息浏览量加一
+func (c *ChannelView) Incr(ctx context.Context, channelId int, recordIds []int) error {
+
+	if len(recordIds) == 0 {
+		return nil
+	}
+
+	items := make([]*model.ChannelView, 0, len(recordIds))
+	for _, recordId := range recordIds {
+		items = append(items, &model.ChannelView{
+			ChannelId: channelId,
+			RecordId:  recordId,
+			ViewNum:   1,
+		})
+	}
+
+	return c.Db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "record_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"view_num":   gorm.Expr("view_num + 1"),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&items).Error
+}
+
+// Counts 批量获取频道消息浏览量
+func (c *ChannelView) Counts(ctx context.Context, recordIds []int) map[int]int64 {
+
+	items := make(map[int]int64, len(recordIds))
+
+	list, err := c.FindAll(ctx, func(db *gorm.DB) {
+		db.Select("record_id", "view_num").Where("record_id in ?", recordIds)
+	})
+	if err != nil {
+		return items
+	}
+
+	for _, item := range list {
+		items[item.RecordId] = item.ViewNum
+	}
+
+	return items
+}
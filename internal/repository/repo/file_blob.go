@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type FileBlob struct {
+	ichat.Repo[model.FileBlob]
+}
+
+func NewFileBlob(db *gorm.DB) *FileBlob {
+	return &FileBlob{Repo: ichat.NewRepo[model.FileBlob](db)}
+}
+
+// FindByHash 根据文件内容 hash 查询文件
+func (f *FileBlob) FindByHash(ctx context.Context, drive int, hash string, isPublic int) (*model.FileBlob, error) {
+	return f.FindByWhere(ctx, "drive = ? and hash = ? and is_public = ?", drive, hash, isPublic)
+}
+
+// Retain 增加文件引用次数，需与引用记录在同一事务中调用
+func (f *FileBlob) Retain(tx *gorm.DB, ids ...int) error {
+	return f.incr(tx, 1, ids)
+}
+
+// Release 减少文件引用次数，需与引用记录在同一事务中调用
+func (f *FileBlob) Release(tx *gorm.DB, ids ...int) error {
+	return f.incr(tx, -1, ids)
+}
+
+func (f *FileBlob) incr(tx *gorm.DB, step int, ids []int) error {
+	counts := make(map[int]int)
+	for _, id := range ids {
+		if id > 0 {
+			counts[id] += step
+		}
+	}
+
+	for id, num := range counts {
+		err := tx.Model(&model.FileBlob{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+			"ref_count":  gorm.Expr("ref_count + ?", num),
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -27,3 +27,29 @@ func (g *Group) SearchOvertList(ctx context.Context, name string, page, size int
 		db.Where("is_dismiss = 0").Order("created_at desc").Offset((page - 1) * size).Limit(size)
 	})
 }
+
+// SearchList 按群名称或群ID搜索群组(含已解散)
+func (g *Group) SearchList(ctx context.Context, keyword string, page, size int) ([]*model.Group, int64, error) {
+
+	where := func(db *gorm.DB) *gorm.DB {
+		if keyword != "" {
+			db = db.Where("(group_name LIKE ? or id = ?)", "%"+keyword+"%", keyword)
+		}
+
+		return db
+	}
+
+	var total int64
+	if err := where(g.Model(ctx)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items, err := g.FindAll(ctx, func(db *gorm.DB) {
+		where(db).Order("id desc").Offset((page - 1) * size).Limit(size)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 
+	"go-chat/internal/entity"
 	"go-chat/internal/pkg/ichat"
 	"go-chat/internal/repository/model"
 	"gorm.io/gorm"
@@ -16,14 +17,20 @@ func NewGroupApply(db *gorm.DB) *GroupApply {
 	return &GroupApply{Repo: ichat.NewRepo[model.GroupApply](db)}
 }
 
-func (g *GroupApply) List(ctx context.Context, groupId int) ([]*model.GroupApplyList, error) {
+// List 入群申请列表，status 为 -1 时查询全部
+func (g *GroupApply) List(ctx context.Context, groupId int, status int) ([]*model.GroupApplyList, error) {
 
 	fields := []string{
 		"group_apply.id",
 		"group_apply.group_id",
 		"group_apply.user_id",
 		"group_apply.remark",
+		"group_apply.answer",
+		"group_apply.status",
+		"group_apply.reviewer_id",
+		"group_apply.reason",
 		"group_apply.created_at",
+		"group_apply.updated_at",
 		"users.avatar",
 		"users.nickname",
 	}
@@ -31,6 +38,11 @@ func (g *GroupApply) List(ctx context.Context, groupId int) ([]*model.GroupApply
 	query := g.Db.WithContext(ctx).Table("group_apply")
 	query.Joins("left join users on users.id = group_apply.user_id")
 	query.Where("group_apply.group_id = ?", groupId)
+
+	if status >= 0 {
+		query.Where("group_apply.status = ?", status)
+	}
+
 	query.Order("group_apply.created_at desc")
 
 	items := make([]*model.GroupApplyList, 0)
@@ -40,3 +52,8 @@ func (g *GroupApply) List(ctx context.Context, groupId int) ([]*model.GroupApply
 
 	return items, nil
 }
+
+// FindPending 获取用户在群内待审核的申请
+func (g *GroupApply) FindPending(ctx context.Context, groupId int, userId int) (*model.GroupApply, error) {
+	return g.FindByWhere(ctx, "group_id = ? and user_id = ? and status = ?", groupId, userId, entity.GroupApplyStatusPending)
+}
@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type GroupInviteLink struct {
+	ichat.Repo[model.GroupInviteLink]
+}
+
+func NewGroupInviteLink(db *gorm.DB) *GroupInviteLink {
+	return &GroupInviteLink{Repo: ichat.NewRepo[model.GroupInviteLink](db)}
+}
+
+// FindByCode 根据邀请码获取邀请链接
+func (g *GroupInviteLink) FindByCode(ctx context.Context, code string) (*model.GroupInviteLink, error) {
+	return g.FindByWhere(ctx, "code = ?", code)
+}
+
+// List 群邀请链接列表，creatorId 大于 0 时仅查询该成员创建的链接
+func (g *GroupInviteLink) List(ctx context.Context, groupId int, creatorId int) ([]*model.GroupInviteLinkItem, error) {
+
+	query := g.Db.WithContext(ctx).Table("group_invite_link")
+	query.Joins("left join users on users.id = group_invite_link.creator_id")
+	query.Where("group_invite_link.group_id = ? and group_invite_link.is_revoke = 0", groupId)
+
+	if creatorId > 0 {
+		query.Where("group_invite_link.creator_id = ?", creatorId)
+	}
+
+	query.Order("group_invite_link.id desc")
+
+	items := make([]*model.GroupInviteLinkItem, 0)
+	if err := query.Select("group_invite_link.*", "users.nickname").Scan(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// IncrUsed 占用一次使用次数，超出最大使用次数时返回 false
+func (g *GroupInviteLink) IncrUsed(ctx context.Context, id int) (bool, error) {
+
+	res := g.Model(ctx).Where("id = ? and (max_uses = 0 or used_num < max_uses)", id).
+		UpdateColumn("used_num", gorm.Expr("used_num + 1"))
+
+	return res.RowsAffected > 0, res.Error
+}
+
+// DecrUsed 归还一次使用次数
+func (g *GroupInviteLink) DecrUsed(ctx context.Context, id int) error {
+	return g.Model(ctx).Where("id = ? and used_num > 0", id).UpdateColumn("used_num", gorm.Expr("used_num - 1")).Error
+}
@@ -62,12 +62,56 @@ func (g *GroupMember) IsMember(ctx context.Context, gid, uid int, cache bool) bo
 // GetMemberIds 获取所有群成员用户ID
 func (g *GroupMember) GetMemberIds(ctx context.Context, groupId int) []int {
 
+	if ids, err := g.relation.GetGroupMemberIds(ctx, groupId); err == nil {
+		return ids
+	}
+
 	var ids []int
 	_ = g.Model(ctx).Select("user_id").Where("group_id = ? and is_quit = ?", groupId, 0).Scan(&ids)
 
+	g.relation.SetGroupMemberIds(ctx, groupId, ids)
+
 	return ids
 }
 
+// ClearMemberIds 群成员变动后清除群成员ID缓存
+func (g *GroupMember) ClearMemberIds(ctx context.Context, groupId int) {
+	g.relation.DelGroupMemberIds(ctx, groupId)
+}
+
+// Paginate 群成员分页列表，支持按昵称或群名片搜索
+func (g *GroupMember) Paginate(ctx context.Context, groupId int, keyword string, page, size int) ([]*model.MemberItem, int64, error) {
+
+	where := func(tx *gorm.DB) *gorm.DB {
+		tx = tx.Joins("left join users on users.id = group_member.user_id")
+		tx = tx.Where("group_member.group_id = ? and group_member.is_quit = ?", groupId, 0)
+
+		if keyword != "" {
+			tx = tx.Where("(users.nickname like ? or group_member.user_card like ?)", "%"+keyword+"%", "%"+keyword+"%")
+		}
+
+		return tx
+	}
+
+	var total int64
+	if err := where(g.Db.WithContext(ctx).Table("group_member")).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]*model.MemberItem, 0)
+	if total == 0 {
+		return items, 0, nil
+	}
+
+	err := where(g.Db.WithContext(ctx).Table("group_member")).Select(groupMemberFields).
+		Order("group_member.leader desc, group_member.id asc").Offset((page - 1) * size).Limit(size).Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
 // GetUserGroupIds 获取所有群成员ID
 func (g *GroupMember) GetUserGroupIds(ctx context.Context, uid int) []int {
 
@@ -92,19 +136,40 @@ func (g *GroupMember) GetMemberRemark(ctx context.Context, groupId int, userId i
 	return remarks
 }
 
+// GetMinRecordId 获取群成员可查看历史记录的最小ID
+func (g *GroupMember) GetMinRecordId(ctx context.Context, groupId int, userId int) int {
+
+	var minRecordId int
+	g.Model(ctx).Select("min_record_id").Where("group_id = ? and user_id = ? and is_quit = 0", groupId, userId).Scan(&minRecordId)
+
+	return minRecordId
+}
+
+// IsRecordVisible 判断群成员是否可查看指定的群消息，未共享的入群前历史消息不可查看
+func (g *GroupMember) IsRecordVisible(ctx context.Context, groupId int, userId int, recordId int) bool {
+
+	exist, err := g.QueryExist(ctx, "group_id = ? and user_id = ? and is_quit = 0 and min_record_id <= ?", groupId, userId, recordId)
+	if err != nil {
+		return false
+	}
+
+	return exist
+}
+
+var groupMemberFields = []string{
+	"group_member.id",
+	"group_member.leader",
+	"group_member.user_card",
+	"group_member.user_id",
+	"group_member.is_mute",
+	"users.avatar",
+	"users.nickname",
+	"users.gender",
+	"users.motto",
+}
+
 // GetMembers 获取群组成员列表
 func (g *GroupMember) GetMembers(ctx context.Context, groupId int) []*model.MemberItem {
-	fields := []string{
-		"group_member.id",
-		"group_member.leader",
-		"group_member.user_card",
-		"group_member.user_id",
-		"group_member.is_mute",
-		"users.avatar",
-		"users.nickname",
-		"users.gender",
-		"users.motto",
-	}
 
 	tx := g.Db.WithContext(ctx).Table("group_member")
 	tx.Joins("left join users on users.id = group_member.user_id")
@@ -112,7 +177,7 @@ func (g *GroupMember) GetMembers(ctx context.Context, groupId int) []*model.Memb
 	tx.Order("group_member.leader desc")
 
 	items := make([]*model.MemberItem, 0)
-	tx.Unscoped().Select(fields).Scan(&items)
+	tx.Unscoped().Select(groupMemberFields).Scan(&items)
 
 	return items
 }
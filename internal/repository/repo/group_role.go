@@ -0,0 +1,24 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type GroupRole struct {
+	ichat.Repo[model.GroupRole]
+}
+
+func NewGroupRole(db *gorm.DB) *GroupRole {
+	return &GroupRole{Repo: ichat.NewRepo[model.GroupRole](db)}
+}
+
+// List 群组自定义角色列表
+func (g *GroupRole) List(ctx context.Context, groupId int) ([]*model.GroupRole, error) {
+	return g.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("group_id = ?", groupId).Order("id asc")
+	})
+}
@@ -0,0 +1,47 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type ModerationWord struct {
+	ichat.Repo[model.ModerationWord]
+}
+
+func NewModerationWord(db *gorm.DB) *ModerationWord {
+	return &ModerationWord{Repo: ichat.NewRepo[model.ModerationWord](db)}
+}
+
+// All 全部敏感词
+func (m *ModerationWord) All(ctx context.Context) ([]*model.ModerationWord, error) {
+	return m.FindAll(ctx, func(db *gorm.DB) {
+		db.Order("id asc")
+	})
+}
+
+type ModerationLink struct {
+	ichat.Repo[model.ModerationLink]
+}
+
+func NewModerationLink(db *gorm.DB) *ModerationLink {
+	return &ModerationLink{Repo: ichat.NewRepo[model.ModerationLink](db)}
+}
+
+// All 全部链接名单
+func (m *ModerationLink) All(ctx context.Context) ([]*model.ModerationLink, error) {
+	return m.FindAll(ctx, func(db *gorm.DB) {
+		db.Order("id asc")
+	})
+}
+
+type ModerationReview struct {
+	ichat.Repo[model.ModerationReview]
+}
+
+func NewModerationReview(db *gorm.DB) *ModerationReview {
+	return &ModerationReview{Repo: ichat.NewRepo[model.ModerationReview](db)}
+}
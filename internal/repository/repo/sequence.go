@@ -20,19 +20,20 @@ func NewSequence(db *gorm.DB, cache *cache.Sequence) *Sequence {
 	return &Sequence{db: db, cache: cache}
 }
 
-func (s *Sequence) try(ctx context.Context, userId int, receiverId int) {
-	result := s.cache.Redis().TTL(ctx, s.cache.Name(userId, receiverId)).Val()
+func (s *Sequence) try(ctx context.Context, talkType int, userId int, receiverId int) {
+	name := s.cache.Name(talkType, userId, receiverId)
+	result := s.cache.Redis().TTL(ctx, name).Val()
 
 	// 当数据不存在时需要从数据库中加载
 	// 这里可能存在并发问题，但会话间 Sequence ID 并发情况下从复也几乎是能忍受的
 	if result == time.Duration(-2) {
 		tx := s.db.WithContext(ctx).Model(&model.TalkRecords{})
 
-		// 检测UserId 是否被设置，未设置则代表群聊
-		if userId == 0 {
-			tx = tx.Where("receiver_id = ? and type = ?", receiverId, entity.ChatGroupMode)
-		} else {
+		// 私聊按双方的消息记录加载，群聊及频道按会话类型加载
+		if talkType == entity.ChatPrivateMode {
 			tx = tx.Where("user_id = ? and receiver_id = ?", userId, receiverId).Or("user_id = ? and receiver_id = ?", receiverId, userId)
+		} else {
+			tx = tx.Where("receiver_id = ? and talk_type = ?", receiverId, talkType)
 		}
 
 		var seq int64
@@ -42,26 +43,26 @@ func (s *Sequence) try(ctx context.Context, userId int, receiverId int) {
 			return
 		}
 
-		if err := s.cache.Init(ctx, userId, receiverId, seq); err != nil {
+		if err := s.cache.Init(ctx, talkType, userId, receiverId, seq); err != nil {
 			logger.Error("[Sequence Init] 加载异常 err: ", err.Error())
 		}
 	} else if result == time.Duration(-1) {
-		s.cache.Redis().Expire(ctx, s.cache.Name(userId, receiverId), 12*time.Hour)
+		s.cache.Redis().Expire(ctx, name, 12*time.Hour)
 	}
 }
 
 // Get 获取会话间的时序ID
-func (s *Sequence) Get(ctx context.Context, userId int, receiverId int) int64 {
+func (s *Sequence) Get(ctx context.Context, talkType int, userId int, receiverId int) int64 {
 
-	s.try(ctx, userId, receiverId)
+	s.try(ctx, talkType, userId, receiverId)
 
-	return s.cache.Get(ctx, userId, receiverId)
+	return s.cache.Get(ctx, talkType, userId, receiverId)
 }
 
 // BatchGet 批量获取会话间的时序ID
-func (s *Sequence) BatchGet(ctx context.Context, userId int, receiverId int, num int64) []int64 {
+func (s *Sequence) BatchGet(ctx context.Context, talkType int, userId int, receiverId int, num int64) []int64 {
 
-	s.try(ctx, userId, receiverId)
+	s.try(ctx, talkType, userId, receiverId)
 
-	return s.cache.BatchGet(ctx, userId, receiverId, num)
+	return s.cache.BatchGet(ctx, talkType, userId, receiverId, num)
 }
@@ -18,7 +18,7 @@ func NewFileSplitUpload(db *gorm.DB) *SplitUpload {
 
 func (s *SplitUpload) GetSplitList(ctx context.Context, uploadId string) ([]*model.SplitUpload, error) {
 	return s.FindAll(ctx, func(db *gorm.DB) {
-		db.Where("upload_id = ? and type = 2", uploadId)
+		db.Where("upload_id = ? and type = 2", uploadId).Order("split_index asc")
 	})
 }
 
@@ -0,0 +1,22 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type UserIdentity struct {
+	ichat.Repo[model.UserIdentity]
+}
+
+func NewUserIdentity(db *gorm.DB) *UserIdentity {
+	return &UserIdentity{Repo: ichat.NewRepo[model.UserIdentity](db)}
+}
+
+// FindBySubject 查询第三方身份关联信息
+func (u *UserIdentity) FindBySubject(ctx context.Context, provider string, subject string) (*model.UserIdentity, error) {
+	return u.FindByWhere(ctx, "provider = ? and subject = ?", provider, subject)
+}
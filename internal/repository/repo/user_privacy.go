@@ -0,0 +1,89 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type UserPrivacy struct {
+	ichat.Repo[model.UserPrivacy]
+}
+
+func NewUserPrivacy(db *gorm.DB) *UserPrivacy {
+	return &UserPrivacy{Repo: ichat.NewRepo[model.UserPrivacy](db)}
+}
+
+// FindByUserId 获取用户隐私设置，未设置时返回默认设置
+func (u *UserPrivacy) FindByUserId(ctx context.Context, uid int) *model.UserPrivacy {
+
+	items := u.FindByUserIds(ctx, []int{uid})
+
+	return items[uid]
+}
+
+// FindByUserIds 批量获取用户隐私设置，未设置的用户返回默认设置
+func (u *UserPrivacy) FindByUserIds(ctx context.Context, uids []int) map[int]*model.UserPrivacy {
+
+	items := make(map[int]*model.UserPrivacy, len(uids))
+	for _, uid := range uids {
+		items[uid] = &model.UserPrivacy{UserId: uid}
+	}
+
+	if len(uids) == 0 {
+		return items
+	}
+
+	list, err := u.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("user_id in ?", uids)
+	})
+	if err != nil {
+		return items
+	}
+
+	for _, item := range list {
+		items[item.UserId] = item
+	}
+
+	return items
+}
+
+type UserBlock struct {
+	ichat.Repo[model.UserBlock]
+}
+
+func NewUserBlock(db *gorm.DB) *UserBlock {
+	return &UserBlock{Repo: ichat.NewRepo[model.UserBlock](db)}
+}
+
+// IsBlocked 判断 uid 是否已将 targetId 拉黑
+func (u *UserBlock) IsBlocked(ctx context.Context, uid int, targetId int) bool {
+
+	ok, err := u.QueryExist(ctx, "user_id = ? and block_user_id = ?", uid, targetId)
+
+	return err == nil && ok
+}
+
+// BlockerIds 获取 uids 中已将 targetId 拉黑的用户ID
+func (u *UserBlock) BlockerIds(ctx context.Context, targetId int, uids []int) []int {
+
+	ids := make([]int, 0)
+	if len(uids) == 0 {
+		return ids
+	}
+
+	u.Model(ctx).Where("user_id in ? and block_user_id = ?", uids, targetId).Pluck("user_id", &ids)
+
+	return ids
+}
+
+// BlockIds 获取 uid 拉黑的用户ID
+func (u *UserBlock) BlockIds(ctx context.Context, uid int) []int {
+
+	ids := make([]int, 0)
+	u.Model(ctx).Where("user_id = ?", uid).Pluck("block_user_id", &ids)
+
+	return ids
+}
@@ -0,0 +1,29 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type UserSession struct {
+	ichat.Repo[model.UserSession]
+}
+
+func NewUserSession(db *gorm.DB) *UserSession {
+	return &UserSession{Repo: ichat.NewRepo[model.UserSession](db)}
+}
+
+// FindBySessionId 根据设备会话标识查询会话
+func (u *UserSession) FindBySessionId(ctx context.Context, sessionId string) (*model.UserSession, error) {
+	return u.FindByWhere(ctx, "session_id = ?", sessionId)
+}
+
+// FindActive 查询用户未注销的设备会话
+func (u *UserSession) FindActive(ctx context.Context, uid int) ([]*model.UserSession, error) {
+	return u.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("user_id = ? and is_revoked = 0 and refresh_expires_at > NOW()", uid).Order("last_active_at desc")
+	})
+}
@@ -0,0 +1,27 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/ichat"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type UserTotp struct {
+	ichat.Repo[model.UserTotp]
+}
+
+func NewUserTotp(db *gorm.DB) *UserTotp {
+	return &UserTotp{Repo: ichat.NewRepo[model.UserTotp](db)}
+}
+
+// FindByUserId 查询用户两步验证信息
+func (u *UserTotp) FindByUserId(ctx context.Context, uid int) (*model.UserTotp, error) {
+	return u.FindByWhere(ctx, "user_id = ?", uid)
+}
+
+// IsEnabled 判断用户是否已启用两步验证
+func (u *UserTotp) IsEnabled(ctx context.Context, uid int) (bool, error) {
+	return u.QueryExist(ctx, "user_id = ? and is_enabled = 1", uid)
+}
@@ -19,7 +19,14 @@ func NewUsers(db *gorm.DB) *Users {
 
 // Create 创建数据
 func (u *Users) Create(user *model.Users) (*model.Users, error) {
-	if err := u.Db.Create(user).Error; err != nil {
+
+	tx := u.Db
+	if user.Mobile == "" {
+		// 未绑定手机号时写入 NULL，避免唯一索引冲突
+		tx = tx.Omit("mobile")
+	}
+
+	if err := tx.Create(user).Error; err != nil {
 		return nil, err
 	}
 
@@ -47,3 +54,35 @@ func (u *Users) IsMobileExist(mobile string) bool {
 
 	return exist
 }
+
+// SearchList 按关键词(手机号、昵称或用户ID)搜索用户，status 小于 0 时不限制状态
+func (u *Users) SearchList(ctx context.Context, keyword string, status int, page, size int) ([]*model.Users, int64, error) {
+
+	where := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("is_robot = 0")
+
+		if keyword != "" {
+			db = db.Where("(mobile = ? or nickname LIKE ? or id = ?)", keyword, "%"+keyword+"%", keyword)
+		}
+
+		if status >= 0 {
+			db = db.Where("status = ?", status)
+		}
+
+		return db
+	}
+
+	var total int64
+	if err := where(u.Model(ctx)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items, err := u.FindAll(ctx, func(db *gorm.DB) {
+		where(db).Order("id desc").Offset((page - 1) * size).Limit(size)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAdminLogin     = errors.New("登录账号或密码填写错误")
+	ErrAdminDisabled  = errors.New("管理员账号已被禁用")
+	ErrAdminForbidden = errors.New("暂无权限操作")
+	ErrAdminExist     = errors.New("登录账号已存在")
+	ErrAdminNotFound  = errors.New("管理员不存在")
+	ErrAdminRole      = errors.New("管理员角色不正确")
+)
+
+// 账号不存在时用于校验的密码哈希，避免通过响应耗时枚举账号
+const adminDummyPassword = "$2a$10$Szqq8MiUxIg8XH6W7RLlxO3bCnWndS9UnbdOSyEQTbz/GNRFOVNQK"
+
+type AdminCreateOpts struct {
+	Username string
+	Password string
+	Nickname string
+	Role     string
+}
+
+type AdminUpdateOpts struct {
+	Id       int
+	Nickname string
+	Role     string
+	Status   int
+	Password string // 为空时不修改密码
+}
+
+type AdminAuditOpts struct {
+	AdminId    int
+	Action     string
+	TargetType string
+	TargetId   int
+	Detail     interface{}
+	Ip         string
+	Agent      string
+}
+
+type AdminAuditListOpts struct {
+	AdminId int
+	Action  string
+	Page    int
+	Size    int
+}
+
+// AdminStats 系统统计数据
+type AdminStats struct {
+	UserTotal          int64 `json:"user_total"`           // 用户总数
+	UserToday          int64 `json:"user_today"`           // 今日注册用户数
+	UserDisabled       int64 `json:"user_disabled"`        // 已禁用用户数
+	GroupTotal         int64 `json:"group_total"`          // 群组总数(不含已解散)
+	GroupToday         int64 `json:"group_today"`          // 今日创建群组数
+	MessageToday       int64 `json:"message_today"`        // 今日消息数
+	ActiveSessionToday int64 `json:"active_session_today"` // 今日活跃设备会话数
+	EmoticonTotal      int64 `json:"emoticon_total"`       // 系统表情包数
+	RobotTotal         int64 `json:"robot_total"`          // 机器人数
+	RobotDisabled      int64 `json:"robot_disabled"`       // 已禁用机器人数
+}
+
+type AdminService struct {
+	*BaseService
+	repo      *repo.Admin
+	auditRepo *repo.AdminAuditLog
+}
+
+func NewAdminService(baseService *BaseService, repo *repo.Admin, auditRepo *repo.AdminAuditLog) *AdminService {
+	return &AdminService{BaseService: baseService, repo: repo, auditRepo: auditRepo}
+}
+
+func (s *AdminService) Dao() *repo.Admin {
+	return s.repo
+}
+
+// Login 管理员登录，校验账号密码及账号状态
+func (s *AdminService) Login(ctx context.Context, username string, password string, ip string) (*model.Admin, error) {
+
+	admin, err := s.repo.FindByUsername(ctx, username)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		encrypt.VerifyPassword(adminDummyPassword, password)
+
+		return nil, ErrAdminLogin
+	}
+
+	if !encrypt.VerifyPassword(admin.Password, password) {
+		return nil, ErrAdminLogin
+	}
+
+	if admin.Status != entity.AdminStatusNormal {
+		return nil, ErrAdminDisabled
+	}
+
+	_, _ = s.repo.UpdateById(ctx, admin.Id, map[string]interface{}{
+		"last_login_ip": ip,
+		"last_login_at": time.Now(),
+	})
+
+	return admin, nil
+}
+
+// Authorize 校验管理员状态及角色，roles 为空时仅校验状态
+func (s *AdminService) Authorize(ctx context.Context, id int, roles ...string) (*model.Admin, error) {
+
+	admin, err := s.repo.FindById(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAdminNotFound
+		}
+
+		return nil, err
+	}
+
+	if admin.Status != entity.AdminStatusNormal {
+		return nil, ErrAdminDisabled
+	}
+
+	if len(roles) == 0 || admin.Role == entity.AdminRoleSuper {
+		return admin, nil
+	}
+
+	for _, role := range roles {
+		if admin.Role == role {
+			return admin, nil
+		}
+	}
+
+	return nil, ErrAdminForbidden
+}
+
+// List 管理员列表
+func (s *AdminService) List(ctx context.Context, page, size int) ([]*model.Admin, int64, error) {
+
+	var total int64
+	if err := s.repo.Model(ctx).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items, err := s.repo.FindAll(ctx, func(db *gorm.DB) {
+		db.Order("id asc").Offset((page - 1) * size).Limit(size)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// Create 创建管理员
+func (s *AdminService) Create(ctx context.Context, opts *AdminCreateOpts) (*model.Admin, error) {
+
+	if !isAdminRole(opts.Role) {
+		return nil, ErrAdminRole
+	}
+
+	if s.repo.IsUsernameExist(ctx, opts.Username) {
+		return nil, ErrAdminExist
+	}
+
+	admin := &model.Admin{
+		Username:    opts.Username,
+		Password:    encrypt.HashPassword(opts.Password),
+		Nickname:    opts.Nickname,
+		Role:        opts.Role,
+		LastLoginAt: time.Now(),
+	}
+
+	if err := s.repo.Create(ctx, admin); err != nil {
+		return nil, err
+	}
+
+	return admin, nil
+}
+
+// Update 修改管理员信息
+func (s *AdminService) Update(ctx context.Context, opts *AdminUpdateOpts) error {
+
+	if !isAdminRole(opts.Role) {
+		return ErrAdminRole
+	}
+
+	if _, err := s.repo.FindById(ctx, opts.Id); err != nil {
+		return ErrAdminNotFound
+	}
+
+	data := map[string]interface{}{
+		"nickname": opts.Nickname,
+		"role":     opts.Role,
+		"status":   opts.Status,
+	}
+
+	if opts.Password != "" {
+		data["password"] = encrypt.HashPassword(opts.Password)
+	}
+
+	_, err := s.repo.UpdateById(ctx, opts.Id, data)
+
+	return err
+}
+
+// Audit 记录管理后台操作日志
+func (s *AdminService) Audit(ctx context.Context, opts *AdminAuditOpts) error {
+
+	agent := opts.Agent
+	if len(agent) > 300 {
+		agent = agent[:300]
+	}
+
+	detail := "{}"
+	if opts.Detail != nil {
+		detail = jsonutil.Encode(opts.Detail)
+	}
+
+	return s.auditRepo.Create(ctx, &model.AdminAuditLog{
+		AdminId:    opts.AdminId,
+		Action:     opts.Action,
+		TargetType: opts.TargetType,
+		TargetId:   opts.TargetId,
+		Detail:     detail,
+		Ip:         opts.Ip,
+		Agent:      agent,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// AuditList 审计日志列表
+func (s *AdminService) AuditList(ctx context.Context, opts *AdminAuditListOpts) ([]*model.AdminAuditLog, int64, error) {
+
+	where := func(db *gorm.DB) *gorm.DB {
+		if opts.AdminId > 0 {
+			db = db.Where("admin_id = ?", opts.AdminId)
+		}
+
+		if opts.Action != "" {
+			db = db.Where("action = ?", opts.Action)
+		}
+
+		return db
+	}
+
+	var total int64
+	if err := where(s.auditRepo.Model(ctx)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items, err := s.auditRepo.FindAll(ctx, func(db *gorm.DB) {
+		where(db).Order("id desc").Offset((opts.Page - 1) * opts.Size).Limit(opts.Size)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// Stats 系统统计数据
+func (s *AdminService) Stats(ctx context.Context) (*AdminStats, error) {
+
+	today := timeutil.DayStartDateTime()
+
+	stats := &AdminStats{}
+
+	queries := []struct {
+		model interface{}
+		value *int64
+		where string
+		args  []interface{}
+	}{
+		{&model.Users{}, &stats.UserTotal, "is_robot = 0", nil},
+		{&model.Users{}, &stats.UserToday, "is_robot = 0 and created_at >= ?", []interface{}{today}},
+		{&model.Users{}, &stats.UserDisabled, "is_robot = 0 and status = ?", []interface{}{entity.UserStatusDisabled}},
+		{&model.Group{}, &stats.GroupTotal, "is_dismiss = 0", nil},
+		{&model.Group{}, &stats.GroupToday, "created_at >= ?", []interface{}{today}},
+		{&model.TalkRecords{}, &stats.MessageToday, "created_at >= ?", []interface{}{today}},
+		{&model.UserSession{}, &stats.ActiveSessionToday, "is_revoked = 0 and last_active_at >= ?", []interface{}{today}},
+		{&model.Emoticon{}, &stats.EmoticonTotal, "status != -1", nil},
+		{&model.Robot{}, &stats.RobotTotal, "status != -1", nil},
+		{&model.Robot{}, &stats.RobotDisabled, "status = 1", nil},
+	}
+
+	for _, query := range queries {
+		if err := s.db.WithContext(ctx).Model(query.model).Where(query.where, query.args...).Count(query.value).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+func isAdminRole(role string) bool {
+	switch role {
+	case entity.AdminRoleSuper, entity.AdminRoleOperator, entity.AdminRoleAuditor:
+		return true
+	}
+
+	return false
+}
@@ -2,22 +2,30 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/logger"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
 	"go-chat/internal/repository/repo/organize"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrGroupNotMember  = errors.New("非群组成员，无权操作！")
+	ErrGroupPermission = errors.New("暂无权限！")
 )
 
 type AuthPermissionService struct {
 	contactDao     *repo.Contact
 	groupMemberDao *repo.GroupMember
 	organizeDao    *organize.Organize
+	groupRoleDao   *repo.GroupRole
 }
 
-func NewAuthPermissionService(contactDao *repo.Contact, groupMemberDao *repo.GroupMember, organizeDao *organize.Organize) *AuthPermissionService {
-	return &AuthPermissionService{contactDao: contactDao, groupMemberDao: groupMemberDao, organizeDao: organizeDao}
+func NewAuthPermissionService(contactDao *repo.Contact, groupMemberDao *repo.GroupMember, organizeDao *organize.Organize, groupRoleDao *repo.GroupRole) *AuthPermissionService {
+	return &AuthPermissionService{contactDao: contactDao, groupMemberDao: groupMemberDao, organizeDao: organizeDao, groupRoleDao: groupRoleDao}
 }
 
 type AuthPermission struct {
@@ -51,3 +59,121 @@ func (a *AuthPermissionService) IsAuth(ctx context.Context, prem *AuthPermission
 
 	return false
 }
+
+// GroupPermissions 查询群成员拥有的权限，群主拥有全部权限，
+// 管理员及普通成员在默认权限的基础上叠加自定义角色的权限
+func (a *AuthPermissionService) GroupPermissions(ctx context.Context, groupId int, uid int) (*model.GroupMember, []string, error) {
+
+	member, err := a.groupMemberDao.FindByWhere(ctx, "group_id = ? and user_id = ? and is_quit = 0", groupId, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrGroupNotMember
+		}
+
+		return nil, nil, err
+	}
+
+	return member, a.memberPermissions(ctx, member), nil
+}
+
+// GroupAuthorizedUserIds 获取拥有指定权限的群成员ID
+func (a *AuthPermissionService) GroupAuthorizedUserIds(ctx context.Context, groupId int, perm string) []int {
+
+	// 普通成员的默认权限不需要逐个查询
+	if hasPermission(entity.GroupMemberPerms, perm) {
+		return a.groupMemberDao.GetMemberIds(ctx, groupId)
+	}
+
+	items, err := a.groupMemberDao.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("group_id = ? and is_quit = 0 and (leader > 0 or role_id > 0)", groupId)
+	})
+	if err != nil {
+		return []int{}
+	}
+
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		if hasPermission(a.memberPermissions(ctx, item), perm) {
+			ids = append(ids, item.UserId)
+		}
+	}
+
+	return ids
+}
+
+// memberPermissions 计算群成员拥有的权限
+func (a *AuthPermissionService) memberPermissions(ctx context.Context, member *model.GroupMember) []string {
+
+	var rolePerms []string
+	if member.RoleId > 0 && member.Leader != entity.GroupLeaderOwner {
+		role, err := a.groupRoleDao.FindById(ctx, member.RoleId)
+		if err == nil && role.GroupId == member.GroupId {
+			rolePerms = role.PermissionList()
+		}
+	}
+
+	return entity.GroupPermissions(member.Leader, rolePerms)
+}
+
+// GroupAuthorize 校验群成员是否拥有指定权限
+func (a *AuthPermissionService) GroupAuthorize(ctx context.Context, groupId int, uid int, perm string) error {
+	_, err := a.groupAuthorize(ctx, groupId, uid, perm)
+	return err
+}
+
+// GroupAuthorizeMember 校验对指定群成员的操作权限，操作者除拥有指定权限外，身份须高于被操作的成员
+func (a *AuthPermissionService) GroupAuthorizeMember(ctx context.Context, groupId int, uid int, perm string, memberIds ...int) error {
+
+	member, err := a.groupAuthorize(ctx, groupId, uid, perm)
+	if err != nil {
+		return err
+	}
+
+	if len(memberIds) == 0 {
+		return nil
+	}
+
+	items, err := a.groupMemberDao.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("group_id = ? and user_id in ? and is_quit = 0", groupId, memberIds)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if item.UserId == uid || groupMemberRank(item) >= groupMemberRank(member) {
+			return ErrGroupPermission
+		}
+	}
+
+	return nil
+}
+
+func (a *AuthPermissionService) groupAuthorize(ctx context.Context, groupId int, uid int, perm string) (*model.GroupMember, error) {
+
+	member, perms, err := a.GroupPermissions(ctx, groupId, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasPermission(perms, perm) {
+		return nil, ErrGroupPermission
+	}
+
+	return member, nil
+}
+
+// groupMemberRank 群成员身份等级，群主 > 管理员 > 普通成员，同等身份下拥有自定义角色的成员更高
+func groupMemberRank(member *model.GroupMember) int {
+
+	rank := member.Leader * 2
+	if member.RoleId > 0 {
+		rank++
+	}
+
+	return rank
+}
+
+func hasPermission(perms []string, perm string) bool {
+	return entity.HasGroupPerm(perms, perm)
+}
@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go-chat/api/pb/message/v1"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/sliceutil"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrChannelNotFound      = errors.New("频道不存在或已解散")
+	ErrChannelPermission    = errors.New("暂无权限操作频道")
+	ErrChannelNotSubscriber = errors.New("请先订阅频道")
+	ErrChannelOwnerLeave    = errors.New("频道创建者不能退订频道")
+	ErrChannelEmoji         = errors.New("不支持的互动表情")
+)
+
+type ChannelCreateOpts struct {
+	UserId  int
+	Name    string
+	Avatar  string
+	Profile string
+	IsOvert bool
+}
+
+type ChannelUpdateOpts struct {
+	ChannelId int
+	UserId    int
+	Name      string
+	Avatar    string
+	Profile   string
+	IsOvert   bool
+}
+
+type ChannelPostOpts struct {
+	ChannelId int
+	UserId    int
+	Content   string
+}
+
+type ChannelPostsOpts struct {
+	ChannelId int
+	UserId    int
+	RecordId  int // 上次查询的最小消息ID
+	Limit     int
+}
+
+type ChannelReactOpts struct {
+	RecordId int
+	UserId   int
+	Emoji    string
+	Cancel   bool // 取消互动
+}
+
+// ChannelPostItem 频道消息
+type ChannelPostItem struct {
+	*TalkRecordsItem
+	Views       int64          `json:"views"`        // 浏览量
+	Reactions   map[string]int `json:"reactions"`    // 互动表情统计
+	MyReactions []string       `json:"my_reactions"` // 当前用户的互动表情
+}
+
+type ChannelService struct {
+	*BaseService
+	repo         *repo.Channel
+	memberRepo   *repo.ChannelMember
+	reactionRepo *repo.ChannelReaction
+	viewRepo     *repo.ChannelView
+	viewStorage  *cache.ChannelViewStorage
+	records      *TalkRecordsService
+	session      *TalkSessionService
+	message      *MessageService
+}
+
+func NewChannelService(baseService *BaseService, repo *repo.Channel, memberRepo *repo.ChannelMember, reactionRepo *repo.ChannelReaction, viewRepo *repo.ChannelView, viewStorage *cache.ChannelViewStorage, records *TalkRecordsService, session *TalkSessionService, message *MessageService) *ChannelService {
+	return &ChannelService{BaseService: baseService, repo: repo, memberRepo: memberRepo, reactionRepo: reactionRepo, viewRepo: viewRepo, viewStorage: viewStorage, records: records, session: session, message: message}
+}
+
+func (s *ChannelService) Dao() *repo.Channel {
+	return s.repo
+}
+
+func (s *ChannelService) MemberDao() *repo.ChannelMember {
+	return s.memberRepo
+}
+
+// Find 获取未解散的频道
+func (s *ChannelService) Find(ctx context.Context, channelId int) (*model.Channel, error) {
+
+	channel, err := s.repo.FindById(ctx, channelId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChannelNotFound
+		}
+
+		return nil, err
+	}
+
+	if channel.IsDismiss == 1 {
+		return nil, ErrChannelNotFound
+	}
+
+	return channel, nil
+}
+
+// Create 创建频道，创建者自动订阅
+func (s *ChannelService) Create(ctx context.Context, opts *ChannelCreateOpts) (*model.Channel, error) {
+
+	channel := &model.Channel{
+		CreatorId:     opts.UserId,
+		Name:          opts.Name,
+		Avatar:        opts.Avatar,
+		Profile:       opts.Profile,
+		IsOvert:       strutil.BoolToInt(opts.IsOvert),
+		SubscriberNum: 1,
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(channel).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&model.ChannelMember{
+			ChannelId: channel.Id,
+			UserId:    opts.UserId,
+			Role:      entity.ChannelRoleOwner,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishJoin(ctx, channel.Id, 1, []int{opts.UserId})
+
+	return channel, nil
+}
+
+// Update 修改频道信息，创建者及管理员可操作
+func (s *ChannelService) Update(ctx context.Context, opts *ChannelUpdateOpts) error {
+
+	if _, err := s.Find(ctx, opts.ChannelId); err != nil {
+		return err
+	}
+
+	if !s.memberRepo.IsAdmin(ctx, opts.ChannelId, opts.UserId) {
+		return ErrChannelPermission
+	}
+
+	_, err := s.repo.UpdateById(ctx, opts.ChannelId, map[string]interface{}{
+		"name":     opts.Name,
+		"avatar":   opts.Avatar,
+		"profile":  opts.Profile,
+		"is_overt": strutil.BoolToInt(opts.IsOvert),
+	})
+
+	return err
+}
+
+// Dismiss 解散频道，仅创建者可操作
+func (s *ChannelService) Dismiss(ctx context.Context, channelId int, uid int) error {
+
+	channel, err := s.Find(ctx, channelId)
+	if err != nil {
+		return err
+	}
+
+	if channel.CreatorId != uid {
+		return ErrChannelPermission
+	}
+
+	if _, err := s.repo.UpdateById(ctx, channelId, map[string]interface{}{"is_dismiss": 1}); err != nil {
+		return err
+	}
+
+	var uids []int
+	_ = s.memberRepo.Model(ctx).Where("channel_id = ?", channelId).Pluck("user_id", &uids)
+
+	s.publishJoin(ctx, channelId, 2, uids)
+
+	return nil
+}
+
+// Subscribe 订阅频道
+func (s *ChannelService) Subscribe(ctx context.Context, channelId int, uid int) error {
+
+	if _, err := s.Find(ctx, channelId); err != nil {
+		return err
+	}
+
+	if s.memberRepo.IsSubscriber(ctx, channelId, uid) {
+		return nil
+	}
+
+	created := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		// 并发订阅时唯一索引冲突视为已订阅
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.ChannelMember{
+			ChannelId: channelId,
+			UserId:    uid,
+			Role:      entity.ChannelRoleSubscriber,
+		})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		created = true
+
+		return s.repo.IncrSubscriber(tx, channelId, 1)
+	})
+	if err != nil || !created {
+		return err
+	}
+
+	s.publishJoin(ctx, channelId, 1, []int{uid})
+
+	return nil
+}
+
+// Unsubscribe 退订频道，同时删除频道会话
+func (s *ChannelService) Unsubscribe(ctx context.Context, channelId int, uid int) error {
+
+	member, err := s.memberRepo.FindMember(ctx, channelId, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrChannelNotSubscriber
+		}
+
+		return err
+	}
+
+	if member.Role == entity.ChannelRoleOwner {
+		return ErrChannelOwnerLeave
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		res := tx.Delete(member)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		return s.repo.IncrSubscriber(tx, channelId, -1)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.db.WithContext(ctx).Model(&model.TalkSession{}).
+		Where("user_id = ? and talk_type = ? and receiver_id = ?", uid, entity.ChatChannelMode, channelId).
+		Update("is_delete", 1)
+
+	s.publishJoin(ctx, channelId, 2, []int{uid})
+
+	return nil
+}
+
+// SetAdmin 设置或取消频道管理员，仅创建者可操作
+func (s *ChannelService) SetAdmin(ctx context.Context, channelId int, uid int, memberId int, isAdmin bool) error {
+
+	channel, err := s.Find(ctx, channelId)
+	if err != nil {
+		return err
+	}
+
+	if channel.CreatorId != uid || memberId == uid {
+		return ErrChannelPermission
+	}
+
+	role := entity.ChannelRoleSubscriber
+	if isAdmin {
+		role = entity.ChannelRoleAdmin
+	}
+
+	res := s.memberRepo.Model(ctx).Where("channel_id = ? and user_id = ? and role <> ?", channelId, memberId, entity.ChannelRoleOwner).Update("role", role)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 && !s.memberRepo.IsSubscriber(ctx, channelId, memberId) {
+		return ErrChannelNotSubscriber
+	}
+
+	return nil
+}
+
+// Open 打开频道，订阅者首次打开时才创建会话
+func (s *ChannelService) Open(ctx context.Context, channelId int, uid int) (*model.TalkSession, error) {
+
+	if _, err := s.Find(ctx, channelId); err != nil {
+		return nil, err
+	}
+
+	if !s.memberRepo.IsSubscriber(ctx, channelId, uid) {
+		return nil, ErrChannelNotSubscriber
+	}
+
+	return s.session.Create(ctx, &TalkSessionCreateOpt{
+		UserId:     uid,
+		TalkType:   entity.ChatChannelMode,
+		ReceiverId: channelId,
+	})
+}
+
+// Post 发布频道消息，仅创建者及管理员可发布
+func (s *ChannelService) Post(ctx context.Context, opts *ChannelPostOpts) (*model.TalkRecords, error) {
+
+	if _, err := s.Find(ctx, opts.ChannelId); err != nil {
+		return nil, err
+	}
+
+	if !s.memberRepo.IsAdmin(ctx, opts.ChannelId, opts.UserId) {
+		return nil, ErrChannelPermission
+	}
+
+	return s.message.sendText(ctx, opts.UserId, &message.TextMessageRequest{
+		Content: opts.Content,
+		Receiver: &message.MessageReceiver{
+			TalkType:   entity.ChatChannelMode,
+			ReceiverId: int32(opts.ChannelId),
+		},
+	})
+}
+
+// Posts 频道消息列表，公开频道未订阅时也可浏览，浏览时记录浏览量
+func (s *ChannelService) Posts(ctx context.Context, opts *ChannelPostsOpts) ([]*ChannelPostItem, error) {
+
+	channel, err := s.Find(ctx, opts.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+
+	if channel.IsOvert == 0 && !s.memberRepo.IsSubscriber(ctx, opts.ChannelId, opts.UserId) {
+		return nil, ErrChannelNotSubscriber
+	}
+
+	records, err := s.records.GetTalkRecords(ctx, &QueryTalkRecordsOpt{
+		TalkType:   entity.ChatChannelMode,
+		UserId:     opts.UserId,
+		ReceiverId: opts.ChannelId,
+		RecordId:   opts.RecordId,
+		Limit:      opts.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*ChannelPostItem, 0, len(records))
+	if len(records) == 0 {
+		return items, nil
+	}
+
+	ids := make([]int, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.Id)
+	}
+
+	// 浏览量持久化保存，缓存仅用于同一用户的浏览去重
+	if viewIds, err := s.viewStorage.Add(ctx, opts.UserId, ids); err == nil {
+		if err := s.viewRepo.Incr(ctx, opts.ChannelId, viewIds); err != nil {
+			logger.Error("[Channel]记录浏览量失败 err: ", err.Error())
+		}
+	}
+
+	views := s.viewRepo.Counts(ctx, ids)
+
+	reactions := make(map[int]map[string]int)
+	if counts, err := s.reactionRepo.Counts(ctx, ids); err == nil {
+		for _, count := range counts {
+			if _, ok := reactions[count.RecordId]; !ok {
+				reactions[count.RecordId] = make(map[string]int)
+			}
+
+			reactions[count.RecordId][count.Emoji] = count.Count
+		}
+	}
+
+	mine := make(map[int][]string)
+	if list, err := s.reactionRepo.UserReactions(ctx, opts.UserId, ids); err == nil {
+		for _, item := range list {
+			mine[item.RecordId] = append(mine[item.RecordId], item.Emoji)
+		}
+	}
+
+	for _, record := range records {
+		item := &ChannelPostItem{
+			TalkRecordsItem: record,
+			Views:           views[record.Id],
+			Reactions:       reactions[record.Id],
+			MyReactions:     mine[record.Id],
+		}
+
+		if item.Reactions == nil {
+			item.Reactions = make(map[string]int)
+		}
+
+		if item.MyReactions == nil {
+			item.MyReactions = make([]string, 0)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// React 订阅者对频道消息添加或取消互动表情
+func (s *ChannelService) React(ctx context.Context, opts *ChannelReactOpts) error {
+
+	if !sliceutil.Include(opts.Emoji, entity.ChannelReactionEmojis) {
+		return ErrChannelEmoji
+	}
+
+	record := &model.TalkRecords{}
+	if err := s.db.WithContext(ctx).First(record, opts.RecordId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrChannelNotFound
+		}
+
+		return err
+	}
+
+	if record.TalkType != entity.ChatChannelMode || record.IsRevoke == 1 {
+		return ErrChannelNotFound
+	}
+
+	if !s.memberRepo.IsSubscriber(ctx, record.ReceiverId, opts.UserId) {
+		return ErrChannelNotSubscriber
+	}
+
+	if opts.Cancel {
+		return s.reactionRepo.Model(ctx).Where("record_id = ? and user_id = ? and emoji = ?", record.Id, opts.UserId, opts.Emoji).Delete(&model.ChannelReaction{}).Error
+	}
+
+	exist, err := s.reactionRepo.QueryExist(ctx, "record_id = ? and user_id = ? and emoji = ?", record.Id, opts.UserId, opts.Emoji)
+	if err != nil || exist {
+		return err
+	}
+
+	return s.reactionRepo.Create(ctx, &model.ChannelReaction{
+		ChannelId: record.ReceiverId,
+		RecordId:  record.Id,
+		UserId:    opts.UserId,
+		Emoji:     opts.Emoji,
+	})
+}
+
+// publishJoin 通知网关更新订阅者的频道房间，mode 1:加入 2:退出
+func (s *ChannelService) publishJoin(ctx context.Context, channelId int, mode int, uids []int) {
+	s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(map[string]interface{}{
+		"event": entity.EventChannelJoin,
+		"data": jsonutil.Encode(map[string]interface{}{
+			"channel_id": channelId,
+			"type":       mode,
+			"uids":       uids,
+		}),
+	}))
+}
@@ -3,17 +3,21 @@ package service
 import (
 	"context"
 
+	"go-chat/internal/entity"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
 )
 
 type ContactService struct {
 	*BaseService
-	repo *repo.Contact
+	repo    *repo.Contact
+	privacy *repo.UserPrivacy
+	block   *repo.UserBlock
 }
 
-func NewContactService(baseService *BaseService, dao *repo.Contact) *ContactService {
-	return &ContactService{BaseService: baseService, repo: dao}
+func NewContactService(baseService *BaseService, dao *repo.Contact, privacy *repo.UserPrivacy, block *repo.UserBlock) *ContactService {
+	return &ContactService{BaseService: baseService, repo: dao, privacy: privacy, block: block}
 }
 
 func (s *ContactService) Dao() *repo.Contact {
@@ -33,16 +37,44 @@ func (s *ContactService) EditRemark(ctx context.Context, uid int, friendId int,
 	return err
 }
 
-// Delete 删除联系人
+// Delete 删除联系人，同时将联系人移出所在分组
 // @params uid      用户ID
 // @params friendId 联系人ID
 func (s *ContactService) Delete(ctx context.Context, uid, friendId int) error {
-	return s.repo.Model(ctx).Where("user_id = ? and friend_id = ?", uid, friendId).Update("status", 0).Error
+
+	contact, err := s.repo.FindByWhere(ctx, "user_id = ? and friend_id = ?", uid, friendId)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Txx(ctx, func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Contact{}).Where("id = ?", contact.Id).Updates(map[string]interface{}{
+			"status":   0,
+			"group_id": 0,
+		}).Error; err != nil {
+			return err
+		}
+
+		return updateContactGroupNum(tx, uid, []int{contact.GroupId})
+	})
 }
 
 // List 获取联系人列表
 // @params uid      用户ID
 func (s *ContactService) List(ctx context.Context, uid int) ([]*model.ContactListItem, error) {
+	return s.list(ctx, uid, func(tx *gorm.DB) {})
+}
+
+// ListByGroup 获取指定分组的联系人列表，groupId 为 0 时获取未分组的联系人
+// @params uid      用户ID
+// @params groupId  联系人分组ID
+func (s *ContactService) ListByGroup(ctx context.Context, uid int, groupId int) ([]*model.ContactListItem, error) {
+	return s.list(ctx, uid, func(tx *gorm.DB) {
+		tx.Where("contact.group_id = ?", groupId)
+	})
+}
+
+func (s *ContactService) list(ctx context.Context, uid int, where func(tx *gorm.DB)) ([]*model.ContactListItem, error) {
 
 	tx := s.repo.Model(ctx)
 
@@ -58,6 +90,8 @@ func (s *ContactService) List(ctx context.Context, uid int) ([]*model.ContactLis
 	tx.Joins("inner join `users` ON `users`.id = contact.friend_id")
 	tx.Where("contact.user_id = ? and contact.status = ?", uid, 1)
 
+	where(tx)
+
 	items := make([]*model.ContactListItem, 0)
 	if err := tx.Scan(&items).Error; err != nil {
 		return nil, err
@@ -66,6 +100,43 @@ func (s *ContactService) List(ctx context.Context, uid int) ([]*model.ContactLis
 	return items, nil
 }
 
+// GetGroupContactIds 获取联系人分组下的好友ID，排除非双向好友及双方任一拉黑的用户
+// @params uid      用户ID
+// @params groupIds 联系人分组ID
+func (s *ContactService) GetGroupContactIds(ctx context.Context, uid int, groupIds []int) []int {
+
+	ids := make([]int, 0)
+	if len(groupIds) == 0 {
+		return ids
+	}
+
+	friends := s.db.Table("contact").Select("user_id").Where("friend_id = ? and status = ?", uid, 1)
+	s.repo.Model(ctx).Where("user_id = ? and group_id in ? and status = ?", uid, groupIds, 1).
+		Where("friend_id in (?)", friends).Pluck("friend_id", &ids)
+
+	if len(ids) == 0 {
+		return ids
+	}
+
+	blocks := make(map[int]struct{})
+	for _, id := range s.block.BlockIds(ctx, uid) {
+		blocks[id] = struct{}{}
+	}
+
+	for _, id := range s.block.BlockerIds(ctx, uid, ids) {
+		blocks[id] = struct{}{}
+	}
+
+	items := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := blocks[id]; !ok {
+			items = append(items, id)
+		}
+	}
+
+	return items
+}
+
 func (s *ContactService) GetContactIds(ctx context.Context, uid int) []int64 {
 
 	var ids []int64
@@ -73,3 +144,67 @@ func (s *ContactService) GetContactIds(ctx context.Context, uid int) []int64 {
 
 	return ids
 }
+
+// GetOnlineNotifyIds 获取需要推送 uid 上下线状态的联系人ID
+// 用户隐藏在线状态时不推送，被用户拉黑的联系人不推送
+func (s *ContactService) GetOnlineNotifyIds(ctx context.Context, uid int) []int64 {
+
+	if s.privacy.FindByUserId(ctx, uid).OnlineStatus == entity.PrivacyOnlineNobody {
+		return []int64{}
+	}
+
+	blocks := make(map[int64]struct{})
+	for _, id := range s.block.BlockIds(ctx, uid) {
+		blocks[int64(id)] = struct{}{}
+	}
+
+	ids := make([]int64, 0)
+	for _, id := range s.GetContactIds(ctx, uid) {
+		if _, ok := blocks[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// OnlineInvisibleIds 获取 uids 中对 viewerId 隐藏在线状态的用户
+func (s *ContactService) OnlineInvisibleIds(ctx context.Context, viewerId int, uids []int) map[int]bool {
+
+	items := make(map[int]bool)
+	if len(uids) == 0 {
+		return items
+	}
+
+	for uid, privacy := range s.privacy.FindByUserIds(ctx, uids) {
+		if privacy.OnlineStatus == entity.PrivacyOnlineNobody {
+			items[uid] = true
+		}
+	}
+
+	for _, uid := range s.block.BlockerIds(ctx, viewerId, uids) {
+		items[uid] = true
+	}
+
+	return items
+}
+
+// SearchByMobile 通过手机号搜索用户
+// 对方关闭手机号搜索或已将 uid 拉黑时按用户不存在处理
+func (s *ContactService) SearchByMobile(ctx context.Context, uid int, mobile string) (*model.Users, error) {
+
+	user := &model.Users{}
+	if err := s.db.WithContext(ctx).Where("mobile = ?", mobile).First(user).Error; err != nil {
+		return nil, err
+	}
+
+	if user.Id == uid {
+		return user, nil
+	}
+
+	if s.privacy.FindByUserId(ctx, user.Id).MobileSearch == entity.PrivacyMobileSearchDeny || s.block.IsBlocked(ctx, user.Id, uid) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return user, nil
+}
@@ -4,14 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/repository/repo/organize"
 	"gorm.io/gorm"
 
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/jsonutil"
 )
 
+var (
+	ErrContactApplyRefused  = errors.New("对方已设置不允许添加为联系人")
+	ErrContactApplyNotFound = errors.New("好友申请不存在或已处理")
+)
+
 type ContactApplyCreateOpts struct {
 	UserId   int
 	Remarks  string
@@ -32,35 +40,66 @@ type ContactApplyDeclineOpts struct {
 
 type ContactApplyService struct {
 	*BaseService
+	organize *organize.Organize
+	privacy  *repo.UserPrivacy
+	block    *repo.UserBlock
 }
 
-func NewContactApplyService(base *BaseService) *ContactApplyService {
-	return &ContactApplyService{BaseService: base}
+func NewContactApplyService(base *BaseService, organize *organize.Organize, privacy *repo.UserPrivacy, block *repo.UserBlock) *ContactApplyService {
+	return &ContactApplyService{BaseService: base, organize: organize, privacy: privacy, block: block}
 }
 
-func (s *ContactApplyService) Create(ctx context.Context, opts *ContactApplyCreateOpts) error {
+// Create 发送好友申请，已存在待处理的申请时直接返回该申请，不重复通知
+func (s *ContactApplyService) Create(ctx context.Context, opts *ContactApplyCreateOpts) (*model.ContactApply, error) {
+
+	if err := s.allowApply(ctx, opts.UserId, opts.FriendId); err != nil {
+		return nil, err
+	}
+
+	apply := &model.ContactApply{}
+	err := s.db.WithContext(ctx).Where("user_id = ? and friend_id = ? and status = ?", opts.UserId, opts.FriendId, entity.ContactApplyStatusPending).First(apply).Error
+	if err == nil {
+		return apply, nil
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
 
-	apply := &model.ContactApply{
+	apply = &model.ContactApply{
 		UserId:   opts.UserId,
 		FriendId: opts.FriendId,
 		Remark:   opts.Remarks,
+		Status:   entity.ContactApplyStatusPending,
 	}
 
-	if err := s.db.Create(apply).Error; err != nil {
-		return err
+	if err := s.db.WithContext(ctx).Create(apply).Error; err != nil {
+		return nil, err
 	}
 
-	body := map[string]interface{}{
-		"event": entity.EventContactApply,
-		"data": jsonutil.Encode(map[string]interface{}{
-			"apply_id": int64(apply.Id),
-			"type":     1,
-		}),
-	}
+	s.publish(ctx, apply.Id, entity.ContactApplyEventCreate)
 
-	s.rds.Incr(ctx, fmt.Sprintf("friend-apply:user_%d", opts.FriendId))
+	return apply, nil
+}
 
-	s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(body))
+// allowApply 根据对方的黑名单及隐私设置判断是否允许发送好友申请
+func (s *ContactApplyService) allowApply(ctx context.Context, uid int, friendId int) error {
+
+	// 被拉黑时与不允许添加的提示保持一致，避免暴露黑名单
+	if s.block.IsBlocked(ctx, friendId, uid) {
+		return ErrContactApplyRefused
+	}
+
+	switch s.privacy.FindByUserId(ctx, friendId).AddContact {
+	case entity.PrivacyAddNobody:
+		return ErrContactApplyRefused
+	case entity.PrivacyAddOrganize:
+		if ok, err := s.organize.IsQiyeMember(ctx, uid, friendId); err != nil {
+			return err
+		} else if !ok {
+			return ErrContactApplyRefused
+		}
+	}
 
 	return nil
 }
@@ -72,11 +111,27 @@ func (s *ContactApplyService) Accept(ctx context.Context, opts *ContactApplyAcce
 		applyInfo *model.ContactApply
 	)
 
-	if err := s.db.First(&applyInfo, "id = ? and friend_id = ?", opts.ApplyId, opts.UserId).Error; err != nil {
+	if err := s.db.First(&applyInfo, "id = ? and friend_id = ? and status = ?", opts.ApplyId, opts.UserId, entity.ContactApplyStatusPending).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContactApplyNotFound
+		}
+
 		return nil, err
 	}
 
 	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.ContactApply{}).Where("id = ? and status = ?", applyInfo.Id, entity.ContactApplyStatusPending).Updates(map[string]interface{}{
+			"status":     entity.ContactApplyStatusAccepted,
+			"updated_at": time.Now(),
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return ErrContactApplyNotFound
+		}
+
 		addFriendFunc := func(uid, fid int, remark string) error {
 			var friends *model.Contact
 
@@ -115,32 +170,54 @@ func (s *ContactApplyService) Accept(ctx context.Context, opts *ContactApplyAcce
 			return err
 		}
 
-		return tx.Delete(&model.ContactApply{}, "user_id = ? and friend_id = ?", applyInfo.UserId, applyInfo.FriendId).Error
+		// 对方发给自己的待处理申请一并视为已同意
+		return tx.Model(&model.ContactApply{}).Where("user_id = ? and friend_id = ? and status = ?", applyInfo.FriendId, applyInfo.UserId, entity.ContactApplyStatusPending).Updates(map[string]interface{}{
+			"status":     entity.ContactApplyStatusAccepted,
+			"updated_at": time.Now(),
+		}).Error
 	})
 
-	return applyInfo, err
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, applyInfo.Id, entity.ContactApplyEventAccept)
+
+	return applyInfo, nil
 }
 
 // Decline 拒绝好友申请
 func (s *ContactApplyService) Decline(ctx context.Context, opts *ContactApplyDeclineOpts) error {
-	err := s.db.Delete(&model.ContactApply{}, "id = ? and friend_id = ?", opts.ApplyId, opts.UserId).Error
 
-	if err == nil {
-		body := map[string]interface{}{
-			"event": entity.EventContactApply,
-			"data": jsonutil.Encode(map[string]interface{}{
-				"apply_id": int64(opts.ApplyId),
-				"type":     2,
-			}),
-		}
+	res := s.db.WithContext(ctx).Model(&model.ContactApply{}).Where("id = ? and friend_id = ? and status = ?", opts.ApplyId, opts.UserId, entity.ContactApplyStatusPending).Updates(map[string]interface{}{
+		"status":     entity.ContactApplyStatusDeclined,
+		"reason":     opts.Remarks,
+		"updated_at": time.Now(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
 
-		s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(body))
+	if res.RowsAffected == 0 {
+		return ErrContactApplyNotFound
 	}
 
-	return err
+	s.publish(ctx, opts.ApplyId, entity.ContactApplyEventDecline)
+
+	return nil
+}
+
+// ClearExpired 将超过有效期的待处理好友申请标记为已过期
+func (s *ContactApplyService) ClearExpired(ctx context.Context) error {
+	return s.db.WithContext(ctx).Model(&model.ContactApply{}).
+		Where("status = ? and created_at < ?", entity.ContactApplyStatusPending, time.Now().AddDate(0, 0, -entity.ContactApplyExpireDays)).
+		Updates(map[string]interface{}{
+			"status":     entity.ContactApplyStatusExpired,
+			"updated_at": time.Now(),
+		}).Error
 }
 
-// List 联系人申请列表
+// List 待处理的联系人申请列表
 func (s *ContactApplyService) List(ctx context.Context, uid, page, size int) ([]*model.ApplyItem, error) {
 	fields := []string{
 		"contact_apply.id",
@@ -155,7 +232,7 @@ func (s *ContactApplyService) List(ctx context.Context, uid, page, size int) ([]
 
 	tx := s.db.Table("contact_apply")
 	tx.Joins("left join `users` ON `users`.id = contact_apply.user_id")
-	tx.Where("contact_apply.friend_id = ?", uid)
+	tx.Where("contact_apply.friend_id = ? and contact_apply.status = ?", uid, entity.ContactApplyStatusPending)
 	tx.Order("contact_apply.id desc")
 
 	items := make([]*model.ApplyItem, 0)
@@ -166,16 +243,38 @@ func (s *ContactApplyService) List(ctx context.Context, uid, page, size int) ([]
 	return items, nil
 }
 
+// GetApplyUnreadNum 上次查看申请列表后收到的待处理申请数
 func (s *ContactApplyService) GetApplyUnreadNum(ctx context.Context, uid int) int {
 
-	num, err := s.rds.Get(ctx, fmt.Sprintf("friend-apply:user_%d", uid)).Int()
-	if err != nil {
+	tx := s.db.WithContext(ctx).Model(&model.ContactApply{}).Where("friend_id = ? and status = ?", uid, entity.ContactApplyStatusPending)
+
+	if unix, err := s.rds.Get(ctx, s.readKey(uid)).Int64(); err == nil {
+		tx.Where("updated_at > ?", time.Unix(unix, 0))
+	}
+
+	var num int64
+	if err := tx.Count(&num).Error; err != nil {
 		return 0
 	}
 
-	return num
+	return int(num)
 }
 
+// ClearApplyUnreadNum 记录查看申请列表的时间
 func (s *ContactApplyService) ClearApplyUnreadNum(ctx context.Context, uid int) {
-	s.rds.Del(ctx, fmt.Sprintf("friend-apply:user_%d", uid))
+	s.rds.Set(ctx, s.readKey(uid), time.Now().Unix(), time.Duration(entity.ContactApplyExpireDays)*24*time.Hour)
+}
+
+func (s *ContactApplyService) readKey(uid int) string {
+	return fmt.Sprintf("contact-apply:read:%d", uid)
+}
+
+func (s *ContactApplyService) publish(ctx context.Context, applyId int, event int) {
+	s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(map[string]interface{}{
+		"event": entity.EventContactApply,
+		"data": jsonutil.Encode(map[string]interface{}{
+			"apply_id": int64(applyId),
+			"type":     event,
+		}),
+	}))
 }
@@ -4,11 +4,17 @@ import (
 	"context"
 	"errors"
 
+	"go-chat/internal/pkg/sliceutil"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrContactGroupNotFound  = errors.New("联系人分组不存在")
+	ErrContactGroupNotFriend = errors.New("部分用户不是你的好友")
+)
+
 type ContactGroupService struct {
 	*BaseService
 	repo *repo.ContactGroup
@@ -42,6 +48,50 @@ func (c *ContactGroupService) Delete(ctx context.Context, id int, uid int) error
 	})
 }
 
+// Assign 将联系人移动到指定分组，groupId 为 0 时移出分组
+func (c *ContactGroupService) Assign(ctx context.Context, uid int, groupId int, friendIds []int) error {
+
+	friendIds = sliceutil.Unique(friendIds)
+
+	if groupId > 0 {
+		exist, err := c.repo.QueryExist(ctx, "id = ? and user_id = ?", groupId, uid)
+		if err != nil {
+			return err
+		}
+
+		if !exist {
+			return ErrContactGroupNotFound
+		}
+	}
+
+	return c.repo.Txx(ctx, func(tx *gorm.DB) error {
+
+		var groupIds []int
+		err := tx.Model(&model.Contact{}).Where("user_id = ? and friend_id in ? and status = 1", uid, friendIds).
+			Distinct().Pluck("group_id", &groupIds).Error
+		if err != nil {
+			return err
+		}
+
+		var num int64
+		if err := tx.Model(&model.Contact{}).Where("user_id = ? and friend_id in ? and status = 1", uid, friendIds).Count(&num).Error; err != nil {
+			return err
+		}
+
+		if int(num) != len(friendIds) {
+			return ErrContactGroupNotFriend
+		}
+
+		err = tx.Model(&model.Contact{}).Where("user_id = ? and friend_id in ? and status = 1", uid, friendIds).
+			UpdateColumn("group_id", groupId).Error
+		if err != nil {
+			return err
+		}
+
+		return updateContactGroupNum(tx, uid, append(groupIds, groupId))
+	})
+}
+
 func (c *ContactGroupService) Sort(ctx context.Context, uid int, values []*model.ContactGroup) error {
 	return c.repo.Txx(ctx, func(tx *gorm.DB) error {
 		for _, value := range values {
@@ -68,3 +118,20 @@ func (c *ContactGroupService) GetUserGroup(ctx context.Context, uid int) ([]*mod
 
 	return items, nil
 }
+
+// updateContactGroupNum 重新统计联系人分组的成员数，需在事务中调用
+func updateContactGroupNum(tx *gorm.DB, uid int, groupIds []int) error {
+
+	ids := make([]int, 0, len(groupIds))
+	for _, id := range sliceutil.Unique(groupIds) {
+		if id > 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return tx.Exec("UPDATE `contact_group` SET `num` = (SELECT COUNT(*) FROM `contact` WHERE `contact`.`user_id` = ? AND `contact`.`group_id` = `contact_group`.`id` AND `contact`.`status` = 1) WHERE `user_id` = ? AND `id` IN ?", uid, uid, ids).Error
+}
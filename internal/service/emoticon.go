@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -9,16 +10,18 @@ import (
 	"go-chat/internal/pkg/sliceutil"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
 )
 
 type EmoticonService struct {
 	*BaseService
 	repo       *repo.Emoticon
 	fileSystem *filesystem.Filesystem
+	blobRepo   *repo.FileBlob
 }
 
-func NewEmoticonService(baseService *BaseService, repo *repo.Emoticon, fileSystem *filesystem.Filesystem) *EmoticonService {
-	return &EmoticonService{BaseService: baseService, repo: repo, fileSystem: fileSystem}
+func NewEmoticonService(baseService *BaseService, repo *repo.Emoticon, fileSystem *filesystem.Filesystem, blobRepo *repo.FileBlob) *EmoticonService {
+	return &EmoticonService{BaseService: baseService, repo: repo, fileSystem: fileSystem, blobRepo: blobRepo}
 }
 
 func (s *EmoticonService) Dao() *repo.Emoticon {
@@ -55,7 +58,39 @@ func (s *EmoticonService) AddUserSysEmoticon(uid int, emoticonId int) error {
 	return s.db.Model(&model.UsersEmoticon{}).Where("user_id = ?", uid).Update("emoticon_ids", sliceutil.ToIds(ids)).Error
 }
 
+// CreateCollect 添加自定义表情包
+func (s *EmoticonService) CreateCollect(ctx context.Context, item *model.EmoticonItem) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+
+		return s.blobRepo.Retain(tx, item.BlobId)
+	})
+}
+
 // DeleteCollect 删除自定义表情包
 func (s *EmoticonService) DeleteCollect(uid int, ids []int) error {
-	return s.db.Delete(&model.EmoticonItem{}, "id in ? and emoticon_id = 0 and user_id = ?", ids, uid).Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		items := make([]*model.EmoticonItem, 0)
+		if err := tx.Model(&model.EmoticonItem{}).Select("id", "blob_id").Where("id in ? and emoticon_id = 0 and user_id = ?", ids, uid).Scan(&items).Error; err != nil {
+			return err
+		}
+
+		if len(items) == 0 {
+			return nil
+		}
+
+		itemIds, blobIds := make([]int, 0, len(items)), make([]int, 0, len(items))
+		for _, item := range items {
+			itemIds = append(itemIds, item.Id)
+			blobIds = append(blobIds, item.BlobId)
+		}
+
+		if err := tx.Delete(&model.EmoticonItem{}, "id in ?", itemIds).Error; err != nil {
+			return err
+		}
+
+		return s.blobRepo.Release(tx, blobIds...)
+	})
 }
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/pkg/filesystem"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
+)
+
+type FileBlobService struct {
+	*BaseService
+	repo       *repo.FileBlob
+	fileSystem *filesystem.Filesystem
+}
+
+func NewFileBlobService(baseService *BaseService, repo *repo.FileBlob, fileSystem *filesystem.Filesystem) *FileBlobService {
+	return &FileBlobService{BaseService: baseService, repo: repo, fileSystem: fileSystem}
+}
+
+func (s *FileBlobService) Dao() *repo.FileBlob {
+	return s.repo
+}
+
+// Store 按文件内容存储，相同内容的文件只保留一份
+// 注:返回的文件未增加引用次数，调用方需在创建引用记录的事务中调用 Dao().Retain()
+func (s *FileBlobService) Store(ctx context.Context, stream []byte, ext string, isPublic bool) (*model.FileBlob, error) {
+	return s.store(ctx, encrypt.Sha256(stream), int64(len(stream)), ext, isPublic, func(filePath string) error {
+		return s.fileSystem.Default.Write(stream, filePath)
+	})
+}
+
+// StoreUpload 存储分片上传合并后的文件
+func (s *FileBlobService) StoreUpload(ctx context.Context, file *model.SplitUpload, isPublic bool) (*model.FileBlob, error) {
+
+	hash := file.Hash
+
+	adapter, err := s.fileSystem.Drive(entity.FileDriveName(file.Drive))
+	if err != nil {
+		return nil, err
+	}
+
+	// 兼容未记录 hash 的历史上传文件
+	if hash == "" {
+		stream, err := adapter.ReadStream(file.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		hash = encrypt.Sha256(stream)
+	}
+
+	return s.store(ctx, hash, file.FileSize, file.FileExt, isPublic, func(filePath string) error {
+		if file.Drive == entity.FileDriveMode(s.fileSystem.Driver()) {
+			return s.fileSystem.Default.Copy(file.Path, filePath)
+		}
+
+		// 上传文件与默认驱动不一致时跨驱动复制
+		stream, err := adapter.ReadStream(file.Path)
+		if err != nil {
+			return err
+		}
+
+		return s.fileSystem.Default.Write(stream, filePath)
+	})
+}
+
+// PublicUrl 获取公开文件的访问地址
+func (s *FileBlobService) PublicUrl(blob *model.FileBlob) string {
+	if blob.IsPublic != 1 {
+		return ""
+	}
+
+	return s.fileSystem.Default.PublicUrl(blob.Path)
+}
+
+// Derive 记录文件的衍生文件(如图片规格图)，文件回收时一并删除
+func (s *FileBlobService) Derive(ctx context.Context, id int, paths []string) error {
+	_, err := s.repo.UpdateById(ctx, id, map[string]interface{}{
+		"derived": jsonutil.Encode(paths),
+	})
+
+	return err
+}
+
+func (s *FileBlobService) store(ctx context.Context, hash string, size int64, ext string, isPublic bool, write func(filePath string) error) (*model.FileBlob, error) {
+
+	drive, public := entity.FileDriveMode(s.fileSystem.Driver()), 0
+	if isPublic {
+		public = 1
+	}
+
+	blob, err := s.repo.FindByHash(ctx, drive, hash, public)
+	if err == nil {
+		// 刷新更新时间，避免文件在被引用前回收
+		affected, err := s.repo.UpdateById(ctx, blob.Id, map[string]interface{}{"updated_at": time.Now()})
+		if err != nil {
+			return nil, err
+		}
+
+		if affected > 0 {
+			return blob, nil
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	blob = &model.FileBlob{
+		Drive:    drive,
+		Hash:     hash,
+		IsPublic: public,
+		Path:     blobPath(hash, ext, isPublic),
+		Size:     size,
+	}
+
+	if err := write(blob.Path); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, blob); err != nil {
+		// 并发存储相同内容时以已存在的记录为准
+		if exist, e := s.repo.FindByHash(ctx, drive, hash, public); e == nil {
+			return exist, nil
+		}
+
+		return nil, err
+	}
+
+	return blob, nil
+}
+
+// blobPath 按 hash 生成文件存储地址
+func blobPath(hash string, ext string, isPublic bool) string {
+	name := hash
+	if ext != "" {
+		name = fmt.Sprintf("%s.%s", hash, ext)
+	}
+
+	if isPublic {
+		return fmt.Sprintf("public/media/blob/%s/%s", hash[:2], name)
+	}
+
+	return fmt.Sprintf("private/files/blob/%s/%s", hash[:2], name)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"go-chat/internal/repository/cache"
@@ -14,18 +16,28 @@ import (
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/jsonutil"
 	"go-chat/internal/pkg/sliceutil"
+	"go-chat/internal/pkg/strutil"
 	"go-chat/internal/pkg/timeutil"
 )
 
+var (
+	ErrGroupInviteRefused = errors.New("部分用户拒绝了你的入群邀请")
+	ErrGroupMemberLimit   = errors.New("群成员数量已达上限")
+)
+
 type GroupService struct {
 	*BaseService
-	repo      *repo.Group
-	memberDao *repo.GroupMember
-	relation  *cache.Relation
+	repo       *repo.Group
+	memberDao  *repo.GroupMember
+	relation   *cache.Relation
+	block      *repo.UserBlock
+	unread     *cache.UnreadStorage
+	permission *AuthPermissionService
+	message    *TalkMessageService
 }
 
-func NewGroupService(baseService *BaseService, repo *repo.Group, memberDao *repo.GroupMember, relation *cache.Relation) *GroupService {
-	return &GroupService{BaseService: baseService, repo: repo, memberDao: memberDao, relation: relation}
+func NewGroupService(baseService *BaseService, repo *repo.Group, memberDao *repo.GroupMember, relation *cache.Relation, block *repo.UserBlock, unread *cache.UnreadStorage, permission *AuthPermissionService, message *TalkMessageService) *GroupService {
+	return &GroupService{BaseService: baseService, repo: repo, memberDao: memberDao, relation: relation, block: block, unread: unread, permission: permission, message: message}
 }
 
 func (s *GroupService) Dao() *repo.Group {
@@ -48,8 +60,16 @@ func (s *GroupService) Create(ctx context.Context, opts *CreateGroupOpt) (int, e
 		talkList []*model.TalkSession
 	)
 
+	// 已将创建者拉黑的用户不可被邀请
+	if len(s.block.BlockerIds(ctx, opts.UserId, opts.MemberIds)) > 0 {
+		return 0, ErrGroupInviteRefused
+	}
+
 	// 群成员用户ID
 	mids := sliceutil.Unique(append(opts.MemberIds, opts.UserId))
+	if len(mids) > model.GroupMemberMaxNum {
+		return 0, ErrGroupMemberLimit
+	}
 
 	group := &model.Group{
 		CreatorId: opts.UserId,
@@ -67,9 +87,9 @@ func (s *GroupService) Create(ctx context.Context, opts *CreateGroupOpt) (int, e
 		}
 
 		for _, val := range mids {
-			leader := 0
+			leader := entity.GroupLeaderMember
 			if opts.UserId == val {
-				leader = 2
+				leader = entity.GroupLeaderOwner
 			}
 
 			members = append(members, &model.GroupMember{
@@ -105,7 +125,7 @@ func (s *GroupService) Create(ctx context.Context, opts *CreateGroupOpt) (int, e
 
 		if err = tx.Create(&model.TalkRecordsInvite{
 			RecordId:      record.Id,
-			Type:          1,
+			Type:          entity.GroupInviteTypeCreate,
 			OperateUserId: opts.UserId,
 			UserIds:       sliceutil.ToIds(mids[0 : len(mids)-1]),
 		}).Error; err != nil {
@@ -115,6 +135,10 @@ func (s *GroupService) Create(ctx context.Context, opts *CreateGroupOpt) (int, e
 		return nil
 	})
 
+	if err == nil {
+		s.unread.InitGroup(ctx, group.Id, mids)
+	}
+
 	// 广播网关将在线的用户加入房间
 	body := map[string]interface{}{
 		"event": entity.EventTalkJoinGroup,
@@ -148,40 +172,92 @@ func (s *GroupService) Update(ctx context.Context, opts *UpdateGroupOpt) error {
 	return err
 }
 
-// Dismiss 解散群组[群主权限]
-func (s *GroupService) Dismiss(_ context.Context, groupId int, uid int) error {
-	err := s.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Model(&model.Group{Id: groupId, CreatorId: uid}).Updates(&model.Group{
-			IsDismiss: 1,
+// Dismiss 解散群组[群主权限]，推送解散消息并将所有成员移出群房间
+func (s *GroupService) Dismiss(ctx context.Context, groupId int, uid int) error {
+
+	group, err := s.repo.FindById(ctx, groupId)
+	if err != nil {
+		return err
+	}
+
+	if group.IsDismiss == 1 {
+		return errors.New("群组已解散！")
+	}
+
+	var uids []int
+	if err := s.db.WithContext(ctx).Model(&model.GroupMember{}).Where("group_id = ? and is_quit = 0", groupId).Pluck("user_id", &uids).Error; err != nil {
+		return err
+	}
+
+	record := &model.TalkRecords{
+		TalkType:   entity.ChatGroupMode,
+		ReceiverId: groupId,
+		MsgType:    entity.MsgTypeGroupInvite,
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Group{}).Where("id = ?", groupId).Updates(map[string]interface{}{
+			"is_dismiss":   1,
+			"dismissed_at": time.Now(),
 		}).Error; err != nil {
 			return err
 		}
 
-		if err := s.db.Model(&model.GroupMember{}).Where("group_id = ?", groupId).Updates(&model.GroupMember{
-			IsQuit: 1,
+		if err := tx.Model(&model.GroupMember{}).Where("group_id = ? and is_quit = 0", groupId).Updates(map[string]interface{}{
+			"is_quit":    1,
+			"deleted_at": time.Now(),
 		}).Error; err != nil {
 			return err
 		}
 
-		return nil
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&model.TalkRecordsInvite{
+			RecordId:      record.Id,
+			Type:          entity.GroupInviteTypeDismiss,
+			OperateUserId: uid,
+			UserIds:       fmt.Sprintf("%v", uid),
+		}).Error
 	})
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	s.relation.BatchDelGroupRelation(ctx, uids, groupId)
+	s.memberDao.ClearMemberIds(ctx, groupId)
+	s.unread.DelGroup(ctx, groupId, uids)
+
+	// 成员均已移出群房间，由网关向离开的成员推送解散消息后再清理房间
+	s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(map[string]interface{}{
+		"event": entity.EventTalkJoinGroup,
+		"data": jsonutil.Encode(map[string]interface{}{
+			"type":      2,
+			"group_id":  groupId,
+			"uids":      uids,
+			"record_id": record.Id,
+		}),
+	}))
+
+	return nil
 }
 
 // Secede 退出群组[仅管理员及群成员]
 func (s *GroupService) Secede(ctx context.Context, groupId int, uid int) error {
 
-	info := &model.GroupMember{}
-	if err := s.db.Where("group_id = ? AND user_id = ? and is_quit = 0", groupId, uid).First(info).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	_, perms, err := s.permission.GroupPermissions(ctx, groupId, uid)
+	if err != nil {
+		if errors.Is(err, ErrGroupNotMember) {
 			return errors.New("数据不存在！")
 		}
 
 		return err
 	}
 
-	if info.Leader == 2 {
+	// 拥有转让权限的成员(群主)需先转让群组
+	if hasPermission(perms, entity.GroupPermHandover) {
 		return errors.New("群主不能退出群组！")
 	}
 
@@ -191,7 +267,7 @@ func (s *GroupService) Secede(ctx context.Context, groupId int, uid int) error {
 		MsgType:    entity.MsgTypeGroupInvite,
 	}
 
-	err := s.db.Transaction(func(tx *gorm.DB) error {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
 		err := tx.Model(&model.GroupMember{}).Where("group_id = ? AND user_id = ?", groupId, uid).Updates(&model.GroupMember{
 			IsQuit: 1,
 		}).Error
@@ -205,7 +281,7 @@ func (s *GroupService) Secede(ctx context.Context, groupId int, uid int) error {
 
 		if err := tx.Create(&model.TalkRecordsInvite{
 			RecordId:      record.Id,
-			Type:          2,
+			Type:          entity.GroupInviteTypeQuit,
 			OperateUserId: uid,
 			UserIds:       fmt.Sprintf("%v", uid),
 		}).Error; err != nil {
@@ -220,6 +296,8 @@ func (s *GroupService) Secede(ctx context.Context, groupId int, uid int) error {
 	}
 
 	s.relation.DelGroupRelation(ctx, uid, groupId)
+	s.memberDao.ClearMemberIds(ctx, groupId)
+	s.unread.DelGroup(ctx, groupId, []int{uid})
 
 	s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(map[string]interface{}{
 		"event": entity.EventTalkJoinGroup,
@@ -247,6 +325,7 @@ type InviteGroupMembersOpt struct {
 	UserId    int   // 操作人ID
 	GroupId   int   // 群ID
 	MemberIds []int // 群成员ID
+	Type      int   // 成员变动消息类型，默认为邀请入群
 }
 
 // InviteMembers 邀请加入群聊
@@ -259,6 +338,11 @@ func (s *GroupService) InviteMembers(ctx context.Context, opts *InviteGroupMembe
 		talkList       []*model.TalkSession
 	)
 
+	// 已将邀请人拉黑的用户不可被邀请
+	if len(s.block.BlockerIds(ctx, opts.UserId, opts.MemberIds)) > 0 {
+		return ErrGroupInviteRefused
+	}
+
 	m := make(map[int]struct{})
 	for _, value := range s.memberDao.GetMemberIds(ctx, opts.GroupId) {
 		m[value] = struct{}{}
@@ -294,6 +378,20 @@ func (s *GroupService) InviteMembers(ctx context.Context, opts *InviteGroupMembe
 		return errors.New("邀请的好友，都已成为群成员")
 	}
 
+	group, err := s.repo.FindById(ctx, opts.GroupId)
+	if err != nil {
+		return err
+	}
+
+	if len(m)+len(addMembers) > group.MaxNum {
+		return ErrGroupMemberLimit
+	}
+
+	inviteType := opts.Type
+	if inviteType == 0 {
+		inviteType = entity.GroupInviteTypeJoin
+	}
+
 	record := &model.TalkRecords{
 		TalkType:   entity.ChatGroupMode,
 		ReceiverId: opts.GroupId,
@@ -301,6 +399,26 @@ func (s *GroupService) InviteMembers(ctx context.Context, opts *InviteGroupMembe
 	}
 
 	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err = tx.Create(record).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&model.TalkRecordsInvite{
+			RecordId:      record.Id,
+			Type:          inviteType,
+			OperateUserId: opts.UserId,
+			UserIds:       sliceutil.ToIds(opts.MemberIds),
+		}).Error; err != nil {
+			return err
+		}
+
+		// 未开启共享历史消息时，新成员仅可查看入群消息之后的记录
+		if group.IsShareHistory == 0 {
+			for _, member := range addMembers {
+				member.MinRecordId = record.Id
+			}
+		}
+
 		// 删除已存在成员记录
 		tx.Where("group_id = ? and user_id in ? and is_quit = 1", opts.GroupId, opts.MemberIds).Delete(&model.GroupMember{})
 
@@ -324,19 +442,6 @@ func (s *GroupService) InviteMembers(ctx context.Context, opts *InviteGroupMembe
 			})
 		}
 
-		if err = tx.Create(record).Error; err != nil {
-			return err
-		}
-
-		if err := tx.Create(&model.TalkRecordsInvite{
-			RecordId:      record.Id,
-			Type:          1,
-			OperateUserId: opts.UserId,
-			UserIds:       sliceutil.ToIds(opts.MemberIds),
-		}).Error; err != nil {
-			return err
-		}
-
 		return nil
 	})
 
@@ -344,6 +449,15 @@ func (s *GroupService) InviteMembers(ctx context.Context, opts *InviteGroupMembe
 		return err
 	}
 
+	s.memberDao.ClearMemberIds(ctx, opts.GroupId)
+
+	newIds := make([]int, 0, len(addMembers))
+	for _, member := range addMembers {
+		newIds = append(newIds, member.UserId)
+	}
+
+	s.unread.InitGroup(ctx, opts.GroupId, newIds)
+
 	// 广播网关将在线的用户加入房间
 	s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(map[string]interface{}{
 		"event": entity.EventTalkJoinGroup,
@@ -367,10 +481,17 @@ func (s *GroupService) InviteMembers(ctx context.Context, opts *InviteGroupMembe
 	return nil
 }
 
+// 移除群成员时可撤回的近期消息范围
+const (
+	groupRevokeRecentTime = 24 * time.Hour
+	groupRevokeRecentMax  = 500
+)
+
 type RemoveMembersOpt struct {
-	UserId    int   // 操作人ID
-	GroupId   int   // 群ID
-	MemberIds []int // 群成员ID
+	UserId       int   // 操作人ID
+	GroupId      int   // 群ID
+	MemberIds    []int // 群成员ID
+	RevokeRecent bool  // 是否同时撤回被移除成员的近期消息
 }
 
 // RemoveMembers 群成员移除群聊
@@ -391,6 +512,11 @@ func (s *GroupService) RemoveMembers(ctx context.Context, opts *RemoveMembersOpt
 		MsgType:    entity.MsgTypeGroupInvite,
 	}
 
+	var (
+		revokeIds   []int
+		revokeUsers []int
+	)
+
 	err := s.Db().Transaction(func(tx *gorm.DB) error {
 		err := tx.Model(&model.GroupMember{}).Where("group_id = ? and user_id in ? and is_quit = 0", opts.GroupId, opts.MemberIds).Updates(map[string]interface{}{
 			"is_quit":    1,
@@ -406,14 +532,30 @@ func (s *GroupService) RemoveMembers(ctx context.Context, opts *RemoveMembersOpt
 
 		if err := tx.Create(&model.TalkRecordsInvite{
 			RecordId:      record.Id,
-			Type:          3,
+			Type:          entity.GroupInviteTypeKicked,
 			OperateUserId: opts.UserId,
 			UserIds:       sliceutil.ToIds(opts.MemberIds),
 		}).Error; err != nil {
 			return err
 		}
 
-		return nil
+		if !opts.RevokeRecent {
+			return nil
+		}
+
+		items := make([]*model.TalkRecords, 0)
+		err = tx.Model(&model.TalkRecords{}).Select("id", "user_id").Where("talk_type = ? and receiver_id = ? and user_id in ? and is_revoke = 0 and created_at >= ?", entity.ChatGroupMode, opts.GroupId, opts.MemberIds, time.Now().Add(-groupRevokeRecentTime)).
+			Order("id desc").Limit(groupRevokeRecentMax).Scan(&items).Error
+		if err != nil || len(items) == 0 {
+			return err
+		}
+
+		for _, item := range items {
+			revokeIds = append(revokeIds, item.Id)
+			revokeUsers = append(revokeUsers, item.UserId)
+		}
+
+		return tx.Model(&model.TalkRecords{}).Where("id in ?", revokeIds).Update("is_revoke", 1).Error
 	})
 
 	// 推送消息
@@ -422,6 +564,8 @@ func (s *GroupService) RemoveMembers(ctx context.Context, opts *RemoveMembersOpt
 	}
 
 	s.relation.BatchDelGroupRelation(ctx, opts.MemberIds, opts.GroupId)
+	s.memberDao.ClearMemberIds(ctx, opts.GroupId)
+	s.unread.DelGroup(ctx, opts.GroupId, opts.MemberIds)
 
 	s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(map[string]interface{}{
 		"event": entity.EventTalkJoinGroup,
@@ -442,9 +586,129 @@ func (s *GroupService) RemoveMembers(ctx context.Context, opts *RemoveMembersOpt
 		}),
 	}))
 
+	if len(revokeIds) == 0 {
+		return nil
+	}
+
+	s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(map[string]interface{}{
+		"event": entity.EventTalkRevoke,
+		"data": jsonutil.Encode(map[string]interface{}{
+			"record_ids":  revokeIds,
+			"operator_id": opts.UserId,
+		}),
+	}))
+
+	// 系统提示仅列出实际被撤回消息的成员
+	_ = s.message.SendSysMessage(ctx, &SysTextMessageOpt{
+		UserId:     opts.UserId,
+		TalkType:   entity.ChatGroupMode,
+		ReceiverId: opts.GroupId,
+		Text:       groupRevokeText(ctx, s.db, opts.UserId, sliceutil.Unique(revokeUsers), len(revokeIds)),
+	})
+
+	return nil
+}
+
+// Handover 交接群主权限，并推送转让群主消息
+func (s *GroupService) Handover(ctx context.Context, groupId int, userId int, memberId int) error {
+
+	record := &model.TalkRecords{
+		TalkType:   entity.ChatGroupMode,
+		ReceiverId: groupId,
+		MsgType:    entity.MsgTypeGroupInvite,
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		err := tx.Model(&model.GroupMember{}).Where("group_id = ? and user_id = ? and leader = 2", groupId, userId).Update("leader", 0).Error
+		if err != nil {
+			return err
+		}
+
+		res := tx.Model(&model.GroupMember{}).Where("group_id = ? and user_id = ? and is_quit = 0", groupId, memberId).Update("leader", 2)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return errors.New("群成员不存在")
+		}
+
+		if err := tx.Model(&model.Group{}).Where("id = ?", groupId).Update("creator_id", memberId).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&model.TalkRecordsInvite{
+			RecordId:      record.Id,
+			Type:          entity.GroupInviteTypeHandover,
+			OperateUserId: userId,
+			UserIds:       strconv.Itoa(memberId),
+		}).Error
+	})
+
+	if err != nil {
+		return err
+	}
+
+	s.message.afterHandle(ctx, record, map[string]string{"text": "[转让群主]"})
+
 	return nil
 }
 
+// Upgrade 升级为超级群，提升群成员数量上限
+func (s *GroupService) Upgrade(ctx context.Context, groupId int) error {
+	_, err := s.repo.UpdateById(ctx, groupId, map[string]interface{}{
+		"is_super": 1,
+		"max_num":  model.SuperGroupMemberMaxNum,
+	})
+
+	return err
+}
+
+// SetShareHistory 设置新成员是否可查看入群前的历史消息
+func (s *GroupService) SetShareHistory(ctx context.Context, groupId int, isShare bool) error {
+	_, err := s.repo.UpdateById(ctx, groupId, map[string]interface{}{
+		"is_share_history": strutil.BoolToInt(isShare),
+	})
+
+	return err
+}
+
+// SetBroadcast 设置是否仅群主及管理员可发言
+func (s *GroupService) SetBroadcast(ctx context.Context, groupId int, isBroadcast bool) error {
+	_, err := s.repo.UpdateById(ctx, groupId, map[string]interface{}{
+		"is_broadcast": strutil.BoolToInt(isBroadcast),
+	})
+
+	return err
+}
+
+// groupRevokeText 管理员撤回成员消息的系统提示文案
+func groupRevokeText(ctx context.Context, db *gorm.DB, operatorId int, memberIds []int, num int) string {
+	items := make([]*model.Users, 0)
+	db.WithContext(ctx).Model(&model.Users{}).Select("id,nickname").Where("id in ?", append([]int{operatorId}, memberIds...)).Scan(&items)
+
+	names := make(map[int]string)
+	for _, item := range items {
+		names[item.Id] = item.Nickname
+	}
+
+	members := make([]string, 0, len(memberIds))
+	for _, id := range memberIds {
+		members = append(members, names[id])
+	}
+
+	if num == 1 {
+		return fmt.Sprintf("「%s」撤回了「%s」的一条消息", names[operatorId], strings.Join(members, "、"))
+	}
+
+	return fmt.Sprintf("「%s」撤回了「%s」的 %d 条消息", names[operatorId], strings.Join(members, "、"), num)
+}
+
 type session struct {
 	ReceiverID int `json:"receiver_id"`
 	IsDisturb  int `json:"is_disturb"`
@@ -3,54 +3,296 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
 )
 
+var (
+	ErrGroupApplyNotFound   = errors.New("入群申请不存在")
+	ErrGroupApplyProcessed  = errors.New("入群申请已处理")
+	ErrGroupApplyDismissed  = errors.New("群组已解散")
+	ErrGroupApplyMember     = errors.New("你已是群成员")
+	ErrGroupApplyInviteOnly = errors.New("该群仅允许成员邀请加入")
+	ErrGroupApplyAnswer     = errors.New("入群问题回答错误")
+)
+
+type GroupApplyCreateOpts struct {
+	GroupId int
+	UserId  int
+	Remark  string
+	Answer  string
+}
+
+type GroupJoinSettingOpts struct {
+	GroupId  int
+	JoinMode int
+	Question string
+	Answer   string
+}
+
 type GroupApplyService struct {
 	*BaseService
-	repo *repo.GroupApply
+	repo       *repo.GroupApply
+	groupRepo  *repo.Group
+	memberRepo *repo.GroupMember
+	group      *GroupService
+	permission *AuthPermissionService
 }
 
-func NewGroupApplyService(baseService *BaseService, repo *repo.GroupApply) *GroupApplyService {
-	return &GroupApplyService{BaseService: baseService, repo: repo}
+func NewGroupApplyService(baseService *BaseService, repo *repo.GroupApply, groupRepo *repo.Group, memberRepo *repo.GroupMember, group *GroupService, permission *AuthPermissionService) *GroupApplyService {
+	return &GroupApplyService{BaseService: baseService, repo: repo, groupRepo: groupRepo, memberRepo: memberRepo, group: group, permission: permission}
 }
 
 func (s *GroupApplyService) Dao() *repo.GroupApply {
 	return s.repo
 }
 
+// Auth 判断用户是否有权处理入群申请
 func (s *GroupApplyService) Auth(ctx context.Context, applyId, userId int) bool {
-	info := &model.GroupApply{}
 
-	err := s.Db().First(info, "id = ?", applyId).Error
+	apply, err := s.repo.FindById(ctx, applyId)
 	if err != nil {
 		return false
 	}
 
-	member := &model.GroupMember{}
-	err = s.Db().First(member, "group_id = ? and user_id = ? and leader in (1,2) and is_quit = 0", info.GroupId).Error
+	return s.permission.GroupAuthorize(ctx, apply.GroupId, userId, entity.GroupPermApply) == nil
+}
+
+// Create 提交入群申请，根据群的入群方式直接加入或等待管理员审核
+func (s *GroupApplyService) Create(ctx context.Context, opts *GroupApplyCreateOpts) (*model.GroupApply, error) {
+
+	group, err := s.groupRepo.FindById(ctx, opts.GroupId)
 	if err != nil {
-		return false
+		return nil, err
+	}
+
+	if group.IsDismiss == 1 {
+		return nil, ErrGroupApplyDismissed
+	}
+
+	if s.memberRepo.IsMember(ctx, opts.GroupId, opts.UserId, false) {
+		return nil, ErrGroupApplyMember
+	}
+
+	approved := false
+	switch group.JoinMode {
+	case entity.GroupJoinOpen:
+		approved = true
+	case entity.GroupJoinInvite:
+		return nil, ErrGroupApplyInviteOnly
+	case entity.GroupJoinQuestion:
+		if group.Answer != "" {
+			if !strings.EqualFold(strings.TrimSpace(opts.Answer), strings.TrimSpace(group.Answer)) {
+				return nil, ErrGroupApplyAnswer
+			}
+
+			approved = true
+		}
+	}
+
+	if approved {
+		return s.join(ctx, opts)
+	}
+
+	return s.Submit(ctx, opts)
+}
+
+// Submit 提交待管理员审核的入群申请，不校验群的入群方式
+func (s *GroupApplyService) Submit(ctx context.Context, opts *GroupApplyCreateOpts) (*model.GroupApply, error) {
+
+	// 重复申请时更新原有的待审核申请
+	apply, err := s.repo.FindPending(ctx, opts.GroupId, opts.UserId)
+	if err == nil {
+		_, err = s.repo.UpdateById(ctx, apply.Id, map[string]interface{}{
+			"remark": opts.Remark,
+			"answer": opts.Answer,
+		})
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		apply = &model.GroupApply{
+			GroupId: opts.GroupId,
+			UserId:  opts.UserId,
+			Remark:  opts.Remark,
+			Answer:  opts.Answer,
+			Status:  entity.GroupApplyStatusPending,
+		}
+
+		err = s.repo.Create(ctx, apply)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, apply, s.permission.GroupAuthorizedUserIds(ctx, apply.GroupId, entity.GroupPermApply))
+
+	return apply, nil
+}
+
+// join 无需审核直接入群，申请记录仅用于留档
+func (s *GroupApplyService) join(ctx context.Context, opts *GroupApplyCreateOpts) (*model.GroupApply, error) {
+
+	err := s.group.InviteMembers(ctx, &InviteGroupMembersOpt{
+		UserId:    opts.UserId,
+		GroupId:   opts.GroupId,
+		MemberIds: []int{opts.UserId},
+		Type:      entity.GroupInviteTypeSelfJoin,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	apply := &model.GroupApply{
+		GroupId: opts.GroupId,
+		UserId:  opts.UserId,
+		Remark:  opts.Remark,
+		Answer:  opts.Answer,
+		Status:  entity.GroupApplyStatusApproved,
+	}
+
+	if err := s.repo.Create(ctx, apply); err != nil {
+		return nil, err
+	}
+
+	return apply, nil
+}
+
+// Agree 同意入群申请
+func (s *GroupApplyService) Agree(ctx context.Context, applyId int, reviewerId int) error {
+
+	apply, err := s.review(ctx, applyId, reviewerId, entity.GroupApplyStatusApproved, "")
+	if err != nil {
+		return err
 	}
 
-	return member.Id == 0
+	if !s.memberRepo.IsMember(ctx, apply.GroupId, apply.UserId, false) {
+		err = s.group.InviteMembers(ctx, &InviteGroupMembersOpt{
+			UserId:    reviewerId,
+			GroupId:   apply.GroupId,
+			MemberIds: []int{apply.UserId},
+		})
+
+		if err != nil {
+			// 入群失败时恢复为待审核，便于重新处理
+			_, _ = s.repo.UpdateById(ctx, apply.Id, map[string]interface{}{
+				"status":      entity.GroupApplyStatusPending,
+				"reviewer_id": 0,
+			})
+
+			return err
+		}
+	}
+
+	s.notify(ctx, apply, []int{apply.UserId})
+
+	return nil
 }
 
-func (s *GroupApplyService) Insert(ctx context.Context, groupId, userId int, remark string) error {
-	return s.Db().Create(&model.GroupApply{
-		GroupId: groupId,
-		UserId:  userId,
-		Remark:  remark,
-	}).Error
+// Decline 拒绝入群申请
+func (s *GroupApplyService) Decline(ctx context.Context, applyId int, reviewerId int, reason string) error {
+
+	apply, err := s.review(ctx, applyId, reviewerId, entity.GroupApplyStatusRejected, reason)
+	if err != nil {
+		return err
+	}
+
+	s.notify(ctx, apply, []int{apply.UserId})
+
+	return nil
+}
+
+// review 校验审核权限并将待审核的申请更新为审核结果
+func (s *GroupApplyService) review(ctx context.Context, applyId int, reviewerId int, status int, reason string) (*model.GroupApply, error) {
+
+	apply, err := s.repo.FindById(ctx, applyId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGroupApplyNotFound
+		}
+
+		return nil, err
+	}
+
+	if err := s.permission.GroupAuthorize(ctx, apply.GroupId, reviewerId, entity.GroupPermApply); err != nil {
+		return nil, err
+	}
+
+	if apply.Status != entity.GroupApplyStatusPending {
+		return nil, ErrGroupApplyProcessed
+	}
+
+	res := s.repo.Model(ctx).Where("id = ? and status = ?", apply.Id, entity.GroupApplyStatusPending).Updates(map[string]interface{}{
+		"status":      status,
+		"reviewer_id": reviewerId,
+		"reason":      reason,
+	})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, ErrGroupApplyProcessed
+	}
+
+	apply.Status = status
+	apply.ReviewerId = reviewerId
+	apply.Reason = reason
+
+	return apply, nil
 }
 
+// Delete 删除入群申请，申请人可撤回待审核的申请，管理员可删除申请记录
 func (s *GroupApplyService) Delete(ctx context.Context, applyId, userId int) error {
 
-	if !s.Auth(ctx, applyId, userId) {
-		return errors.New("auth failed")
+	apply, err := s.repo.FindById(ctx, applyId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrGroupApplyNotFound
+		}
+
+		return err
+	}
+
+	isOwner := apply.UserId == userId && apply.Status == entity.GroupApplyStatusPending
+	if !isOwner && !s.Auth(ctx, applyId, userId) {
+		return ErrGroupPermission
 	}
 
 	return s.Db().Delete(&model.GroupApply{}, "id = ?", applyId).Error
 }
+
+// JoinSetting 修改入群方式
+func (s *GroupApplyService) JoinSetting(ctx context.Context, opts *GroupJoinSettingOpts) error {
+
+	_, err := s.groupRepo.UpdateById(ctx, opts.GroupId, map[string]interface{}{
+		"join_mode":     opts.JoinMode,
+		"join_question": opts.Question,
+		"join_answer":   opts.Answer,
+	})
+
+	return err
+}
+
+// notify 推送入群申请通知，新申请推送给有审核权限的成员，审核结果推送给申请人
+func (s *GroupApplyService) notify(ctx context.Context, apply *model.GroupApply, receivers []int) {
+
+	if len(receivers) == 0 {
+		return
+	}
+
+	s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(map[string]interface{}{
+		"event": entity.EventGroupApply,
+		"data": jsonutil.Encode(map[string]interface{}{
+			"apply_id":  apply.Id,
+			"group_id":  apply.GroupId,
+			"user_id":   apply.UserId,
+			"status":    apply.Status,
+			"reason":    apply.Reason,
+			"receivers": receivers,
+		}),
+	}))
+}
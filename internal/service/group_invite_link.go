@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrGroupInviteLinkInvalid   = errors.New("邀请链接无效")
+	ErrGroupInviteLinkExpired   = errors.New("邀请链接已过期")
+	ErrGroupInviteLinkExhausted = errors.New("邀请链接使用次数已达上限")
+)
+
+type GroupInviteLinkCreateOpts struct {
+	GroupId      int
+	UserId       int
+	ExpireSecond int  // 有效时长(单位秒)，0 为永久有效
+	MaxUses      int  // 最大使用次数，0 为不限
+	NeedApproval bool // 通过链接入群是否需要管理员审核
+}
+
+type GroupInvitePreview struct {
+	GroupId      int        `json:"group_id"`
+	GroupName    string     `json:"group_name"`
+	Avatar       string     `json:"avatar"`
+	Profile      string     `json:"profile"`
+	MemberCount  int64      `json:"member_count"`
+	NeedApproval int        `json:"need_approval"`
+	ExpiredAt    *time.Time `json:"expired_at"`
+}
+
+type GroupInviteLinkService struct {
+	*BaseService
+	config     *config.Config
+	repo       *repo.GroupInviteLink
+	groupRepo  *repo.Group
+	memberRepo *repo.GroupMember
+	group      *GroupService
+	apply      *GroupApplyService
+	permission *AuthPermissionService
+}
+
+func NewGroupInviteLinkService(baseService *BaseService, conf *config.Config, repo *repo.GroupInviteLink, groupRepo *repo.Group, memberRepo *repo.GroupMember, group *GroupService, apply *GroupApplyService, permission *AuthPermissionService) *GroupInviteLinkService {
+	return &GroupInviteLinkService{BaseService: baseService, config: conf, repo: repo, groupRepo: groupRepo, memberRepo: memberRepo, group: group, apply: apply, permission: permission}
+}
+
+func (s *GroupInviteLinkService) Dao() *repo.GroupInviteLink {
+	return s.repo
+}
+
+// Create 创建群邀请链接，仅拥有邀请权限的成员可创建
+func (s *GroupInviteLinkService) Create(ctx context.Context, opts *GroupInviteLinkCreateOpts) (*model.GroupInviteLink, error) {
+
+	if err := s.permission.GroupAuthorize(ctx, opts.GroupId, opts.UserId, entity.GroupPermInvite); err != nil {
+		return nil, err
+	}
+
+	code := make([]byte, 16)
+	if _, err := rand.Read(code); err != nil {
+		return nil, err
+	}
+
+	link := &model.GroupInviteLink{
+		GroupId:   opts.GroupId,
+		CreatorId: opts.UserId,
+		Code:      hex.EncodeToString(code),
+		MaxUses:   opts.MaxUses,
+	}
+
+	if opts.NeedApproval {
+		link.NeedApproval = 1
+	}
+
+	if opts.ExpireSecond > 0 {
+		expiredAt := time.Now().Add(time.Duration(opts.ExpireSecond) * time.Second)
+		link.ExpiredAt = &expiredAt
+	}
+
+	if err := s.repo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// Token 生成邀请链接的签名令牌，用于分享链接及二维码
+func (s *GroupInviteLinkService) Token(link *model.GroupInviteLink) string {
+	return link.Code + "." + s.sign(link.Code)
+}
+
+func (s *GroupInviteLinkService) sign(code string) string {
+	return encrypt.HmacSha256(s.config.Jwt.Secret, "group_invite:"+code)[:16]
+}
+
+// Parse 校验邀请令牌并返回可用的邀请链接
+func (s *GroupInviteLinkService) Parse(ctx context.Context, token string) (*model.GroupInviteLink, error) {
+
+	code, sign, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sign), []byte(s.sign(code))) {
+		return nil, ErrGroupInviteLinkInvalid
+	}
+
+	link, err := s.repo.FindByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGroupInviteLinkInvalid
+		}
+
+		return nil, err
+	}
+
+	if link.IsRevoke == 1 {
+		return nil, ErrGroupInviteLinkInvalid
+	}
+
+	if link.ExpiredAt != nil && link.ExpiredAt.Before(time.Now()) {
+		return nil, ErrGroupInviteLinkExpired
+	}
+
+	if link.MaxUses > 0 && link.UsedNum >= link.MaxUses {
+		return nil, ErrGroupInviteLinkExhausted
+	}
+
+	// 创建人已失去邀请权限时链接失效
+	if s.permission.GroupAuthorize(ctx, link.GroupId, link.CreatorId, entity.GroupPermInvite) != nil {
+		return nil, ErrGroupInviteLinkInvalid
+	}
+
+	return link, nil
+}
+
+// Preview 预览邀请链接对应的群信息
+func (s *GroupInviteLinkService) Preview(ctx context.Context, token string) (*GroupInvitePreview, error) {
+
+	link, err := s.Parse(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.groupRepo.FindById(ctx, link.GroupId)
+	if err != nil {
+		return nil, err
+	}
+
+	if group.IsDismiss == 1 {
+		return nil, ErrGroupApplyDismissed
+	}
+
+	return &GroupInvitePreview{
+		GroupId:      group.Id,
+		GroupName:    group.Name,
+		Avatar:       group.Avatar,
+		Profile:      group.Profile,
+		MemberCount:  s.memberRepo.CountMemberTotal(ctx, group.Id),
+		NeedApproval: link.NeedApproval,
+		ExpiredAt:    link.ExpiredAt,
+	}, nil
+}
+
+// Redeem 通过邀请链接入群，需要审核的链接将提交入群申请，返回入群申请状态
+func (s *GroupInviteLinkService) Redeem(ctx context.Context, token string, uid int) (int, error) {
+
+	link, err := s.Parse(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+
+	group, err := s.groupRepo.FindById(ctx, link.GroupId)
+	if err != nil {
+		return 0, err
+	}
+
+	if group.IsDismiss == 1 {
+		return 0, ErrGroupApplyDismissed
+	}
+
+	if s.memberRepo.IsMember(ctx, link.GroupId, uid, false) {
+		return 0, ErrGroupApplyMember
+	}
+
+	// 已有待审核的入群申请时重复提交不再占用链接的使用次数
+	consume := true
+	if link.NeedApproval == 1 {
+		if _, err := s.apply.Dao().FindPending(ctx, link.GroupId, uid); err == nil {
+			consume = false
+		}
+	}
+
+	if consume {
+		if ok, err := s.repo.IncrUsed(ctx, link.Id); err != nil {
+			return 0, err
+		} else if !ok {
+			return 0, ErrGroupInviteLinkExhausted
+		}
+	}
+
+	if link.NeedApproval == 1 {
+		_, err = s.apply.Submit(ctx, &GroupApplyCreateOpts{
+			GroupId: link.GroupId,
+			UserId:  uid,
+			Remark:  "通过邀请链接申请入群",
+		})
+	} else {
+		err = s.group.InviteMembers(ctx, &InviteGroupMembersOpt{
+			UserId:    link.CreatorId,
+			GroupId:   link.GroupId,
+			MemberIds: []int{uid},
+		})
+	}
+
+	if err != nil {
+		if consume {
+			_ = s.repo.DecrUsed(ctx, link.Id)
+		}
+
+		return 0, err
+	}
+
+	if link.NeedApproval == 1 {
+		return entity.GroupApplyStatusPending, nil
+	}
+
+	return entity.GroupApplyStatusApproved, nil
+}
+
+// List 群邀请链接列表，管理员可查看全部链接，其他成员仅可查看自己创建的链接
+func (s *GroupInviteLinkService) List(ctx context.Context, groupId int, uid int) ([]*model.GroupInviteLinkItem, error) {
+
+	if s.permission.GroupAuthorize(ctx, groupId, uid, entity.GroupPermApply) == nil {
+		return s.repo.List(ctx, groupId, 0)
+	}
+
+	if err := s.permission.GroupAuthorize(ctx, groupId, uid, entity.GroupPermInvite); err != nil {
+		return nil, err
+	}
+
+	return s.repo.List(ctx, groupId, uid)
+}
+
+// Revoke 作废邀请链接，创建人或管理员可操作
+func (s *GroupInviteLinkService) Revoke(ctx context.Context, linkId int, uid int) error {
+
+	link, err := s.repo.FindById(ctx, linkId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrGroupInviteLinkInvalid
+		}
+
+		return err
+	}
+
+	if link.CreatorId != uid && s.permission.GroupAuthorize(ctx, link.GroupId, uid, entity.GroupPermApply) != nil {
+		return ErrGroupPermission
+	}
+
+	_, err = s.repo.UpdateById(ctx, link.Id, map[string]interface{}{
+		"is_revoke": 1,
+	})
+
+	return err
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
+)
+
+var ErrGroupMuteMember = errors.New("群成员不存在")
+
+type GroupMuteOpts struct {
+	GroupId  int  // 群ID
+	UserId   int  // 操作人ID，为 0 时表示禁言到期自动解除
+	MemberId int  // 禁言成员ID，为 0 时表示全员禁言
+	Mute     bool // 禁言或解除禁言
+	Duration int  // 禁言时长(单位秒)，0 为永久禁言
+}
+
+type GroupMuteService struct {
+	*BaseService
+	groupRepo  *repo.Group
+	memberRepo *repo.GroupMember
+	message    *TalkMessageService
+}
+
+func NewGroupMuteService(baseService *BaseService, groupRepo *repo.Group, memberRepo *repo.GroupMember, message *TalkMessageService) *GroupMuteService {
+	return &GroupMuteService{BaseService: baseService, groupRepo: groupRepo, memberRepo: memberRepo, message: message}
+}
+
+// Mute 修改群成员或全员禁言状态，并推送系统消息及禁言事件
+func (s *GroupMuteService) Mute(ctx context.Context, opts *GroupMuteOpts) error {
+
+	var muteUntil *time.Time
+	if opts.Mute && opts.Duration > 0 {
+		until := time.Now().Add(time.Duration(opts.Duration) * time.Second)
+		muteUntil = &until
+	}
+
+	data := map[string]interface{}{
+		"is_mute":    0,
+		"mute_until": muteUntil,
+	}
+
+	if opts.Mute {
+		data["is_mute"] = 1
+	}
+
+	if opts.MemberId > 0 {
+		res := s.memberRepo.Model(ctx).Where("group_id = ? and user_id = ? and is_quit = 0", opts.GroupId, opts.MemberId).Updates(data)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return ErrGroupMuteMember
+		}
+	} else if _, err := s.groupRepo.UpdateById(ctx, opts.GroupId, data); err != nil {
+		return err
+	}
+
+	return s.notify(ctx, opts, muteUntil)
+}
+
+// ClearExpired 解除已到期的禁言
+func (s *GroupMuteService) ClearExpired(ctx context.Context) error {
+
+	now := time.Now()
+
+	members, err := s.memberRepo.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("is_mute = 1 and mute_until <= ?", now)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		res := s.memberRepo.Model(ctx).Where("id = ? and is_mute = 1 and mute_until <= ?", member.Id, now).Updates(map[string]interface{}{
+			"is_mute":    0,
+			"mute_until": nil,
+		})
+
+		// 已被手动修改的禁言不再重复通知
+		if res.Error != nil || res.RowsAffected == 0 {
+			continue
+		}
+
+		_ = s.notify(ctx, &GroupMuteOpts{GroupId: member.GroupId, MemberId: member.UserId}, nil)
+	}
+
+	groups, err := s.groupRepo.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("is_mute = 1 and mute_until <= ?", now)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		res := s.groupRepo.Model(ctx).Where("id = ? and is_mute = 1 and mute_until <= ?", group.Id, now).Updates(map[string]interface{}{
+			"is_mute":    0,
+			"mute_until": nil,
+		})
+
+		if res.Error != nil || res.RowsAffected == 0 {
+			continue
+		}
+
+		_ = s.notify(ctx, &GroupMuteOpts{GroupId: group.Id}, nil)
+	}
+
+	return nil
+}
+
+// notify 保存禁言群通知消息，并广播禁言事件通知客户端更新输入框状态
+// 禁言消息与入群退群消息一样以 talk_records_invite 记录类型，历史记录中通过 sys_type 区分
+func (s *GroupMuteService) notify(ctx context.Context, opts *GroupMuteOpts, muteUntil *time.Time) error {
+
+	inviteType := entity.GroupInviteTypeMuted
+	switch {
+	case opts.MemberId > 0 && opts.Mute:
+		inviteType = entity.GroupInviteTypeMemberMuted
+	case opts.MemberId > 0:
+		inviteType = entity.GroupInviteTypeMemberCancelMuted
+	case !opts.Mute:
+		inviteType = entity.GroupInviteTypeCancelMuted
+	}
+
+	record := &model.TalkRecords{
+		TalkType:   entity.ChatGroupMode,
+		ReceiverId: opts.GroupId,
+		MsgType:    entity.MsgTypeGroupInvite,
+		Content:    s.muteText(ctx, opts),
+	}
+
+	userIds := ""
+	if opts.MemberId > 0 {
+		userIds = strconv.Itoa(opts.MemberId)
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&model.TalkRecordsInvite{
+			RecordId:      record.Id,
+			Type:          inviteType,
+			OperateUserId: opts.UserId,
+			UserIds:       userIds,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.message.afterHandle(ctx, record, map[string]string{
+		"text": strutil.MtSubstr(record.Content, 0, 30),
+	})
+
+	until := ""
+	if muteUntil != nil {
+		until = timeutil.FormatDatetime(*muteUntil)
+	}
+
+	s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(map[string]interface{}{
+		"event": entity.EventGroupMute,
+		"data": jsonutil.Encode(map[string]interface{}{
+			"type":        entity.GroupInviteSysTypes[inviteType],
+			"group_id":    opts.GroupId,
+			"user_id":     opts.MemberId,
+			"operator_id": opts.UserId,
+			"is_mute":     opts.Mute,
+			"mute_until":  until,
+		}),
+	}))
+
+	return nil
+}
+
+// muteText 禁言系统消息文案
+func (s *GroupMuteService) muteText(ctx context.Context, opts *GroupMuteOpts) string {
+
+	items := make([]*model.Users, 0)
+	s.db.WithContext(ctx).Model(&model.Users{}).Select("id,nickname").Where("id in ?", []int{opts.UserId, opts.MemberId}).Scan(&items)
+
+	names := make(map[int]string)
+	for _, item := range items {
+		names[item.Id] = item.Nickname
+	}
+
+	duration := "永久"
+	if opts.Duration > 0 {
+		duration = muteDurationText(opts.Duration)
+	}
+
+	switch {
+	case opts.UserId == 0 && opts.MemberId > 0:
+		return fmt.Sprintf("「%s」的禁言已到期解除", names[opts.MemberId])
+	case opts.UserId == 0:
+		return "全员禁言已到期解除"
+	case opts.MemberId > 0 && opts.Mute:
+		return fmt.Sprintf("「%s」将「%s」禁言%s", names[opts.UserId], names[opts.MemberId], duration)
+	case opts.MemberId > 0:
+		return fmt.Sprintf("「%s」解除了「%s」的禁言", names[opts.UserId], names[opts.MemberId])
+	case opts.Mute:
+		return fmt.Sprintf("「%s」开启了全员禁言(%s)", names[opts.UserId], duration)
+	default:
+		return fmt.Sprintf("「%s」关闭了全员禁言", names[opts.UserId])
+	}
+}
+
+func muteDurationText(second int) string {
+	switch {
+	case second%86400 == 0:
+		return fmt.Sprintf("%d天", second/86400)
+	case second%3600 == 0:
+		return fmt.Sprintf("%d小时", second/3600)
+	default:
+		return fmt.Sprintf("%d分钟", (second+59)/60)
+	}
+}
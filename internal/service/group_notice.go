@@ -2,21 +2,39 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/strutil"
 	"go-chat/internal/pkg/timeutil"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
 )
 
+var (
+	ErrGroupNoticeNotFound  = errors.New("群公告不存在")
+	ErrGroupNoticeNoConfirm = errors.New("该群公告无需确认")
+	ErrGroupNoticeConfirmed = errors.New("已确认过该群公告")
+)
+
+// 群公告发布后的提醒时长，超过后不再提醒未确认成员
+const groupNoticeRemindDays = 7
+
 type GroupNoticeService struct {
-	repo *repo.GroupNotice
+	*BaseService
+	repo       *repo.GroupNotice
+	memberRepo *repo.GroupMember
+	message    *MessageService
 }
 
-func NewGroupNoticeService(repo *repo.GroupNotice) *GroupNoticeService {
-	return &GroupNoticeService{
-		repo: repo,
-	}
+func NewGroupNoticeService(baseService *BaseService, repo *repo.GroupNotice, memberRepo *repo.GroupMember, message *MessageService) *GroupNoticeService {
+	return &GroupNoticeService{BaseService: baseService, repo: repo, memberRepo: memberRepo, message: message}
 }
 
 func (s *GroupNoticeService) Dao() *repo.GroupNotice {
@@ -33,9 +51,10 @@ type GroupNoticeEditOpt struct {
 	IsConfirm int
 }
 
-// Create 创建群公告
+// Create 创建群公告，并在群聊中推送群公告消息
 func (s *GroupNoticeService) Create(ctx context.Context, opts *GroupNoticeEditOpt) error {
-	return s.repo.Db.Create(&model.GroupNotice{
+
+	notice := &model.GroupNotice{
 		GroupId:      opts.GroupId,
 		CreatorId:    opts.UserId,
 		Title:        opts.Title,
@@ -43,21 +62,74 @@ func (s *GroupNoticeService) Create(ctx context.Context, opts *GroupNoticeEditOp
 		IsTop:        opts.IsTop,
 		IsConfirm:    opts.IsConfirm,
 		ConfirmUsers: "{}",
-	}).Error
+	}
+
+	record := s.record(ctx, opts.UserId, notice.GroupId)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(notice).Error; err != nil {
+			return err
+		}
+
+		return s.createRecord(tx, record, notice, false)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.push(ctx, record)
+
+	return nil
 }
 
-// Update 更新群公告
+// Update 更新群公告，标题或内容变更时重置确认状态并推送群公告消息
 func (s *GroupNoticeService) Update(ctx context.Context, opts *GroupNoticeEditOpt) error {
 
-	_, err := s.repo.UpdateWhere(ctx, map[string]interface{}{
+	notice, err := s.repo.FindById(ctx, opts.NoticeId)
+	if err != nil || notice.GroupId != opts.GroupId || notice.IsDelete == 1 {
+		return ErrGroupNoticeNotFound
+	}
+
+	changed := notice.Title != opts.Title || notice.Content != opts.Content
+
+	data := map[string]interface{}{
 		"title":      opts.Title,
 		"content":    opts.Content,
 		"is_top":     opts.IsTop,
 		"is_confirm": opts.IsConfirm,
 		"updated_at": time.Now(),
-	}, "id = ? and group_id = ?", opts.NoticeId, opts.GroupId)
+	}
 
-	return err
+	if changed {
+		data["confirm_users"] = "{}"
+	}
+
+	// 仅修改置顶状态时不推送群公告消息
+	if !changed && notice.IsConfirm == opts.IsConfirm {
+		_, err := s.repo.UpdateWhere(ctx, data, "id = ? and group_id = ?", opts.NoticeId, opts.GroupId)
+		return err
+	}
+
+	notice.Title = opts.Title
+	notice.Content = opts.Content
+	notice.IsConfirm = opts.IsConfirm
+
+	record := s.record(ctx, opts.UserId, notice.GroupId)
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.GroupNotice{}).Where("id = ? and group_id = ?", opts.NoticeId, opts.GroupId).Updates(data).Error; err != nil {
+			return err
+		}
+
+		return s.createRecord(tx, record, notice, true)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.push(ctx, record)
+
+	return nil
 }
 
 func (s *GroupNoticeService) Delete(ctx context.Context, groupId, noticeId int) error {
@@ -70,3 +142,167 @@ func (s *GroupNoticeService) Delete(ctx context.Context, groupId, noticeId int)
 
 	return err
 }
+
+// Confirm 群成员确认群公告
+func (s *GroupNoticeService) Confirm(ctx context.Context, groupId, noticeId, uid int) error {
+
+	notice, err := s.repo.FindById(ctx, noticeId)
+	if err != nil || notice.GroupId != groupId || notice.IsDelete == 1 {
+		return ErrGroupNoticeNotFound
+	}
+
+	if notice.IsConfirm != 1 {
+		return ErrGroupNoticeNoConfirm
+	}
+
+	path := fmt.Sprintf(`$."%d"`, uid)
+
+	// 已确认的成员不覆盖确认时间
+	res := s.repo.Model(ctx).
+		Where("id = ? and JSON_CONTAINS_PATH(IFNULL(confirm_users, '{}'), 'one', ?) = 0", noticeId, path).
+		Update("confirm_users", gorm.Expr("JSON_SET(IFNULL(confirm_users, '{}'), ?, ?)", path, timeutil.DateTime()))
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return ErrGroupNoticeConfirmed
+	}
+
+	return nil
+}
+
+// GroupNoticeConfirmItem 群公告确认情况
+type GroupNoticeConfirmItem struct {
+	UserId    int    `json:"user_id"`
+	Nickname  string `json:"nickname"`
+	Avatar    string `json:"avatar"`
+	UserCard  string `json:"user_card"`
+	ConfirmAt string `json:"confirm_at"`
+}
+
+// ConfirmList 群公告确认情况，返回已确认及未确认的群成员
+func (s *GroupNoticeService) ConfirmList(ctx context.Context, groupId, noticeId int) ([]*GroupNoticeConfirmItem, []*GroupNoticeConfirmItem, error) {
+
+	notice, err := s.repo.FindById(ctx, noticeId)
+	if err != nil || notice.GroupId != groupId || notice.IsDelete == 1 {
+		return nil, nil, ErrGroupNoticeNotFound
+	}
+
+	confirmUsers := s.confirmUsers(notice)
+
+	confirmed := make([]*GroupNoticeConfirmItem, 0)
+	unconfirmed := make([]*GroupNoticeConfirmItem, 0)
+	for _, member := range s.memberRepo.GetMembers(ctx, groupId) {
+		uid, _ := strconv.Atoi(member.UserId)
+
+		item := &GroupNoticeConfirmItem{
+			UserId:   uid,
+			Nickname: member.Nickname,
+			Avatar:   member.Avatar,
+			UserCard: member.UserCard,
+		}
+
+		if datetime, ok := confirmUsers[uid]; ok {
+			item.ConfirmAt = datetime
+			confirmed = append(confirmed, item)
+		} else if uid != notice.CreatorId {
+			unconfirmed = append(unconfirmed, item)
+		}
+	}
+
+	return confirmed, unconfirmed, nil
+}
+
+// Remind 提醒未确认群公告的群成员
+func (s *GroupNoticeService) Remind(ctx context.Context) error {
+
+	notices, err := s.repo.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("is_confirm = 1 and is_delete = 0 and updated_at >= ?", time.Now().AddDate(0, 0, -groupNoticeRemindDays))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, notice := range notices {
+		confirmUsers := s.confirmUsers(notice)
+
+		receivers := make([]int, 0)
+		for _, uid := range s.memberRepo.GetMemberIds(ctx, notice.GroupId) {
+			if _, ok := confirmUsers[uid]; !ok && uid != notice.CreatorId {
+				receivers = append(receivers, uid)
+			}
+		}
+
+		if len(receivers) == 0 {
+			continue
+		}
+
+		if err := s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(map[string]interface{}{
+			"event": entity.EventGroupNoticeRemind,
+			"data": jsonutil.Encode(map[string]interface{}{
+				"group_id":  notice.GroupId,
+				"notice_id": notice.Id,
+				"title":     notice.Title,
+				"receivers": receivers,
+			}),
+		})).Err(); err != nil {
+			logger.Error(fmt.Sprintf("[GroupNotice] 群公告提醒推送失败 %s", err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// confirmUsers 解析已确认成员，格式为 {"用户ID":"确认时间"}
+func (s *GroupNoticeService) confirmUsers(notice *model.GroupNotice) map[int]string {
+
+	values := make(map[string]string)
+	if notice.ConfirmUsers != "" {
+		_ = jsonutil.Decode(notice.ConfirmUsers, &values)
+	}
+
+	items := make(map[int]string, len(values))
+	for key, datetime := range values {
+		if uid, err := strconv.Atoi(key); err == nil {
+			items[uid] = datetime
+		}
+	}
+
+	return items
+}
+
+// record 群公告消息记录
+func (s *GroupNoticeService) record(ctx context.Context, uid int, groupId int) *model.TalkRecords {
+	return &model.TalkRecords{
+		MsgId:      strutil.NewUuid(),
+		Sequence:   s.message.Sequence.Get(ctx, entity.ChatGroupMode, 0, groupId),
+		TalkType:   entity.ChatGroupMode,
+		MsgType:    entity.MsgTypeGroupNotice,
+		UserId:     uid,
+		ReceiverId: groupId,
+	}
+}
+
+// createRecord 保存群公告消息，需与群公告在同一事务中调用
+func (s *GroupNoticeService) createRecord(tx *gorm.DB, record *model.TalkRecords, notice *model.GroupNotice, isUpdate bool) error {
+
+	if err := tx.Create(record).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&model.TalkRecordsNotice{
+		RecordId:  record.Id,
+		NoticeId:  notice.Id,
+		Title:     notice.Title,
+		Content:   notice.Content,
+		IsConfirm: notice.IsConfirm,
+		IsUpdate:  strutil.BoolToInt(isUpdate),
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+// push 在群聊中推送群公告消息
+func (s *GroupNoticeService) push(ctx context.Context, record *model.TalkRecords) {
+	s.message.afterHandle(ctx, record, map[string]string{"text": "[群公告]"})
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
+)
+
+const groupRoleMaxNum = 20 // 每个群组最多可创建的自定义角色数
+
+var (
+	ErrGroupRoleNotFound   = errors.New("角色不存在！")
+	ErrGroupRoleLimit      = errors.New("自定义角色数量已达上限！")
+	ErrGroupRolePermission = errors.New("包含不可授予的权限！")
+)
+
+type GroupRoleEditOpt struct {
+	GroupId     int
+	RoleId      int
+	Name        string
+	Permissions []string
+}
+
+type GroupRoleService struct {
+	*BaseService
+	repo *repo.GroupRole
+}
+
+func NewGroupRoleService(baseService *BaseService, repo *repo.GroupRole) *GroupRoleService {
+	return &GroupRoleService{BaseService: baseService, repo: repo}
+}
+
+func (s *GroupRoleService) Dao() *repo.GroupRole {
+	return s.repo
+}
+
+// Create 创建自定义角色
+func (s *GroupRoleService) Create(ctx context.Context, opts *GroupRoleEditOpt) (*model.GroupRole, error) {
+
+	perms, err := s.permissions(opts.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	num, err := s.repo.QueryCount(ctx, "group_id = ?", opts.GroupId)
+	if err != nil {
+		return nil, err
+	}
+
+	if num >= groupRoleMaxNum {
+		return nil, ErrGroupRoleLimit
+	}
+
+	role := &model.GroupRole{
+		GroupId:     opts.GroupId,
+		Name:        opts.Name,
+		Permissions: perms,
+	}
+
+	if err := s.repo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// Update 修改自定义角色
+func (s *GroupRoleService) Update(ctx context.Context, opts *GroupRoleEditOpt) error {
+
+	perms, err := s.permissions(opts.Permissions)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.find(ctx, opts.GroupId, opts.RoleId); err != nil {
+		return err
+	}
+
+	_, err = s.repo.UpdateById(ctx, opts.RoleId, map[string]interface{}{
+		"name":        opts.Name,
+		"permissions": perms,
+	})
+
+	return err
+}
+
+// Delete 删除自定义角色，并解除成员的角色关联
+func (s *GroupRoleService) Delete(ctx context.Context, groupId int, roleId int) error {
+
+	if _, err := s.find(ctx, groupId, roleId); err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.GroupRole{}, roleId).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.GroupMember{}).Where("group_id = ? and role_id = ?", groupId, roleId).Update("role_id", 0).Error
+	})
+}
+
+// Assign 设置群成员的自定义角色，roleId 为 0 时取消角色
+func (s *GroupRoleService) Assign(ctx context.Context, groupId int, roleId int, memberIds []int) error {
+
+	if roleId > 0 {
+		if _, err := s.find(ctx, groupId, roleId); err != nil {
+			return err
+		}
+	}
+
+	return s.db.WithContext(ctx).Model(&model.GroupMember{}).
+		Where("group_id = ? and user_id in ? and is_quit = 0 and leader != ?", groupId, memberIds, entity.GroupLeaderOwner).
+		Update("role_id", roleId).Error
+}
+
+func (s *GroupRoleService) find(ctx context.Context, groupId int, roleId int) (*model.GroupRole, error) {
+
+	role, err := s.repo.FindById(ctx, roleId)
+	if err != nil || role.GroupId != groupId {
+		return nil, ErrGroupRoleNotFound
+	}
+
+	return role, nil
+}
+
+// permissions 校验并去重权限列表
+func (s *GroupRoleService) permissions(items []string) (string, error) {
+
+	perms := make([]string, 0, len(items))
+	for _, perm := range items {
+		if !entity.IsGroupGrantablePerm(perm) {
+			return "", ErrGroupRolePermission
+		}
+
+		if !hasPermission(perms, perm) {
+			perms = append(perms, perm)
+		}
+	}
+
+	return strings.Join(perms, ","), nil
+}
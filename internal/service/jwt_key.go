@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-chat/config"
+	"go-chat/internal/pkg/jwt"
+	"go-chat/internal/repository/cache"
+)
+
+// 本地缓存的密钥定时回源，以便及时感知密钥退役
+const jwtKeyCacheTime = time.Minute
+
+type jwtKeyItem struct {
+	key      *jwt.Key
+	loadedAt time.Time
+}
+
+// JwtKeyService JWT 签名密钥管理，负责签发、按 kid 验签及密钥轮换
+type JwtKeyService struct {
+	conf    *config.Config
+	storage *cache.JwtKeyStorage
+	lock    *cache.RedisLock
+	mu      sync.RWMutex
+	items   map[string]*jwtKeyItem
+}
+
+func NewJwtKeyService(conf *config.Config, storage *cache.JwtKeyStorage, lock *cache.RedisLock) *JwtKeyService {
+	return &JwtKeyService{conf: conf, storage: storage, lock: lock, items: make(map[string]*jwtKeyItem)}
+}
+
+// IsAsymmetric 是否启用非对称签名
+func (s *JwtKeyService) IsAsymmetric() bool {
+	return s.conf.Jwt.GetAlgorithm() != jwt.AlgorithmHS256
+}
+
+// GenerateToken 生成 JWT 令牌
+func (s *JwtKeyService) GenerateToken(ctx context.Context, guard string, ops *jwt.Options) (string, error) {
+
+	if !s.IsAsymmetric() {
+		return jwt.GenerateToken(guard, s.conf.Jwt.Secret, ops), nil
+	}
+
+	key, err := s.Current(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return jwt.GenerateTokenWithKey(guard, key, ops)
+}
+
+// ParseToken 解析 JWT 令牌
+func (s *JwtKeyService) ParseToken(ctx context.Context, token string) (*jwt.AuthClaims, error) {
+	return jwt.ParseTokenWithKey(ctx, token, s.conf.Jwt.Secret, s)
+}
+
+// Current 获取当前签名密钥，不存在或已到轮换周期时生成新密钥
+func (s *JwtKeyService) Current(ctx context.Context) (*jwt.Key, error) {
+
+	var current *jwt.Key
+	if kid := s.storage.GetCurrent(ctx); kid != "" {
+		if key, err := s.find(ctx, kid); err == nil && s.isUsable(key) {
+			current = key
+		}
+	}
+
+	if current != nil && !s.isDue(current) {
+		return current, nil
+	}
+
+	if err := s.Rotate(ctx, false); err != nil {
+		// 轮换失败时继续使用当前密钥，等待下次轮换
+		if current != nil {
+			return current, nil
+		}
+
+		return nil, err
+	}
+
+	kid := s.storage.GetCurrent(ctx)
+	if kid == "" {
+		return nil, jwt.ErrKeyNotFound
+	}
+
+	return s.find(ctx, kid)
+}
+
+// FindKey 根据 kid 查找验签密钥，超出宽限期的退役密钥视为不存在
+func (s *JwtKeyService) FindKey(ctx context.Context, kid string) (*jwt.Key, error) {
+
+	key, err := s.find(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.IsExpired(s.grace()) {
+		return nil, jwt.ErrKeyNotFound
+	}
+
+	return key, nil
+}
+
+// Keys 获取可用于验签的全部密钥
+func (s *JwtKeyService) Keys(ctx context.Context) ([]*jwt.Key, error) {
+
+	items, err := s.storage.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*jwt.Key, 0, len(items))
+	for _, key := range items {
+		if !key.IsExpired(s.grace()) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// Rotate 轮换签名密钥，force 为 false 时仅在当前密钥到达轮换周期后轮换
+// 旧密钥标记为退役并在宽限期内继续用于验签，超出宽限期的密钥将被删除
+func (s *JwtKeyService) Rotate(ctx context.Context, force bool) error {
+
+	if !s.IsAsymmetric() {
+		return nil
+	}
+
+	if !s.lock.Lock(ctx, "jwt-key-rotate", 10) {
+		return errors.New("签名密钥正在轮换中，请稍后再试")
+	}
+
+	defer s.lock.UnLock(ctx, "jwt-key-rotate")
+
+	items, err := s.storage.All(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := s.storage.GetCurrent(ctx)
+
+	var (
+		expired []string
+		retired []*jwt.Key
+		now     = time.Now()
+	)
+
+	for _, key := range items {
+		if key.Kid == current && !key.IsRetired() {
+			if !force && s.isUsable(key) && !s.isDue(key) {
+				return nil
+			}
+		}
+
+		if key.IsExpired(s.grace()) {
+			expired = append(expired, key.Kid)
+		} else if !key.IsRetired() {
+			key.RetiredAt = now
+			retired = append(retired, key)
+		}
+	}
+
+	key, err := jwt.GenerateKey(s.conf.Jwt.GetAlgorithm())
+	if err != nil {
+		return err
+	}
+
+	if err := s.storage.Save(ctx, append(retired, key)...); err != nil {
+		return err
+	}
+
+	if err := s.storage.SetCurrent(ctx, key.Kid); err != nil {
+		return err
+	}
+
+	if len(expired) > 0 {
+		_ = s.storage.Del(ctx, expired...)
+	}
+
+	s.mu.Lock()
+	s.items = make(map[string]*jwtKeyItem)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *JwtKeyService) find(ctx context.Context, kid string) (*jwt.Key, error) {
+
+	s.mu.RLock()
+	item, ok := s.items[kid]
+	s.mu.RUnlock()
+
+	if ok && time.Since(item.loadedAt) < jwtKeyCacheTime {
+		return item.key, nil
+	}
+
+	key, err := s.storage.Get(ctx, kid)
+	if err != nil {
+		return nil, jwt.ErrKeyNotFound
+	}
+
+	s.mu.Lock()
+	s.items[kid] = &jwtKeyItem{key: key, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return key, nil
+}
+
+// isUsable 密钥是否可用于签名
+func (s *JwtKeyService) isUsable(key *jwt.Key) bool {
+	return key.PrivateKey != nil && !key.IsRetired() && key.Algorithm == s.conf.Jwt.GetAlgorithm()
+}
+
+func (s *JwtKeyService) isDue(key *jwt.Key) bool {
+	return time.Since(key.CreatedAt) >= time.Duration(s.conf.Jwt.GetRotateTime())*time.Second
+}
+
+func (s *JwtKeyService) grace() time.Duration {
+	return time.Duration(s.conf.Jwt.GetGraceTime()) * time.Second
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/jwt"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/moderation"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/repo"
+)
+
+var (
+	ErrLivePermission = errors.New("暂无权限操作聊天室")
+	ErrLiveBanned     = errors.New("已被管理员禁止发言")
+	ErrLiveSlowMode   = errors.New("聊天室已开启慢速模式，请稍后再发言")
+	ErrLiveContent    = errors.New("消息内容不能为空且不能超过 500 个字符")
+	ErrLiveGuestToken = errors.New("游客令牌无效")
+)
+
+// 游客令牌有效期
+const liveGuestExpire = 12 * time.Hour
+
+type LiveRoomCreateOpts struct {
+	UserId   int
+	Name     string
+	SlowMode int
+}
+
+type LiveRoomService struct {
+	*BaseService
+	storage    *cache.LiveRoomStorage
+	userRepo   *repo.Users
+	jwtKey     *JwtKeyService
+	moderation *ModerationService
+}
+
+func NewLiveRoomService(baseService *BaseService, storage *cache.LiveRoomStorage, userRepo *repo.Users, jwtKey *JwtKeyService, moderation *ModerationService) *LiveRoomService {
+	return &LiveRoomService{BaseService: baseService, storage: storage, userRepo: userRepo, jwtKey: jwtKey, moderation: moderation}
+}
+
+func (s *LiveRoomService) Storage() *cache.LiveRoomStorage {
+	return s.storage
+}
+
+// Create 创建聊天室，创建者默认为管理员
+func (s *LiveRoomService) Create(ctx context.Context, opts *LiveRoomCreateOpts) (*cache.LiveRoom, error) {
+
+	code, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+
+	room := &cache.LiveRoom{
+		Code:      code,
+		Name:      opts.Name,
+		OwnerId:   opts.UserId,
+		SlowMode:  opts.SlowMode,
+		CreatedAt: timeutil.DateTime(),
+	}
+
+	if err := s.storage.Create(ctx, room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// Close 关闭聊天室，仅创建者可操作
+func (s *LiveRoomService) Close(ctx context.Context, code string, uid int) error {
+
+	room, err := s.storage.Get(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	if room.OwnerId != uid {
+		return ErrLivePermission
+	}
+
+	s.publish(ctx, entity.EventLiveClose, entity.H{"room": code})
+
+	return s.storage.Delete(ctx, code)
+}
+
+// SetSlowMode 修改慢速模式，管理员可操作
+func (s *LiveRoomService) SetSlowMode(ctx context.Context, code string, uid int, second int) error {
+
+	if _, err := s.authorize(ctx, code, uid); err != nil {
+		return err
+	}
+
+	if err := s.storage.SetSlowMode(ctx, code, second); err != nil {
+		return err
+	}
+
+	s.publish(ctx, entity.EventLiveSetting, entity.H{"room": code, "slow_mode": second})
+
+	return nil
+}
+
+// SetModerator 设置或取消管理员，仅创建者可操作
+func (s *LiveRoomService) SetModerator(ctx context.Context, code string, uid int, memberUid int, isModerator bool) error {
+
+	room, err := s.storage.Get(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	if room.OwnerId != uid || memberUid == uid {
+		return ErrLivePermission
+	}
+
+	if err := s.storage.SetModerator(ctx, code, memberUid, isModerator); err != nil {
+		return err
+	}
+
+	s.publish(ctx, entity.EventLiveSetting, entity.H{"room": code, "moderators": s.storage.Moderators(ctx, code)})
+
+	return nil
+}
+
+// DeleteMessage 删除聊天室消息，管理员可操作
+func (s *LiveRoomService) DeleteMessage(ctx context.Context, code string, uid int, msgId string) error {
+
+	if _, err := s.authorize(ctx, code, uid); err != nil {
+		return err
+	}
+
+	s.storage.DelMessage(ctx, code, msgId)
+
+	s.publish(ctx, entity.EventLiveDelete, entity.H{"room": code, "msg_id": msgId})
+
+	return nil
+}
+
+// Ban 禁止成员发言，管理员可操作
+func (s *LiveRoomService) Ban(ctx context.Context, code string, uid int, memberId string) error {
+
+	room, err := s.authorize(ctx, code, uid)
+	if err != nil {
+		return err
+	}
+
+	// 创建者不能被禁言
+	if memberId == liveMemberId(room.OwnerId) {
+		return ErrLivePermission
+	}
+
+	return s.storage.Ban(ctx, code, memberId)
+}
+
+// Send 发送聊天室消息，消息仅保存在缓存中
+func (s *LiveRoomService) Send(ctx context.Context, code string, member *cache.LiveMember, content string) (*cache.LiveMessage, error) {
+
+	if content == "" || utf8.RuneCountInString(content) > 500 {
+		return nil, ErrLiveContent
+	}
+
+	room, err := s.storage.Get(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.storage.IsBanned(ctx, code, member.Id) {
+		return nil, ErrLiveBanned
+	}
+
+	// 管理员不受慢速模式限制
+	if !s.storage.IsModerator(ctx, code, member.Uid) && !s.storage.Allow(ctx, code, member.Id, room.SlowMode) {
+		return nil, ErrLiveSlowMode
+	}
+
+	// 聊天室消息不落库，需人工审核的消息直接放行
+	result, err := s.moderation.Check(ctx, &moderation.Message{
+		UserId:  member.Uid,
+		Content: content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Action == moderation.ActionBlock {
+		return nil, ErrMessageBlocked
+	}
+
+	message := &cache.LiveMessage{
+		MsgId:     strutil.NewUuid(),
+		Room:      code,
+		Sender:    member,
+		Content:   html.EscapeString(result.Content),
+		CreatedAt: timeutil.DateTime(),
+	}
+
+	if err := s.storage.Push(ctx, message); err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, entity.EventLiveMessage, message)
+
+	return message, nil
+}
+
+// Join 客户端进入聊天室
+func (s *LiveRoomService) Join(ctx context.Context, sid string, code string, cid int64, member *cache.LiveMember) error {
+
+	online, err := s.storage.Join(ctx, sid, code, cid)
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, entity.EventLiveJoin, entity.H{"room": code, "member": member, "online": online})
+
+	return nil
+}
+
+// Leave 客户端离开聊天室，聊天室无人在线时自动销毁
+func (s *LiveRoomService) Leave(ctx context.Context, sid string, code string, cid int64, member *cache.LiveMember) {
+
+	online, err := s.storage.Leave(ctx, sid, code, cid)
+	if err != nil {
+		logger.Error(fmt.Sprintf("[Live] 离开聊天室失败 %s", err.Error()))
+		return
+	}
+
+	// 无人在线时聊天室已在离开时原子销毁
+	if online <= 0 {
+		return
+	}
+
+	s.publish(ctx, entity.EventLiveLeave, entity.H{"room": code, "member": member, "online": online})
+}
+
+// GuestToken 生成游客令牌，令牌仅可进入指定聊天室
+func (s *LiveRoomService) GuestToken(ctx context.Context, code string, nickname string) (string, error) {
+
+	if _, err := s.storage.Get(ctx, code); err != nil {
+		return "", err
+	}
+
+	guestId, err := randomHex(8)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.storage.SetGuest(ctx, guestId, nickname, liveGuestExpire); err != nil {
+		return "", err
+	}
+
+	return s.jwtKey.GenerateToken(ctx, entity.LiveGuestGuard, &jwt.Options{
+		Audience:  []string{code},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(liveGuestExpire)),
+		ID:        "0",
+		Subject:   guestId,
+		Issuer:    "im.live",
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	})
+}
+
+// Member 根据授权信息获取聊天室成员，guestId 不为空时为游客
+func (s *LiveRoomService) Member(ctx context.Context, uid int, guestId string) (*cache.LiveMember, error) {
+
+	if guestId != "" {
+		nickname, err := s.storage.GetGuest(ctx, guestId)
+		if err != nil {
+			return nil, ErrLiveGuestToken
+		}
+
+		return &cache.LiveMember{Id: "g_" + guestId, Nickname: nickname}, nil
+	}
+
+	user, err := s.userRepo.FindById(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cache.LiveMember{
+		Id:       liveMemberId(uid),
+		Uid:      uid,
+		Nickname: user.Nickname,
+		Avatar:   user.Avatar,
+	}, nil
+}
+
+// ParseGuestToken 解析游客令牌，返回游客标识
+func (s *LiveRoomService) ParseGuestToken(claims *jwt.AuthClaims, code string) (string, error) {
+
+	if claims.Guard != entity.LiveGuestGuard || claims.Subject == "" || !claims.VerifyAudience(code, true) {
+		return "", ErrLiveGuestToken
+	}
+
+	return claims.Subject, nil
+}
+
+// authorize 校验聊天室管理员权限
+func (s *LiveRoomService) authorize(ctx context.Context, code string, uid int) (*cache.LiveRoom, error) {
+
+	room, err := s.storage.Get(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.storage.IsModerator(ctx, code, uid) {
+		return nil, ErrLivePermission
+	}
+
+	return room, nil
+}
+
+func (s *LiveRoomService) publish(ctx context.Context, event string, data interface{}) {
+	if err := s.rds.Publish(ctx, entity.ImTopicLive, jsonutil.Encode(entity.H{
+		"event": event,
+		"data":  jsonutil.Encode(data),
+	})).Err(); err != nil {
+		logger.Error(fmt.Sprintf("[Live] 消息推送失败 %s", err.Error()))
+	}
+}
+
+func liveMemberId(uid int) string {
+	return "u_" + strconv.Itoa(uid)
+}
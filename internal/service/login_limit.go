@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go-chat/api/pb/message/v1"
+	"go-chat/internal/repository/cache"
+)
+
+const (
+	LoginLimitSceneWeb   = "web"
+	LoginLimitSceneAdmin = "admin"
+)
+
+const (
+	loginFailWindow     = 15 * time.Minute // 登录失败统计窗口
+	loginCaptchaFails   = 3                // 账号失败达到该次数后需要图形验证码
+	loginCaptchaIpFails = 10               // IP 失败达到该次数后需要图形验证码
+	loginDelayFails     = 3                // 账号失败达到该次数后开始递增延迟
+	loginMaxDelay       = 30 * time.Second // 最大延迟
+	loginLockFails      = 10               // 账号失败达到该次数后临时锁定
+	loginLockTime       = 15 * time.Minute // 首次锁定时长，之后每次翻倍
+	loginMaxLockTime    = 24 * time.Hour   // 最大锁定时长
+	loginLockWindow     = 24 * time.Hour   // 锁定次数统计窗口
+	loginIpLockFails    = 50               // IP 失败达到该次数后临时锁定
+	loginIpLockTime     = 30 * time.Minute // IP 锁定时长
+	smsMobileInterval   = time.Minute      // 同一手机号发送间隔
+	smsMobileHourLimit  = 5                // 同一手机号每小时发送上限
+	smsMobileDayLimit   = 10               // 同一手机号每天发送上限
+	smsIpHourLimit      = 20               // 同一 IP 每小时发送上限
+	smsIpDayLimit       = 50               // 同一 IP 每天发送上限
+)
+
+type LoginLimitOpts struct {
+	Scene    string // 登录场景
+	Account  string // 登录账号
+	Ip       string // 客户端 IP
+	UserId   int    // 账号对应的用户ID，用于推送安全提醒，未知时为 0
+	Platform string
+	Agent    string
+}
+
+type LoginLimitState struct {
+	Fails           int64         // 窗口内账号失败次数
+	CaptchaRequired bool          // 是否需要图形验证码
+	LockTTL         time.Duration // 剩余锁定时长
+}
+
+// LoginLimitError 账号或 IP 已被临时锁定
+type LoginLimitError struct {
+	TTL time.Duration
+}
+
+func (e *LoginLimitError) Error() string {
+	return fmt.Sprintf("登录失败次数过多，请 %s 后再试", formatLimitDuration(e.TTL))
+}
+
+// LoginLimitService 登录防暴力破解，按账号及 IP 统计失败次数，
+// 失败后递增延迟、要求图形验证码，超出次数后临时锁定
+type LoginLimitService struct {
+	storage   *cache.RateLimitStorage
+	message   *MessageService
+	ipAddress *IpAddressService
+}
+
+func NewLoginLimitService(storage *cache.RateLimitStorage, message *MessageService, ipAddress *IpAddressService) *LoginLimitService {
+	return &LoginLimitService{storage: storage, message: message, ipAddress: ipAddress}
+}
+
+// Check 登录前校验是否允许尝试
+func (s *LoginLimitService) Check(ctx context.Context, opts *LoginLimitOpts) (*LoginLimitState, error) {
+
+	for _, key := range []string{s.accountKey(opts), s.ipKey(opts)} {
+		if ttl := s.storage.LockTTL(ctx, key); ttl > 0 {
+			return &LoginLimitState{LockTTL: ttl}, &LoginLimitError{TTL: ttl}
+		}
+	}
+
+	state := &LoginLimitState{Fails: s.storage.Count(ctx, s.accountKey(opts), loginFailWindow)}
+	state.CaptchaRequired = state.Fails >= loginCaptchaFails || s.storage.Count(ctx, s.ipKey(opts), loginFailWindow) >= loginCaptchaIpFails
+
+	return state, nil
+}
+
+// Fail 记录一次登录失败，返回失败后的状态
+func (s *LoginLimitService) Fail(ctx context.Context, opts *LoginLimitOpts) *LoginLimitState {
+
+	fails, _ := s.storage.Incr(ctx, s.accountKey(opts), loginFailWindow)
+	ipFails, _ := s.storage.Incr(ctx, s.ipKey(opts), loginFailWindow)
+
+	state := &LoginLimitState{
+		Fails:           fails,
+		CaptchaRequired: fails >= loginCaptchaFails || ipFails >= loginCaptchaIpFails,
+	}
+
+	if ipFails >= loginIpLockFails {
+		_ = s.storage.Lock(ctx, s.ipKey(opts), loginIpLockTime)
+		state.LockTTL = loginIpLockTime
+	}
+
+	switch {
+	case fails >= loginLockFails:
+		locks, _ := s.storage.Incr(ctx, s.accountKey(opts)+":locks", loginLockWindow)
+
+		ttl := time.Duration(float64(loginLockTime) * math.Pow(2, float64(locks-1)))
+		if ttl > loginMaxLockTime {
+			ttl = loginMaxLockTime
+		}
+
+		_ = s.storage.Lock(ctx, s.accountKey(opts), ttl)
+		_ = s.storage.Clear(ctx, s.accountKey(opts))
+
+		if ttl > state.LockTTL {
+			state.LockTTL = ttl
+		}
+
+		s.notify(ctx, opts, fmt.Sprintf("连续 %d 次登录失败，账号已临时锁定 %s", fails, formatLimitDuration(ttl)))
+	case fails >= loginDelayFails:
+		// 递增延迟，锁定期内拒绝继续尝试
+		delay := time.Duration(math.Pow(2, float64(fails-loginDelayFails))) * time.Second
+		if delay > loginMaxDelay {
+			delay = loginMaxDelay
+		}
+
+		_ = s.storage.Lock(ctx, s.accountKey(opts), delay)
+
+		if delay > state.LockTTL {
+			state.LockTTL = delay
+		}
+	}
+
+	return state
+}
+
+// Success 登录成功后清空账号失败记录，此前存在失败记录时推送安全提醒
+func (s *LoginLimitService) Success(ctx context.Context, opts *LoginLimitOpts) {
+
+	fails := s.storage.Count(ctx, s.accountKey(opts), loginFailWindow)
+
+	_ = s.storage.Clear(ctx, s.accountKey(opts))
+
+	if fails > 0 {
+		s.notify(ctx, opts, fmt.Sprintf("登录成功前有 %d 次密码错误的登录尝试", fails))
+	}
+}
+
+// AllowSms 校验短信发送频率，按手机号及 IP 限制
+func (s *LoginLimitService) AllowSms(ctx context.Context, mobile string, ip string) error {
+
+	rules := []struct {
+		key    string
+		limit  int64
+		window time.Duration
+	}{
+		{"sms:mobile:" + mobile + ":interval", 1, smsMobileInterval},
+		{"sms:mobile:" + mobile + ":hour", smsMobileHourLimit, time.Hour},
+		{"sms:mobile:" + mobile + ":day", smsMobileDayLimit, 24 * time.Hour},
+		{"sms:ip:" + ip + ":hour", smsIpHourLimit, time.Hour},
+		{"sms:ip:" + ip + ":day", smsIpDayLimit, 24 * time.Hour},
+	}
+
+	for _, rule := range rules {
+		// 先判断再记录，避免被拒绝的请求占用额度
+		if s.storage.Count(ctx, rule.key, rule.window) >= rule.limit {
+			if rule.window == smsMobileInterval {
+				return fmt.Errorf("短信发送过于频繁，请 %s 后再试", formatLimitDuration(rule.window))
+			}
+
+			return fmt.Errorf("短信发送次数已达上限，请稍后再试")
+		}
+	}
+
+	for _, rule := range rules {
+		ok, err := s.storage.Allow(ctx, rule.key, rule.limit, rule.window)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return fmt.Errorf("短信发送次数已达上限，请稍后再试")
+		}
+	}
+
+	return nil
+}
+
+func (s *LoginLimitService) accountKey(opts *LoginLimitOpts) string {
+	return fmt.Sprintf("login:%s:account:%s", opts.Scene, opts.Account)
+}
+
+func (s *LoginLimitService) ipKey(opts *LoginLimitOpts) string {
+	return fmt.Sprintf("login:%s:ip:%s", opts.Scene, opts.Ip)
+}
+
+// notify 通过登录助手推送账号安全提醒
+func (s *LoginLimitService) notify(ctx context.Context, opts *LoginLimitOpts, reason string) {
+
+	if opts.UserId == 0 || opts.Scene != LoginLimitSceneWeb {
+		return
+	}
+
+	address, err := s.ipAddress.FindAddress(opts.Ip)
+	if err != nil || address == "" {
+		address = "未知"
+	}
+
+	_ = s.message.SendLogin(ctx, opts.UserId, &message.LoginMessageRequest{
+		Ip:       opts.Ip,
+		Address:  address,
+		Platform: opts.Platform,
+		Agent:    opts.Agent,
+		Reason:   reason,
+	})
+}
+
+func formatLimitDuration(value time.Duration) string {
+
+	if value >= time.Hour {
+		return fmt.Sprintf("%d 小时", int(math.Ceil(value.Hours())))
+	}
+
+	if value >= time.Minute {
+		return fmt.Sprintf("%d 分钟", int(math.Ceil(value.Minutes())))
+	}
+
+	return fmt.Sprintf("%d 秒", int(math.Ceil(value.Seconds())))
+}
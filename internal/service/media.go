@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/filesystem"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/media"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/repository/model"
+)
+
+const mediaProbeMaxSize = 100 << 20 // 服务端探测的最大文件大小
+
+var mediaFileTypes = map[string]int{
+	media.KindImage: entity.MediaFileImage,
+	media.KindAudio: entity.MediaFileAudio,
+	media.KindVideo: entity.MediaFileVideo,
+}
+
+// 未配置图片处理参数时的默认值
+var defaultImageProcess = config.ImageProcess{
+	MaxDimension: 2048,
+	Quality:      85,
+	Renditions:   []config.ImageRendition{{Name: "thumb", Size: 240}, {Name: "medium", Size: 1080}},
+}
+
+// ImageRendition 图片规格信息
+type ImageRendition struct {
+	Name   string `json:"name"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Path   string `json:"-"`
+	Url    string `json:"url"`
+}
+
+// ImageResult 图片处理结果
+type ImageResult struct {
+	Path       string
+	Url        string
+	Ext        string
+	Width      int
+	Height     int
+	Size       int
+	BlobId     int // 文件存储ID，按内容去重存储时有效
+	Renditions []*ImageRendition
+}
+
+type MediaService struct {
+	conf       *config.Config
+	fileSystem *filesystem.Filesystem
+	blob       *FileBlobService
+}
+
+func NewMediaService(conf *config.Config, fileSystem *filesystem.Filesystem, blob *FileBlobService) *MediaService {
+	return &MediaService{conf: conf, fileSystem: fileSystem, blob: blob}
+}
+
+// ReadStream 读取已存储的媒体文件，超出探测大小限制的文件不读取
+func (s *MediaService) ReadStream(drive int, filePath string) ([]byte, error) {
+	adapter, err := s.fileSystem.Drive(entity.FileDriveName(drive))
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := adapter.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if stat.Size > mediaProbeMaxSize {
+		return nil, fmt.Errorf("媒体文件过大，无法解析[%d]", stat.Size)
+	}
+
+	return adapter.ReadStream(filePath)
+}
+
+// Analyse 探测媒体文件信息并写入文件记录，图片文件会额外生成多尺寸规格图
+func (s *MediaService) Analyse(file *model.TalkRecordsFile, stream []byte) (*media.Meta, error) {
+	meta, err := media.Probe(stream)
+	if err != nil {
+		return nil, err
+	}
+
+	file.Type = mediaFileTypes[meta.Kind]
+	file.Duration = meta.Seconds()
+	file.Width = meta.Width
+	file.Height = meta.Height
+	file.Codec = meta.Codec
+
+	if meta.Kind == media.KindImage {
+		opts := s.imageOptions()
+		opts.MaxDimension = 0
+
+		processed, err := media.ProcessImage(stream, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		renditions, err := s.writeRenditions(processed, file.Path, file.Url, file.BlobId)
+		if err != nil {
+			return nil, err
+		}
+
+		s.setRenditions(file, renditions)
+	}
+
+	return meta, nil
+}
+
+// AnalyseExpect 探测媒体文件信息，并校验是否为指定的媒体类型
+func (s *MediaService) AnalyseExpect(file *model.TalkRecordsFile, stream []byte, kind string) error {
+	meta, err := s.Analyse(file, stream)
+	if err != nil {
+		return err
+	}
+
+	if meta.Kind != kind {
+		return errors.New("媒体文件类型不正确")
+	}
+
+	return nil
+}
+
+// ProcessImage 处理上传图片(自动旋转、去除 EXIF 信息、压缩原图)，并生成多尺寸规格图写入 dir 目录
+func (s *MediaService) ProcessImage(stream []byte, dir string) (*ImageResult, error) {
+	return s.processImage(stream, func(original *media.ImageOutput) (string, int, error) {
+		filePath := path.Join(dir, strutil.GenImageName(original.Ext, original.Width, original.Height))
+
+		return filePath, 0, s.fileSystem.Default.Write(original.Data, filePath)
+	})
+}
+
+// StoreImage 处理上传图片，处理后的原图按内容去重存储
+// 注:返回的文件未增加引用次数，调用方需在创建引用记录的事务中调用 Retain()
+func (s *MediaService) StoreImage(ctx context.Context, stream []byte) (*ImageResult, error) {
+	return s.processImage(stream, func(original *media.ImageOutput) (string, int, error) {
+		blob, err := s.blob.Store(ctx, original.Data, original.Ext, true)
+		if err != nil {
+			return "", 0, err
+		}
+
+		return blob.Path, blob.Id, nil
+	})
+}
+
+func (s *MediaService) processImage(stream []byte, store func(original *media.ImageOutput) (string, int, error)) (*ImageResult, error) {
+	processed, err := media.ProcessImage(stream, s.imageOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	original := processed.Original
+
+	filePath, blobId, err := store(original)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImageResult{
+		Path:   filePath,
+		Url:    s.fileSystem.Default.PublicUrl(filePath),
+		Ext:    original.Ext,
+		Width:  original.Width,
+		Height: original.Height,
+		Size:   len(original.Data),
+		BlobId: blobId,
+	}
+
+	if result.Renditions, err = s.writeRenditions(processed, result.Path, result.Url, blobId); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ApplyImage 将图片处理结果写入文件记录
+func (s *MediaService) ApplyImage(file *model.TalkRecordsFile, result *ImageResult) {
+	file.Type = entity.MediaFileImage
+	file.Suffix = result.Ext
+	file.Size = result.Size
+	file.Path = result.Path
+	file.Url = result.Url
+	file.Width = result.Width
+	file.Height = result.Height
+	file.Codec = result.Ext
+	file.BlobId = result.BlobId
+
+	s.setRenditions(file, result.Renditions)
+}
+
+// writeRenditions 写入规格图，规格图与原图尺寸一致时直接使用原图地址
+// 原图为去重存储的文件时，规格图记录为其衍生文件，随原图一起回收
+func (s *MediaService) writeRenditions(processed *media.ProcessedImage, filePath, fileUrl string, blobId int) ([]*ImageRendition, error) {
+	items := make([]*ImageRendition, 0, len(processed.Renditions))
+	derived := make([]string, 0)
+
+	for _, output := range processed.Renditions {
+		item := &ImageRendition{Name: output.Name, Width: output.Width, Height: output.Height, Path: filePath, Url: fileUrl}
+
+		if output.Data != nil {
+			item.Path = fmt.Sprintf("%s_%s.%s", strings.TrimSuffix(filePath, path.Ext(filePath)), output.Name, output.Ext)
+			if err := s.fileSystem.Default.Write(output.Data, item.Path); err != nil {
+				return nil, err
+			}
+
+			item.Url = s.fileSystem.Default.PublicUrl(item.Path)
+			derived = append(derived, item.Path)
+		}
+
+		items = append(items, item)
+	}
+
+	if blobId > 0 && len(derived) > 0 {
+		if err := s.blob.Derive(context.Background(), blobId, derived); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// setRenditions 记录规格图信息，缩略图取最小的规格
+func (s *MediaService) setRenditions(file *model.TalkRecordsFile, renditions []*ImageRendition) {
+	if len(renditions) == 0 {
+		return
+	}
+
+	thumb := renditions[0]
+	for _, item := range renditions {
+		if item.Width*item.Height < thumb.Width*thumb.Height {
+			thumb = item
+		}
+	}
+
+	file.Thumbnail = thumb.Path
+	file.Renditions = jsonutil.Encode(renditions)
+}
+
+func (s *MediaService) imageOptions() *media.ImageOptions {
+	conf := defaultImageProcess
+	if s.conf.Filesystem != nil {
+		image := s.conf.Filesystem.Image
+		if image.MaxDimension > 0 {
+			conf.MaxDimension = image.MaxDimension
+		}
+
+		if image.Quality > 0 {
+			conf.Quality = image.Quality
+		}
+
+		if len(image.Renditions) > 0 {
+			conf.Renditions = image.Renditions
+		}
+	}
+
+	opts := &media.ImageOptions{MaxDimension: conf.MaxDimension, Quality: conf.Quality}
+	for _, item := range conf.Renditions {
+		opts.Renditions = append(opts.Renditions, media.Rendition{Name: item.Name, Size: item.Size})
+	}
+
+	return opts
+}
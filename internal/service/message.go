@@ -5,16 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"html"
-	"net/url"
 	"strconv"
 
 	"go-chat/api/pb/message/v1"
 	"go-chat/internal/entity"
 	"go-chat/internal/logic"
-	"go-chat/internal/pkg/encrypt"
 	"go-chat/internal/pkg/filesystem"
 	"go-chat/internal/pkg/jsonutil"
 	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/media"
+	"go-chat/internal/pkg/moderation"
 	"go-chat/internal/pkg/strutil"
 	"go-chat/internal/pkg/timeutil"
 	"go-chat/internal/repository/cache"
@@ -29,19 +29,32 @@ type MessageService struct {
 	groupMemberRepo *repo.GroupMember
 	splitUploadRepo *repo.SplitUpload
 	fileSystem      *filesystem.Filesystem
+	media           *MediaService
+	blob            *FileBlobService
 	unreadStorage   *cache.UnreadStorage
 	messageStorage  *cache.MessageStorage
 	sidStorage      *cache.ServerStorage
 	clientStorage   *cache.ClientStorage
 	Sequence        *repo.Sequence
+	moderation      *ModerationService
 }
 
-func NewMessageService(baseService *BaseService, forward *logic.MessageForwardLogic, groupMemberRepo *repo.GroupMember, splitUploadRepo *repo.SplitUpload, fileSystem *filesystem.Filesystem, unreadStorage *cache.UnreadStorage, messageStorage *cache.MessageStorage, sidStorage *cache.ServerStorage, clientStorage *cache.ClientStorage, sequence *repo.Sequence) *MessageService {
-	return &MessageService{BaseService: baseService, forward: forward, groupMemberRepo: groupMemberRepo, splitUploadRepo: splitUploadRepo, fileSystem: fileSystem, unreadStorage: unreadStorage, messageStorage: messageStorage, sidStorage: sidStorage, clientStorage: clientStorage, Sequence: sequence}
+func NewMessageService(baseService *BaseService, forward *logic.MessageForwardLogic, groupMemberRepo *repo.GroupMember, splitUploadRepo *repo.SplitUpload, fileSystem *filesystem.Filesystem, media *MediaService, blob *FileBlobService, unreadStorage *cache.UnreadStorage, messageStorage *cache.MessageStorage, sidStorage *cache.ServerStorage, clientStorage *cache.ClientStorage, sequence *repo.Sequence, moderation *ModerationService) *MessageService {
+	return &MessageService{BaseService: baseService, forward: forward, groupMemberRepo: groupMemberRepo, splitUploadRepo: splitUploadRepo, fileSystem: fileSystem, media: media, blob: blob, unreadStorage: unreadStorage, messageStorage: messageStorage, sidStorage: sidStorage, clientStorage: clientStorage, Sequence: sequence, moderation: moderation}
 }
 
 // SendText 文本消息
 func (m *MessageService) SendText(ctx context.Context, uid int, req *message.TextMessageRequest) error {
+	_, err := m.sendText(ctx, uid, req)
+	return err
+}
+
+func (m *MessageService) sendText(ctx context.Context, uid int, req *message.TextMessageRequest) (*model.TalkRecords, error) {
+
+	result, err := m.moderate(ctx, uid, req.Receiver, req.Content)
+	if err != nil {
+		return nil, err
+	}
 
 	data := &model.TalkRecords{
 		MsgId:      strutil.NewUuid(),
@@ -49,30 +62,28 @@ func (m *MessageService) SendText(ctx context.Context, uid int, req *message.Tex
 		MsgType:    entity.MsgTypeText,
 		UserId:     uid,
 		ReceiverId: int(req.Receiver.ReceiverId),
-		Content:    html.EscapeString(req.Content),
+		Content:    html.EscapeString(result.Content),
 	}
 
-	if req.Receiver.TalkType == entity.ChatGroupMode {
-		data.Sequence = m.Sequence.Get(ctx, 0, int(req.Receiver.ReceiverId))
-	} else {
-		data.Sequence = m.Sequence.Get(ctx, uid, int(req.Receiver.ReceiverId))
-	}
+	data.Sequence = m.Sequence.Get(ctx, int(req.Receiver.TalkType), uid, int(req.Receiver.ReceiverId))
 
 	if err := m.db.WithContext(ctx).Create(data).Error; err != nil {
-		return err
+		return nil, err
 	}
 
+	m.review(ctx, data, result)
+
 	m.afterHandle(ctx, data, map[string]string{
 		"text": strutil.MtSubstr(data.Content, 0, 300),
 	})
 
-	return nil
+	return data, nil
 }
 
 // SendImage 图片文件消息
 func (m *MessageService) SendImage(ctx context.Context, uid int, req *message.ImageMessageRequest) error {
 
-	parse, err := url.Parse(req.Url)
+	file, err := m.loadMediaFile(ctx, uid, req.UploadId, media.KindImage)
 	if err != nil {
 		return err
 	}
@@ -85,31 +96,21 @@ func (m *MessageService) SendImage(ctx context.Context, uid int, req *message.Im
 		ReceiverId: int(req.Receiver.ReceiverId),
 	}
 
-	if req.Receiver.TalkType == entity.ChatGroupMode {
-		data.Sequence = m.Sequence.Get(ctx, 0, int(req.Receiver.ReceiverId))
-	} else {
-		data.Sequence = m.Sequence.Get(ctx, uid, int(req.Receiver.ReceiverId))
-	}
+	data.Sequence = m.Sequence.Get(ctx, int(req.Receiver.TalkType), uid, int(req.Receiver.ReceiverId))
 
 	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(data).Error; err != nil {
 			return err
 		}
 
-		file := &model.TalkRecordsFile{
-			RecordId:     data.Id,
-			UserId:       uid,
-			Source:       1,
-			Type:         entity.MediaFileImage,
-			Drive:        entity.FileDriveMode("local"),
-			OriginalName: "图片名称",
-			Suffix:       strutil.FileSuffix(req.Url),
-			Size:         int(req.Size),
-			Path:         parse.Path,
-			Url:          req.Url,
+		file.RecordId = data.Id
+		file.OriginalName = "图片名称"
+
+		if err := tx.Create(file).Error; err != nil {
+			return err
 		}
 
-		return tx.Create(file).Error
+		return m.blob.Dao().Retain(tx, file.BlobId)
 	})
 
 	if err == nil {
@@ -122,7 +123,7 @@ func (m *MessageService) SendImage(ctx context.Context, uid int, req *message.Im
 // SendVoice 语音文件消息
 func (m *MessageService) SendVoice(ctx context.Context, uid int, req *message.VoiceMessageRequest) error {
 
-	parse, err := url.Parse(req.Url)
+	file, err := m.loadMediaFile(ctx, uid, req.UploadId, media.KindAudio)
 	if err != nil {
 		return err
 	}
@@ -135,31 +136,21 @@ func (m *MessageService) SendVoice(ctx context.Context, uid int, req *message.Vo
 		ReceiverId: int(req.Receiver.ReceiverId),
 	}
 
-	if req.Receiver.TalkType == entity.ChatGroupMode {
-		data.Sequence = m.Sequence.Get(ctx, 0, int(req.Receiver.ReceiverId))
-	} else {
-		data.Sequence = m.Sequence.Get(ctx, uid, int(req.Receiver.ReceiverId))
-	}
+	data.Sequence = m.Sequence.Get(ctx, int(req.Receiver.TalkType), uid, int(req.Receiver.ReceiverId))
 
 	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(data).Error; err != nil {
 			return err
 		}
 
-		file := &model.TalkRecordsFile{
-			RecordId:     data.Id,
-			UserId:       uid,
-			Source:       1,
-			Type:         entity.MediaFileAudio,
-			Drive:        entity.FileDriveMode("local"),
-			OriginalName: "语音文件",
-			Suffix:       strutil.FileSuffix(req.Url),
-			Size:         int(req.Size),
-			Path:         parse.Path,
-			Url:          req.Url,
+		file.RecordId = data.Id
+		file.OriginalName = "语音文件"
+
+		if err := tx.Create(file).Error; err != nil {
+			return err
 		}
 
-		return tx.Create(file).Error
+		return m.blob.Dao().Retain(tx, file.BlobId)
 	})
 
 	if err == nil {
@@ -172,7 +163,7 @@ func (m *MessageService) SendVoice(ctx context.Context, uid int, req *message.Vo
 // SendVideo 视频文件消息
 func (m *MessageService) SendVideo(ctx context.Context, uid int, req *message.VideoMessageRequest) error {
 
-	parse, err := url.Parse(req.Url)
+	file, err := m.loadMediaFile(ctx, uid, req.UploadId, media.KindVideo)
 	if err != nil {
 		return err
 	}
@@ -185,31 +176,21 @@ func (m *MessageService) SendVideo(ctx context.Context, uid int, req *message.Vi
 		ReceiverId: int(req.Receiver.ReceiverId),
 	}
 
-	if req.Receiver.TalkType == entity.ChatGroupMode {
-		data.Sequence = m.Sequence.Get(ctx, 0, int(req.Receiver.ReceiverId))
-	} else {
-		data.Sequence = m.Sequence.Get(ctx, uid, int(req.Receiver.ReceiverId))
-	}
+	data.Sequence = m.Sequence.Get(ctx, int(req.Receiver.TalkType), uid, int(req.Receiver.ReceiverId))
 
 	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(data).Error; err != nil {
 			return err
 		}
 
-		file := &model.TalkRecordsFile{
-			RecordId:     data.Id,
-			UserId:       uid,
-			Source:       1,
-			Type:         entity.MediaFileVideo,
-			Drive:        entity.FileDriveMode("local"),
-			OriginalName: "语音文件",
-			Suffix:       strutil.FileSuffix(req.Url),
-			Size:         int(req.Size),
-			Path:         parse.Path,
-			Url:          req.Url,
+		file.RecordId = data.Id
+		file.OriginalName = "视频文件"
+
+		if err := tx.Create(file).Error; err != nil {
+			return err
 		}
 
-		return tx.Create(file).Error
+		return m.blob.Dao().Retain(tx, file.BlobId)
 	})
 
 	if err == nil {
@@ -219,6 +200,46 @@ func (m *MessageService) SendVideo(ctx context.Context, uid int, req *message.Vi
 	return err
 }
 
+// loadMediaFile 读取当前用户已上传完成的媒体文件，由服务端探测媒体信息而非信任客户端上报的数据
+// 文件按内容去重存储并由服务端生成访问地址
+// 注:返回的文件未增加引用次数，调用方需在创建文件记录的事务中调用 Retain()
+func (m *MessageService) loadMediaFile(ctx context.Context, uid int, uploadId string, kind string) (*model.TalkRecordsFile, error) {
+
+	upload, err := m.splitUploadRepo.GetFile(ctx, uid, uploadId)
+	if err != nil || upload.Hash == "" {
+		return nil, errors.New("媒体文件不存在")
+	}
+
+	stream, err := m.media.ReadStream(upload.Drive, upload.Path)
+	if err != nil {
+		logger.Error("媒体文件读取失败 err: ", err.Error())
+		return nil, errors.New("媒体文件不存在")
+	}
+
+	blob, err := m.blob.StoreUpload(ctx, upload, true)
+	if err != nil {
+		logger.Error("媒体文件存储失败 err: ", err.Error())
+		return nil, err
+	}
+
+	file := &model.TalkRecordsFile{
+		UserId: uid,
+		Source: 1,
+		Drive:  blob.Drive,
+		Suffix: upload.FileExt,
+		Size:   len(stream),
+		Path:   blob.Path,
+		Url:    m.blob.PublicUrl(blob),
+		BlobId: blob.Id,
+	}
+
+	if err := m.media.AnalyseExpect(file, stream, kind); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
 // SendFile 文件消息
 func (m *MessageService) SendFile(ctx context.Context, uid int, req *message.FileMessageRequest) error {
 
@@ -227,9 +248,14 @@ func (m *MessageService) SendFile(ctx context.Context, uid int, req *message.Fil
 		return err
 	}
 
-	filePath := fmt.Sprintf("private/files/talks/%s/%s.%s", timeutil.DateNumber(), encrypt.Md5(strutil.Random(16)), file.FileExt)
-	if err := m.fileSystem.Default.Copy(file.Path, filePath); err != nil {
-		logger.Error("文件拷贝失败 err: ", err.Error())
+	names, result, err := m.moderateFields(ctx, uid, req.Receiver, file.OriginalName)
+	if err != nil {
+		return err
+	}
+
+	blob, err := m.blob.StoreUpload(ctx, file, false)
+	if err != nil {
+		logger.Error("文件存储失败 err: ", err.Error())
 		return err
 	}
 
@@ -241,11 +267,7 @@ func (m *MessageService) SendFile(ctx context.Context, uid int, req *message.Fil
 		ReceiverId: int(req.Receiver.ReceiverId),
 	}
 
-	if req.Receiver.TalkType == entity.ChatGroupMode {
-		data.Sequence = m.Sequence.Get(ctx, 0, int(req.Receiver.ReceiverId))
-	} else {
-		data.Sequence = m.Sequence.Get(ctx, uid, int(req.Receiver.ReceiverId))
-	}
+	data.Sequence = m.Sequence.Get(ctx, int(req.Receiver.TalkType), uid, int(req.Receiver.ReceiverId))
 
 	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
@@ -258,17 +280,23 @@ func (m *MessageService) SendFile(ctx context.Context, uid int, req *message.Fil
 			UserId:       uid,
 			Source:       1,
 			Type:         entity.MediaFileOther,
-			Drive:        file.Drive,
-			OriginalName: file.OriginalName,
+			Drive:        blob.Drive,
+			OriginalName: names[0],
 			Suffix:       file.FileExt,
 			Size:         int(file.FileSize),
-			Path:         filePath,
+			Path:         blob.Path,
+			BlobId:       blob.Id,
 		}
 
-		return tx.Create(data).Error
+		if err := tx.Create(data).Error; err != nil {
+			return err
+		}
+
+		return m.blob.Dao().Retain(tx, data.BlobId)
 	})
 
 	if err == nil {
+		m.review(ctx, data, result)
 		m.afterHandle(ctx, data, map[string]string{"text": "[文件消息]"})
 	}
 
@@ -278,6 +306,11 @@ func (m *MessageService) SendFile(ctx context.Context, uid int, req *message.Fil
 // SendCode 代码消息
 func (m *MessageService) SendCode(ctx context.Context, uid int, req *message.CodeMessageRequest) error {
 
+	result, err := m.moderate(ctx, uid, req.Receiver, req.Code)
+	if err != nil {
+		return err
+	}
+
 	data := &model.TalkRecords{
 		MsgId:      strutil.NewUuid(),
 		TalkType:   int(req.Receiver.TalkType),
@@ -286,13 +319,9 @@ func (m *MessageService) SendCode(ctx context.Context, uid int, req *message.Cod
 		ReceiverId: int(req.Receiver.ReceiverId),
 	}
 
-	if req.Receiver.TalkType == entity.ChatGroupMode {
-		data.Sequence = m.Sequence.Get(ctx, 0, int(req.Receiver.ReceiverId))
-	} else {
-		data.Sequence = m.Sequence.Get(ctx, uid, int(req.Receiver.ReceiverId))
-	}
+	data.Sequence = m.Sequence.Get(ctx, int(req.Receiver.TalkType), uid, int(req.Receiver.ReceiverId))
 
-	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(data).Error; err != nil {
 			return err
 		}
@@ -301,13 +330,14 @@ func (m *MessageService) SendCode(ctx context.Context, uid int, req *message.Cod
 			RecordId: data.Id,
 			UserId:   uid,
 			Lang:     req.Lang,
-			Code:     req.Code,
+			Code:     result.Content,
 		}
 
 		return tx.Create(data).Error
 	})
 
 	if err == nil {
+		m.review(ctx, data, result)
 		m.afterHandle(ctx, data, map[string]string{"text": "[代码消息]"})
 	}
 
@@ -317,6 +347,11 @@ func (m *MessageService) SendCode(ctx context.Context, uid int, req *message.Cod
 // SendVote 投票消息
 func (m *MessageService) SendVote(ctx context.Context, uid int, req *message.VoteMessageRequest) error {
 
+	fields, result, err := m.moderateFields(ctx, uid, req.Receiver, append([]string{req.Title}, req.Options...)...)
+	if err != nil {
+		return err
+	}
+
 	data := &model.TalkRecords{
 		MsgId:      strutil.NewUuid(),
 		TalkType:   entity.ChatGroupMode,
@@ -325,20 +360,16 @@ func (m *MessageService) SendVote(ctx context.Context, uid int, req *message.Vot
 		ReceiverId: int(req.Receiver.ReceiverId),
 	}
 
-	if req.Receiver.TalkType == entity.ChatGroupMode {
-		data.Sequence = m.Sequence.Get(ctx, 0, int(req.Receiver.ReceiverId))
-	} else {
-		data.Sequence = m.Sequence.Get(ctx, uid, int(req.Receiver.ReceiverId))
-	}
+	data.Sequence = m.Sequence.Get(ctx, int(req.Receiver.TalkType), uid, int(req.Receiver.ReceiverId))
 
 	options := make(map[string]string)
-	for i, value := range req.Options {
+	for i, value := range fields[1:] {
 		options[fmt.Sprintf("%c", 65+i)] = value
 	}
 
 	num := m.groupMemberRepo.CountMemberTotal(ctx, int(req.Receiver.ReceiverId))
 
-	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
 		if err := tx.Create(data).Error; err != nil {
 			return err
@@ -347,7 +378,7 @@ func (m *MessageService) SendVote(ctx context.Context, uid int, req *message.Vot
 		data := &model.TalkRecordsVote{
 			RecordId:     data.Id,
 			UserId:       uid,
-			Title:        req.Title,
+			Title:        fields[0],
 			AnswerMode:   int(req.Mode),
 			AnswerOption: jsonutil.Encode(options),
 			AnswerNum:    int(num),
@@ -358,6 +389,7 @@ func (m *MessageService) SendVote(ctx context.Context, uid int, req *message.Vot
 	})
 
 	if err == nil {
+		m.review(ctx, data, result)
 		m.afterHandle(ctx, data, map[string]string{"text": "[投票消息]"})
 	}
 
@@ -384,11 +416,7 @@ func (m *MessageService) SendEmoticon(ctx context.Context, uid int, req *message
 		ReceiverId: int(req.Receiver.ReceiverId),
 	}
 
-	if req.Receiver.TalkType == entity.ChatGroupMode {
-		data.Sequence = m.Sequence.Get(ctx, 0, int(req.Receiver.ReceiverId))
-	} else {
-		data.Sequence = m.Sequence.Get(ctx, uid, int(req.Receiver.ReceiverId))
-	}
+	data.Sequence = m.Sequence.Get(ctx, int(req.Receiver.TalkType), uid, int(req.Receiver.ReceiverId))
 
 	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
@@ -406,11 +434,12 @@ func (m *MessageService) SendEmoticon(ctx context.Context, uid int, req *message
 			Size:         emoticon.FileSize,
 			Path:         emoticon.Url,
 			Url:          emoticon.Url,
+			BlobId:       emoticon.BlobId,
 		}).Error; err != nil {
 			return err
 		}
 
-		return nil
+		return m.blob.Dao().Retain(tx, emoticon.BlobId)
 	})
 
 	if err == nil {
@@ -428,6 +457,19 @@ func (m *MessageService) SendForward(ctx context.Context, uid int, req *message.
 		return err
 	}
 
+	// 被转发的文本消息按当前审核名单重新审核
+	contents, err := m.forward.Contents(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	result := &moderation.Result{Action: moderation.ActionPass}
+	if len(contents) > 0 {
+		if _, result, err = m.moderateFields(ctx, uid, req.Receiver, contents...); err != nil {
+			return err
+		}
+	}
+
 	var items []*logic.ForwardRecord
 	// 发送方式 1:逐条发送 2:合并发送
 	if req.Mode == 1 {
@@ -437,6 +479,13 @@ func (m *MessageService) SendForward(ctx context.Context, uid int, req *message.
 	}
 
 	for _, item := range items {
+		m.review(ctx, &model.TalkRecords{
+			Id:         item.RecordId,
+			TalkType:   item.TalkType,
+			UserId:     uid,
+			ReceiverId: item.ReceiverId,
+		}, result)
+
 		m.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(entity.MapStrAny{
 			"event": entity.EventTalk,
 			"data": jsonutil.Encode(entity.MapStrAny{
@@ -454,6 +503,11 @@ func (m *MessageService) SendForward(ctx context.Context, uid int, req *message.
 // SendLocation 位置消息
 func (m *MessageService) SendLocation(ctx context.Context, uid int, req *message.LocationMessageRequest) error {
 
+	result, err := m.moderate(ctx, uid, req.Receiver, req.Description)
+	if err != nil {
+		return err
+	}
+
 	data := &model.TalkRecords{
 		MsgId:      strutil.NewUuid(),
 		TalkType:   int(req.Receiver.TalkType),
@@ -462,13 +516,9 @@ func (m *MessageService) SendLocation(ctx context.Context, uid int, req *message
 		ReceiverId: int(req.Receiver.ReceiverId),
 	}
 
-	if req.Receiver.TalkType == entity.ChatGroupMode {
-		data.Sequence = m.Sequence.Get(ctx, 0, int(req.Receiver.ReceiverId))
-	} else {
-		data.Sequence = m.Sequence.Get(ctx, uid, int(req.Receiver.ReceiverId))
-	}
+	data.Sequence = m.Sequence.Get(ctx, int(req.Receiver.TalkType), uid, int(req.Receiver.ReceiverId))
 
-	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
 		if err := tx.Create(data).Error; err != nil {
 			return err
@@ -485,6 +535,7 @@ func (m *MessageService) SendLocation(ctx context.Context, uid int, req *message
 	})
 
 	if err == nil {
+		m.review(ctx, data, result)
 		m.afterHandle(ctx, data, map[string]string{"text": "[位置消息]"})
 	}
 
@@ -501,7 +552,7 @@ func (m *MessageService) SendLogin(ctx context.Context, uid int, req *message.Lo
 
 	data := &model.TalkRecords{
 		MsgId:      strutil.NewUuid(),
-		Sequence:   m.Sequence.Get(ctx, 4257, uid),
+		Sequence:   m.Sequence.Get(ctx, entity.ChatPrivateMode, 4257, uid),
 		TalkType:   entity.ChatPrivateMode,
 		MsgType:    entity.MsgTypeLogin,
 		UserId:     4257, // 机器人ID
@@ -534,6 +585,66 @@ func (m *MessageService) SendLogin(ctx context.Context, uid int, req *message.Lo
 	return err
 }
 
+// moderate 发送前审核消息内容，被拦截时返回 ErrMessageBlocked
+func (m *MessageService) moderate(ctx context.Context, uid int, receiver *message.MessageReceiver, content string) (*moderation.Result, error) {
+
+	result, err := m.moderation.Check(ctx, &moderation.Message{
+		UserId:     uid,
+		TalkType:   int(receiver.TalkType),
+		ReceiverId: int(receiver.ReceiverId),
+		Content:    content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.blocked(uid, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// moderateFields 发送前审核多段文本内容，返回审核后的各段文本
+func (m *MessageService) moderateFields(ctx context.Context, uid int, receiver *message.MessageReceiver, fields ...string) ([]string, *moderation.Result, error) {
+
+	items, result, err := m.moderation.CheckFields(ctx, &moderation.Message{
+		UserId:     uid,
+		TalkType:   int(receiver.TalkType),
+		ReceiverId: int(receiver.ReceiverId),
+	}, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := m.blocked(uid, result); err != nil {
+		return nil, nil, err
+	}
+
+	return items, result, nil
+}
+
+func (m *MessageService) blocked(uid int, result *moderation.Result) error {
+
+	if result.Action != moderation.ActionBlock {
+		return nil
+	}
+
+	logger.WithFields(entity.H{
+		"user_id": uid,
+		"hook":    result.Hook,
+	}).Warn(fmt.Sprintf("[Moderation]消息已拦截 %s", result.Reason()))
+
+	return ErrMessageBlocked
+}
+
+// review 需要人工审核的消息加入审核队列
+func (m *MessageService) review(ctx context.Context, record *model.TalkRecords, result *moderation.Result) {
+	if err := m.moderation.Review(ctx, record, result); err != nil {
+		logger.Error(fmt.Sprintf("[Moderation]加入审核队列失败 %s", err.Error()))
+	}
+}
+
 // 发送消息后置处理
 func (m *MessageService) afterHandle(ctx context.Context, record *model.TalkRecords, opts map[string]string) {
 
@@ -544,17 +655,8 @@ func (m *MessageService) afterHandle(ctx context.Context, record *model.TalkReco
 			m.unreadStorage.Incr(ctx, 1, record.ReceiverId, record.UserId)
 		}
 	} else if record.TalkType == entity.ChatGroupMode {
-
-		// todo 需要加缓存
-		ids := m.groupMemberRepo.GetMemberIds(ctx, record.ReceiverId)
-		for _, uid := range ids {
-
-			if uid == record.UserId {
-				continue
-			}
-
-			m.unreadStorage.Incr(ctx, entity.ChatGroupMode, record.ReceiverId, uid)
-		}
+		// 群消息仅累加群消息计数，成员未读数由已读游标计算
+		m.unreadStorage.IncrGroup(ctx, record.ReceiverId, record.UserId)
 	}
 
 	_ = m.messageStorage.Set(ctx, record.TalkType, record.UserId, record.ReceiverId, &cache.LastCacheMessage{
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/moderation"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrMessageBlocked      = errors.New("消息包含违规内容，发送失败")
+	ErrModerationAction    = errors.New("敏感词处理动作不正确")
+	ErrModerationNotFound  = errors.New("审核记录不存在")
+	ErrModerationProcessed = errors.New("审核记录已处理")
+)
+
+// 敏感词及链接名单的重新加载周期，管理后台修改后当前实例立即生效，其它实例在周期内同步
+const moderationReloadInterval = time.Minute
+
+type ModerationReviewListOpts struct {
+	Status int // 为 -1 时查询全部
+	Page   int
+	Size   int
+}
+
+type ModerationService struct {
+	*BaseService
+	config     *config.Config
+	wordRepo   *repo.ModerationWord
+	linkRepo   *repo.ModerationLink
+	reviewRepo *repo.ModerationReview
+	keyword    *moderation.KeywordFilter
+	link       *moderation.LinkFilter
+	pipeline   *moderation.Pipeline
+	loadedAt   atomic.Int64 // 最近一次加载名单的时间（纳秒）
+	reloading  atomic.Bool  // 是否有协程正在重新加载名单
+}
+
+func NewModerationService(baseService *BaseService, conf *config.Config, client *http.Client, rateLimit *cache.RateLimitStorage, wordRepo *repo.ModerationWord, linkRepo *repo.ModerationLink, reviewRepo *repo.ModerationReview) *ModerationService {
+
+	s := &ModerationService{
+		BaseService: baseService,
+		config:      conf,
+		wordRepo:    wordRepo,
+		linkRepo:    linkRepo,
+		reviewRepo:  reviewRepo,
+		keyword:     moderation.NewKeywordFilter(nil),
+		link:        moderation.NewLinkFilter(nil, nil),
+	}
+
+	s.pipeline = moderation.NewPipeline(s.keyword, s.link)
+
+	if !conf.Moderation.IsEnabled() {
+		return s
+	}
+
+	if conf.Moderation.SpamLimit > 0 && conf.Moderation.SpamWindow > 0 {
+		s.pipeline.Use(moderation.NewSpamDetector(rateLimit, time.Duration(conf.Moderation.SpamWindow)*time.Second, int64(conf.Moderation.SpamLimit)))
+	}
+
+	if c := conf.Moderation.Classifier; c != nil && c.Url != "" {
+		s.pipeline.Use(moderation.NewClassifierHook(moderation.NewHttpClassifier(c.Url, client), c.FlagScore, c.BlockScore, c.FailOpen))
+	}
+
+	return s
+}
+
+// Check 审核待发送的消息内容
+func (s *ModerationService) Check(ctx context.Context, msg *moderation.Message) (*moderation.Result, error) {
+
+	if !s.config.Moderation.IsEnabled() {
+		return &moderation.Result{Action: moderation.ActionPass, Content: msg.Content}, nil
+	}
+
+	// 名单过期时仅由一个协程重新加载，其它协程继续使用旧名单，过滤器内部自行保证读写安全
+	if time.Since(time.Unix(0, s.loadedAt.Load())) > moderationReloadInterval && s.reloading.CompareAndSwap(false, true) {
+		if err := s.load(ctx); err != nil {
+			logger.Error("[Moderation] 加载审核名单失败 err: ", err.Error())
+		}
+		s.reloading.Store(false)
+	}
+
+	return s.pipeline.Run(ctx, msg)
+}
+
+// CheckFields 审核由多段文本组成的消息内容（投票标题及选项、文件名等），返回审核后的各段文本
+func (s *ModerationService) CheckFields(ctx context.Context, msg *moderation.Message, fields []string) ([]string, *moderation.Result, error) {
+
+	items := make([]string, 0, len(fields))
+	for _, field := range fields {
+		items = append(items, strings.ReplaceAll(field, "\n", " "))
+	}
+
+	msg.Content = strings.Join(items, "\n")
+
+	result, err := s.Check(ctx, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 替换违规内容不会改变换行符，分段数量不一致时保留原内容
+	if values := strings.Split(result.Content, "\n"); len(values) == len(items) {
+		items = values
+	}
+
+	return items, result, nil
+}
+
+// Review 需要人工审核的消息加入审核队列
+func (s *ModerationService) Review(ctx context.Context, record *model.TalkRecords, res *moderation.Result) error {
+
+	if res == nil || res.Action != moderation.ActionFlag {
+		return nil
+	}
+
+	return s.reviewRepo.Create(ctx, &model.ModerationReview{
+		RecordId:   record.Id,
+		UserId:     record.UserId,
+		TalkType:   record.TalkType,
+		ReceiverId: record.ReceiverId,
+		Content:    res.Content,
+		Hook:       res.Hook,
+		Reason:     res.Reason(),
+		Status:     entity.ModerationReviewPending,
+	})
+}
+
+// Reload 重新加载敏感词及链接名单
+func (s *ModerationService) Reload(ctx context.Context) error {
+	return s.load(ctx)
+}
+
+func (s *ModerationService) load(ctx context.Context) error {
+
+	words, err := s.wordRepo.All(ctx)
+	if err != nil {
+		return err
+	}
+
+	links, err := s.linkRepo.All(ctx)
+	if err != nil {
+		return err
+	}
+
+	items := make([]moderation.Word, 0, len(words))
+	for _, word := range words {
+		items = append(items, moderation.Word{Word: word.Word, Action: moderation.ParseAction(word.Action)})
+	}
+
+	allow, deny := make([]string, 0), make([]string, 0)
+	for _, link := range links {
+		if link.Type == entity.ModerationLinkAllow {
+			allow = append(allow, link.Domain)
+		} else if link.Type == entity.ModerationLinkDeny {
+			deny = append(deny, link.Domain)
+		}
+	}
+
+	s.keyword.Load(items)
+	s.link.Load(allow, deny)
+	s.loadedAt.Store(time.Now().UnixNano())
+
+	return nil
+}
+
+// WordList 敏感词列表
+func (s *ModerationService) WordList(ctx context.Context) ([]*model.ModerationWord, error) {
+	return s.wordRepo.All(ctx)
+}
+
+// LinkList 链接域名名单
+func (s *ModerationService) LinkList(ctx context.Context) ([]*model.ModerationLink, error) {
+	return s.linkRepo.All(ctx)
+}
+
+// WordCreate 批量添加敏感词，已存在的敏感词更新处理动作
+func (s *ModerationService) WordCreate(ctx context.Context, words []string, action string) error {
+
+	if moderation.ParseAction(action) == moderation.ActionPass {
+		return ErrModerationAction
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, word := range words {
+			word = strings.TrimSpace(word)
+			if word == "" {
+				continue
+			}
+
+			item := &model.ModerationWord{}
+			if err := tx.Where("word = ?", word).Attrs(model.ModerationWord{Action: action}).FirstOrCreate(item).Error; err != nil {
+				return err
+			}
+
+			if item.Action != action {
+				if err := tx.Model(item).Update("action", action).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return s.Reload(ctx)
+}
+
+// WordDelete 删除敏感词
+func (s *ModerationService) WordDelete(ctx context.Context, ids []int) error {
+
+	if err := s.db.WithContext(ctx).Delete(&model.ModerationWord{}, "id in ?", ids).Error; err != nil {
+		return err
+	}
+
+	return s.Reload(ctx)
+}
+
+// LinkCreate 添加链接域名名单
+func (s *ModerationService) LinkCreate(ctx context.Context, domain string, linkType int) error {
+
+	domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "*.")
+
+	err := s.db.WithContext(ctx).Where("domain = ? and type = ?", domain, linkType).FirstOrCreate(&model.ModerationLink{Domain: domain, Type: linkType}).Error
+	if err != nil {
+		return err
+	}
+
+	return s.Reload(ctx)
+}
+
+// LinkDelete 删除链接域名名单
+func (s *ModerationService) LinkDelete(ctx context.Context, ids []int) error {
+
+	if err := s.db.WithContext(ctx).Delete(&model.ModerationLink{}, "id in ?", ids).Error; err != nil {
+		return err
+	}
+
+	return s.Reload(ctx)
+}
+
+// ReviewList 审核队列列表
+func (s *ModerationService) ReviewList(ctx context.Context, opts *ModerationReviewListOpts) ([]*model.ModerationReview, int64, error) {
+
+	where := func(db *gorm.DB) *gorm.DB {
+		if opts.Status >= 0 {
+			db = db.Where("status = ?", opts.Status)
+		}
+
+		return db
+	}
+
+	var total int64
+	if err := where(s.reviewRepo.Model(ctx)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items, err := s.reviewRepo.FindAll(ctx, func(db *gorm.DB) {
+		where(db).Order("id desc").Offset((opts.Page - 1) * opts.Size).Limit(opts.Size)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// ReviewHandle 处理审核记录，驳回时撤回对应的消息
+func (s *ModerationService) ReviewHandle(ctx context.Context, adminId int, reviewId int, status int) (*model.ModerationReview, error) {
+
+	review, err := s.reviewRepo.FindById(ctx, reviewId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrModerationNotFound
+		}
+
+		return nil, err
+	}
+
+	if review.Status != entity.ModerationReviewPending {
+		return nil, ErrModerationProcessed
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.ModerationReview{}).Where("id = ? and status = ?", review.Id, entity.ModerationReviewPending).Updates(map[string]interface{}{
+			"status":   status,
+			"admin_id": adminId,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return ErrModerationProcessed
+		}
+
+		if status != entity.ModerationReviewReject {
+			return nil
+		}
+
+		return tx.Model(&model.TalkRecords{}).Where("id = ?", review.RecordId).Update("is_revoke", 1).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status == entity.ModerationReviewReject {
+		s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(map[string]interface{}{
+			"event": entity.EventTalkRevoke,
+			"data": jsonutil.Encode(map[string]interface{}{
+				"record_id": review.RecordId,
+			}),
+		}))
+	}
+
+	return review, nil
+}
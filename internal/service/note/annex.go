@@ -3,21 +3,24 @@ package note
 import (
 	"context"
 
+	"go-chat/internal/entity"
 	"go-chat/internal/pkg/filesystem"
 	"go-chat/internal/pkg/timeutil"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo/note"
 	"go-chat/internal/service"
+	"gorm.io/gorm"
 )
 
 type ArticleAnnexService struct {
 	*service.BaseService
 	dao        *note.ArticleAnnex
 	fileSystem *filesystem.Filesystem
+	blob       *service.FileBlobService
 }
 
-func NewArticleAnnexService(baseService *service.BaseService, dao *note.ArticleAnnex, fileSystem *filesystem.Filesystem) *ArticleAnnexService {
-	return &ArticleAnnexService{BaseService: baseService, dao: dao, fileSystem: fileSystem}
+func NewArticleAnnexService(baseService *service.BaseService, dao *note.ArticleAnnex, fileSystem *filesystem.Filesystem, blob *service.FileBlobService) *ArticleAnnexService {
+	return &ArticleAnnexService{BaseService: baseService, dao: dao, fileSystem: fileSystem, blob: blob}
 }
 
 func (s *ArticleAnnexService) Dao() *note.ArticleAnnex {
@@ -25,7 +28,13 @@ func (s *ArticleAnnexService) Dao() *note.ArticleAnnex {
 }
 
 func (s *ArticleAnnexService) Create(ctx context.Context, data *model.ArticleAnnex) error {
-	return s.Db().Create(data).Error
+	return s.Db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(data).Error; err != nil {
+			return err
+		}
+
+		return s.blob.Dao().Retain(tx, data.BlobId)
+	})
 }
 
 // UpdateStatus 更新附件状态
@@ -50,11 +59,19 @@ func (s *ArticleAnnexService) ForeverDelete(ctx context.Context, uid int, id int
 		return err
 	}
 
-	switch annex.Drive {
-	case 1:
-		_ = s.fileSystem.Local.Delete(annex.Path)
-	case 2:
-		_ = s.fileSystem.Cos.Delete(annex.Path)
+	// 去重存储的附件仅释放引用，由定时任务回收文件
+	if annex.BlobId > 0 {
+		return s.Db().Transaction(func(tx *gorm.DB) error {
+			if err := tx.Delete(&model.ArticleAnnex{}, id).Error; err != nil {
+				return err
+			}
+
+			return s.blob.Dao().Release(tx, annex.BlobId)
+		})
+	}
+
+	if adapter, err := s.fileSystem.Drive(entity.FileDriveName(annex.Drive)); err == nil {
+		_ = adapter.Delete(annex.Path)
 	}
 
 	return s.Db().Delete(&model.ArticleAnnex{}, id).Error
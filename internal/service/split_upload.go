@@ -2,14 +2,18 @@ package service
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash/crc32"
+	"io"
 	"math"
-	"mime/multipart"
 	"path"
 	"strings"
 
-	"github.com/tencentyun/cos-go-sdk-v5"
+	"go-chat/internal/repository/cache"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
 
@@ -22,6 +26,25 @@ import (
 	"go-chat/internal/pkg/timeutil"
 )
 
+const (
+	// SplitUploadPartSize 分片大小，S3 协议要求除最后一个分片外不小于 5MB
+	SplitUploadPartSize = 5 << 20
+
+	// SplitUploadPathPrefix 分片上传文件的存储目录
+	SplitUploadPathPrefix = "private/tmp/multipart/"
+)
+
+var (
+	ErrSplitUploadNotFound = errors.New("上传任务不存在")
+	ErrSplitUploadMerged   = errors.New("文件已上传完成")
+	ErrSplitUploadChecksum = errors.New("分片校验失败")
+	ErrSplitUploadExists   = errors.New("分片已上传")
+	ErrSplitUploadOffset   = errors.New("上传偏移量不一致")
+	ErrSplitUploadBusy     = errors.New("文件正在上传中，请稍后再试")
+	ErrSplitUploadOverflow = errors.New("上传内容超出文件大小")
+	ErrSplitUploadPartSize = fmt.Errorf("分片大小不能小于 %d 字节", SplitUploadPartSize)
+)
+
 type MultipartInitiateOpts struct {
 	UserId int
 	Name   string
@@ -32,8 +55,20 @@ type MultipartUploadOpts struct {
 	UserId     int
 	UploadId   string
 	SplitIndex int
-	SplitNum   int
-	File       *multipart.FileHeader
+	Stream     []byte
+	Md5        string // 分片 MD5 校验值(十六进制)
+	Crc32      string // 分片 CRC32 校验值(十六进制)
+}
+
+// MultipartUploadInfo 上传进度信息
+type MultipartUploadInfo struct {
+	UploadId  string `json:"upload_id"`
+	FileSize  int64  `json:"file_size"`
+	SplitNum  int    `json:"split_num"`
+	SplitSize int    `json:"split_size"`
+	Offset    int64  `json:"offset"`   // 已上传的字节数
+	Uploaded  []int  `json:"uploaded"` // 已上传的分片索引
+	IsMerge   bool   `json:"is_merge"` // 是否已合并完成
 }
 
 type SplitUploadService struct {
@@ -41,10 +76,11 @@ type SplitUploadService struct {
 	repo       *repo.SplitUpload
 	conf       *config.Config
 	fileSystem *filesystem.Filesystem
+	redisLock  *cache.RedisLock
 }
 
-func NewSplitUploadService(baseService *BaseService, repo *repo.SplitUpload, conf *config.Config, fileSystem *filesystem.Filesystem) *SplitUploadService {
-	return &SplitUploadService{BaseService: baseService, repo: repo, conf: conf, fileSystem: fileSystem}
+func NewSplitUploadService(baseService *BaseService, repo *repo.SplitUpload, conf *config.Config, fileSystem *filesystem.Filesystem, redisLock *cache.RedisLock) *SplitUploadService {
+	return &SplitUploadService{BaseService: baseService, repo: repo, conf: conf, fileSystem: fileSystem, redisLock: redisLock}
 }
 
 func (s *SplitUploadService) Dao() *repo.SplitUpload {
@@ -53,8 +89,8 @@ func (s *SplitUploadService) Dao() *repo.SplitUpload {
 
 func (s *SplitUploadService) InitiateMultipartUpload(ctx context.Context, params *MultipartInitiateOpts) (*model.SplitUpload, error) {
 
-	// 计算拆分数量 3M
-	num := math.Ceil(float64(params.Size) / float64(3<<20))
+	// 计算拆分数量
+	num := math.Ceil(float64(params.Size) / float64(SplitUploadPartSize))
 
 	m := &model.SplitUpload{
 		Type:         1,
@@ -64,7 +100,7 @@ func (s *SplitUploadService) InitiateMultipartUpload(ctx context.Context, params
 		SplitNum:     int(num),
 		FileExt:      strings.TrimPrefix(path.Ext(params.Name), "."),
 		FileSize:     params.Size,
-		Path:         fmt.Sprintf("private/tmp/multipart/%s/%s.tmp", timeutil.DateNumber(), encrypt.Md5(strutil.Random(20))),
+		Path:         fmt.Sprintf("%s%s/%s.tmp", SplitUploadPathPrefix, timeutil.DateNumber(), encrypt.Md5(strutil.Random(20))),
 		Attr:         "{}",
 	}
 
@@ -82,101 +118,318 @@ func (s *SplitUploadService) InitiateMultipartUpload(ctx context.Context, params
 	return m, nil
 }
 
-func (s *SplitUploadService) MultipartUpload(ctx context.Context, opts *MultipartUploadOpts) error {
-	info := &model.SplitUpload{}
-	if err := s.Db().First(info, "upload_id = ? and type = 1", opts.UploadId).Error; err != nil {
+// UploadInfo 查询上传进度，用于断点续传
+func (s *SplitUploadService) UploadInfo(ctx context.Context, uid int, uploadId string) (*MultipartUploadInfo, error) {
+	info, err := s.repo.GetFile(ctx, uid, uploadId)
+	if err != nil {
+		return nil, ErrSplitUploadNotFound
+	}
+
+	items, err := s.repo.GetSplitList(ctx, uploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &MultipartUploadInfo{
+		UploadId:  info.UploadId,
+		FileSize:  info.FileSize,
+		SplitNum:  info.SplitNum,
+		SplitSize: SplitUploadPartSize,
+		Uploaded:  make([]int, 0, len(items)),
+		IsMerge:   info.Hash != "",
+	}
+
+	for _, item := range items {
+		data.Offset += item.FileSize
+		data.Uploaded = append(data.Uploaded, item.SplitIndex)
+	}
+
+	return data, nil
+}
+
+// MultipartUpload 上传分片，所有分片上传完成后自动合并
+// 返回值表示文件是否已合并完成
+func (s *SplitUploadService) MultipartUpload(ctx context.Context, opts *MultipartUploadOpts) (bool, error) {
+	info, err := s.repo.GetFile(ctx, opts.UserId, opts.UploadId)
+	if err != nil {
+		return false, ErrSplitUploadNotFound
+	}
+
+	if info.Hash != "" {
+		return false, ErrSplitUploadMerged
+	}
+
+	if opts.SplitIndex < 0 || opts.SplitIndex >= info.SplitNum {
+		return false, fmt.Errorf("分片索引不正确[%d]", opts.SplitIndex)
+	}
+
+	if err := verifyChecksum(opts.Stream, opts.Md5, opts.Crc32); err != nil {
+		return false, err
+	}
+
+	// 除最后一个分片外，分片大小必须一致，保证合并后文件大小正确
+	size := int64(len(opts.Stream))
+	if opts.SplitIndex < info.SplitNum-1 && size != SplitUploadPartSize || size > SplitUploadPartSize {
+		return false, fmt.Errorf("分片大小不正确[%d]", size)
+	}
+
+	if err := s.savePart(ctx, info, opts.SplitIndex, opts.Stream); err != nil {
+		return false, err
+	}
+
+	return s.tryMerge(ctx, info)
+}
+
+// AppendUpload 按偏移量追加上传(tus 协议)，offset 必须与已上传的字节数一致
+// 返回追加后的偏移量
+func (s *SplitUploadService) AppendUpload(ctx context.Context, uid int, uploadId string, offset int64, stream []byte) (int64, error) {
+	info, err := s.repo.GetFile(ctx, uid, uploadId)
+	if err != nil {
+		return 0, ErrSplitUploadNotFound
+	}
+
+	if info.Hash != "" {
+		return 0, ErrSplitUploadMerged
+	}
+
+	// 同一文件的追加上传需串行处理
+	lockName := fmt.Sprintf("split-upload:append:%s", uploadId)
+	if !s.redisLock.Lock(ctx, lockName, 60) {
+		return 0, ErrSplitUploadBusy
+	}
+
+	defer s.redisLock.UnLock(ctx, lockName)
+
+	items, err := s.repo.GetSplitList(ctx, uploadId)
+	if err != nil {
+		return 0, err
+	}
+
+	var current int64
+	for _, item := range items {
+		current += item.FileSize
+	}
+
+	if offset != current {
+		return current, ErrSplitUploadOffset
+	}
+
+	if current+int64(len(stream)) > info.FileSize {
+		return current, ErrSplitUploadOverflow
+	}
+
+	// 除最后一个分片外分片不小于 SplitUploadPartSize，保证分片数不超过初始化时计算的分片数
+	if current+int64(len(stream)) < info.FileSize && len(stream) < SplitUploadPartSize {
+		return current, ErrSplitUploadPartSize
+	}
+
+	if len(stream) > 0 && len(items) >= info.SplitNum {
+		return current, fmt.Errorf("分片索引不正确[%d]", len(items))
+	}
+
+	if len(stream) > 0 {
+		if err := s.savePart(ctx, info, len(items), stream); err != nil {
+			return current, err
+		}
+
+		current += int64(len(stream))
+	}
+
+	if current == info.FileSize {
+		if _, err := s.merge(ctx, info); err != nil {
+			return current, err
+		}
+	}
+
+	return current, nil
+}
+
+// savePart 保存分片，重复上传的分片将被拒绝
+// 先写入分片记录占位再上传，上传失败时删除记录，避免产生无记录的分片文件
+func (s *SplitUploadService) savePart(ctx context.Context, info *model.SplitUpload, index int, stream []byte) error {
+
+	if index < 0 || index >= info.SplitNum {
+		return fmt.Errorf("分片索引不正确[%d]", index)
+	}
+
+	exist, err := s.repo.QueryExist(ctx, "upload_id = ? and type = 2 and split_index = ?", info.UploadId, index)
+	if err != nil {
 		return err
 	}
 
-	stream, err := filesystem.ReadMultipartStream(opts.File)
+	if exist {
+		return ErrSplitUploadExists
+	}
+
+	adapter, err := s.fileSystem.Drive(entity.FileDriveName(info.Drive))
 	if err != nil {
 		return err
 	}
 
-	dirPath := fmt.Sprintf("private/tmp/%s/%s/%d-%s.tmp", timeutil.DateNumber(), encrypt.Md5(opts.UploadId), opts.SplitIndex, opts.UploadId)
+	attr := map[string]string{
+		"md5": fmt.Sprintf("%x", md5.Sum(stream)),
+	}
 
 	data := &model.SplitUpload{
 		Type:         2,
 		Drive:        info.Drive,
-		UserId:       opts.UserId,
-		UploadId:     opts.UploadId,
+		UserId:       info.UserId,
+		UploadId:     info.UploadId,
 		OriginalName: info.OriginalName,
-		SplitIndex:   opts.SplitIndex,
-		SplitNum:     opts.SplitNum,
-		Path:         dirPath,
+		SplitIndex:   index,
+		SplitNum:     info.SplitNum,
+		Path:         info.Path,
 		FileExt:      info.FileExt,
-		FileSize:     opts.File.Size,
-		Attr:         "{}",
+		FileSize:     int64(len(stream)),
+		Attr:         jsonutil.Encode(attr),
 	}
 
-	switch data.Drive {
-	case entity.FileDriveLocal:
-		_ = s.fileSystem.Default.Write(stream, data.Path)
-	case entity.FileDriveCos:
-		etag, err := s.fileSystem.Cos.UploadPart(info.Path, data.UploadId, data.SplitIndex+1, stream)
-		if err != nil {
-			return err
-		}
-
-		data.Attr = jsonutil.Encode(map[string]string{
-			"etag": etag,
-		})
-
-	default:
-		return errors.New("未知文件驱动类型")
+	// 依赖唯一索引(upload_id,type,split_index)拒绝并发重复上传的分片
+	if err := s.Db().Create(data).Error; err != nil {
+		return ErrSplitUploadExists
 	}
 
-	if err := s.Db().Create(data).Error; err != nil {
+	etag, err := adapter.UploadPart(info.Path, info.UploadId, index+1, stream)
+	if err != nil {
+		s.Db().Delete(&model.SplitUpload{}, data.Id)
 		return err
 	}
 
-	// 判断是否为最后一个分片上传
-	if opts.SplitNum == opts.SplitIndex+1 {
-		err = s.merge(info)
+	attr["etag"] = etag
+
+	return s.Db().Model(&model.SplitUpload{}).Where("id = ?", data.Id).Update("attr", jsonutil.Encode(attr)).Error
+}
+
+// tryMerge 所有分片上传完成后合并文件
+func (s *SplitUploadService) tryMerge(ctx context.Context, info *model.SplitUpload) (bool, error) {
+	count, err := s.repo.QueryCount(ctx, "upload_id = ? and type = 2", info.UploadId)
+	if err != nil {
+		return false, err
 	}
 
-	return err
+	if int(count) < info.SplitNum {
+		return false, nil
+	}
+
+	return s.merge(ctx, info)
 }
 
-// combine
-func (s *SplitUploadService) merge(info *model.SplitUpload) error {
-	items, err := s.repo.GetSplitList(context.Background(), info.UploadId)
+// merge 合并分片，同一文件只允许一个合并任务执行
+func (s *SplitUploadService) merge(ctx context.Context, info *model.SplitUpload) (bool, error) {
+
+	lockName := fmt.Sprintf("split-upload:merge:%s", info.UploadId)
+	if !s.redisLock.Lock(ctx, lockName, 300) {
+		return false, nil
+	}
+
+	defer s.redisLock.UnLock(ctx, lockName)
+
+	// 加锁后重新确认合并状态
+	if err := s.Db().First(info, info.Id).Error; err != nil {
+		return false, err
+	}
+
+	if info.Hash != "" {
+		return true, nil
+	}
+
+	items, err := s.repo.GetSplitList(ctx, info.UploadId)
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	var total int64
+	for i, item := range items {
+		if item.SplitIndex != i {
+			return false, fmt.Errorf("分片缺失[%d]", i)
+		}
+
+		total += item.FileSize
+	}
+
+	if total != info.FileSize {
+		return false, fmt.Errorf("文件大小不一致[%d/%d]", total, info.FileSize)
 	}
 
-	switch info.Drive {
-	case entity.FileDriveLocal:
-		for _, item := range items {
-			stream, err := s.fileSystem.Default.ReadStream(item.Path)
-			if err != nil {
-				return err
-			}
+	adapter, err := s.fileSystem.Drive(entity.FileDriveName(info.Drive))
+	if err != nil {
+		return false, err
+	}
 
-			if err := s.fileSystem.Local.AppendWrite(stream, info.Path); err != nil {
-				return err
-			}
+	parts := make([]filesystem.MultipartPart, 0, len(items))
+	for _, item := range items {
+		attr := make(map[string]string)
+
+		if err := jsonutil.Decode(item.Attr, &attr); err != nil {
+			return false, err
 		}
-	case entity.FileDriveCos:
-		opt := &cos.CompleteMultipartUploadOptions{}
-		for _, item := range items {
-			attr := make(map[string]string)
-
-			if err := jsonutil.Decode(item.Attr, &attr); err != nil {
-				return err
-			}
-
-			opt.Parts = append(opt.Parts, cos.Object{
-				PartNumber: item.SplitIndex + 1,
-				ETag:       attr["etag"],
-			})
+
+		// 分片仍在上传中
+		if attr["etag"] == "" {
+			return false, nil
 		}
 
-		if err := s.fileSystem.Cos.CompleteMultipartUpload(info.Path, info.UploadId, opt); err != nil {
-			return err
+		parts = append(parts, filesystem.MultipartPart{Num: item.SplitIndex + 1, ETag: attr["etag"]})
+	}
+
+	if err := adapter.CompleteMultipartUpload(info.Path, info.UploadId, parts); err != nil {
+		// 上次合并已完成但未记录 hash 时，分片已不存在，以合并后的文件为准
+		stat, statErr := adapter.Stat(info.Path)
+		if statErr != nil || stat.Size != info.FileSize {
+			return false, err
 		}
-	default:
-		return errors.New("未知文件驱动类型")
+	}
+
+	hash, size, err := hashFile(adapter, info.Path)
+	if err != nil {
+		return false, err
+	}
+
+	if size != info.FileSize {
+		return false, fmt.Errorf("文件大小不一致[%d/%d]", size, info.FileSize)
+	}
+
+	// 记录文件内容 hash，发送文件时按内容去重存储
+	info.Hash = hash
+
+	if err := s.Db().Model(&model.SplitUpload{}).Where("id = ?", info.Id).Update("hash", info.Hash).Error; err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// hashFile 以流的方式计算文件内容 SHA-256，避免将整个文件读入内存
+func hashFile(adapter filesystem.IAdapter, filePath string) (string, int64, error) {
+	reader, err := adapter.Open(filePath)
+	if err != nil {
+		return "", 0, err
+	}
+
+	defer reader.Close()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, reader)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// verifyChecksum 校验分片内容，至少需要提供一种校验值
+func verifyChecksum(stream []byte, md5sum string, crc string) error {
+	if md5sum == "" && crc == "" {
+		return errors.New("分片校验值不能为空")
+	}
+
+	if md5sum != "" && !strings.EqualFold(md5sum, fmt.Sprintf("%x", md5.Sum(stream))) {
+		return ErrSplitUploadChecksum
+	}
+
+	if crc != "" && !strings.EqualFold(strings.TrimLeft(crc, "0"), strings.TrimLeft(fmt.Sprintf("%x", crc32.ChecksumIEEE(stream)), "0")) {
+		return ErrSplitUploadChecksum
 	}
 
 	return nil
@@ -9,15 +9,17 @@ import (
 	"go-chat/internal/pkg/sliceutil"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
 )
 
 type TalkService struct {
 	*BaseService
 	groupMemberRepo *repo.GroupMember
+	blobRepo        *repo.FileBlob
 }
 
-func NewTalkService(baseService *BaseService, groupMemberRepo *repo.GroupMember) *TalkService {
-	return &TalkService{BaseService: baseService, groupMemberRepo: groupMemberRepo}
+func NewTalkService(baseService *BaseService, groupMemberRepo *repo.GroupMember, blobRepo *repo.FileBlob) *TalkService {
+	return &TalkService{BaseService: baseService, groupMemberRepo: groupMemberRepo, blobRepo: blobRepo}
 }
 
 type TalkMessageDeleteOpt struct {
@@ -89,7 +91,7 @@ func (s *TalkService) CollectRecord(ctx context.Context, uid int, recordId int)
 			return entity.ErrPermissionDenied
 		}
 	} else if record.TalkType == entity.ChatGroupMode {
-		if !s.groupMemberRepo.IsMember(ctx, record.ReceiverId, uid, true) {
+		if !s.groupMemberRepo.IsRecordVisible(ctx, record.ReceiverId, uid, record.Id) {
 			return entity.ErrPermissionDenied
 		}
 	}
@@ -103,7 +105,14 @@ func (s *TalkService) CollectRecord(ctx context.Context, uid int, recordId int)
 		Url:        fileInfo.Url,
 		FileSuffix: fileInfo.Suffix,
 		FileSize:   fileInfo.Size,
+		BlobId:     fileInfo.BlobId,
 	}
 
-	return s.db.Create(emoticon).Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(emoticon).Error; err != nil {
+			return err
+		}
+
+		return s.blobRepo.Retain(tx, emoticon.BlobId)
+	})
 }
@@ -5,19 +5,20 @@ import (
 	"errors"
 
 	"go-chat/internal/entity"
-	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
 	"go-chat/internal/repository/repo/organize"
-	"gorm.io/gorm"
 )
 
 type TalkAuthService struct {
-	organize *organize.Organize
-	contact  *repo.Contact
+	organize   *organize.Organize
+	contact    *repo.Contact
+	block      *repo.UserBlock
+	groupRepo  *repo.Group
+	permission *AuthPermissionService
 }
 
-func NewTalkAuthService(organize *organize.Organize, contact *repo.Contact) *TalkAuthService {
-	return &TalkAuthService{organize: organize, contact: contact}
+func NewTalkAuthService(organize *organize.Organize, contact *repo.Contact, block *repo.UserBlock, groupRepo *repo.Group, permission *AuthPermissionService) *TalkAuthService {
+	return &TalkAuthService{organize: organize, contact: contact, block: block, groupRepo: groupRepo, permission: permission}
 }
 
 type TalkAuthOption struct {
@@ -28,7 +29,17 @@ type TalkAuthOption struct {
 
 func (t *TalkAuthService) IsAuth(ctx context.Context, opt *TalkAuthOption) error {
 
+	// 频道仅允许管理员通过频道接口发布内容，订阅者不能回复
+	if opt.TalkType == entity.ChatChannelMode {
+		return errors.New("频道暂不支持回复消息！")
+	}
+
 	if opt.TalkType == entity.ChatPrivateMode {
+		// 已被对方拉黑
+		if t.block.IsBlocked(ctx, opt.ReceiverId, opt.UserId) {
+			return errors.New("消息已发出，但被对方拒收了！")
+		}
+
 		// 这里需要判断双方是否都是企业成员，如果是则无需添加好友即可聊天
 		if isOk, err := t.organize.IsQiyeMember(ctx, opt.UserId, opt.ReceiverId); err != nil {
 			return errors.New("系统繁忙，请稍后再试！！！")
@@ -44,22 +55,30 @@ func (t *TalkAuthService) IsAuth(ctx context.Context, opt *TalkAuthOption) error
 		return errors.New("暂无权限发送消息！")
 	}
 
-	memberInfo := &model.GroupMember{}
-	err := t.contact.Db.First(memberInfo, "group_id = ? and user_id = ?", opt.ReceiverId, opt.UserId).Error
+	memberInfo, perms, err := t.permission.GroupPermissions(ctx, opt.ReceiverId, opt.UserId)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if errors.Is(err, ErrGroupNotMember) {
 			return errors.New("暂无权限发送消息！")
 		}
 
 		return errors.New("系统繁忙，请稍后再试！！！")
 	}
 
-	if memberInfo.IsQuit == 1 {
-		return errors.New("暂无权限发送消息！")
+	if memberInfo.IsMuted() {
+		return errors.New("已被群主或管理员禁言！")
 	}
 
-	if memberInfo.IsMute == 1 {
-		return errors.New("已被群主或管理员禁言！")
+	// 全员禁言及仅管理员发言不包含拥有禁言权限的成员
+	if !hasPermission(perms, entity.GroupPermMute) {
+		if group, err := t.groupRepo.FindById(ctx, opt.ReceiverId); err == nil {
+			if group.IsBroadcast == 1 {
+				return errors.New("当前群仅允许群主及管理员发言！")
+			}
+
+			if group.IsMuted() {
+				return errors.New("当前群已开启全员禁言！")
+			}
+		}
 	}
 
 	return nil
@@ -1,10 +1,10 @@
 package service
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"html"
 	"mime/multipart"
 	"sort"
 	"strconv"
@@ -19,12 +19,11 @@ import (
 
 	"go-chat/config"
 	"go-chat/internal/entity"
-	"go-chat/internal/pkg/encrypt"
 	"go-chat/internal/pkg/filesystem"
 	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/moderation"
 	"go-chat/internal/pkg/strutil"
 	"go-chat/internal/pkg/timeutil"
-	"go-chat/internal/pkg/utils"
 )
 
 type TalkMessageService struct {
@@ -38,10 +37,14 @@ type TalkMessageService struct {
 	client              *cache.ClientStorage
 	fileSystem          *filesystem.Filesystem
 	splitUploadDao      *repo.SplitUpload
+	media               *MediaService
+	blob                *FileBlobService
+	permission          *AuthPermissionService
+	moderation          *ModerationService
 }
 
-func NewTalkMessageService(baseService *BaseService, config *config.Config, unreadTalkCache *cache.UnreadStorage, lastMessage *cache.MessageStorage, talkRecordsVoteDao *repo.TalkRecordsVote, groupMemberDao *repo.GroupMember, sidServer *cache.ServerStorage, client *cache.ClientStorage, fileSystem *filesystem.Filesystem, splitUploadDao *repo.SplitUpload) *TalkMessageService {
-	return &TalkMessageService{BaseService: baseService, config: config, unreadTalkCache: unreadTalkCache, lastMessage: lastMessage, talkRecordsVoteRepo: talkRecordsVoteDao, groupMemberRepo: groupMemberDao, sidServer: sidServer, client: client, fileSystem: fileSystem, splitUploadDao: splitUploadDao}
+func NewTalkMessageService(baseService *BaseService, config *config.Config, unreadTalkCache *cache.UnreadStorage, lastMessage *cache.MessageStorage, talkRecordsVoteDao *repo.TalkRecordsVote, groupMemberDao *repo.GroupMember, sidServer *cache.ServerStorage, client *cache.ClientStorage, fileSystem *filesystem.Filesystem, splitUploadDao *repo.SplitUpload, media *MediaService, blob *FileBlobService, permission *AuthPermissionService, moderation *ModerationService) *TalkMessageService {
+	return &TalkMessageService{BaseService: baseService, config: config, unreadTalkCache: unreadTalkCache, lastMessage: lastMessage, talkRecordsVoteRepo: talkRecordsVoteDao, groupMemberRepo: groupMemberDao, sidServer: sidServer, client: client, fileSystem: fileSystem, splitUploadDao: splitUploadDao, media: media, blob: blob, permission: permission, moderation: moderation}
 }
 
 type SysTextMessageOpt struct {
@@ -72,6 +75,28 @@ func (s *TalkMessageService) SendSysMessage(ctx context.Context, opts *SysTextMe
 	return nil
 }
 
+// SendFriendApplyMessage 在新建立的私聊会话中发送好友申请验证消息
+func (s *TalkMessageService) SendFriendApplyMessage(ctx context.Context, apply *model.ContactApply) error {
+	record := &model.TalkRecords{
+		MsgId:      strutil.NewUuid(),
+		TalkType:   entity.ChatPrivateMode,
+		MsgType:    entity.MsgTypeFriendApply,
+		UserId:     apply.UserId,
+		ReceiverId: apply.FriendId,
+		Content:    html.EscapeString(apply.Remark),
+	}
+
+	if err := s.db.WithContext(ctx).Create(record).Error; err != nil {
+		return err
+	}
+
+	s.afterHandle(ctx, record, map[string]string{
+		"text": "[好友申请]",
+	})
+
+	return nil
+}
+
 type ImageMessageOpt struct {
 	UserId     int
 	TalkType   int
@@ -97,37 +122,32 @@ func (s *TalkMessageService) SendImageMessage(ctx context.Context, opts *ImageMe
 		return err
 	}
 
-	ext := strutil.FileSuffix(opts.File.Filename)
-
-	meta := utils.ReadImageMeta(bytes.NewReader(stream))
-
-	filePath := fmt.Sprintf("public/media/image/talk/%s/%s", timeutil.DateNumber(), strutil.GenImageName(ext, meta.Width, meta.Height))
-
-	if err := s.fileSystem.Default.Write(stream, filePath); err != nil {
+	image, err := s.media.StoreImage(ctx, stream)
+	if err != nil {
 		return err
 	}
 
+	file := &model.TalkRecordsFile{
+		UserId:       opts.UserId,
+		Source:       1,
+		Drive:        entity.FileDriveMode(s.fileSystem.Driver()),
+		OriginalName: opts.File.Filename,
+	}
+
+	s.media.ApplyImage(file, image)
+
 	err = s.db.Transaction(func(tx *gorm.DB) error {
-		if err = s.db.Create(record).Error; err != nil {
+		if err = tx.Create(record).Error; err != nil {
 			return err
 		}
 
-		if err = s.db.Create(&model.TalkRecordsFile{
-			RecordId:     record.Id,
-			UserId:       opts.UserId,
-			Source:       1,
-			Type:         entity.GetMediaType(ext),
-			Drive:        entity.FileDriveMode(s.fileSystem.Driver()),
-			OriginalName: opts.File.Filename,
-			Suffix:       ext,
-			Size:         int(opts.File.Size),
-			Path:         filePath,
-			Url:          s.fileSystem.Default.PublicUrl(filePath),
-		}).Error; err != nil {
+		file.RecordId = record.Id
+
+		if err = tx.Create(file).Error; err != nil {
 			return err
 		}
 
-		return nil
+		return s.blob.Dao().Retain(tx, file.BlobId)
 	})
 
 	if err != nil {
@@ -165,45 +185,72 @@ func (s *TalkMessageService) SendFileMessage(ctx context.Context, opts *FileMess
 		return err
 	}
 
-	filePath := fmt.Sprintf("private/files/talks/%s/%s.%s", timeutil.DateNumber(), encrypt.Md5(strutil.Random(16)), file.FileExt)
-	url := ""
-	if entity.GetMediaType(file.FileExt) <= 3 {
-		filePath = fmt.Sprintf("public/media/%s/%s.%s", timeutil.DateNumber(), encrypt.Md5(strutil.Random(16)), file.FileExt)
-		url = s.fileSystem.Default.PublicUrl(filePath)
+	names, result, err := s.moderation.CheckFields(ctx, &moderation.Message{
+		UserId:     opts.UserId,
+		TalkType:   opts.TalkType,
+		ReceiverId: opts.ReceiverId,
+	}, []string{file.OriginalName})
+	if err != nil {
+		return err
+	}
+
+	if result.Action == moderation.ActionBlock {
+		return ErrMessageBlocked
 	}
 
-	if err := s.fileSystem.Default.Copy(file.Path, filePath); err != nil {
-		logrus.Error("文件拷贝失败 err: ", err.Error())
+	// 媒体文件公开访问，其它文件私有存储
+	blob, err := s.blob.StoreUpload(ctx, file, entity.GetMediaType(file.FileExt) <= 3)
+	if err != nil {
+		logrus.Error("文件存储失败 err: ", err.Error())
 		return err
 	}
 
+	filePath := blob.Path
+
+	recordFile := &model.TalkRecordsFile{
+		UserId:       opts.UserId,
+		Source:       1,
+		Type:         entity.GetMediaType(file.FileExt),
+		Drive:        blob.Drive,
+		OriginalName: names[0],
+		Suffix:       file.FileExt,
+		Size:         int(file.FileSize),
+		Path:         filePath,
+		Url:          s.blob.PublicUrl(blob),
+		BlobId:       blob.Id,
+	}
+
+	// 媒体文件由服务端探测时长及尺寸等信息，探测失败时按普通文件发送
+	if recordFile.Type != entity.MediaFileOther {
+		if stream, err := s.media.ReadStream(blob.Drive, filePath); err == nil {
+			if _, err := s.media.Analyse(recordFile, stream); err != nil {
+				logrus.Warn("媒体文件解析失败 err: ", err.Error())
+			}
+		}
+	}
+
 	err = s.db.Transaction(func(tx *gorm.DB) error {
-		if err = s.db.Create(record).Error; err != nil {
+		if err = tx.Create(record).Error; err != nil {
 			return err
 		}
 
-		if err = s.db.Create(&model.TalkRecordsFile{
-			RecordId:     record.Id,
-			UserId:       opts.UserId,
-			Source:       1,
-			Type:         entity.GetMediaType(file.FileExt),
-			Drive:        file.Drive,
-			OriginalName: file.OriginalName,
-			Suffix:       file.FileExt,
-			Size:         int(file.FileSize),
-			Path:         filePath,
-			Url:          url,
-		}).Error; err != nil {
+		recordFile.RecordId = record.Id
+
+		if err = tx.Create(recordFile).Error; err != nil {
 			return err
 		}
 
-		return nil
+		return s.blob.Dao().Retain(tx, recordFile.BlobId)
 	})
 
 	if err != nil {
 		return err
 	}
 
+	if err := s.moderation.Review(ctx, record, result); err != nil {
+		logrus.Error("加入审核队列失败 err: ", err.Error())
+	}
+
 	s.afterHandle(ctx, record, map[string]string{"text": "[文件消息]"})
 
 	return nil
@@ -239,11 +286,11 @@ func (s *TalkMessageService) SendEmoticonMessage(ctx context.Context, opts *Emot
 	}
 
 	err = s.db.Transaction(func(tx *gorm.DB) error {
-		if err = s.db.Create(record).Error; err != nil {
+		if err = tx.Create(record).Error; err != nil {
 			return err
 		}
 
-		if err = s.db.Create(&model.TalkRecordsFile{
+		if err = tx.Create(&model.TalkRecordsFile{
 			RecordId:     record.Id,
 			UserId:       opts.UserId,
 			Source:       2,
@@ -253,11 +300,12 @@ func (s *TalkMessageService) SendEmoticonMessage(ctx context.Context, opts *Emot
 			Size:         emoticon.FileSize,
 			Path:         emoticon.Url,
 			Url:          emoticon.Url,
+			BlobId:       emoticon.BlobId,
 		}).Error; err != nil {
 			return err
 		}
 
-		return nil
+		return s.blob.Dao().Retain(tx, emoticon.BlobId)
 	})
 
 	if err != nil {
@@ -285,10 +333,15 @@ func (s *TalkMessageService) SendRevokeRecordMessage(ctx context.Context, uid in
 	}
 
 	if record.UserId != uid {
-		return errors.New("无权撤回回消息")
-	}
+		// 群聊中拥有撤回权限的成员可撤回身份低于自己的成员的消息
+		if record.TalkType != entity.ChatGroupMode || record.UserId == 0 {
+			return errors.New("无权撤回回消息")
+		}
 
-	if time.Now().Unix() > record.CreatedAt.Add(3*time.Minute).Unix() {
+		if err := s.permission.GroupAuthorizeMember(ctx, record.ReceiverId, uid, entity.GroupPermRevoke, record.UserId); err != nil {
+			return errors.New("无权撤回回消息")
+		}
+	} else if time.Now().Unix() > record.CreatedAt.Add(3*time.Minute).Unix() {
 		return errors.New("超出有效撤回时间范围，无法进行撤销！")
 	}
 
@@ -299,12 +352,23 @@ func (s *TalkMessageService) SendRevokeRecordMessage(ctx context.Context, uid in
 	body := map[string]interface{}{
 		"event": entity.EventTalkRevoke,
 		"data": jsonutil.Encode(map[string]interface{}{
-			"record_id": record.Id,
+			"record_id":   record.Id,
+			"operator_id": uid,
 		}),
 	}
 
 	s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(body))
 
+	// 管理员撤回他人消息时在群内留下系统提示
+	if record.UserId != uid {
+		_ = s.SendSysMessage(ctx, &SysTextMessageOpt{
+			UserId:     uid,
+			TalkType:   entity.ChatGroupMode,
+			ReceiverId: record.ReceiverId,
+			Text:       groupRevokeText(ctx, s.db, uid, []int{record.UserId}, 1),
+		})
+	}
+
 	return nil
 }
 
@@ -344,6 +408,9 @@ func (s *TalkMessageService) VoteHandle(ctx context.Context, opts *VoteMessageHa
 	}
 
 	// 判断是否有投票权限
+	if vote.TalkType != entity.ChatGroupMode || !s.groupMemberRepo.IsRecordVisible(ctx, vote.ReceiverId, opts.UserId, opts.RecordId) {
+		return 0, entity.ErrPermissionDenied
+	}
 
 	var count int64
 	s.db.Table("talk_records_vote_answer").Where("vote_id = ? and user_id = ？", vote.VoteId, opts.UserId).Count(&count)
@@ -415,17 +482,8 @@ func (s *TalkMessageService) afterHandle(ctx context.Context, record *model.Talk
 			s.unreadTalkCache.Incr(ctx, 1, record.ReceiverId, record.UserId)
 		}
 	} else if record.TalkType == entity.ChatGroupMode {
-
-		// todo 需要加缓存
-		ids := s.groupMemberRepo.GetMemberIds(ctx, record.ReceiverId)
-		for _, uid := range ids {
-
-			if uid == record.UserId {
-				continue
-			}
-
-			s.unreadTalkCache.Incr(ctx, entity.ChatGroupMode, record.ReceiverId, uid)
-		}
+		// 群消息仅累加群消息计数，成员未读数由已读游标计算
+		s.unreadTalkCache.IncrGroup(ctx, record.ReceiverId, record.UserId)
 	}
 
 	_ = s.lastMessage.Set(ctx, record.TalkType, record.UserId, record.ReceiverId, &cache.LastCacheMessage{
@@ -35,9 +35,16 @@ type TalkRecordsItem struct {
 	Vote       interface{} `json:"vote,omitempty"`
 	Login      interface{} `json:"login,omitempty"`
 	Location   interface{} `json:"location,omitempty"`
+	Notice     interface{} `json:"notice,omitempty"`
 	CreatedAt  string      `json:"created_at"`
 }
 
+// TalkRecordsFileItem 文件消息信息
+type TalkRecordsFileItem struct {
+	*model.TalkRecordsFile
+	Renditions []*ImageRendition `json:"renditions,omitempty"` // 图片多尺寸规格
+}
+
 type TalkRecordsService struct {
 	*BaseService
 	talkVoteCache       *cache.TalkVote
@@ -92,6 +99,13 @@ func (s *TalkRecordsService) GetTalkRecords(ctx context.Context, opts *QueryTalk
 		query.Where("talk_records.id < ?", opts.RecordId)
 	}
 
+	// 群成员仅可查看可见范围内的历史消息
+	if opts.TalkType == entity.ChatGroupMode {
+		if minRecordId := s.groupMemberRepo.GetMinRecordId(ctx, opts.ReceiverId, opts.UserId); minRecordId > 0 {
+			query.Where("talk_records.id >= ?", minRecordId)
+		}
+	}
+
 	if opts.TalkType == entity.ChatPrivateMode {
 		subQuery := s.db.Where("talk_records.user_id = ? and talk_records.receiver_id = ?", opts.UserId, opts.ReceiverId)
 		subQuery.Or("talk_records.user_id = ? and talk_records.receiver_id = ?", opts.ReceiverId, opts.UserId)
@@ -172,7 +186,7 @@ func (s *TalkRecordsService) GetForwardRecords(ctx context.Context, uid int, rec
 			return nil, entity.ErrPermissionDenied
 		}
 	} else if record.TalkType == entity.ChatGroupMode {
-		if !s.groupMemberRepo.IsMember(ctx, record.ReceiverId, uid, true) {
+		if !s.groupMemberRepo.IsRecordVisible(ctx, record.ReceiverId, uid, record.Id) {
 			return nil, entity.ErrPermissionDenied
 		}
 	} else {
@@ -221,6 +235,7 @@ func (s *TalkRecordsService) HandleTalkRecords(ctx context.Context, items []*mod
 		votes     []int
 		logins    []int
 		locations []int
+		notices   []int
 
 		fileItems     []*model.TalkRecordsFile
 		codeItems     []*model.TalkRecordsCode
@@ -229,6 +244,7 @@ func (s *TalkRecordsService) HandleTalkRecords(ctx context.Context, items []*mod
 		voteItems     []*model.TalkRecordsVote
 		loginItems    []*model.TalkRecordsLogin
 		locationItems []*model.TalkRecordsLocation
+		noticeItems   []*model.TalkRecordsNotice
 	)
 
 	for _, item := range items {
@@ -242,6 +258,7 @@ func (s *TalkRecordsService) HandleTalkRecords(ctx context.Context, items []*mod
 		case entity.MsgTypeVote:
 			votes = append(votes, item.Id)
 		case entity.MsgTypeGroupNotice:
+			notices = append(notices, item.Id)
 		case entity.MsgTypeFriendApply:
 		case entity.MsgTypeLogin:
 			logins = append(logins, item.Id)
@@ -284,6 +301,14 @@ func (s *TalkRecordsService) HandleTalkRecords(ctx context.Context, items []*mod
 		}
 	}
 
+	hashNotices := make(map[int]*model.TalkRecordsNotice)
+	if len(notices) > 0 {
+		s.db.Model(&model.TalkRecordsNotice{}).Where("record_id in ?", notices).Scan(&noticeItems)
+		for i := range noticeItems {
+			hashNotices[noticeItems[i].RecordId] = noticeItems[i]
+		}
+	}
+
 	hashLogins := make(map[int]*model.TalkRecordsLogin)
 	if len(logins) > 0 {
 		s.db.Model(&model.TalkRecordsLogin{}).Where("record_id in ?", logins).Scan(&loginItems)
@@ -331,7 +356,12 @@ func (s *TalkRecordsService) HandleTalkRecords(ctx context.Context, items []*mod
 		switch item.MsgType {
 		case entity.MsgTypeFile:
 			if value, ok := hashFiles[item.Id]; ok {
-				data.File = value
+				file := &TalkRecordsFileItem{TalkRecordsFile: value}
+				if value.Renditions != "" {
+					_ = jsonutil.Decode(value.Renditions, &file.Renditions)
+				}
+
+				data.File = file
 			} else {
 				logger.Warnf("文件消息信息不存在[%d]", item.Id)
 			}
@@ -403,6 +433,15 @@ func (s *TalkRecordsService) HandleTalkRecords(ctx context.Context, items []*mod
 				}
 			}
 		case entity.MsgTypeGroupNotice:
+			if value, ok := hashNotices[item.Id]; ok {
+				data.Notice = map[string]interface{}{
+					"notice_id":  value.NoticeId,
+					"title":      value.Title,
+					"content":    value.Content,
+					"is_confirm": value.IsConfirm,
+					"is_update":  value.IsUpdate,
+				}
+			}
 		case entity.MsgTypeFriendApply:
 		case entity.MsgTypeLogin:
 			if value, ok := hashLogins[item.Id]; ok {
@@ -429,11 +468,12 @@ func (s *TalkRecordsService) HandleTalkRecords(ctx context.Context, items []*mod
 
 				m := map[string]interface{}{
 					"type":         value.Type,
+					"sys_type":     entity.GroupInviteSysTypes[value.Type],
 					"operate_user": operateUser,
 					"users":        map[string]interface{}{},
 				}
 
-				if value.Type == 1 || value.Type == 3 {
+				if sliceutil.Include(value.Type, []int{entity.GroupInviteTypeJoin, entity.GroupInviteTypeSelfJoin, entity.GroupInviteTypeKicked, entity.GroupInviteTypeCreate, entity.GroupInviteTypeHandover, entity.GroupInviteTypeMemberMuted, entity.GroupInviteTypeMemberCancelMuted}) {
 					var results []map[string]interface{}
 					s.db.Model(&model.Users{}).Select("id", "nickname").Where("id in ?", sliceutil.ParseIds(value.UserIds)).Scan(&results)
 					m["users"] = results
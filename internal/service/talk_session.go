@@ -38,11 +38,13 @@ func (s *TalkSessionService) List(ctx context.Context, uid int) ([]*model.Search
 		"list.is_disturb", "list.is_top", "list.is_robot",
 		"`users`.avatar as user_avatar", "`users`.nickname",
 		"`group`.group_name", "`group`.avatar as group_avatar",
+		"`channel`.name as channel_name", "`channel`.avatar as channel_avatar",
 	}
 
 	query := s.db.Table("talk_session list")
 	query.Joins("left join `users` ON list.receiver_id = `users`.id AND list.talk_type = 1")
 	query.Joins("left join `group` ON list.receiver_id = `group`.id AND list.talk_type = 2")
+	query.Joins("left join `channel` ON list.receiver_id = `channel`.id AND list.talk_type = 3")
 	query.Where("list.user_id = ? and list.is_delete = 0", uid)
 	query.Order("list.updated_at desc")
 
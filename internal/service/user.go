@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 
+	"go-chat/internal/entity"
 	"go-chat/internal/pkg/encrypt"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
 	"gorm.io/gorm"
 )
 
+var ErrUserDisabled = errors.New("账号已被禁用，请联系管理员! ")
+
 type UserService struct {
 	repo *repo.Users
 }
@@ -63,6 +66,10 @@ func (s *UserService) Login(mobile string, password string) (*model.Users, error
 		return nil, errors.New("登录密码填写错误! ")
 	}
 
+	if user.Status == entity.UserStatusDisabled {
+		return nil, ErrUserDisabled
+	}
+
 	return user, nil
 }
 
@@ -110,3 +117,16 @@ func (s *UserService) UpdatePassword(uid int, oldPassword string, password strin
 
 	return nil
 }
+
+// SetStatus 设置用户账号状态
+func (s *UserService) SetStatus(ctx context.Context, uid int, status int) error {
+
+	user, err := s.repo.FindById(ctx, uid)
+	if err != nil || user.IsRobot == 1 {
+		return errors.New("用户不存在！")
+	}
+
+	_, err = s.repo.UpdateById(ctx, uid, map[string]interface{}{"status": status})
+
+	return err
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-redis/redis/v8"
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/oidc"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/repository/repo/organize"
+	"gorm.io/gorm"
+)
+
+const oidcStateExpire = 10 * time.Minute // 授权请求有效期
+
+var (
+	ErrOidcDisabled     = errors.New("未开启单点登录")
+	ErrOidcState        = errors.New("登录请求已失效，请重新登录")
+	ErrOidcUnregistered = errors.New("账号未开通，请联系管理员")
+)
+
+var mobileRegexp = regexp.MustCompile(`^1[3-9]\d{9}$`)
+
+// OidcState 单点登录授权请求
+type OidcState struct {
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+	Platform string `json:"platform"`
+}
+
+// UserOidcService OpenID Connect 单点登录
+type UserOidcService struct {
+	*BaseService
+	conf         *config.Config
+	provider     *oidc.Provider
+	usersRepo    *repo.Users
+	identityRepo *repo.UserIdentity
+	organizeRepo *organize.Organize
+}
+
+func NewUserOidcService(baseService *BaseService, conf *config.Config, client *http.Client, usersRepo *repo.Users, identityRepo *repo.UserIdentity, organizeRepo *organize.Organize) *UserOidcService {
+
+	s := &UserOidcService{BaseService: baseService, conf: conf, usersRepo: usersRepo, identityRepo: identityRepo, organizeRepo: organizeRepo}
+
+	if conf.Oidc.IsEnabled() {
+		s.provider = oidc.NewProvider(&oidc.Config{
+			Issuer:       conf.Oidc.Issuer,
+			ClientId:     conf.Oidc.ClientId,
+			ClientSecret: conf.Oidc.ClientSecret,
+			RedirectUrl:  conf.Oidc.RedirectUrl,
+			Scopes:       conf.Oidc.Scopes,
+		}, client)
+	}
+
+	return s
+}
+
+// IsEnabled 是否开启单点登录
+func (s *UserOidcService) IsEnabled() bool {
+	return s.provider != nil
+}
+
+// Authorize 创建授权请求，返回 IdP 授权地址
+func (s *UserOidcService) Authorize(ctx context.Context, platform string) (string, error) {
+
+	if !s.IsEnabled() {
+		return "", ErrOidcDisabled
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+
+	data := &OidcState{Platform: platform}
+	if data.Verifier, err = oidc.RandomString(); err != nil {
+		return "", err
+	}
+
+	if data.Nonce, err = oidc.RandomString(); err != nil {
+		return "", err
+	}
+
+	uri, err := s.provider.AuthCodeURL(ctx, state, data.Nonce, data.Verifier)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.rds.Set(ctx, s.stateKey(state), jsonutil.Encode(data), oidcStateExpire).Err(); err != nil {
+		return "", err
+	}
+
+	return uri, nil
+}
+
+// Login 使用授权码完成登录，返回关联的用户及登录平台
+func (s *UserOidcService) Login(ctx context.Context, state string, code string) (*model.Users, string, error) {
+
+	if !s.IsEnabled() {
+		return nil, "", ErrOidcDisabled
+	}
+
+	data, err := s.state(ctx, state)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := s.provider.Exchange(ctx, code, data.Verifier)
+	if err != nil {
+		return nil, "", err
+	}
+
+	claims, err := s.provider.VerifyIdToken(ctx, token.IdToken, data.Nonce)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := s.link(ctx, claims)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if user.Status == entity.UserStatusDisabled {
+		return nil, "", ErrUserDisabled
+	}
+
+	s.syncOrganize(ctx, user.Id, claims)
+
+	return user, data.Platform, nil
+}
+
+// state 读取并删除授权请求，授权请求只能使用一次
+func (s *UserOidcService) state(ctx context.Context, state string) (*OidcState, error) {
+
+	if state == "" {
+		return nil, ErrOidcState
+	}
+
+	pipe := s.rds.TxPipeline()
+	get := pipe.Get(ctx, s.stateKey(state))
+	pipe.Del(ctx, s.stateKey(state))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrOidcState
+		}
+
+		return nil, err
+	}
+
+	data := &OidcState{}
+	if err := jsonutil.Decode(get.Val(), data); err != nil {
+		return nil, ErrOidcState
+	}
+
+	return data, nil
+}
+
+// link 查找第三方身份关联的用户，未关联时按邮箱关联或自动创建账号
+func (s *UserOidcService) link(ctx context.Context, claims *oidc.Claims) (*model.Users, error) {
+
+	issuer := s.conf.Oidc.Issuer
+
+	identity, err := s.identityRepo.FindBySubject(ctx, issuer, claims.Subject)
+	if err == nil {
+		return s.usersRepo.FindById(ctx, identity.UserId)
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var user *model.Users
+
+	// 仅信任 IdP 已验证的邮箱
+	if s.conf.Oidc.LinkEmail && claims.Email != "" && claims.EmailVerified {
+		items, err := s.usersRepo.FindAll(ctx, func(db *gorm.DB) {
+			db.Where("email = ? and is_robot = 0", claims.Email).Limit(2)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(items) == 1 {
+			user = items[0]
+		}
+	}
+
+	if user == nil {
+		if !s.conf.Oidc.AutoRegister {
+			return nil, ErrOidcUnregistered
+		}
+
+		if user, err = s.register(claims); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.identityRepo.Create(ctx, &model.UserIdentity{
+		UserId:   user.Id,
+		Provider: issuer,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// register 根据 ID Token 中的用户信息创建账号
+func (s *UserOidcService) register(claims *oidc.Claims) (*model.Users, error) {
+
+	password, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.Users{
+		Nickname: truncateRunes(s.nickname(claims), 20),
+		Password: encrypt.HashPassword(password),
+	}
+
+	if len(claims.Email) <= 30 && claims.EmailVerified {
+		user.Email = claims.Email
+	}
+
+	if len(claims.Picture) <= 255 {
+		user.Avatar = claims.Picture
+	}
+
+	mobile := strings.TrimPrefix(claims.PhoneNumber, "+86")
+	if mobileRegexp.MatchString(mobile) && !s.usersRepo.IsMobileExist(mobile) {
+		user.Mobile = mobile
+	}
+
+	return s.usersRepo.Create(user)
+}
+
+func (s *UserOidcService) nickname(claims *oidc.Claims) string {
+
+	for _, value := range []string{claims.Name, claims.PreferredUsername} {
+		if value != "" {
+			return value
+		}
+	}
+
+	if name, _, ok := strings.Cut(claims.Email, "@"); ok && name != "" {
+		return name
+	}
+
+	return fmt.Sprintf("用户%s", truncateRunes(claims.Subject, 8))
+}
+
+// syncOrganize 根据声明同步企业成员的部门
+func (s *UserOidcService) syncOrganize(ctx context.Context, uid int, claims *oidc.Claims) {
+
+	conf := s.conf.Oidc.Organize
+	if conf == nil || !conf.Enable {
+		return
+	}
+
+	department := conf.DefaultDepartment
+	if value, ok := conf.DepartmentMapping[claims.String(conf.DepartmentClaim)]; ok {
+		department = value
+	}
+
+	info, err := s.organizeRepo.FindByWhere(ctx, "user_id = ?", uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = s.organizeRepo.Create(ctx, &model.Organize{UserId: uid, Department: department})
+		}
+
+		return
+	}
+
+	if department != "" && info.Department != department {
+		_, _ = s.organizeRepo.UpdateById(ctx, info.Id, map[string]interface{}{"department": department})
+	}
+}
+
+func (s *UserOidcService) stateKey(state string) string {
+	return fmt.Sprintf("auth:oidc-state:%s", state)
+}
+
+func truncateRunes(value string, length int) string {
+
+	if utf8.RuneCountInString(value) <= length {
+		return value
+	}
+
+	return string([]rune(value)[:length])
+}
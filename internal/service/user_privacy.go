@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrUserBlockSelf     = errors.New("不能将自己加入黑名单")
+	ErrUserBlockNotFound = errors.New("用户不存在")
+)
+
+type UserPrivacyUpdateOpts struct {
+	AddContact   int
+	OnlineStatus int
+	MobileSearch int
+}
+
+type UserPrivacyService struct {
+	*BaseService
+	repo      *repo.UserPrivacy
+	blockRepo *repo.UserBlock
+}
+
+func NewUserPrivacyService(baseService *BaseService, repo *repo.UserPrivacy, blockRepo *repo.UserBlock) *UserPrivacyService {
+	return &UserPrivacyService{BaseService: baseService, repo: repo, blockRepo: blockRepo}
+}
+
+// Get 获取隐私设置
+func (s *UserPrivacyService) Get(ctx context.Context, uid int) *model.UserPrivacy {
+	return s.repo.FindByUserId(ctx, uid)
+}
+
+// Update 修改隐私设置
+func (s *UserPrivacyService) Update(ctx context.Context, uid int, opts *UserPrivacyUpdateOpts) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"add_contact", "online_status", "mobile_search", "updated_at"}),
+	}).Create(&model.UserPrivacy{
+		UserId:       uid,
+		AddContact:   opts.AddContact,
+		OnlineStatus: opts.OnlineStatus,
+		MobileSearch: opts.MobileSearch,
+	}).Error
+}
+
+// Block 将用户加入黑名单
+func (s *UserPrivacyService) Block(ctx context.Context, uid int, blockUserId int) error {
+
+	if uid == blockUserId {
+		return ErrUserBlockSelf
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&model.Users{}).Where("id = ?", blockUserId).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrUserBlockNotFound
+	}
+
+	return s.db.WithContext(ctx).Where("user_id = ? and block_user_id = ?", uid, blockUserId).FirstOrCreate(&model.UserBlock{
+		UserId:      uid,
+		BlockUserId: blockUserId,
+	}).Error
+}
+
+// Unblock 将用户移出黑名单
+func (s *UserPrivacyService) Unblock(ctx context.Context, uid int, blockUserId int) error {
+	return s.db.WithContext(ctx).Where("user_id = ? and block_user_id = ?", uid, blockUserId).Delete(&model.UserBlock{}).Error
+}
+
+// BlockList 黑名单列表
+func (s *UserPrivacyService) BlockList(ctx context.Context, uid int) ([]*model.UserBlockItem, error) {
+
+	items := make([]*model.UserBlockItem, 0)
+
+	err := s.blockRepo.Model(ctx).Select("user_block.block_user_id as user_id,users.nickname,users.avatar,user_block.created_at").
+		Joins("inner join users on users.id = user_block.block_user_id").
+		Where("user_block.user_id = ?", uid).
+		Order("user_block.id desc").
+		Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+var (
+	ErrUserSessionInvalid  = errors.New("登录已失效，请重新登录")
+	ErrUserSessionReused   = errors.New("刷新令牌已失效，该设备已被注销登录")
+	ErrUserSessionNotFound = errors.New("设备会话不存在")
+)
+
+type UserSessionCreateOpts struct {
+	UserId   int
+	Platform string
+	Ip       string
+	Agent    string
+}
+
+type UserSessionService struct {
+	*BaseService
+	repo      *repo.UserSession
+	conf      *config.Config
+	session   *cache.TokenSessionStorage
+	ipAddress *IpAddressService
+}
+
+func NewUserSessionService(baseService *BaseService, repo *repo.UserSession, conf *config.Config, session *cache.TokenSessionStorage, ipAddress *IpAddressService) *UserSessionService {
+	return &UserSessionService{BaseService: baseService, repo: repo, conf: conf, session: session, ipAddress: ipAddress}
+}
+
+func (s *UserSessionService) Dao() *repo.UserSession {
+	return s.repo
+}
+
+// Create 创建设备会话，返回会话信息及刷新令牌
+func (s *UserSessionService) Create(ctx context.Context, opts *UserSessionCreateOpts) (*model.UserSession, string, error) {
+
+	address, _ := s.ipAddress.FindAddress(opts.Ip)
+
+	sessionId := uuid.New().String()
+
+	token, err := newRefreshToken(sessionId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	agent := opts.Agent
+	if len(agent) > 300 {
+		agent = agent[:300]
+	}
+
+	now := time.Now()
+
+	data := &model.UserSession{
+		UserId:           opts.UserId,
+		SessionId:        sessionId,
+		Platform:         opts.Platform,
+		Ip:               opts.Ip,
+		Address:          address,
+		Agent:            agent,
+		RefreshToken:     encrypt.Sha256([]byte(token)),
+		RefreshExpiresAt: now.Add(time.Duration(s.conf.Jwt.GetRefreshExpiresTime()) * time.Second),
+		LastActiveAt:     now,
+	}
+
+	if err := s.repo.Create(ctx, data); err != nil {
+		return nil, "", err
+	}
+
+	return data, token, nil
+}
+
+// Refresh 轮换刷新令牌，返回会话信息及新的刷新令牌
+// 已轮换的刷新令牌再次使用时视为令牌泄露，注销该设备会话
+func (s *UserSessionService) Refresh(ctx context.Context, refreshToken string) (*model.UserSession, string, error) {
+
+	sessionId, _, ok := strings.Cut(refreshToken, ".")
+	if !ok {
+		return nil, "", ErrUserSessionInvalid
+	}
+
+	info, err := s.repo.FindBySessionId(ctx, sessionId)
+	if err != nil {
+		return nil, "", ErrUserSessionInvalid
+	}
+
+	if info.IsRevoked == 1 || info.RefreshExpiresAt.Before(time.Now()) {
+		return nil, "", ErrUserSessionInvalid
+	}
+
+	hash := encrypt.Sha256([]byte(refreshToken))
+	if hash != info.RefreshToken {
+		_ = s.revoke(ctx, info)
+		return nil, "", ErrUserSessionReused
+	}
+
+	token, err := newRefreshToken(sessionId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+
+	// 条件更新，并发刷新时仅有一个请求能够轮换成功
+	res := s.Db().Model(&model.UserSession{}).Where("id = ? and refresh_token = ? and is_revoked = 0", info.Id, hash).Updates(map[string]interface{}{
+		"refresh_token":      encrypt.Sha256([]byte(token)),
+		"refresh_expires_at": now.Add(time.Duration(s.conf.Jwt.GetRefreshExpiresTime()) * time.Second),
+		"last_active_at":     now,
+	})
+
+	if res.Error != nil {
+		return nil, "", res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, "", ErrUserSessionInvalid
+	}
+
+	return info, token, nil
+}
+
+// List 用户已登录的设备列表
+func (s *UserSessionService) List(ctx context.Context, uid int) ([]*model.UserSession, error) {
+	return s.repo.FindActive(ctx, uid)
+}
+
+// Revoke 注销指定设备会话
+func (s *UserSessionService) Revoke(ctx context.Context, uid int, sessionId string) error {
+
+	info, err := s.repo.FindByWhere(ctx, "user_id = ? and session_id = ? and is_revoked = 0", uid, sessionId)
+	if err != nil {
+		return ErrUserSessionNotFound
+	}
+
+	return s.revoke(ctx, info)
+}
+
+// RevokeOthers 注销除当前设备外的所有设备会话，返回注销的设备数
+func (s *UserSessionService) RevokeOthers(ctx context.Context, uid int, current string) (int, error) {
+
+	items, err := s.repo.FindActive(ctx, uid)
+	if err != nil {
+		return 0, err
+	}
+
+	list := make([]*model.UserSession, 0, len(items))
+	for _, item := range items {
+		if item.SessionId != current {
+			list = append(list, item)
+		}
+	}
+
+	if err := s.revoke(ctx, list...); err != nil {
+		return 0, err
+	}
+
+	return len(list), nil
+}
+
+// RevokeAll 注销用户的所有设备会话，返回注销的设备数
+func (s *UserSessionService) RevokeAll(ctx context.Context, uid int) (int, error) {
+	return s.RevokeOthers(ctx, uid, "")
+}
+
+// revoke 注销设备会话，并断开该设备的 IM 连接
+func (s *UserSessionService) revoke(ctx context.Context, items ...*model.UserSession) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.Id)
+	}
+
+	if err := s.Db().Model(&model.UserSession{}).Where("id in ?", ids).Update("is_revoked", 1).Error; err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		// 已签发的访问令牌在过期前仍需拦截
+		_ = s.session.SetRevoked(ctx, item.SessionId, time.Duration(s.conf.Jwt.ExpiresTime)*time.Second)
+
+		s.rds.Publish(ctx, entity.ImTopicChat, jsonutil.Encode(map[string]interface{}{
+			"event": entity.EventSessionRevoke,
+			"data": jsonutil.Encode(map[string]interface{}{
+				"user_id":    item.UserId,
+				"session_id": item.SessionId,
+			}),
+		}))
+	}
+
+	return nil
+}
+
+// newRefreshToken 生成刷新令牌，格式为 {会话标识}.{随机串}
+func newRefreshToken(sessionId string) (string, error) {
+	value, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	return sessionId + "." + value, nil
+}
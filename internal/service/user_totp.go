@@ -0,0 +1,430 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-chat/config"
+	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/totp"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/repository/repo/organize"
+	"gorm.io/gorm"
+)
+
+const (
+	totpChallengeExpire   = 5 * time.Minute  // 登录挑战有效期
+	totpChallengeAttempts = 5                // 登录挑战最大尝试次数
+	totpFailureWindow     = 15 * time.Minute // 用户验证失败次数统计周期
+	totpFailureLimit      = 10               // 统计周期内用户最大验证失败次数(不区分登录挑战)
+	totpRecoveryCodeNum   = 10               // 恢复码数量
+)
+
+var (
+	ErrTotpInvalidCode  = errors.New("验证码填写错误")
+	ErrTotpNotEnabled   = errors.New("未开启两步验证")
+	ErrTotpEnabled      = errors.New("已开启两步验证")
+	ErrTotpNotEnrolled  = errors.New("请先绑定认证器")
+	ErrTotpForced       = errors.New("企业成员已被要求开启两步验证，无法关闭")
+	ErrTotpChallenge    = errors.New("登录验证已失效，请重新登录")
+	ErrTotpPassword     = errors.New("登录密码填写错误")
+	ErrTotpSetupInvalid = errors.New("当前账号无需绑定认证器")
+	ErrTotpTooMany      = errors.New("验证失败次数过多，请稍后再试")
+)
+
+// TotpChallenge 两步验证登录挑战
+type TotpChallenge struct {
+	UserId   int    `json:"user_id"`
+	Platform string `json:"platform"`
+	Setup    bool   `json:"setup"` // 是否需要先绑定认证器(企业强制开启且未绑定)
+}
+
+// TotpEnrollment 认证器绑定信息
+type TotpEnrollment struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
+
+type UserTotpService struct {
+	*BaseService
+	repo         *repo.UserTotp
+	conf         *config.Config
+	usersRepo    *repo.Users
+	organizeRepo *organize.Organize
+	rateLimit    *cache.RateLimitStorage
+}
+
+func NewUserTotpService(baseService *BaseService, repo *repo.UserTotp, conf *config.Config, usersRepo *repo.Users, organizeRepo *organize.Organize, rateLimit *cache.RateLimitStorage) *UserTotpService {
+	return &UserTotpService{BaseService: baseService, repo: repo, conf: conf, usersRepo: usersRepo, organizeRepo: organizeRepo, rateLimit: rateLimit}
+}
+
+func (s *UserTotpService) Dao() *repo.UserTotp {
+	return s.repo
+}
+
+// IsForced 判断用户是否被企业强制开启两步验证
+func (s *UserTotpService) IsForced(ctx context.Context, uid int) bool {
+	exist, _ := s.organizeRepo.QueryExist(ctx, "user_id = ? and is_force_totp = 1", uid)
+	return exist
+}
+
+// Status 两步验证状态
+func (s *UserTotpService) Status(ctx context.Context, uid int) (enabled bool, forced bool, recovery int) {
+	if info, err := s.repo.FindByUserId(ctx, uid); err == nil && info.IsEnabled == 1 {
+		enabled, recovery = true, len(s.recoveryCodes(info))
+	}
+
+	return enabled, s.IsForced(ctx, uid), recovery
+}
+
+// CreateChallenge 密码验证通过后创建登录挑战，不需要两步验证时返回空字符串
+func (s *UserTotpService) CreateChallenge(ctx context.Context, uid int, platform string) (string, *TotpChallenge, error) {
+
+	enabled, err := s.repo.IsEnabled(ctx, uid)
+	if err != nil {
+		return "", nil, err
+	}
+
+	challenge := &TotpChallenge{UserId: uid, Platform: platform}
+	if !enabled {
+		if !s.IsForced(ctx, uid) {
+			return "", nil, nil
+		}
+
+		challenge.Setup = true
+	}
+
+	token, err := randomHex(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := s.rds.Set(ctx, s.challengeKey(token), jsonutil.Encode(challenge), totpChallengeExpire).Err(); err != nil {
+		return "", nil, err
+	}
+
+	return token, challenge, nil
+}
+
+// ChallengeSetup 登录挑战中绑定认证器(企业强制开启且未绑定)
+func (s *UserTotpService) ChallengeSetup(ctx context.Context, token string) (*TotpEnrollment, error) {
+	challenge, err := s.challenge(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !challenge.Setup {
+		return nil, ErrTotpSetupInvalid
+	}
+
+	return s.Enroll(ctx, challenge.UserId)
+}
+
+// ChallengeVerify 校验登录挑战，成功后返回挑战信息
+// 处于绑定流程时同时完成认证器绑定并返回恢复码
+func (s *UserTotpService) ChallengeVerify(ctx context.Context, token string, code string) (*TotpChallenge, []string, error) {
+	challenge, err := s.challenge(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 验证前先原子占用尝试次数，并发请求也无法超出限制
+	if ok, err := s.rateLimit.Allow(ctx, s.attemptsKey(token), totpChallengeAttempts, totpChallengeExpire); err != nil {
+		return nil, nil, err
+	} else if !ok {
+		s.rds.Del(ctx, s.challengeKey(token))
+		return nil, nil, ErrTotpChallenge
+	}
+
+	// 用户维度的失败次数限制，避免通过重新登录创建新挑战继续尝试
+	if ok, err := s.rateLimit.Allow(ctx, s.failureKey(challenge.UserId), totpFailureLimit, totpFailureWindow); err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, ErrTotpTooMany
+	}
+
+	var codes []string
+	if challenge.Setup {
+		codes, err = s.Confirm(ctx, challenge.UserId, code)
+	} else {
+		err = s.Verify(ctx, challenge.UserId, code)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 挑战仅允许使用一次
+	if s.rds.Del(ctx, s.challengeKey(token)).Val() == 0 {
+		return nil, nil, ErrTotpChallenge
+	}
+
+	_ = s.rateLimit.Clear(ctx, s.attemptsKey(token))
+	_ = s.rateLimit.Clear(ctx, s.failureKey(challenge.UserId))
+
+	return challenge, codes, nil
+}
+
+// Enroll 生成待确认的认证器秘钥，已启用时需先关闭
+func (s *UserTotpService) Enroll(ctx context.Context, uid int) (*TotpEnrollment, error) {
+
+	user, err := s.usersRepo.FindById(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.repo.FindByUserId(ctx, uid)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if info != nil && info.IsEnabled == 1 {
+		return nil, ErrTotpEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if info == nil {
+		err = s.repo.Create(ctx, &model.UserTotp{UserId: uid, Secret: secret, RecoveryCodes: "[]"})
+	} else {
+		_, err = s.repo.UpdateById(ctx, info.Id, map[string]interface{}{"secret": secret, "last_counter": 0})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &TotpEnrollment{
+		Secret: secret,
+		Uri:    totp.ProvisioningURI(s.issuer(), user.Mobile, secret),
+	}, nil
+}
+
+// Confirm 校验验证码确认绑定认证器，返回一次性恢复码
+func (s *UserTotpService) Confirm(ctx context.Context, uid int, code string) ([]string, error) {
+
+	info, err := s.repo.FindByUserId(ctx, uid)
+	if err != nil {
+		return nil, ErrTotpNotEnrolled
+	}
+
+	if info.IsEnabled == 1 {
+		return nil, ErrTotpEnabled
+	}
+
+	counter, ok := totp.Validate(info.Secret, code, time.Now(), 1)
+	if !ok {
+		return nil, ErrTotpInvalidCode
+	}
+
+	codes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.repo.UpdateById(ctx, info.Id, map[string]interface{}{
+		"is_enabled":     1,
+		"recovery_codes": jsonutil.Encode(hashes),
+		"last_counter":   counter,
+		"enabled_at":     time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Verify 校验验证码或恢复码，恢复码使用后失效
+func (s *UserTotpService) Verify(ctx context.Context, uid int, code string) error {
+
+	info, err := s.repo.FindByUserId(ctx, uid)
+	if err != nil || info.IsEnabled != 1 {
+		return ErrTotpNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+
+	if counter, ok := totp.Validate(info.Secret, code, time.Now(), 1); ok {
+		// 条件更新，同一验证码只允许使用一次
+		res := s.Db().Model(&model.UserTotp{}).Where("id = ? and last_counter < ?", info.Id, counter).Update("last_counter", counter)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return ErrTotpInvalidCode
+		}
+
+		return nil
+	}
+
+	hash := encrypt.Sha256([]byte(strings.ToLower(code)))
+
+	hashes := s.recoveryCodes(info)
+	for i, value := range hashes {
+		if value != hash {
+			continue
+		}
+
+		remain := append(hashes[:i:i], hashes[i+1:]...)
+
+		res := s.Db().Model(&model.UserTotp{}).Where("id = ? and recovery_codes = ?", info.Id, info.RecoveryCodes).Update("recovery_codes", jsonutil.Encode(remain))
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return ErrTotpInvalidCode
+		}
+
+		return nil
+	}
+
+	return ErrTotpInvalidCode
+}
+
+// Disable 关闭两步验证，需重新校验登录密码及验证码
+func (s *UserTotpService) Disable(ctx context.Context, uid int, password string, code string) error {
+
+	if s.IsForced(ctx, uid) {
+		return ErrTotpForced
+	}
+
+	if err := s.verifyPassword(ctx, uid, password); err != nil {
+		return err
+	}
+
+	if err := s.Verify(ctx, uid, code); err != nil {
+		return err
+	}
+
+	return s.Db().Where("user_id = ?", uid).Delete(&model.UserTotp{}).Error
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，需重新校验登录密码及验证码
+func (s *UserTotpService) RegenerateRecoveryCodes(ctx context.Context, uid int, password string, code string) ([]string, error) {
+
+	if err := s.verifyPassword(ctx, uid, password); err != nil {
+		return nil, err
+	}
+
+	if err := s.Verify(ctx, uid, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Db().Model(&model.UserTotp{}).Where("user_id = ?", uid).Update("recovery_codes", jsonutil.Encode(hashes)).Error; err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// SetForce 设置企业成员是否强制开启两步验证，返回更新的成员数
+func (s *UserTotpService) SetForce(ctx context.Context, uids []int, force bool) (int64, error) {
+	value := 0
+	if force {
+		value = 1
+	}
+
+	return s.organizeRepo.UpdateWhere(ctx, map[string]interface{}{"is_force_totp": value}, "user_id in ?", uids)
+}
+
+func (s *UserTotpService) verifyPassword(ctx context.Context, uid int, password string) error {
+	user, err := s.usersRepo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	if !encrypt.VerifyPassword(user.Password, password) {
+		return ErrTotpPassword
+	}
+
+	return nil
+}
+
+func (s *UserTotpService) challenge(ctx context.Context, token string) (*TotpChallenge, error) {
+	value, err := s.rds.Get(ctx, s.challengeKey(token)).Result()
+	if err != nil {
+		return nil, ErrTotpChallenge
+	}
+
+	challenge := &TotpChallenge{}
+	if err := jsonutil.Decode(value, challenge); err != nil {
+		return nil, ErrTotpChallenge
+	}
+
+	return challenge, nil
+}
+
+func (s *UserTotpService) challengeKey(token string) string {
+	return fmt.Sprintf("auth:totp-challenge:%s", token)
+}
+
+func (s *UserTotpService) attemptsKey(token string) string {
+	return fmt.Sprintf("totp:challenge:%s", token)
+}
+
+func (s *UserTotpService) failureKey(uid int) string {
+	return fmt.Sprintf("totp:user:%d", uid)
+}
+
+func (s *UserTotpService) recoveryCodes(info *model.UserTotp) []string {
+	hashes := make([]string, 0)
+	_ = jsonutil.Decode(info.RecoveryCodes, &hashes)
+	return hashes
+}
+
+// generateRecoveryCodes 生成恢复码，格式为 xxxxx-xxxxx，仅保存 hash
+func (s *UserTotpService) generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, totpRecoveryCodeNum)
+	hashes := make([]string, 0, totpRecoveryCodeNum)
+
+	for i := 0; i < totpRecoveryCodeNum; i++ {
+		value, err := randomHex(5)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		code := value[:5] + "-" + value[5:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, encrypt.Sha256([]byte(code)))
+	}
+
+	return codes, hashes, nil
+}
+
+func (s *UserTotpService) issuer() string {
+	if s.conf.App != nil && s.conf.App.AppName != "" {
+		return s.conf.App.AppName
+	}
+
+	return "LumenIM"
+}
+
+// randomHex 生成指定字节数的随机十六进制字符串
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}